@@ -4,3 +4,15 @@ package gen
 //go:generate mockgen -destination=mocks/repository/appointment_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain AppointmentRepository
 //go:generate mockgen -destination=mocks/repository/service_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain ServiceRepository
 //go:generate mockgen -destination=mocks/repository/doctor_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain DoctorRepository
+//go:generate mockgen -destination=mocks/repository/schedule_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain ScheduleRepository
+//go:generate mockgen -destination=mocks/repository/session_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain SessionRepository
+//go:generate mockgen -destination=mocks/repository/login_attempt_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain LoginAttemptRepository
+//go:generate mockgen -destination=mocks/repository/two_factor_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain TwoFactorRepository
+//go:generate mockgen -destination=mocks/repository/audit_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain AuditRepository
+//go:generate mockgen -destination=mocks/repository/unit_of_work_mock.go -package=repository github.com/sdk17/crmstom/internal/domain UnitOfWork
+//go:generate mockgen -destination=mocks/repository/dental_chart_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain DentalChartRepository
+//go:generate mockgen -destination=mocks/repository/treatment_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain TreatmentRepository
+//go:generate mockgen -destination=mocks/repository/treatment_plan_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain TreatmentPlanRepository
+//go:generate mockgen -destination=mocks/repository/diagnosis_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain DiagnosisRepository
+//go:generate mockgen -destination=mocks/repository/appointment_diagnosis_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain AppointmentDiagnosisRepository
+//go:generate mockgen -destination=mocks/service/appointment_service_mock.go -package=service github.com/sdk17/crmstom/internal/domain AppointmentService
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: AppointmentDiagnosisRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/appointment_diagnosis_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain AppointmentDiagnosisRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAppointmentDiagnosisRepository is a mock of AppointmentDiagnosisRepository interface.
+type MockAppointmentDiagnosisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppointmentDiagnosisRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAppointmentDiagnosisRepositoryMockRecorder is the mock recorder for MockAppointmentDiagnosisRepository.
+type MockAppointmentDiagnosisRepositoryMockRecorder struct {
+	mock *MockAppointmentDiagnosisRepository
+}
+
+// NewMockAppointmentDiagnosisRepository creates a new mock instance.
+func NewMockAppointmentDiagnosisRepository(ctrl *gomock.Controller) *MockAppointmentDiagnosisRepository {
+	mock := &MockAppointmentDiagnosisRepository{ctrl: ctrl}
+	mock.recorder = &MockAppointmentDiagnosisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppointmentDiagnosisRepository) EXPECT() *MockAppointmentDiagnosisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAppointmentDiagnosisRepository) Create(ctx context.Context, diagnosis *domain.AppointmentDiagnosis) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, diagnosis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAppointmentDiagnosisRepositoryMockRecorder) Create(ctx, diagnosis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppointmentDiagnosisRepository)(nil).Create), ctx, diagnosis)
+}
+
+// Delete mocks base method.
+func (m *MockAppointmentDiagnosisRepository) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAppointmentDiagnosisRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAppointmentDiagnosisRepository)(nil).Delete), ctx, id)
+}
+
+// GetByAppointmentID mocks base method.
+func (m *MockAppointmentDiagnosisRepository) GetByAppointmentID(ctx context.Context, appointmentID int) ([]*domain.AppointmentDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAppointmentID", ctx, appointmentID)
+	ret0, _ := ret[0].([]*domain.AppointmentDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAppointmentID indicates an expected call of GetByAppointmentID.
+func (mr *MockAppointmentDiagnosisRepositoryMockRecorder) GetByAppointmentID(ctx, appointmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAppointmentID", reflect.TypeOf((*MockAppointmentDiagnosisRepository)(nil).GetByAppointmentID), ctx, appointmentID)
+}
+
+// GetByID mocks base method.
+func (m *MockAppointmentDiagnosisRepository) GetByID(ctx context.Context, id int) (*domain.AppointmentDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.AppointmentDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAppointmentDiagnosisRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAppointmentDiagnosisRepository)(nil).GetByID), ctx, id)
+}
+
+// GetReport mocks base method.
+func (m *MockAppointmentDiagnosisRepository) GetReport(ctx context.Context, filter domain.DiagnosisReportFilter) ([]*domain.DiagnosisReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, filter)
+	ret0, _ := ret[0].([]*domain.DiagnosisReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockAppointmentDiagnosisRepositoryMockRecorder) GetReport(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockAppointmentDiagnosisRepository)(nil).GetReport), ctx, filter)
+}
@@ -10,6 +10,7 @@
 package repository
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -41,134 +42,165 @@ func (m *MockAppointmentRepository) EXPECT() *MockAppointmentRepositoryMockRecor
 	return m.recorder
 }
 
-// CheckTimeConflict mocks base method.
-func (m *MockAppointmentRepository) CheckTimeConflict(date time.Time, arg1 string, excludeID int) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckTimeConflict", date, arg1, excludeID)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// CheckTimeConflict indicates an expected call of CheckTimeConflict.
-func (mr *MockAppointmentRepositoryMockRecorder) CheckTimeConflict(date, arg1, excludeID any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTimeConflict", reflect.TypeOf((*MockAppointmentRepository)(nil).CheckTimeConflict), date, arg1, excludeID)
-}
-
 // Create mocks base method.
-func (m *MockAppointmentRepository) Create(appointment *domain.Appointment) error {
+func (m *MockAppointmentRepository) Create(ctx context.Context, appointment *domain.Appointment) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", appointment)
+	ret := m.ctrl.Call(m, "Create", ctx, appointment)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockAppointmentRepositoryMockRecorder) Create(appointment any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) Create(ctx, appointment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppointmentRepository)(nil).Create), appointment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppointmentRepository)(nil).Create), ctx, appointment)
 }
 
 // Delete mocks base method.
-func (m *MockAppointmentRepository) Delete(id int) error {
+func (m *MockAppointmentRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAppointmentRepositoryMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAppointmentRepository)(nil).Delete), ctx, id)
+}
+
+// FindConflicts mocks base method.
+func (m *MockAppointmentRepository) FindConflicts(ctx context.Context, appointment *domain.Appointment) ([]*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConflicts", ctx, appointment)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConflicts indicates an expected call of FindConflicts.
+func (mr *MockAppointmentRepositoryMockRecorder) FindConflicts(ctx, appointment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAppointmentRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConflicts", reflect.TypeOf((*MockAppointmentRepository)(nil).FindConflicts), ctx, appointment)
+}
+
+// FindOverlapping mocks base method.
+func (m *MockAppointmentRepository) FindOverlapping(ctx context.Context, doctorID int, start, end time.Time) ([]*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOverlapping", ctx, doctorID, start, end)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOverlapping indicates an expected call of FindOverlapping.
+func (mr *MockAppointmentRepositoryMockRecorder) FindOverlapping(ctx, doctorID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverlapping", reflect.TypeOf((*MockAppointmentRepository)(nil).FindOverlapping), ctx, doctorID, start, end)
 }
 
 // GetAll mocks base method.
-func (m *MockAppointmentRepository) GetAll() ([]*domain.Appointment, error) {
+func (m *MockAppointmentRepository) GetAll(ctx context.Context) ([]*domain.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]*domain.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockAppointmentRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAppointmentRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAppointmentRepository)(nil).GetAll), ctx)
 }
 
 // GetByDate mocks base method.
-func (m *MockAppointmentRepository) GetByDate(date time.Time) ([]*domain.Appointment, error) {
+func (m *MockAppointmentRepository) GetByDate(ctx context.Context, date time.Time) ([]*domain.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDate", date)
+	ret := m.ctrl.Call(m, "GetByDate", ctx, date)
 	ret0, _ := ret[0].([]*domain.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByDate indicates an expected call of GetByDate.
-func (mr *MockAppointmentRepositoryMockRecorder) GetByDate(date any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) GetByDate(ctx, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByDate), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByDate), ctx, date)
 }
 
 // GetByDateRange mocks base method.
-func (m *MockAppointmentRepository) GetByDateRange(start, end time.Time) ([]*domain.Appointment, error) {
+func (m *MockAppointmentRepository) GetByDateRange(ctx context.Context, start, end time.Time) ([]*domain.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDateRange", start, end)
+	ret := m.ctrl.Call(m, "GetByDateRange", ctx, start, end)
 	ret0, _ := ret[0].([]*domain.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByDateRange indicates an expected call of GetByDateRange.
-func (mr *MockAppointmentRepositoryMockRecorder) GetByDateRange(start, end any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) GetByDateRange(ctx, start, end any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDateRange", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByDateRange), start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDateRange", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByDateRange), ctx, start, end)
 }
 
 // GetByID mocks base method.
-func (m *MockAppointmentRepository) GetByID(id int) (*domain.Appointment, error) {
+func (m *MockAppointmentRepository) GetByID(ctx context.Context, id int) (*domain.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*domain.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockAppointmentRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByID), ctx, id)
 }
 
 // GetByPatientID mocks base method.
-func (m *MockAppointmentRepository) GetByPatientID(patientID int) ([]*domain.Appointment, error) {
+func (m *MockAppointmentRepository) GetByPatientID(ctx context.Context, patientID int) ([]*domain.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByPatientID", patientID)
+	ret := m.ctrl.Call(m, "GetByPatientID", ctx, patientID)
 	ret0, _ := ret[0].([]*domain.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByPatientID indicates an expected call of GetByPatientID.
-func (mr *MockAppointmentRepositoryMockRecorder) GetByPatientID(patientID any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) GetByPatientID(ctx, patientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPatientID", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByPatientID), ctx, patientID)
+}
+
+// List mocks base method.
+func (m *MockAppointmentRepository) List(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAppointmentRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPatientID", reflect.TypeOf((*MockAppointmentRepository)(nil).GetByPatientID), patientID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAppointmentRepository)(nil).List), ctx, filter)
 }
 
 // Update mocks base method.
-func (m *MockAppointmentRepository) Update(appointment *domain.Appointment) error {
+func (m *MockAppointmentRepository) Update(ctx context.Context, appointment *domain.Appointment) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", appointment)
+	ret := m.ctrl.Call(m, "Update", ctx, appointment)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAppointmentRepositoryMockRecorder) Update(appointment any) *gomock.Call {
+func (mr *MockAppointmentRepositoryMockRecorder) Update(ctx, appointment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAppointmentRepository)(nil).Update), appointment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAppointmentRepository)(nil).Update), ctx, appointment)
 }
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: DentalChartRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/dental_chart_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain DentalChartRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDentalChartRepository is a mock of DentalChartRepository interface.
+type MockDentalChartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDentalChartRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDentalChartRepositoryMockRecorder is the mock recorder for MockDentalChartRepository.
+type MockDentalChartRepositoryMockRecorder struct {
+	mock *MockDentalChartRepository
+}
+
+// NewMockDentalChartRepository creates a new mock instance.
+func NewMockDentalChartRepository(ctrl *gomock.Controller) *MockDentalChartRepository {
+	mock := &MockDentalChartRepository{ctrl: ctrl}
+	mock.recorder = &MockDentalChartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDentalChartRepository) EXPECT() *MockDentalChartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetHistory mocks base method.
+func (m *MockDentalChartRepository) GetHistory(ctx context.Context, patientID, tooth int) ([]*domain.ToothChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, patientID, tooth)
+	ret0, _ := ret[0].([]*domain.ToothChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockDentalChartRepositoryMockRecorder) GetHistory(ctx, patientID, tooth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockDentalChartRepository)(nil).GetHistory), ctx, patientID, tooth)
+}
+
+// GetTeeth mocks base method.
+func (m *MockDentalChartRepository) GetTeeth(ctx context.Context, patientID int) ([]*domain.ToothState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeeth", ctx, patientID)
+	ret0, _ := ret[0].([]*domain.ToothState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeeth indicates an expected call of GetTeeth.
+func (mr *MockDentalChartRepositoryMockRecorder) GetTeeth(ctx, patientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeeth", reflect.TypeOf((*MockDentalChartRepository)(nil).GetTeeth), ctx, patientID)
+}
+
+// RecordChanges mocks base method.
+func (m *MockDentalChartRepository) RecordChanges(ctx context.Context, changes []*domain.ToothChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordChanges", ctx, changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordChanges indicates an expected call of RecordChanges.
+func (mr *MockDentalChartRepositoryMockRecorder) RecordChanges(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordChanges", reflect.TypeOf((*MockDentalChartRepository)(nil).RecordChanges), ctx, changes)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: DiagnosisRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/diagnosis_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain DiagnosisRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDiagnosisRepository is a mock of DiagnosisRepository interface.
+type MockDiagnosisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDiagnosisRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDiagnosisRepositoryMockRecorder is the mock recorder for MockDiagnosisRepository.
+type MockDiagnosisRepositoryMockRecorder struct {
+	mock *MockDiagnosisRepository
+}
+
+// NewMockDiagnosisRepository creates a new mock instance.
+func NewMockDiagnosisRepository(ctrl *gomock.Controller) *MockDiagnosisRepository {
+	mock := &MockDiagnosisRepository{ctrl: ctrl}
+	mock.recorder = &MockDiagnosisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDiagnosisRepository) EXPECT() *MockDiagnosisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByCode mocks base method.
+func (m *MockDiagnosisRepository) GetByCode(ctx context.Context, code string) (*domain.Diagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCode", ctx, code)
+	ret0, _ := ret[0].(*domain.Diagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCode indicates an expected call of GetByCode.
+func (mr *MockDiagnosisRepositoryMockRecorder) GetByCode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCode", reflect.TypeOf((*MockDiagnosisRepository)(nil).GetByCode), ctx, code)
+}
+
+// Search mocks base method.
+func (m *MockDiagnosisRepository) Search(ctx context.Context, query string, limit int) ([]*domain.Diagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]*domain.Diagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockDiagnosisRepositoryMockRecorder) Search(ctx, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDiagnosisRepository)(nil).Search), ctx, query, limit)
+}
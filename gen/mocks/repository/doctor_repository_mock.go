@@ -10,7 +10,9 @@
 package repository
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/sdk17/crmstom/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -41,88 +43,161 @@ func (m *MockDoctorRepository) EXPECT() *MockDoctorRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockDoctorRepository) Create(doctor *domain.Doctor) error {
+func (m *MockDoctorRepository) Create(ctx context.Context, doctor *domain.Doctor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", doctor)
+	ret := m.ctrl.Call(m, "Create", ctx, doctor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockDoctorRepositoryMockRecorder) Create(doctor any) *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) Create(ctx, doctor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDoctorRepository)(nil).Create), doctor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDoctorRepository)(nil).Create), ctx, doctor)
 }
 
 // Delete mocks base method.
-func (m *MockDoctorRepository) Delete(id int) error {
+func (m *MockDoctorRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockDoctorRepositoryMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDoctorRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDoctorRepository)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockDoctorRepository) GetAll() ([]*domain.Doctor, error) {
+func (m *MockDoctorRepository) GetAll(ctx context.Context) ([]*domain.Doctor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]*domain.Doctor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockDoctorRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDoctorRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDoctorRepository)(nil).GetAll), ctx)
 }
 
 // GetByID mocks base method.
-func (m *MockDoctorRepository) GetByID(id int) (*domain.Doctor, error) {
+func (m *MockDoctorRepository) GetByID(ctx context.Context, id int) (*domain.Doctor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*domain.Doctor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockDoctorRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDoctorRepository)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDoctorRepository)(nil).GetByID), ctx, id)
 }
 
 // GetByLogin mocks base method.
-func (m *MockDoctorRepository) GetByLogin(login string) (*domain.Doctor, error) {
+func (m *MockDoctorRepository) GetByLogin(ctx context.Context, login string) (*domain.Doctor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByLogin", login)
+	ret := m.ctrl.Call(m, "GetByLogin", ctx, login)
 	ret0, _ := ret[0].(*domain.Doctor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByLogin indicates an expected call of GetByLogin.
-func (mr *MockDoctorRepositoryMockRecorder) GetByLogin(login any) *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) GetByLogin(ctx, login any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLogin", reflect.TypeOf((*MockDoctorRepository)(nil).GetByLogin), login)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLogin", reflect.TypeOf((*MockDoctorRepository)(nil).GetByLogin), ctx, login)
+}
+
+// IncrementFailedLogins mocks base method.
+func (m *MockDoctorRepository) IncrementFailedLogins(ctx context.Context, id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementFailedLogins", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementFailedLogins indicates an expected call of IncrementFailedLogins.
+func (mr *MockDoctorRepositoryMockRecorder) IncrementFailedLogins(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementFailedLogins", reflect.TypeOf((*MockDoctorRepository)(nil).IncrementFailedLogins), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockDoctorRepository) List(ctx context.Context, filter domain.DoctorFilter) ([]*domain.Doctor, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Doctor)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockDoctorRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDoctorRepository)(nil).List), ctx, filter)
+}
+
+// LockUntil mocks base method.
+func (m *MockDoctorRepository) LockUntil(ctx context.Context, id int, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUntil", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUntil indicates an expected call of LockUntil.
+func (mr *MockDoctorRepositoryMockRecorder) LockUntil(ctx, id, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUntil", reflect.TypeOf((*MockDoctorRepository)(nil).LockUntil), ctx, id, until)
+}
+
+// ResetFailedLogins mocks base method.
+func (m *MockDoctorRepository) ResetFailedLogins(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedLogins", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedLogins indicates an expected call of ResetFailedLogins.
+func (mr *MockDoctorRepositoryMockRecorder) ResetFailedLogins(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLogins", reflect.TypeOf((*MockDoctorRepository)(nil).ResetFailedLogins), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockDoctorRepository) Update(doctor *domain.Doctor) error {
+func (m *MockDoctorRepository) Update(ctx context.Context, doctor *domain.Doctor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", doctor)
+	ret := m.ctrl.Call(m, "Update", ctx, doctor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockDoctorRepositoryMockRecorder) Update(doctor any) *gomock.Call {
+func (mr *MockDoctorRepositoryMockRecorder) Update(ctx, doctor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDoctorRepository)(nil).Update), ctx, doctor)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockDoctorRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockDoctorRepositoryMockRecorder) UpdatePassword(ctx, id, passwordHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDoctorRepository)(nil).Update), doctor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockDoctorRepository)(nil).UpdatePassword), ctx, id, passwordHash)
 }
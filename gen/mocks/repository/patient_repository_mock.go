@@ -10,6 +10,7 @@
 package repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	domain "github.com/sdk17/crmstom/internal/domain"
@@ -41,118 +42,134 @@ func (m *MockPatientRepository) EXPECT() *MockPatientRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockPatientRepository) Create(patient *domain.Patient) error {
+func (m *MockPatientRepository) Create(ctx context.Context, patient *domain.Patient) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", patient)
+	ret := m.ctrl.Call(m, "Create", ctx, patient)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockPatientRepositoryMockRecorder) Create(patient any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) Create(ctx, patient any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPatientRepository)(nil).Create), patient)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPatientRepository)(nil).Create), ctx, patient)
 }
 
 // Delete mocks base method.
-func (m *MockPatientRepository) Delete(id int) error {
+func (m *MockPatientRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockPatientRepositoryMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPatientRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPatientRepository)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockPatientRepository) GetAll() ([]*domain.Patient, error) {
+func (m *MockPatientRepository) GetAll(ctx context.Context) ([]*domain.Patient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]*domain.Patient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockPatientRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPatientRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPatientRepository)(nil).GetAll), ctx)
 }
 
 // GetByID mocks base method.
-func (m *MockPatientRepository) GetByID(id int) (*domain.Patient, error) {
+func (m *MockPatientRepository) GetByID(ctx context.Context, id int) (*domain.Patient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*domain.Patient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockPatientRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPatientRepository)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPatientRepository)(nil).GetByID), ctx, id)
 }
 
 // GetByIIN mocks base method.
-func (m *MockPatientRepository) GetByIIN(iin string) (*domain.Patient, error) {
+func (m *MockPatientRepository) GetByIIN(ctx context.Context, iin string) (*domain.Patient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIIN", iin)
+	ret := m.ctrl.Call(m, "GetByIIN", ctx, iin)
 	ret0, _ := ret[0].(*domain.Patient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIIN indicates an expected call of GetByIIN.
-func (mr *MockPatientRepositoryMockRecorder) GetByIIN(iin any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) GetByIIN(ctx, iin any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIIN", reflect.TypeOf((*MockPatientRepository)(nil).GetByIIN), iin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIIN", reflect.TypeOf((*MockPatientRepository)(nil).GetByIIN), ctx, iin)
 }
 
 // GetByPhone mocks base method.
-func (m *MockPatientRepository) GetByPhone(phone string) (*domain.Patient, error) {
+func (m *MockPatientRepository) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByPhone", phone)
+	ret := m.ctrl.Call(m, "GetByPhone", ctx, phone)
 	ret0, _ := ret[0].(*domain.Patient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByPhone indicates an expected call of GetByPhone.
-func (mr *MockPatientRepositoryMockRecorder) GetByPhone(phone any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) GetByPhone(ctx, phone any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPhone", reflect.TypeOf((*MockPatientRepository)(nil).GetByPhone), phone)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPhone", reflect.TypeOf((*MockPatientRepository)(nil).GetByPhone), ctx, phone)
+}
+
+// List mocks base method.
+func (m *MockPatientRepository) List(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Patient)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPatientRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPatientRepository)(nil).List), ctx, filter)
 }
 
 // Search mocks base method.
-func (m *MockPatientRepository) Search(query string) ([]*domain.Patient, error) {
+func (m *MockPatientRepository) Search(ctx context.Context, query string) ([]*domain.Patient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", query)
+	ret := m.ctrl.Call(m, "Search", ctx, query)
 	ret0, _ := ret[0].([]*domain.Patient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockPatientRepositoryMockRecorder) Search(query any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) Search(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPatientRepository)(nil).Search), query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPatientRepository)(nil).Search), ctx, query)
 }
 
 // Update mocks base method.
-func (m *MockPatientRepository) Update(patient *domain.Patient) error {
+func (m *MockPatientRepository) Update(ctx context.Context, patient *domain.Patient) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", patient)
+	ret := m.ctrl.Call(m, "Update", ctx, patient)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPatientRepositoryMockRecorder) Update(patient any) *gomock.Call {
+func (mr *MockPatientRepositoryMockRecorder) Update(ctx, patient any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPatientRepository)(nil).Update), patient)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPatientRepository)(nil).Update), ctx, patient)
 }
@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: ScheduleRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/schedule_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain ScheduleRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScheduleRepository is a mock of ScheduleRepository interface.
+type MockScheduleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduleRepositoryMockRecorder is the mock recorder for MockScheduleRepository.
+type MockScheduleRepositoryMockRecorder struct {
+	mock *MockScheduleRepository
+}
+
+// NewMockScheduleRepository creates a new mock instance.
+func NewMockScheduleRepository(ctrl *gomock.Controller) *MockScheduleRepository {
+	mock := &MockScheduleRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleRepository) EXPECT() *MockScheduleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateException mocks base method.
+func (m *MockScheduleRepository) CreateException(ctx context.Context, exception *domain.ScheduleException) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateException", ctx, exception)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateException indicates an expected call of CreateException.
+func (mr *MockScheduleRepositoryMockRecorder) CreateException(ctx, exception any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateException", reflect.TypeOf((*MockScheduleRepository)(nil).CreateException), ctx, exception)
+}
+
+// CreateHoliday mocks base method.
+func (m *MockScheduleRepository) CreateHoliday(ctx context.Context, holiday *domain.ClinicHoliday) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHoliday", ctx, holiday)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHoliday indicates an expected call of CreateHoliday.
+func (mr *MockScheduleRepositoryMockRecorder) CreateHoliday(ctx, holiday any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHoliday", reflect.TypeOf((*MockScheduleRepository)(nil).CreateHoliday), ctx, holiday)
+}
+
+// DeleteException mocks base method.
+func (m *MockScheduleRepository) DeleteException(ctx context.Context, doctorID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteException", ctx, doctorID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteException indicates an expected call of DeleteException.
+func (mr *MockScheduleRepositoryMockRecorder) DeleteException(ctx, doctorID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteException", reflect.TypeOf((*MockScheduleRepository)(nil).DeleteException), ctx, doctorID, id)
+}
+
+// DeleteHoliday mocks base method.
+func (m *MockScheduleRepository) DeleteHoliday(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHoliday", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHoliday indicates an expected call of DeleteHoliday.
+func (mr *MockScheduleRepositoryMockRecorder) DeleteHoliday(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHoliday", reflect.TypeOf((*MockScheduleRepository)(nil).DeleteHoliday), ctx, id)
+}
+
+// GetBreaks mocks base method.
+func (m *MockScheduleRepository) GetBreaks(ctx context.Context, doctorID int) ([]*domain.ScheduleBreak, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBreaks", ctx, doctorID)
+	ret0, _ := ret[0].([]*domain.ScheduleBreak)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBreaks indicates an expected call of GetBreaks.
+func (mr *MockScheduleRepositoryMockRecorder) GetBreaks(ctx, doctorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBreaks", reflect.TypeOf((*MockScheduleRepository)(nil).GetBreaks), ctx, doctorID)
+}
+
+// GetExceptions mocks base method.
+func (m *MockScheduleRepository) GetExceptions(ctx context.Context, doctorID int, from, to time.Time) ([]*domain.ScheduleException, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExceptions", ctx, doctorID, from, to)
+	ret0, _ := ret[0].([]*domain.ScheduleException)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExceptions indicates an expected call of GetExceptions.
+func (mr *MockScheduleRepositoryMockRecorder) GetExceptions(ctx, doctorID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExceptions", reflect.TypeOf((*MockScheduleRepository)(nil).GetExceptions), ctx, doctorID, from, to)
+}
+
+// GetHolidays mocks base method.
+func (m *MockScheduleRepository) GetHolidays(ctx context.Context, from, to time.Time) ([]*domain.ClinicHoliday, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolidays", ctx, from, to)
+	ret0, _ := ret[0].([]*domain.ClinicHoliday)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolidays indicates an expected call of GetHolidays.
+func (mr *MockScheduleRepositoryMockRecorder) GetHolidays(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolidays", reflect.TypeOf((*MockScheduleRepository)(nil).GetHolidays), ctx, from, to)
+}
+
+// GetWorkingHours mocks base method.
+func (m *MockScheduleRepository) GetWorkingHours(ctx context.Context, doctorID int) ([]*domain.WorkingHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkingHours", ctx, doctorID)
+	ret0, _ := ret[0].([]*domain.WorkingHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkingHours indicates an expected call of GetWorkingHours.
+func (mr *MockScheduleRepositoryMockRecorder) GetWorkingHours(ctx, doctorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHours", reflect.TypeOf((*MockScheduleRepository)(nil).GetWorkingHours), ctx, doctorID)
+}
+
+// ReplaceWeeklySchedule mocks base method.
+func (m *MockScheduleRepository) ReplaceWeeklySchedule(ctx context.Context, doctorID int, hours []*domain.WorkingHours, breaks []*domain.ScheduleBreak) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceWeeklySchedule", ctx, doctorID, hours, breaks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceWeeklySchedule indicates an expected call of ReplaceWeeklySchedule.
+func (mr *MockScheduleRepositoryMockRecorder) ReplaceWeeklySchedule(ctx, doctorID, hours, breaks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceWeeklySchedule", reflect.TypeOf((*MockScheduleRepository)(nil).ReplaceWeeklySchedule), ctx, doctorID, hours, breaks)
+}
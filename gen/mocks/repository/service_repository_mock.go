@@ -10,7 +10,9 @@
 package repository
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/sdk17/crmstom/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -40,104 +42,164 @@ func (m *MockServiceRepository) EXPECT() *MockServiceRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddPrice mocks base method.
+func (m *MockServiceRepository) AddPrice(ctx context.Context, price *domain.ServicePrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrice", ctx, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrice indicates an expected call of AddPrice.
+func (mr *MockServiceRepositoryMockRecorder) AddPrice(ctx, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrice", reflect.TypeOf((*MockServiceRepository)(nil).AddPrice), ctx, price)
+}
+
 // Create mocks base method.
-func (m *MockServiceRepository) Create(service *domain.Service) error {
+func (m *MockServiceRepository) Create(ctx context.Context, service *domain.Service) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", service)
+	ret := m.ctrl.Call(m, "Create", ctx, service)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockServiceRepositoryMockRecorder) Create(service any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) Create(ctx, service any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceRepository)(nil).Create), service)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceRepository)(nil).Create), ctx, service)
 }
 
 // Delete mocks base method.
-func (m *MockServiceRepository) Delete(id int) error {
+func (m *MockServiceRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockServiceRepositoryMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockServiceRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockServiceRepository)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockServiceRepository) GetAll() ([]*domain.Service, error) {
+func (m *MockServiceRepository) GetAll(ctx context.Context) ([]*domain.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]*domain.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockServiceRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockServiceRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockServiceRepository)(nil).GetAll), ctx)
 }
 
 // GetByCategory mocks base method.
-func (m *MockServiceRepository) GetByCategory(category string) ([]*domain.Service, error) {
+func (m *MockServiceRepository) GetByCategory(ctx context.Context, category string) ([]*domain.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCategory", category)
+	ret := m.ctrl.Call(m, "GetByCategory", ctx, category)
 	ret0, _ := ret[0].([]*domain.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCategory indicates an expected call of GetByCategory.
-func (mr *MockServiceRepositoryMockRecorder) GetByCategory(category any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetByCategory(ctx, category any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategory", reflect.TypeOf((*MockServiceRepository)(nil).GetByCategory), category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategory", reflect.TypeOf((*MockServiceRepository)(nil).GetByCategory), ctx, category)
 }
 
 // GetByID mocks base method.
-func (m *MockServiceRepository) GetByID(id int) (*domain.Service, error) {
+func (m *MockServiceRepository) GetByID(ctx context.Context, id int) (*domain.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*domain.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockServiceRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceRepository)(nil).GetByID), ctx, id)
+}
+
+// GetPriceAt mocks base method.
+func (m *MockServiceRepository) GetPriceAt(ctx context.Context, serviceID int, at time.Time) (domain.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceAt", ctx, serviceID, at)
+	ret0, _ := ret[0].(domain.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceAt indicates an expected call of GetPriceAt.
+func (mr *MockServiceRepositoryMockRecorder) GetPriceAt(ctx, serviceID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceAt", reflect.TypeOf((*MockServiceRepository)(nil).GetPriceAt), ctx, serviceID, at)
+}
+
+// GetPriceHistory mocks base method.
+func (m *MockServiceRepository) GetPriceHistory(ctx context.Context, serviceID int) ([]*domain.ServicePrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, serviceID)
+	ret0, _ := ret[0].([]*domain.ServicePrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockServiceRepositoryMockRecorder) GetPriceHistory(ctx, serviceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockServiceRepository)(nil).GetPriceHistory), ctx, serviceID)
+}
+
+// List mocks base method.
+func (m *MockServiceRepository) List(ctx context.Context, filter domain.ServiceFilter) ([]*domain.Service, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Service)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockServiceRepository)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServiceRepository)(nil).List), ctx, filter)
 }
 
 // Search mocks base method.
-func (m *MockServiceRepository) Search(query string) ([]*domain.Service, error) {
+func (m *MockServiceRepository) Search(ctx context.Context, query string) ([]*domain.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", query)
+	ret := m.ctrl.Call(m, "Search", ctx, query)
 	ret0, _ := ret[0].([]*domain.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockServiceRepositoryMockRecorder) Search(query any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) Search(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockServiceRepository)(nil).Search), query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockServiceRepository)(nil).Search), ctx, query)
 }
 
 // Update mocks base method.
-func (m *MockServiceRepository) Update(service *domain.Service) error {
+func (m *MockServiceRepository) Update(ctx context.Context, service *domain.Service) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", service)
+	ret := m.ctrl.Call(m, "Update", ctx, service)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceRepositoryMockRecorder) Update(service any) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) Update(ctx, service any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockServiceRepository)(nil).Update), service)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockServiceRepository)(nil).Update), ctx, service)
 }
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: TreatmentPlanRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/treatment_plan_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain TreatmentPlanRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTreatmentPlanRepository is a mock of TreatmentPlanRepository interface.
+type MockTreatmentPlanRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreatmentPlanRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTreatmentPlanRepositoryMockRecorder is the mock recorder for MockTreatmentPlanRepository.
+type MockTreatmentPlanRepositoryMockRecorder struct {
+	mock *MockTreatmentPlanRepository
+}
+
+// NewMockTreatmentPlanRepository creates a new mock instance.
+func NewMockTreatmentPlanRepository(ctrl *gomock.Controller) *MockTreatmentPlanRepository {
+	mock := &MockTreatmentPlanRepository{ctrl: ctrl}
+	mock.recorder = &MockTreatmentPlanRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreatmentPlanRepository) EXPECT() *MockTreatmentPlanRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTreatmentPlanRepository) Create(ctx context.Context, plan *domain.TreatmentPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) Create(ctx, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).Create), ctx, plan)
+}
+
+// Delete mocks base method.
+func (m *MockTreatmentPlanRepository) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockTreatmentPlanRepository) GetByID(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.TreatmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByPatientID mocks base method.
+func (m *MockTreatmentPlanRepository) GetByPatientID(ctx context.Context, patientID int) ([]*domain.TreatmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPatientID", ctx, patientID)
+	ret0, _ := ret[0].([]*domain.TreatmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPatientID indicates an expected call of GetByPatientID.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) GetByPatientID(ctx, patientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPatientID", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).GetByPatientID), ctx, patientID)
+}
+
+// GetProgress mocks base method.
+func (m *MockTreatmentPlanRepository) GetProgress(ctx context.Context, patientID int) ([]*domain.TreatmentPlanProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProgress", ctx, patientID)
+	ret0, _ := ret[0].([]*domain.TreatmentPlanProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProgress indicates an expected call of GetProgress.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) GetProgress(ctx, patientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProgress", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).GetProgress), ctx, patientID)
+}
+
+// SetStepAppointment mocks base method.
+func (m *MockTreatmentPlanRepository) SetStepAppointment(ctx context.Context, stepID, appointmentID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStepAppointment", ctx, stepID, appointmentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStepAppointment indicates an expected call of SetStepAppointment.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) SetStepAppointment(ctx, stepID, appointmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStepAppointment", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).SetStepAppointment), ctx, stepID, appointmentID)
+}
+
+// Update mocks base method.
+func (m *MockTreatmentPlanRepository) Update(ctx context.Context, plan *domain.TreatmentPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) Update(ctx, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).Update), ctx, plan)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockTreatmentPlanRepository) UpdateStatus(ctx context.Context, plan *domain.TreatmentPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockTreatmentPlanRepositoryMockRecorder) UpdateStatus(ctx, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockTreatmentPlanRepository)(nil).UpdateStatus), ctx, plan)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: TreatmentRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/repository/treatment_repository_mock.go -package=repository github.com/sdk17/crmstom/internal/domain TreatmentRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTreatmentRepository is a mock of TreatmentRepository interface.
+type MockTreatmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTreatmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTreatmentRepositoryMockRecorder is the mock recorder for MockTreatmentRepository.
+type MockTreatmentRepositoryMockRecorder struct {
+	mock *MockTreatmentRepository
+}
+
+// NewMockTreatmentRepository creates a new mock instance.
+func NewMockTreatmentRepository(ctrl *gomock.Controller) *MockTreatmentRepository {
+	mock := &MockTreatmentRepository{ctrl: ctrl}
+	mock.recorder = &MockTreatmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTreatmentRepository) EXPECT() *MockTreatmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTreatmentRepository) Create(ctx context.Context, treatment *domain.Treatment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, treatment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTreatmentRepositoryMockRecorder) Create(ctx, treatment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTreatmentRepository)(nil).Create), ctx, treatment)
+}
+
+// Delete mocks base method.
+func (m *MockTreatmentRepository) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTreatmentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTreatmentRepository)(nil).Delete), ctx, id)
+}
+
+// GetByAppointmentID mocks base method.
+func (m *MockTreatmentRepository) GetByAppointmentID(ctx context.Context, appointmentID int) ([]*domain.Treatment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAppointmentID", ctx, appointmentID)
+	ret0, _ := ret[0].([]*domain.Treatment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAppointmentID indicates an expected call of GetByAppointmentID.
+func (mr *MockTreatmentRepositoryMockRecorder) GetByAppointmentID(ctx, appointmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAppointmentID", reflect.TypeOf((*MockTreatmentRepository)(nil).GetByAppointmentID), ctx, appointmentID)
+}
+
+// GetByID mocks base method.
+func (m *MockTreatmentRepository) GetByID(ctx context.Context, id int) (*domain.Treatment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Treatment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTreatmentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTreatmentRepository)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockTreatmentRepository) Update(ctx context.Context, treatment *domain.Treatment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, treatment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTreatmentRepositoryMockRecorder) Update(ctx, treatment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTreatmentRepository)(nil).Update), ctx, treatment)
+}
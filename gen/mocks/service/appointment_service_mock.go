@@ -0,0 +1,295 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/sdk17/crmstom/internal/domain (interfaces: AppointmentService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/service/appointment_service_mock.go -package=service github.com/sdk17/crmstom/internal/domain AppointmentService
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/sdk17/crmstom/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAppointmentService is a mock of AppointmentService interface.
+type MockAppointmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppointmentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAppointmentServiceMockRecorder is the mock recorder for MockAppointmentService.
+type MockAppointmentServiceMockRecorder struct {
+	mock *MockAppointmentService
+}
+
+// NewMockAppointmentService creates a new mock instance.
+func NewMockAppointmentService(ctrl *gomock.Controller) *MockAppointmentService {
+	mock := &MockAppointmentService{ctrl: ctrl}
+	mock.recorder = &MockAppointmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppointmentService) EXPECT() *MockAppointmentServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelAppointment mocks base method.
+func (m *MockAppointmentService) CancelAppointment(ctx context.Context, id int, reason string) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAppointment", ctx, id, reason)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelAppointment indicates an expected call of CancelAppointment.
+func (mr *MockAppointmentServiceMockRecorder) CancelAppointment(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAppointment", reflect.TypeOf((*MockAppointmentService)(nil).CancelAppointment), ctx, id, reason)
+}
+
+// CompleteAppointment mocks base method.
+func (m *MockAppointmentService) CompleteAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteAppointment", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteAppointment indicates an expected call of CompleteAppointment.
+func (mr *MockAppointmentServiceMockRecorder) CompleteAppointment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteAppointment", reflect.TypeOf((*MockAppointmentService)(nil).CompleteAppointment), ctx, id)
+}
+
+// ConfirmAppointment mocks base method.
+func (m *MockAppointmentService) ConfirmAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmAppointment", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmAppointment indicates an expected call of ConfirmAppointment.
+func (mr *MockAppointmentServiceMockRecorder) ConfirmAppointment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmAppointment", reflect.TypeOf((*MockAppointmentService)(nil).ConfirmAppointment), ctx, id)
+}
+
+// CreateAppointment mocks base method.
+func (m *MockAppointmentService) CreateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppointment", ctx, appointment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppointment indicates an expected call of CreateAppointment.
+func (mr *MockAppointmentServiceMockRecorder) CreateAppointment(ctx, appointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppointment", reflect.TypeOf((*MockAppointmentService)(nil).CreateAppointment), ctx, appointment)
+}
+
+// DeleteAppointment mocks base method.
+func (m *MockAppointmentService) DeleteAppointment(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppointment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppointment indicates an expected call of DeleteAppointment.
+func (mr *MockAppointmentServiceMockRecorder) DeleteAppointment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppointment", reflect.TypeOf((*MockAppointmentService)(nil).DeleteAppointment), ctx, id)
+}
+
+// FindFreeSlots mocks base method.
+func (m *MockAppointmentService) FindFreeSlots(ctx context.Context, query *domain.SlotQuery) ([]domain.TimeSlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFreeSlots", ctx, query)
+	ret0, _ := ret[0].([]domain.TimeSlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFreeSlots indicates an expected call of FindFreeSlots.
+func (mr *MockAppointmentServiceMockRecorder) FindFreeSlots(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFreeSlots", reflect.TypeOf((*MockAppointmentService)(nil).FindFreeSlots), ctx, query)
+}
+
+// GetAllAppointments mocks base method.
+func (m *MockAppointmentService) GetAllAppointments(ctx context.Context) ([]*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAppointments", ctx)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAppointments indicates an expected call of GetAllAppointments.
+func (mr *MockAppointmentServiceMockRecorder) GetAllAppointments(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAppointments", reflect.TypeOf((*MockAppointmentService)(nil).GetAllAppointments), ctx)
+}
+
+// GetAppointment mocks base method.
+func (m *MockAppointmentService) GetAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointment", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointment indicates an expected call of GetAppointment.
+func (mr *MockAppointmentServiceMockRecorder) GetAppointment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointment", reflect.TypeOf((*MockAppointmentService)(nil).GetAppointment), ctx, id)
+}
+
+// GetAppointmentsByDate mocks base method.
+func (m *MockAppointmentService) GetAppointmentsByDate(ctx context.Context, date time.Time) ([]*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointmentsByDate", ctx, date)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointmentsByDate indicates an expected call of GetAppointmentsByDate.
+func (mr *MockAppointmentServiceMockRecorder) GetAppointmentsByDate(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointmentsByDate", reflect.TypeOf((*MockAppointmentService)(nil).GetAppointmentsByDate), ctx, date)
+}
+
+// GetAppointmentsByPatient mocks base method.
+func (m *MockAppointmentService) GetAppointmentsByPatient(ctx context.Context, patientID int) ([]*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointmentsByPatient", ctx, patientID)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointmentsByPatient indicates an expected call of GetAppointmentsByPatient.
+func (mr *MockAppointmentServiceMockRecorder) GetAppointmentsByPatient(ctx, patientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointmentsByPatient", reflect.TypeOf((*MockAppointmentService)(nil).GetAppointmentsByPatient), ctx, patientID)
+}
+
+// ListAppointments mocks base method.
+func (m *MockAppointmentService) ListAppointments(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppointments", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Appointment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAppointments indicates an expected call of ListAppointments.
+func (mr *MockAppointmentServiceMockRecorder) ListAppointments(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppointments", reflect.TypeOf((*MockAppointmentService)(nil).ListAppointments), ctx, filter)
+}
+
+// MarkAppointmentArrived mocks base method.
+func (m *MockAppointmentService) MarkAppointmentArrived(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAppointmentArrived", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAppointmentArrived indicates an expected call of MarkAppointmentArrived.
+func (mr *MockAppointmentServiceMockRecorder) MarkAppointmentArrived(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAppointmentArrived", reflect.TypeOf((*MockAppointmentService)(nil).MarkAppointmentArrived), ctx, id)
+}
+
+// MarkAppointmentNoShow mocks base method.
+func (m *MockAppointmentService) MarkAppointmentNoShow(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAppointmentNoShow", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAppointmentNoShow indicates an expected call of MarkAppointmentNoShow.
+func (mr *MockAppointmentServiceMockRecorder) MarkAppointmentNoShow(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAppointmentNoShow", reflect.TypeOf((*MockAppointmentService)(nil).MarkAppointmentNoShow), ctx, id)
+}
+
+// RescheduleAppointment mocks base method.
+func (m *MockAppointmentService) RescheduleAppointment(ctx context.Context, id int, date time.Time) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleAppointment", ctx, id, date)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RescheduleAppointment indicates an expected call of RescheduleAppointment.
+func (mr *MockAppointmentServiceMockRecorder) RescheduleAppointment(ctx, id, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleAppointment", reflect.TypeOf((*MockAppointmentService)(nil).RescheduleAppointment), ctx, id, date)
+}
+
+// StartAppointment mocks base method.
+func (m *MockAppointmentService) StartAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartAppointment", ctx, id)
+	ret0, _ := ret[0].(*domain.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartAppointment indicates an expected call of StartAppointment.
+func (mr *MockAppointmentServiceMockRecorder) StartAppointment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartAppointment", reflect.TypeOf((*MockAppointmentService)(nil).StartAppointment), ctx, id)
+}
+
+// UpdateAppointment mocks base method.
+func (m *MockAppointmentService) UpdateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppointment", ctx, appointment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppointment indicates an expected call of UpdateAppointment.
+func (mr *MockAppointmentServiceMockRecorder) UpdateAppointment(ctx, appointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppointment", reflect.TypeOf((*MockAppointmentService)(nil).UpdateAppointment), ctx, appointment)
+}
+
+// ValidateAppointment mocks base method.
+func (m *MockAppointmentService) ValidateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateAppointment", ctx, appointment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateAppointment indicates an expected call of ValidateAppointment.
+func (mr *MockAppointmentServiceMockRecorder) ValidateAppointment(ctx, appointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAppointment", reflect.TypeOf((*MockAppointmentService)(nil).ValidateAppointment), ctx, appointment)
+}
@@ -0,0 +1,310 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config содержит все настройки приложения.
+// Источники применяются по возрастанию приоритета: значения по умолчанию, YAML файл, переменные окружения, флаги
+type Config struct {
+	Clinic   ClinicConfig   `yaml:"clinic"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Features FeaturesConfig `yaml:"features"`
+}
+
+// ClinicConfig содержит сведения о клинике
+type ClinicConfig struct {
+	Name     string `yaml:"name"`
+	Timezone string `yaml:"timezone"`
+}
+
+// ServerConfig содержит адрес, таймауты и TLS сертификат HTTP сервера
+type ServerConfig struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	RequestTimeout    time.Duration `yaml:"request_timeout"`
+	TLSCertFile       string        `yaml:"tls_cert_file"`
+	TLSKeyFile        string        `yaml:"tls_key_file"`
+}
+
+// DatabaseConfig содержит подключение к PostgreSQL и размеры пула соединений
+type DatabaseConfig struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Name            string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	MigrationsPath  string        `yaml:"migrations_path"`
+}
+
+// AuthConfig содержит настройки входа
+type AuthConfig struct {
+	RequireAdmin2FA bool `yaml:"require_admin_2fa"`
+}
+
+// FeaturesConfig содержит переключатели возможностей
+type FeaturesConfig struct {
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+// Default возвращает настройки по умолчанию
+func Default() *Config {
+	return &Config{
+		Clinic: ClinicConfig{
+			Name:     "CRM Стоматология",
+			Timezone: "Asia/Almaty",
+		},
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			RequestTimeout:    30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port:            5432,
+			User:            "crmstom_user",
+			Password:        "crmstom_password",
+			Name:            "crmstom",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			MigrationsPath:  "migrations",
+		},
+		Features: FeaturesConfig{
+			AutoMigrate: true,
+		},
+	}
+}
+
+// option связывает настройку с ключом YAML/флага и переменной окружения
+type option struct {
+	key    string
+	env    string
+	usage  string
+	secret bool
+	value  interface{}
+}
+
+// options перечисляет все настройки в порядке вывода
+func (c *Config) options() []option {
+	return []option{
+		{key: "clinic.name", env: "CLINIC_NAME", usage: "название клиники, используется как издатель TOTP", value: &c.Clinic.Name},
+		{key: "clinic.timezone", env: "CLINIC_TIMEZONE", usage: "часовой пояс клиники (IANA)", value: &c.Clinic.Timezone},
+		{key: "server.addr", env: "HTTP_ADDR", usage: "адрес HTTP сервера", value: &c.Server.Addr},
+		{key: "server.read_header_timeout", env: "HTTP_READ_HEADER_TIMEOUT", usage: "таймаут чтения заголовков запроса", value: &c.Server.ReadHeaderTimeout},
+		{key: "server.read_timeout", env: "HTTP_READ_TIMEOUT", usage: "таймаут чтения запроса", value: &c.Server.ReadTimeout},
+		{key: "server.write_timeout", env: "HTTP_WRITE_TIMEOUT", usage: "таймаут записи ответа", value: &c.Server.WriteTimeout},
+		{key: "server.idle_timeout", env: "HTTP_IDLE_TIMEOUT", usage: "таймаут простоя keep-alive соединения", value: &c.Server.IdleTimeout},
+		{key: "server.shutdown_timeout", env: "HTTP_SHUTDOWN_TIMEOUT", usage: "ожидание текущих запросов при остановке", value: &c.Server.ShutdownTimeout},
+		{key: "server.request_timeout", env: "REQUEST_TIMEOUT", usage: "ограничение времени обработки запроса, 0 отключает", value: &c.Server.RequestTimeout},
+		{key: "server.tls_cert_file", env: "TLS_CERT_FILE", usage: "путь к TLS сертификату", value: &c.Server.TLSCertFile},
+		{key: "server.tls_key_file", env: "TLS_KEY_FILE", usage: "путь к TLS ключу", value: &c.Server.TLSKeyFile},
+		{key: "database.host", env: "DB_HOST", usage: "хост PostgreSQL", value: &c.Database.Host},
+		{key: "database.port", env: "DB_PORT", usage: "порт PostgreSQL", value: &c.Database.Port},
+		{key: "database.user", env: "DB_USER", usage: "пользователь PostgreSQL", value: &c.Database.User},
+		{key: "database.password", env: "DB_PASSWORD", usage: "пароль PostgreSQL", secret: true, value: &c.Database.Password},
+		{key: "database.name", env: "DB_NAME", usage: "имя базы данных", value: &c.Database.Name},
+		{key: "database.sslmode", env: "DB_SSLMODE", usage: "режим SSL подключения", value: &c.Database.SSLMode},
+		{key: "database.max_open_conns", env: "DB_MAX_OPEN_CONNS", usage: "максимум открытых соединений, 0 без ограничения", value: &c.Database.MaxOpenConns},
+		{key: "database.max_idle_conns", env: "DB_MAX_IDLE_CONNS", usage: "максимум простаивающих соединений", value: &c.Database.MaxIdleConns},
+		{key: "database.conn_max_lifetime", env: "DB_CONN_MAX_LIFETIME", usage: "время жизни соединения, 0 без ограничения", value: &c.Database.ConnMaxLifetime},
+		{key: "database.conn_max_idle_time", env: "DB_CONN_MAX_IDLE_TIME", usage: "время простоя соединения, 0 без ограничения", value: &c.Database.ConnMaxIdleTime},
+		{key: "database.migrations_path", env: "MIGRATIONS_PATH", usage: "каталог миграций", value: &c.Database.MigrationsPath},
+		{key: "auth.require_admin_2fa", env: "REQUIRE_ADMIN_2FA", usage: "обязательная двухфакторная аутентификация администраторов", value: &c.Auth.RequireAdmin2FA},
+		{key: "features.auto_migrate", env: "AUTO_MIGRATE", usage: "применять миграции при запуске", value: &c.Features.AutoMigrate},
+	}
+}
+
+// Load собирает настройки из файла, окружения и флагов командной строки и проверяет их.
+// Путь к файлу задается флагом -config или переменной CONFIG_FILE; без него файл не читается
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	config := Default()
+
+	fs := flag.NewFlagSet("crmstom", flag.ContinueOnError)
+	configPath := fs.String("config", getenv("CONFIG_FILE"), "путь к YAML файлу настроек")
+	overrides := make(map[string]string)
+	for _, opt := range config.options() {
+		key := opt.key
+		fs.Func(key, opt.usage+" ("+opt.env+")", func(value string) error {
+			overrides[key] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		if err := config.loadFile(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range config.options() {
+		if value := getenv(opt.env); value != "" {
+			if err := setValue(opt.value, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", opt.env, err)
+			}
+		}
+	}
+
+	for _, opt := range config.options() {
+		if value, ok := overrides[opt.key]; ok {
+			if err := setValue(opt.value, value); err != nil {
+				return nil, fmt.Errorf("invalid -%s: %w", opt.key, err)
+			}
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadFile читает YAML файл настроек поверх текущих значений; неизвестные ключи считаются ошибкой
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// setValue разбирает строковое значение настройки в поле соответствующего типа
+func setValue(target interface{}, value string) error {
+	switch target := target.(type) {
+	case *string:
+		*target = value
+	case *int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*target = parsed
+	case *bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*target = parsed
+	case *time.Duration:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*target = parsed
+	default:
+		return fmt.Errorf("unsupported config type %T", target)
+	}
+	return nil
+}
+
+// Validate проверяет согласованность настроек
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	check(strings.TrimSpace(c.Clinic.Name) != "", "clinic.name is required")
+	if _, err := time.LoadLocation(c.Clinic.Timezone); err != nil || c.Clinic.Timezone == "" {
+		problems = append(problems, fmt.Sprintf("clinic.timezone %q is not a valid IANA time zone", c.Clinic.Timezone))
+	}
+
+	check(c.Server.Addr != "", "server.addr is required")
+	check(c.Server.ReadHeaderTimeout >= 0 && c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 &&
+		c.Server.IdleTimeout >= 0 && c.Server.RequestTimeout >= 0,
+		"server timeouts must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.WriteTimeout == 0 || c.Server.RequestTimeout == 0 || c.Server.WriteTimeout > c.Server.RequestTimeout,
+		"server.write_timeout must be greater than server.request_timeout")
+	check((c.Server.TLSCertFile == "") == (c.Server.TLSKeyFile == ""),
+		"server.tls_cert_file and server.tls_key_file must be set together")
+
+	check(c.Database.Host != "", "database.host is required (DB_HOST)")
+	check(c.Database.Port > 0 && c.Database.Port <= 65535, "database.port must be between 1 and 65535")
+	check(c.Database.User != "", "database.user is required")
+	check(c.Database.Name != "", "database.name is required")
+	check(c.Database.MaxOpenConns >= 0 && c.Database.MaxIdleConns >= 0, "database pool sizes must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns must not exceed database.max_open_conns")
+	check(c.Database.ConnMaxLifetime >= 0 && c.Database.ConnMaxIdleTime >= 0, "database connection lifetimes must not be negative")
+	check(!c.Features.AutoMigrate || c.Database.MigrationsPath != "", "database.migrations_path is required when features.auto_migrate is enabled")
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Location возвращает часовой пояс клиники
+func (c *Config) Location() *time.Location {
+	location, err := time.LoadLocation(c.Clinic.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Dump выводит действующие настройки; значения секретов скрываются
+func (c *Config) Dump(w io.Writer) {
+	for _, opt := range c.options() {
+		value := fmt.Sprint(indirect(opt.value))
+		if opt.secret && value != "" {
+			value = "******"
+		}
+		fmt.Fprintf(w, "%s = %s\n", opt.key, value)
+	}
+}
+
+// indirect возвращает значение, на которое указывает поле настройки
+func indirect(target interface{}) interface{} {
+	switch target := target.(type) {
+	case *string:
+		return *target
+	case *int:
+		return *target
+	case *bool:
+		return *target
+	case *time.Duration:
+		return *target
+	}
+	return target
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	file := writeConfigFile(t, `
+clinic:
+  name: Белая улыбка
+server:
+  addr: ":9090"
+  request_timeout: 10s
+database:
+  host: db.local
+  port: 6432
+  max_open_conns: 50
+auth:
+  require_admin_2fa: true
+`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		check   func(*testing.T, *Config)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"DB_HOST": "localhost"},
+			check: func(t *testing.T, c *Config) {
+				assert.Equal(t, ":8080", c.Server.Addr)
+				assert.Equal(t, 30*time.Second, c.Server.RequestTimeout)
+				assert.Equal(t, "localhost", c.Database.Host)
+				assert.Equal(t, 5432, c.Database.Port)
+				assert.Equal(t, "Asia/Almaty", c.Clinic.Timezone)
+				assert.True(t, c.Features.AutoMigrate)
+				assert.False(t, c.Auth.RequireAdmin2FA)
+			},
+		},
+		{
+			name: "file overrides defaults",
+			args: []string{"-config", file},
+			check: func(t *testing.T, c *Config) {
+				assert.Equal(t, "Белая улыбка", c.Clinic.Name)
+				assert.Equal(t, ":9090", c.Server.Addr)
+				assert.Equal(t, 10*time.Second, c.Server.RequestTimeout)
+				assert.Equal(t, "db.local", c.Database.Host)
+				assert.Equal(t, 6432, c.Database.Port)
+				assert.Equal(t, 50, c.Database.MaxOpenConns)
+				assert.Equal(t, 5, c.Database.MaxIdleConns)
+				assert.True(t, c.Auth.RequireAdmin2FA)
+			},
+		},
+		{
+			name: "config file from environment",
+			env:  map[string]string{"CONFIG_FILE": file},
+			check: func(t *testing.T, c *Config) {
+				assert.Equal(t, "db.local", c.Database.Host)
+			},
+		},
+		{
+			name: "environment overrides file",
+			args: []string{"-config", file},
+			env:  map[string]string{"DB_HOST": "db.env", "REQUEST_TIMEOUT": "5s", "REQUIRE_ADMIN_2FA": "false"},
+			check: func(t *testing.T, c *Config) {
+				assert.Equal(t, "db.env", c.Database.Host)
+				assert.Equal(t, 5*time.Second, c.Server.RequestTimeout)
+				assert.False(t, c.Auth.RequireAdmin2FA)
+				assert.Equal(t, 6432, c.Database.Port)
+			},
+		},
+		{
+			name: "flags override environment",
+			args: []string{"-config", file, "-database.host", "db.flag", "-server.addr=:7070", "-features.auto_migrate=false"},
+			env:  map[string]string{"DB_HOST": "db.env", "HTTP_ADDR": ":6060"},
+			check: func(t *testing.T, c *Config) {
+				assert.Equal(t, "db.flag", c.Database.Host)
+				assert.Equal(t, ":7070", c.Server.Addr)
+				assert.False(t, c.Features.AutoMigrate)
+			},
+		},
+		{
+			name:    "database host is required",
+			wantErr: true,
+			errMsg:  "database.host is required",
+		},
+		{
+			name:    "invalid environment value",
+			env:     map[string]string{"DB_HOST": "localhost", "DB_PORT": "postgres"},
+			wantErr: true,
+			errMsg:  "invalid DB_PORT",
+		},
+		{
+			name:    "invalid flag value",
+			args:    []string{"-server.request_timeout", "soon"},
+			env:     map[string]string{"DB_HOST": "localhost"},
+			wantErr: true,
+			errMsg:  "invalid -server.request_timeout",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"-port", "80"},
+			wantErr: true,
+			errMsg:  "flag provided but not defined",
+		},
+		{
+			name:    "unknown file key",
+			args:    []string{"-config", writeConfigFile(t, "database:\n  hostname: db.local\n")},
+			wantErr: true,
+			errMsg:  "field hostname not found",
+		},
+		{
+			name:    "missing config file",
+			args:    []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")},
+			wantErr: true,
+			errMsg:  "failed to open config file",
+		},
+		{
+			name:    "invalid timezone",
+			env:     map[string]string{"DB_HOST": "localhost", "CLINIC_TIMEZONE": "Mars/Olympus"},
+			wantErr: true,
+			errMsg:  "clinic.timezone",
+		},
+		{
+			name:    "tls requires both files",
+			env:     map[string]string{"DB_HOST": "localhost", "TLS_CERT_FILE": "cert.pem"},
+			wantErr: true,
+			errMsg:  "must be set together",
+		},
+		{
+			name:    "write timeout shorter than request timeout",
+			env:     map[string]string{"DB_HOST": "localhost", "HTTP_WRITE_TIMEOUT": "10s", "REQUEST_TIMEOUT": "30s"},
+			wantErr: true,
+			errMsg:  "server.write_timeout must be greater than server.request_timeout",
+		},
+		{
+			name:    "zero shutdown timeout",
+			env:     map[string]string{"DB_HOST": "localhost", "HTTP_SHUTDOWN_TIMEOUT": "0s"},
+			wantErr: true,
+			errMsg:  "server.shutdown_timeout must be positive",
+		},
+		{
+			name:    "idle pool larger than open pool",
+			env:     map[string]string{"DB_HOST": "localhost", "DB_MAX_OPEN_CONNS": "2", "DB_MAX_IDLE_CONNS": "5"},
+			wantErr: true,
+			errMsg:  "database.max_idle_conns must not exceed database.max_open_conns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+
+			config, err := Load(tt.args, getenv)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, config)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestConfig_Dump(t *testing.T) {
+	config := Default()
+	config.Database.Host = "db.local"
+	config.Database.Password = "s3cret"
+
+	var out bytes.Buffer
+	config.Dump(&out)
+
+	assert.Contains(t, out.String(), "database.host = db.local\n")
+	assert.Contains(t, out.String(), "database.password = ******\n")
+	assert.Contains(t, out.String(), "server.request_timeout = 30s\n")
+	assert.NotContains(t, out.String(), "s3cret")
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
@@ -8,11 +9,77 @@ import (
 type AppointmentStatus string
 
 const (
-	StatusScheduled AppointmentStatus = "scheduled"
-	StatusCompleted AppointmentStatus = "completed"
-	StatusCancelled AppointmentStatus = "cancelled"
+	StatusScheduled   AppointmentStatus = "scheduled"
+	StatusConfirmed   AppointmentStatus = "confirmed"
+	StatusArrived     AppointmentStatus = "arrived"
+	StatusInProgress  AppointmentStatus = "in_progress"
+	StatusCompleted   AppointmentStatus = "completed"
+	StatusCancelled   AppointmentStatus = "cancelled"
+	StatusNoShow      AppointmentStatus = "no_show"
+	StatusRescheduled AppointmentStatus = "rescheduled"
 )
 
+// appointmentTransitions перечисляет допустимые переходы между статусами записи;
+// из завершенного, отмененного, неявки и перенесенного статуса переходов нет
+var appointmentTransitions = map[AppointmentStatus][]AppointmentStatus{
+	StatusScheduled:  {StatusConfirmed, StatusArrived, StatusCancelled, StatusNoShow, StatusRescheduled},
+	StatusConfirmed:  {StatusArrived, StatusCancelled, StatusNoShow, StatusRescheduled},
+	StatusArrived:    {StatusInProgress, StatusCompleted, StatusCancelled},
+	StatusInProgress: {StatusCompleted},
+}
+
+// Valid проверяет, что статус входит в список известных
+func (s AppointmentStatus) Valid() bool {
+	switch s {
+	case StatusScheduled, StatusConfirmed, StatusArrived, StatusInProgress,
+		StatusCompleted, StatusCancelled, StatusNoShow, StatusRescheduled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo проверяет, что из статуса s можно перейти в next
+func (s AppointmentStatus) CanTransitionTo(next AppointmentStatus) bool {
+	for _, allowed := range appointmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Active сообщает, занимает ли запись время врача: отмененная, пропущенная и перенесенная запись его не занимают
+func (s AppointmentStatus) Active() bool {
+	switch s {
+	case StatusCancelled, StatusNoShow, StatusRescheduled:
+		return false
+	}
+	return true
+}
+
+// Closed сообщает, что прием состоялся или не состоится: из этих статусов переходов нет
+func (s AppointmentStatus) Closed() bool {
+	switch s {
+	case StatusCompleted, StatusCancelled, StatusNoShow, StatusRescheduled:
+		return true
+	}
+	return false
+}
+
+// Upcoming сообщает, что прием еще не начался и пациент не пришел
+func (s AppointmentStatus) Upcoming() bool {
+	return s == "" || s == StatusScheduled || s == StatusConfirmed
+}
+
+// ErrInvalidStatusTransition возвращается при переходе между статусами записи, который не допускает жизненный цикл
+var ErrInvalidStatusTransition = &Error{Kind: KindConflict, Code: "invalid_status_transition", Message: "invalid appointment status transition"}
+
+// ErrAppointmentClosed возвращается при попытке перенести или переназначить запись, прием по которой уже закрыт
+var ErrAppointmentClosed = &Error{Kind: KindConflict, Code: "appointment_closed", Message: "time, doctor, service and duration of a closed appointment cannot be changed"}
+
+// DefaultAppointmentDuration длительность приема в минутах, если она не указана
+const DefaultAppointmentDuration = 30
+
 // Appointment представляет запись в доменной модели
 type Appointment struct {
 	ID          int               `json:"id"`
@@ -20,39 +87,123 @@ type Appointment struct {
 	PatientName string            `json:"patient_name"`
 	Date        time.Time         `json:"date"`
 	Time        string            `json:"time"`
-	Service     string            `json:"service"`
-	Doctor      string            `json:"doctor"`
+	ServiceID   int               `json:"service_id"`
+	Service     string            `json:"service"` // название услуги, только для отображения
+	DoctorID    int               `json:"doctor_id"`
+	Doctor      string            `json:"doctor"` // имя врача, только для отображения
 	Status      AppointmentStatus `json:"status"`
-	Price       float64           `json:"price"`
+	Price       Money             `json:"price"`
 	Duration    int               `json:"duration"` // в минутах
 	Notes       string            `json:"notes"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
+
+	// Время переходов жизненного цикла; заполняются только через Transition
+	ConfirmedAt        *time.Time `json:"confirmed_at,omitempty"`
+	ArrivedAt          *time.Time `json:"arrived_at,omitempty"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	NoShowAt           *time.Time `json:"no_show_at,omitempty"`
+	RescheduledAt      *time.Time `json:"rescheduled_at,omitempty"`
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+	RescheduledFromID  int        `json:"rescheduled_from_id,omitempty"` // запись, вместо которой создана эта
+}
+
+// EndTime возвращает время окончания приема с учетом длительности
+func (a *Appointment) EndTime() time.Time {
+	return a.Date.Add(time.Duration(a.Duration) * time.Minute)
+}
+
+// Transition переводит запись в статус next и запоминает время перехода
+func (a *Appointment) Transition(next AppointmentStatus, at time.Time) error {
+	if !a.Status.CanTransitionTo(next) {
+		return ErrInvalidStatusTransition.WithMessage("cannot change appointment status from %s to %s", a.Status, next)
+	}
+
+	switch next {
+	case StatusConfirmed:
+		a.ConfirmedAt = &at
+	case StatusArrived:
+		a.ArrivedAt = &at
+	case StatusInProgress:
+		a.StartedAt = &at
+	case StatusCompleted:
+		a.CompletedAt = &at
+	case StatusCancelled:
+		a.CancelledAt = &at
+	case StatusNoShow:
+		a.NoShowAt = &at
+	case StatusRescheduled:
+		a.RescheduledAt = &at
+	}
+	a.Status = next
+	return nil
+}
+
+// RestoreLifecycle переносит в запись статус и время переходов из сохраненной версии: клиент не может изменить их напрямую
+func (a *Appointment) RestoreLifecycle(stored *Appointment) {
+	a.Status = stored.Status
+	a.ConfirmedAt = stored.ConfirmedAt
+	a.ArrivedAt = stored.ArrivedAt
+	a.StartedAt = stored.StartedAt
+	a.CompletedAt = stored.CompletedAt
+	a.CancelledAt = stored.CancelledAt
+	a.NoShowAt = stored.NoShowAt
+	a.RescheduledAt = stored.RescheduledAt
+	a.CancellationReason = stored.CancellationReason
+	a.RescheduledFromID = stored.RescheduledFromID
+}
+
+// TimeSlot представляет свободное окно для записи
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SlotQuery описывает параметры поиска свободных окон у врача
+type SlotQuery struct {
+	DoctorID  int
+	ServiceID int
+	From      time.Time
+	To        time.Time
+	Duration  int // в минутах, 0 — длительность по умолчанию
+	Buffer    int // перерыв между приемами в минутах
+	Limit     int
 }
 
 // AppointmentRepository определяет интерфейс для работы с записями
 type AppointmentRepository interface {
-	GetByID(id int) (*Appointment, error)
-	GetAll() ([]*Appointment, error)
-	Create(appointment *Appointment) error
-	Update(appointment *Appointment) error
-	Delete(id int) error
-	GetByPatientID(patientID int) ([]*Appointment, error)
-	GetByDate(date time.Time) ([]*Appointment, error)
-	GetByDateRange(start, end time.Time) ([]*Appointment, error)
-	CheckTimeConflict(date time.Time, time string, excludeID int) (bool, error)
+	GetByID(ctx context.Context, id int) (*Appointment, error)
+	GetAll(ctx context.Context) ([]*Appointment, error)
+	List(ctx context.Context, filter AppointmentFilter) ([]*Appointment, int, error)
+	Create(ctx context.Context, appointment *Appointment) error
+	Update(ctx context.Context, appointment *Appointment) error
+	Delete(ctx context.Context, id int) error
+	GetByPatientID(ctx context.Context, patientID int) ([]*Appointment, error)
+	GetByDate(ctx context.Context, date time.Time) ([]*Appointment, error)
+	GetByDateRange(ctx context.Context, start, end time.Time) ([]*Appointment, error)
+	FindConflicts(ctx context.Context, appointment *Appointment) ([]*Appointment, error)
+	FindOverlapping(ctx context.Context, doctorID int, start, end time.Time) ([]*Appointment, error)
 }
 
 // AppointmentService определяет бизнес-логику для работы с записями
 type AppointmentService interface {
-	GetAppointment(id int) (*Appointment, error)
-	GetAllAppointments() ([]*Appointment, error)
-	CreateAppointment(appointment *Appointment) error
-	UpdateAppointment(appointment *Appointment) error
-	DeleteAppointment(id int) error
-	GetAppointmentsByPatient(patientID int) ([]*Appointment, error)
-	GetAppointmentsByDate(date time.Time) ([]*Appointment, error)
-	CompleteAppointment(id int) error
-	CancelAppointment(id int) error
-	ValidateAppointment(appointment *Appointment) error
+	GetAppointment(ctx context.Context, id int) (*Appointment, error)
+	GetAllAppointments(ctx context.Context) ([]*Appointment, error)
+	ListAppointments(ctx context.Context, filter AppointmentFilter) ([]*Appointment, int, error)
+	CreateAppointment(ctx context.Context, appointment *Appointment) error
+	UpdateAppointment(ctx context.Context, appointment *Appointment) error
+	DeleteAppointment(ctx context.Context, id int) error
+	GetAppointmentsByPatient(ctx context.Context, patientID int) ([]*Appointment, error)
+	GetAppointmentsByDate(ctx context.Context, date time.Time) ([]*Appointment, error)
+	ConfirmAppointment(ctx context.Context, id int) (*Appointment, error)
+	MarkAppointmentArrived(ctx context.Context, id int) (*Appointment, error)
+	StartAppointment(ctx context.Context, id int) (*Appointment, error)
+	CompleteAppointment(ctx context.Context, id int) (*Appointment, error)
+	CancelAppointment(ctx context.Context, id int, reason string) (*Appointment, error)
+	MarkAppointmentNoShow(ctx context.Context, id int) (*Appointment, error)
+	RescheduleAppointment(ctx context.Context, id int, date time.Time) (*Appointment, error)
+	ValidateAppointment(ctx context.Context, appointment *Appointment) error
+	FindFreeSlots(ctx context.Context, query *SlotQuery) ([]TimeSlot, error)
 }
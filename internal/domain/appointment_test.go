@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppointment_Transition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    AppointmentStatus
+		to      AppointmentStatus
+		wantErr bool
+	}{
+		{name: "confirm scheduled", from: StatusScheduled, to: StatusConfirmed},
+		{name: "arrive without confirmation", from: StatusScheduled, to: StatusArrived},
+		{name: "arrive confirmed", from: StatusConfirmed, to: StatusArrived},
+		{name: "start after arrival", from: StatusArrived, to: StatusInProgress},
+		{name: "complete in progress", from: StatusInProgress, to: StatusCompleted},
+		{name: "complete after arrival", from: StatusArrived, to: StatusCompleted},
+		{name: "cancel confirmed", from: StatusConfirmed, to: StatusCancelled},
+		{name: "no show", from: StatusScheduled, to: StatusNoShow},
+		{name: "reschedule confirmed", from: StatusConfirmed, to: StatusRescheduled},
+		{name: "complete scheduled", from: StatusScheduled, to: StatusCompleted, wantErr: true},
+		{name: "start without arrival", from: StatusConfirmed, to: StatusInProgress, wantErr: true},
+		{name: "cancel in progress", from: StatusInProgress, to: StatusCancelled, wantErr: true},
+		{name: "reopen cancelled", from: StatusCancelled, to: StatusScheduled, wantErr: true},
+		{name: "reopen completed", from: StatusCompleted, to: StatusInProgress, wantErr: true},
+		{name: "no show after arrival", from: StatusArrived, to: StatusNoShow, wantErr: true},
+		{name: "same status", from: StatusConfirmed, to: StatusConfirmed, wantErr: true},
+		{name: "unknown status", from: StatusScheduled, to: "done", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appointment := &Appointment{Status: tt.from}
+			err := appointment.Transition(tt.to, time.Now())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrInvalidStatusTransition))
+				assert.Equal(t, tt.from, appointment.Status)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.to, appointment.Status)
+			}
+		})
+	}
+}
+
+func TestAppointment_TransitionTimestamps(t *testing.T) {
+	at := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	appointment := &Appointment{Status: StatusScheduled}
+
+	for _, next := range []AppointmentStatus{StatusConfirmed, StatusArrived, StatusInProgress, StatusCompleted} {
+		require.NoError(t, appointment.Transition(next, at))
+		at = at.Add(10 * time.Minute)
+	}
+
+	require.NotNil(t, appointment.ConfirmedAt)
+	require.NotNil(t, appointment.ArrivedAt)
+	require.NotNil(t, appointment.StartedAt)
+	require.NotNil(t, appointment.CompletedAt)
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), *appointment.ConfirmedAt)
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC), *appointment.CompletedAt)
+	assert.Nil(t, appointment.CancelledAt)
+}
+
+func TestAppointmentStatus_Active(t *testing.T) {
+	for _, status := range []AppointmentStatus{StatusScheduled, StatusConfirmed, StatusArrived, StatusInProgress, StatusCompleted} {
+		assert.True(t, status.Active(), status)
+	}
+	for _, status := range []AppointmentStatus{StatusCancelled, StatusNoShow, StatusRescheduled} {
+		assert.False(t, status.Active(), status)
+	}
+}
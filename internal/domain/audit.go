@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// AuditEntity представляет тип сущности в журнале аудита
+type AuditEntity string
+
+const (
+	AuditEntityPatient              AuditEntity = "patient"
+	AuditEntityAppointment          AuditEntity = "appointment"
+	AuditEntityService              AuditEntity = "service"
+	AuditEntityServicePrice         AuditEntity = "service_price"
+	AuditEntityTreatment            AuditEntity = "treatment"
+	AuditEntityTreatmentPlan        AuditEntity = "treatment_plan"
+	AuditEntityAppointmentDiagnosis AuditEntity = "appointment_diagnosis"
+)
+
+// Valid проверяет, что тип сущности известен журналу
+func (e AuditEntity) Valid() bool {
+	switch e {
+	case AuditEntityPatient, AuditEntityAppointment, AuditEntityService, AuditEntityServicePrice, AuditEntityTreatment, AuditEntityTreatmentPlan,
+		AuditEntityAppointmentDiagnosis:
+		return true
+	}
+	return false
+}
+
+// AuditAction представляет действие над сущностью
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// Valid проверяет, что действие известно журналу
+func (a AuditAction) Valid() bool {
+	switch a {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete:
+		return true
+	}
+	return false
+}
+
+// AuditChange содержит значение поля до и после изменения
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry представляет запись журнала аудита; записи только добавляются
+type AuditEntry struct {
+	ID         int                    `json:"id"`
+	ActorID    *int                   `json:"actor_id,omitempty"`
+	ActorLogin string                 `json:"actor_login,omitempty"`
+	Entity     AuditEntity            `json:"entity"`
+	EntityID   int                    `json:"entity_id"`
+	Action     AuditAction            `json:"action"`
+	Changes    map[string]AuditChange `json:"changes"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditFilter задает условия выборки журнала аудита; пустые поля не ограничивают выборку
+type AuditFilter struct {
+	Entity   AuditEntity
+	EntityID int
+	ActorID  int
+	Action   AuditAction
+	From     *time.Time
+	To       *time.Time
+	Page     Page
+}
+
+// AuditRepository определяет методы для чтения журнала аудита.
+// Записи журнала создают сами репозитории в транзакции изменения.
+type AuditRepository interface {
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEntry, int, error)
+}
+
+// auditIgnoredFields не попадают в журнал: служебные метки времени и вычисляемые поля
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"time":       true,
+}
+
+// AuditChanges сравнивает JSON представления сущности до и после изменения и возвращает измененные поля.
+// Для создания before равен nil, для удаления after равен nil.
+func AuditChanges(before, after interface{}) (map[string]AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditChange)
+	for name, value := range beforeFields {
+		if next, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, next) {
+			changes[name] = AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// auditFields превращает сущность в набор полей по ее JSON тегам
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if entity == nil {
+		return fields, nil
+	}
+	if value := reflect.ValueOf(entity); value.Kind() == reflect.Ptr && value.IsNil() {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+
+	return fields, nil
+}
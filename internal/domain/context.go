@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+type contextKey string
+
+const (
+	doctorContextKey    contextKey = "doctor"
+	requestIDContextKey contextKey = "request_id"
+)
+
+// WithDoctor кладет аутентифицированного врача в контекст
+func WithDoctor(ctx context.Context, doctor *Doctor) context.Context {
+	return context.WithValue(ctx, doctorContextKey, doctor)
+}
+
+// DoctorFromContext возвращает аутентифицированного врача из контекста
+func DoctorFromContext(ctx context.Context) (*Doctor, bool) {
+	doctor, ok := ctx.Value(doctorContextKey).(*Doctor)
+	return doctor, ok && doctor != nil
+}
+
+// WithRequestID кладет идентификатор запроса в контекст
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса из контекста
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
@@ -1,33 +1,37 @@
 package domain
 
+import "context"
+
 // DashboardStats представляет статистику дашборда
 type DashboardStats struct {
-	TodayAppointments int     `json:"today_appointments"`
-	TodayRevenue      float64 `json:"today_revenue"`
-	TotalPatients     int     `json:"total_patients"`
+	TodayAppointments int      `json:"today_appointments"`
+	TodayRevenue      Money    `json:"today_revenue"`
+	TotalPatients     int      `json:"total_patients"`
+	Currency          Currency `json:"currency"`
 }
 
 // FinanceReport представляет финансовый отчет
 type FinanceReport struct {
-	TotalIncome float64      `json:"total_income"`
+	TotalIncome Money        `json:"total_income"`
 	ByDay       []DayIncome  `json:"by_day"`
 	ByWeek      []WeekIncome `json:"by_week"`
+	Currency    Currency     `json:"currency"`
 }
 
 // DayIncome представляет доход за день
 type DayIncome struct {
-	Date   string  `json:"date"`
-	Income float64 `json:"income"`
+	Date   string `json:"date"`
+	Income Money  `json:"income"`
 }
 
 // WeekIncome представляет доход за неделю
 type WeekIncome struct {
-	Week   string  `json:"week"`
-	Income float64 `json:"income"`
+	Week   string `json:"week"`
+	Income Money  `json:"income"`
 }
 
 // DashboardService определяет бизнес-логику для дашборда
 type DashboardService interface {
-	GetDashboardStats() (*DashboardStats, error)
-	GetFinanceReport() (*FinanceReport, error)
+	GetDashboardStats(ctx context.Context) (*DashboardStats, error)
+	GetFinanceReport(ctx context.Context) (*FinanceReport, error)
 }
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ToothStatus представляет состояние зуба в зубной формуле
+type ToothStatus string
+
+const (
+	ToothHealthy   ToothStatus = "healthy"
+	ToothCaries    ToothStatus = "caries"
+	ToothFilled    ToothStatus = "filled"
+	ToothRootCanal ToothStatus = "root_canal" // депульпирован, каналы запломбированы
+	ToothCrown     ToothStatus = "crown"
+	ToothVeneer    ToothStatus = "veneer"
+	ToothBridge    ToothStatus = "bridge" // опора или промежуточная часть мостовидного протеза
+	ToothImplant   ToothStatus = "implant"
+	ToothImpacted  ToothStatus = "impacted" // ретинированный зуб
+	ToothMissing   ToothStatus = "missing"
+)
+
+// Valid проверяет, что состояние зуба известно
+func (s ToothStatus) Valid() bool {
+	switch s {
+	case ToothHealthy, ToothCaries, ToothFilled, ToothRootCanal, ToothCrown,
+		ToothVeneer, ToothBridge, ToothImplant, ToothImpacted, ToothMissing:
+		return true
+	}
+	return false
+}
+
+// HasSurfaces сообщает, указываются ли для состояния поверхности зуба
+func (s ToothStatus) HasSurfaces() bool {
+	return s == ToothCaries || s == ToothFilled
+}
+
+// ToothSurface представляет поверхность зуба
+type ToothSurface string
+
+const (
+	SurfaceMesial   ToothSurface = "M"
+	SurfaceOcclusal ToothSurface = "O" // жевательная, у премоляров и моляров
+	SurfaceIncisal  ToothSurface = "I" // режущий край, у резцов и клыков
+	SurfaceDistal   ToothSurface = "D"
+	SurfaceBuccal   ToothSurface = "B" // вестибулярная (щечная, губная)
+	SurfaceLingual  ToothSurface = "L" // оральная (язычная, небная)
+)
+
+// ToothSurfaces перечисляет поверхности в порядке записи в формуле (MOD, MID, ...)
+var ToothSurfaces = []ToothSurface{SurfaceMesial, SurfaceOcclusal, SurfaceIncisal, SurfaceDistal, SurfaceBuccal, SurfaceLingual}
+
+// ValidFor проверяет, что поверхность есть у зуба: жевательная — у боковых зубов, режущий край — у фронтальных
+func (s ToothSurface) ValidFor(tooth int) bool {
+	switch s {
+	case SurfaceMesial, SurfaceDistal, SurfaceBuccal, SurfaceLingual:
+		return true
+	case SurfaceOcclusal:
+		return !IsAnteriorTooth(tooth)
+	case SurfaceIncisal:
+		return IsAnteriorTooth(tooth)
+	}
+	return false
+}
+
+// ValidToothNumber проверяет номер зуба по системе FDI: 11–48 для постоянных зубов, 51–85 для молочных
+func ValidToothNumber(tooth int) bool {
+	quadrant, position := tooth/10, tooth%10
+	switch {
+	case quadrant >= 1 && quadrant <= 4:
+		return position >= 1 && position <= 8
+	case quadrant >= 5 && quadrant <= 8:
+		return position >= 1 && position <= 5
+	}
+	return false
+}
+
+// IsPrimaryTooth сообщает, что номер FDI относится к молочному зубу
+func IsPrimaryTooth(tooth int) bool {
+	return ValidToothNumber(tooth) && tooth/10 >= 5
+}
+
+// IsAnteriorTooth сообщает, что зуб фронтальный: резец или клык
+func IsAnteriorTooth(tooth int) bool {
+	return ValidToothNumber(tooth) && tooth%10 <= 3
+}
+
+// ToothState представляет текущее состояние зуба пациента; зубы без записи считаются здоровыми
+type ToothState struct {
+	Tooth         int            `json:"tooth"` // номер FDI
+	Status        ToothStatus    `json:"status"`
+	Surfaces      []ToothSurface `json:"surfaces"`
+	Notes         string         `json:"notes"`
+	AppointmentID int            `json:"appointment_id,omitempty"` // прием, на котором состояние изменено последний раз
+	DoctorID      int            `json:"doctor_id,omitempty"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// ToothChange представляет запись истории зубной формулы: новое состояние зуба и прием, на котором оно установлено
+type ToothChange struct {
+	ID            int            `json:"id"`
+	PatientID     int            `json:"patient_id"`
+	Tooth         int            `json:"tooth"`
+	Status        ToothStatus    `json:"status"`
+	Surfaces      []ToothSurface `json:"surfaces"`
+	Notes         string         `json:"notes"`
+	AppointmentID int            `json:"appointment_id,omitempty"`
+	DoctorID      int            `json:"doctor_id,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// DentalChart представляет зубную формулу пациента
+type DentalChart struct {
+	PatientID int           `json:"patient_id"`
+	Teeth     []*ToothState `json:"teeth"` // только зубы с записанным состоянием, по возрастанию номера
+}
+
+// DentalChartRepository определяет интерфейс для работы с зубной формулой
+type DentalChartRepository interface {
+	GetTeeth(ctx context.Context, patientID int) ([]*ToothState, error)
+	GetHistory(ctx context.Context, patientID, tooth int) ([]*ToothChange, error)
+	RecordChanges(ctx context.Context, changes []*ToothChange) error
+}
+
+// DentalChartService определяет бизнес-логику для работы с зубной формулой
+type DentalChartService interface {
+	GetChart(ctx context.Context, patientID int) (*DentalChart, error)
+	GetToothHistory(ctx context.Context, patientID, tooth int) ([]*ToothChange, error)
+	RecordChanges(ctx context.Context, patientID, appointmentID int, changes []*ToothChange) (*DentalChart, error)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidToothNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		tooth int
+		want  bool
+	}{
+		{name: "upper right central incisor", tooth: 11, want: true},
+		{name: "lower right third molar", tooth: 48, want: true},
+		{name: "primary upper right central incisor", tooth: 51, want: true},
+		{name: "primary lower right second molar", tooth: 85, want: true},
+		{name: "zero", tooth: 0, want: false},
+		{name: "position zero", tooth: 20, want: false},
+		{name: "permanent position nine", tooth: 19, want: false},
+		{name: "primary position six", tooth: 56, want: false},
+		{name: "quadrant nine", tooth: 91, want: false},
+		{name: "three digits", tooth: 111, want: false},
+		{name: "negative", tooth: -11, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidToothNumber(tt.tooth))
+		})
+	}
+}
+
+func TestToothSurface_ValidFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		surface ToothSurface
+		tooth   int
+		want    bool
+	}{
+		{name: "occlusal molar", surface: SurfaceOcclusal, tooth: 36, want: true},
+		{name: "occlusal premolar", surface: SurfaceOcclusal, tooth: 24, want: true},
+		{name: "occlusal incisor", surface: SurfaceOcclusal, tooth: 11, want: false},
+		{name: "incisal canine", surface: SurfaceIncisal, tooth: 43, want: true},
+		{name: "incisal molar", surface: SurfaceIncisal, tooth: 46, want: false},
+		{name: "occlusal primary molar", surface: SurfaceOcclusal, tooth: 74, want: true},
+		{name: "mesial incisor", surface: SurfaceMesial, tooth: 21, want: true},
+		{name: "unknown surface", surface: "X", tooth: 36, want: false},
+		{name: "invalid tooth", surface: SurfaceIncisal, tooth: 19, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.surface.ValidFor(tt.tooth))
+		})
+	}
+}
+
+func TestIsPrimaryTooth(t *testing.T) {
+	assert.True(t, IsPrimaryTooth(55))
+	assert.True(t, IsPrimaryTooth(81))
+	assert.False(t, IsPrimaryTooth(16))
+	assert.False(t, IsPrimaryTooth(58))
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Ошибки справочника диагнозов и диагнозов приема
+var (
+	ErrDiagnosisNotFound            = &Error{Kind: KindNotFound, Code: "diagnosis_not_found", Message: "diagnosis not found"}
+	ErrAppointmentDiagnosisNotFound = &Error{Kind: KindNotFound, Code: "appointment_diagnosis_not_found", Message: "appointment diagnosis not found"}
+	ErrAppointmentDiagnosisExists   = &Error{Kind: KindConflict, Code: "appointment_diagnosis_exists", Message: "diagnosis is already recorded for this appointment"}
+)
+
+// Diagnosis представляет диагноз из справочника МКБ-10
+type Diagnosis struct {
+	Code        string `json:"code"` // например K02.1
+	Name        string `json:"name"`
+	Category    string `json:"category"`     // трехзначная рубрика, например K02
+	HasSubcodes bool   `json:"has_subcodes"` // рубрика уточняется подрубриками, для кодирования нужен более точный код
+}
+
+// DiagnosisRepository определяет интерфейс справочника диагнозов МКБ-10
+type DiagnosisRepository interface {
+	GetByCode(ctx context.Context, code string) (*Diagnosis, error)
+	Search(ctx context.Context, query string, limit int) ([]*Diagnosis, error) // по началу кода или части названия
+}
+
+// AppointmentDiagnosis представляет диагноз, поставленный на приеме, — всему рту или одному зубу
+type AppointmentDiagnosis struct {
+	ID            int       `json:"id"`
+	AppointmentID int       `json:"appointment_id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`            // название по справочнику, только для отображения
+	Tooth         int       `json:"tooth,omitempty"` // номер FDI, 0 — диагноз не относится к одному зубу
+	Comment       string    `json:"comment"`
+	DoctorID      int       `json:"doctor_id,omitempty"` // врач, поставивший диагноз
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DiagnosisReportFilter задает выборку отчета по диагнозам
+type DiagnosisReportFilter struct {
+	From       *time.Time
+	To         *time.Time
+	DoctorID   int
+	ByCategory bool // группировать по трехзначным рубрикам вместо полных кодов
+}
+
+// DiagnosisReportRow строка отчета: число завершенных приемов и пациентов с диагнозом. Учитываются диагнозы приема
+// и коды диагнозов процедур
+type DiagnosisReportRow struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Visits   int    `json:"visits"`
+	Patients int    `json:"patients"`
+}
+
+// AppointmentDiagnosisRepository определяет интерфейс для работы с диагнозами приемов
+type AppointmentDiagnosisRepository interface {
+	GetByID(ctx context.Context, id int) (*AppointmentDiagnosis, error)
+	GetByAppointmentID(ctx context.Context, appointmentID int) ([]*AppointmentDiagnosis, error)
+	Create(ctx context.Context, diagnosis *AppointmentDiagnosis) error
+	Delete(ctx context.Context, id int) error
+	GetReport(ctx context.Context, filter DiagnosisReportFilter) ([]*DiagnosisReportRow, error)
+}
+
+// DiagnosisService определяет бизнес-логику для работы со справочником диагнозов и диагнозами приемов
+type DiagnosisService interface {
+	SearchDiagnoses(ctx context.Context, query string, limit int) ([]*Diagnosis, error)
+	GetDiagnosis(ctx context.Context, code string) (*Diagnosis, error)
+	ListAppointmentDiagnoses(ctx context.Context, appointmentID int) ([]*AppointmentDiagnosis, error)
+	AddAppointmentDiagnosis(ctx context.Context, diagnosis *AppointmentDiagnosis) error
+	DeleteAppointmentDiagnosis(ctx context.Context, appointmentID, id int) error
+	GetDiagnosisReport(ctx context.Context, filter DiagnosisReportFilter) ([]*DiagnosisReportRow, error)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Doctor представляет врача в системе
 type Doctor struct {
@@ -9,17 +12,50 @@ type Doctor struct {
 	Email     string    `json:"email"`
 	Login     string    `json:"login"`
 	Password  string    `json:"password,omitempty"` // omitempty для безопасности при отправке на фронт
-	IsAdmin   bool      `json:"isAdmin"`
+	Role      Role      `json:"role"`
+	IsAdmin   bool      `json:"isAdmin"` // вычисляется из роли, сохранен для совместимости клиентов
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+
+	TOTPSecret  string `json:"-"` // секрет TOTP; при TOTPEnabled=false — незавершенное подключение
+	TOTPEnabled bool   `json:"totp_enabled"`
+}
+
+// IsLocked сообщает, заблокирован ли вход врача на момент now
+func (d *Doctor) IsLocked(now time.Time) bool {
+	return d.LockedUntil != nil && now.Before(*d.LockedUntil)
+}
+
+// EffectiveRole возвращает роль врача; без явной роли учитывается признак IsAdmin
+func (d *Doctor) EffectiveRole() Role {
+	if d.Role != "" {
+		return d.Role
+	}
+	if d.IsAdmin {
+		return RoleAdmin
+	}
+	return RoleDoctor
+}
+
+// Can проверяет, есть ли у врача право
+func (d *Doctor) Can(permission Permission) bool {
+	return d.EffectiveRole().Can(permission)
 }
 
 // DoctorRepository определяет методы для работы с врачами
 type DoctorRepository interface {
-	Create(doctor *Doctor) error
-	GetByID(id int) (*Doctor, error)
-	GetAll() ([]*Doctor, error)
-	Update(doctor *Doctor) error
-	Delete(id int) error
-	GetByLogin(login string) (*Doctor, error)
+	Create(ctx context.Context, doctor *Doctor) error
+	GetByID(ctx context.Context, id int) (*Doctor, error)
+	GetAll(ctx context.Context) ([]*Doctor, error)
+	List(ctx context.Context, filter DoctorFilter) ([]*Doctor, int, error)
+	Update(ctx context.Context, doctor *Doctor) error
+	Delete(ctx context.Context, id int) error
+	GetByLogin(ctx context.Context, login string) (*Doctor, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	IncrementFailedLogins(ctx context.Context, id int) (int, error)
+	LockUntil(ctx context.Context, id int, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
 }
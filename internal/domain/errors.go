@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorKind определяет категорию доменной ошибки; по ней API выбирает HTTP статус
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindTooManyRequests
+)
+
+// Error доменная ошибка со стабильным кодом, по которому клиенты API различают ошибки и переводят сообщения
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is считает равными ошибки с одним кодом, чтобы errors.Is находил ошибку с уточненным сообщением
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage возвращает ошибку с тем же кодом и уточненным сообщением
+func (e *Error) WithMessage(format string, args ...interface{}) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Коды причин ошибок проверки полей
+const (
+	FieldRequired = "required"
+	FieldTooLong  = "too_long"
+	FieldTooShort = "too_short"
+	FieldInvalid  = "invalid"
+	FieldNegative = "negative"
+	FieldRange    = "out_of_range"
+)
+
+// FieldError описывает некорректное поле запроса
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError возвращается, когда входные данные не прошли проверку; Fields перечисляет некорректные поля
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError создает ошибку проверки одного поля
+func NewValidationError(field, code, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Ошибки отсутствующих сущностей
+var (
+	ErrPatientNotFound           = &Error{Kind: KindNotFound, Code: "patient_not_found", Message: "patient not found"}
+	ErrServiceNotFound           = &Error{Kind: KindNotFound, Code: "service_not_found", Message: "service not found"}
+	ErrDoctorNotFound            = &Error{Kind: KindNotFound, Code: "doctor_not_found", Message: "doctor not found"}
+	ErrAppointmentNotFound       = &Error{Kind: KindNotFound, Code: "appointment_not_found", Message: "appointment not found"}
+	ErrScheduleExceptionNotFound = &Error{Kind: KindNotFound, Code: "schedule_exception_not_found", Message: "schedule exception not found"}
+	ErrHolidayNotFound           = &Error{Kind: KindNotFound, Code: "holiday_not_found", Message: "holiday not found"}
+	ErrTreatmentNotFound         = &Error{Kind: KindNotFound, Code: "treatment_not_found", Message: "treatment not found"}
+)
+
+// Ошибки уникальности пациентов
+var (
+	ErrPatientIINExists   = &Error{Kind: KindConflict, Code: "patient_iin_exists", Message: "пациент с таким ИИН уже существует"}
+	ErrPatientPhoneExists = &Error{Kind: KindConflict, Code: "patient_phone_exists", Message: "пациент с таким номером телефона уже существует"}
+)
+
+// ErrDoctorLoginExists возвращается при создании или переименовании врача с уже занятым логином
+var ErrDoctorLoginExists = &Error{Kind: KindConflict, Code: "doctor_login_exists", Message: "врач с таким логином уже существует"}
+
+// ErrDoctorUnavailable возвращается, когда время записи не попадает в рабочие часы врача
+var ErrDoctorUnavailable = &Error{Kind: KindConflict, Code: "doctor_unavailable", Message: "doctor is not available at this time"}
+
+// ErrForbidden возвращается, когда у пользователя нет права на действие
+var ErrForbidden = &Error{Kind: KindForbidden, Code: "forbidden", Message: "access denied"}
+
+// ErrInvalidCredentials возвращается при неверном логине или пароле
+var ErrInvalidCredentials = &Error{Kind: KindUnauthorized, Code: "invalid_credentials", Message: "invalid login or password"}
+
+// Ошибки защиты входа от перебора паролей
+var (
+	ErrAccountLocked        = &Error{Kind: KindTooManyRequests, Code: "account_locked", Message: "account is temporarily locked, try again later"}
+	ErrTooManyLoginAttempts = &Error{Kind: KindTooManyRequests, Code: "too_many_login_attempts", Message: "too many login attempts, try again later"}
+)
+
+// Ошибки двухфакторной аутентификации
+var (
+	ErrInvalidMFACode       = &Error{Kind: KindUnauthorized, Code: "invalid_mfa_code", Message: "invalid two-factor code"}
+	ErrInvalidMFAChallenge  = &Error{Kind: KindUnauthorized, Code: "invalid_mfa_challenge", Message: "two-factor challenge is invalid or expired"}
+	ErrTOTPAlreadyEnabled   = &Error{Kind: KindConflict, Code: "totp_already_enabled", Message: "two-factor authentication is already enabled"}
+	ErrTOTPNotEnrolled      = &Error{Kind: KindConflict, Code: "totp_not_enrolled", Message: "two-factor enrollment has not been started"}
+	ErrTOTPRequiredForAdmin = &Error{Kind: KindForbidden, Code: "totp_required_for_admin", Message: "two-factor authentication is required for administrators"}
+)
+
+// ErrConcurrentUpdate возвращается, когда транзакцию не удалось выполнить из-за параллельных изменений тех же данных
+var ErrConcurrentUpdate = &Error{Kind: KindConflict, Code: "concurrent_update", Message: "data was changed concurrently, try again"}
+
+// ErrInvalidMoney возвращается, если сумма не является десятичным числом с точностью до тиына
+var ErrInvalidMoney = &Error{Kind: KindValidation, Code: "invalid_money", Message: "invalid money amount, expected a decimal number with at most 2 fractional digits"}
+
+// ErrInvalidSession возвращается для отсутствующей, истекшей или отозванной сессии
+var ErrInvalidSession = &Error{Kind: KindUnauthorized, Code: "invalid_session", Message: "invalid or expired session"}
+
+// AppointmentConflictError возвращается, когда запись пересекается по времени
+// с другими записями того же врача
+type AppointmentConflictError struct {
+	Conflicts []*Appointment
+}
+
+func (e *AppointmentConflictError) Error() string {
+	return "time slot is already occupied"
+}
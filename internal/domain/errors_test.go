@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{name: "same error", err: ErrPatientNotFound, target: ErrPatientNotFound, want: true},
+		{name: "with message", err: ErrPatientNotFound.WithMessage("пациент с ID %d не найден", 7), target: ErrPatientNotFound, want: true},
+		{name: "wrapped", err: fmt.Errorf("get patient: %w", ErrPatientNotFound.WithMessage("not found")), target: ErrPatientNotFound, want: true},
+		{name: "other code", err: ErrServiceNotFound, target: ErrPatientNotFound, want: false},
+		{name: "plain error", err: errors.New("patient not found"), target: ErrPatientNotFound, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestError_WithMessage(t *testing.T) {
+	err := ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", 42)
+
+	assert.Equal(t, "запись с ID 42 не найдена", err.Error())
+	assert.Equal(t, ErrAppointmentNotFound.Code, err.Code)
+	assert.Equal(t, ErrAppointmentNotFound.Kind, err.Kind)
+	assert.Equal(t, "appointment not found", ErrAppointmentNotFound.Message)
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Fields: []FieldError{
+		{Field: "name", Code: FieldRequired, Message: "name is required"},
+		{Field: "phone", Code: FieldInvalid, Message: "invalid phone"},
+	}}
+
+	var target *ValidationError
+	assert.True(t, errors.As(fmt.Errorf("create patient: %w", err), &target))
+	assert.Equal(t, "name is required; invalid phone", err.Error())
+	assert.Equal(t, []FieldError{{Field: "age", Code: FieldRange, Message: "age out of range"}},
+		NewValidationError("age", FieldRange, "age out of range").Fields)
+}
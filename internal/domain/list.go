@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Ограничения размера страницы списков
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// Page задает страницу списка: не больше Limit элементов, пропустив первые Offset
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Sort задает поле сортировки списка; пустое поле означает сортировку по умолчанию
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Поля, по которым можно сортировать списки; остальные поля отклоняются
+var (
+	PatientSortFields     = []string{"name", "birth_date", "last_visit", "created_at"}
+	AppointmentSortFields = []string{"date", "status", "price", "patient_name", "created_at"}
+	ServiceSortFields     = []string{"name", "type", "price", "duration", "created_at"}
+	DoctorSortFields      = []string{"name", "login", "role", "created_at"}
+)
+
+// PatientFilter задает условия выборки пациентов; пустые поля не ограничивают выборку
+type PatientFilter struct {
+	Query string // подстрока имени, телефона, email или ИИН
+	Sort  Sort
+	Page  Page
+}
+
+// AppointmentFilter задает условия выборки записей; пустые поля не ограничивают выборку
+type AppointmentFilter struct {
+	PatientID int
+	DoctorID  int
+	ServiceID int
+	Status    AppointmentStatus
+	From      *time.Time // начало приема не раньше From
+	To        *time.Time // начало приема раньше To
+	// RescheduledFromID выбирает запись, созданную при переносе записи с этим ID
+	RescheduledFromID int
+	Sort              Sort
+	Page              Page
+}
+
+// ServiceFilter задает условия выборки услуг; пустые поля не ограничивают выборку
+type ServiceFilter struct {
+	Query string // подстрока названия или примечаний
+	Type  string
+	Sort  Sort
+	Page  Page
+}
+
+// DoctorFilter задает условия выборки врачей; пустые поля не ограничивают выборку
+type DoctorFilter struct {
+	Query string // подстрока имени, логина или email
+	Role  Role
+	Sort  Sort
+	Page  Page
+}
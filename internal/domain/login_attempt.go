@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttemptReason представляет причину неудачной попытки входа
+type LoginAttemptReason string
+
+const (
+	LoginReasonUnknownLogin    LoginAttemptReason = "unknown_login"
+	LoginReasonInvalidPassword LoginAttemptReason = "invalid_password"
+	LoginReasonAccountLocked   LoginAttemptReason = "account_locked"
+	LoginReasonIPBlocked       LoginAttemptReason = "ip_blocked"
+	LoginReasonInvalidMFACode  LoginAttemptReason = "invalid_mfa_code"
+)
+
+// LoginAttempt представляет запись журнала попыток входа
+type LoginAttempt struct {
+	ID        int                `json:"id"`
+	Login     string             `json:"login"`
+	DoctorID  *int               `json:"doctor_id,omitempty"`
+	IP        string             `json:"ip"`
+	Success   bool               `json:"success"`
+	Reason    LoginAttemptReason `json:"reason,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// LoginAttemptRepository определяет методы для работы с журналом попыток входа
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *LoginAttempt) error
+	CountFailuresByIP(ctx context.Context, ip string, since time.Time) (int, error)
+	GetRecent(ctx context.Context, login string, limit int) ([]*LoginAttempt, error)
+}
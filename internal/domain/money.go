@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency представляет код валюты ISO 4217
+type Currency string
+
+// ClinicCurrency валюта, в которой клиника ведет все цены и отчеты
+const ClinicCurrency Currency = "KZT"
+
+// minorUnits количество минимальных единиц (тиынов) в одной единице валюты
+const minorUnits = 100
+
+// Money представляет денежную сумму в валюте клиники, хранимую в тиынах.
+// Сложение и сравнение сумм точные, в отличие от float64; в JSON и базе данных сумма записывается десятичным числом
+type Money int64
+
+// Tenge возвращает сумму из целого числа тенге
+func Tenge(amount int64) Money {
+	return Money(amount * minorUnits)
+}
+
+// ParseMoney разбирает десятичную запись суммы: "1500", "1500.5", "-20.05"
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	units, fraction, hasFraction := strings.Cut(value, ".")
+	if units == "" || !isDigits(units) || (hasFraction && (fraction == "" || !isDigits(fraction))) {
+		return 0, ErrInvalidMoney
+	}
+
+	// Нули после второго знака не меняют сумму: "100.500" из JSON равно 100.50
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > 2 {
+		return 0, ErrInvalidMoney
+	}
+
+	major, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || major > math.MaxInt64/minorUnits-1 {
+		return 0, ErrInvalidMoney
+	}
+
+	var minor int64
+	if fraction != "" {
+		minor, _ = strconv.ParseInt(fraction+strings.Repeat("0", 2-len(fraction)), 10, 64)
+	}
+
+	amount := major*minorUnits + minor
+	if negative {
+		amount = -amount
+	}
+	return Money(amount), nil
+}
+
+// isDigits проверяет, что строка состоит только из десятичных цифр
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor возвращает сумму в тиынах
+func (m Money) Minor() int64 {
+	return int64(m)
+}
+
+// IsNegative сообщает, что сумма меньше нуля
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+// String возвращает десятичную запись суммы с двумя знаками после точки
+func (m Money) String() string {
+	sign := ""
+	amount := int64(m)
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnits, amount%minorUnits)
+}
+
+// MarshalJSON записывает сумму JSON числом без потери точности: 1500.50
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON читает сумму из JSON числа или строки
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	amount, err := ParseMoney(strings.Trim(value, `"`))
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// Scan читает сумму из колонки DECIMAL; NULL читается как ноль
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		return m.Scan(string(value))
+	case string:
+		amount, err := ParseMoney(value)
+		if err != nil {
+			return err
+		}
+		*m = amount
+	case int64:
+		*m = Tenge(value)
+	case float64:
+		*m = Money(math.Round(value * minorUnits))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// Value передает сумму в базу десятичной строкой, чтобы DECIMAL получил точное значение
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
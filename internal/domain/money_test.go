@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{name: "integer", value: "1500", want: 150000},
+		{name: "one fractional digit", value: "1500.5", want: 150050},
+		{name: "two fractional digits", value: "1500.05", want: 150005},
+		{name: "trailing zeros", value: "100.500", want: 10050},
+		{name: "negative", value: "-20.05", want: -2005},
+		{name: "zero", value: "0.00", want: 0},
+		{name: "surrounding spaces", value: " 42.10 ", want: 4210},
+		{name: "empty", value: "", wantErr: true},
+		{name: "sub-tiyn precision", value: "0.001", wantErr: true},
+		{name: "exponent", value: "1e3", wantErr: true},
+		{name: "missing units", value: ".5", wantErr: true},
+		{name: "missing fraction", value: "5.", wantErr: true},
+		{name: "letters", value: "сто", wantErr: true},
+		{name: "overflow", value: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidMoney)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: Tenge(1500), want: "1500.00"},
+		{money: 150005, want: "1500.05"},
+		{money: 5, want: "0.05"},
+		{money: -2005, want: "-20.05"},
+		{money: -5, want: "-0.05"},
+		{money: 0, want: "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.String())
+		})
+	}
+}
+
+func TestMoney_ExactArithmetic(t *testing.T) {
+	var total Money
+	var floatTotal float64
+	for i := 0; i < 10000; i++ {
+		price, err := ParseMoney("1500.10")
+		require.NoError(t, err)
+		total += price
+		floatTotal += 1500.10
+	}
+
+	assert.Equal(t, "15001000.00", total.String())
+	assert.NotEqual(t, 15001000.00, floatTotal)
+
+	var cents Money
+	for i := 0; i < 10; i++ {
+		cents += 10
+	}
+	assert.Equal(t, Tenge(1), cents)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Money `json:"price"`
+	}{Price: 150050})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":1500.50}`, string(data))
+
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "number", data: `{"price":1500.5}`, want: 150050},
+		{name: "string", data: `{"price":"1500.50"}`, want: 150050},
+		{name: "null", data: `{"price":null}`, want: 0},
+		{name: "too precise", data: `{"price":0.30000000000000004}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request struct {
+				Price Money `json:"price"`
+			}
+			err := json.Unmarshal([]byte(tt.data), &request)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, request.Price)
+		})
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "decimal bytes", src: []byte("1500.50"), want: 150050},
+		{name: "decimal string", src: "0.10", want: 10},
+		{name: "integer", src: int64(1500), want: Tenge(1500)},
+		{name: "float", src: 0.1 + 0.2, want: 30},
+		{name: "null", src: nil, want: 0},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money := Money(99)
+			err := money.Scan(tt.src)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, money)
+		})
+	}
+
+	value, err := Money(150050).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "1500.50", value)
+}
@@ -1,43 +1,46 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
 // Patient представляет пациента в доменной модели
 type Patient struct {
-	ID        int       `json:"id"`
-	IIN       string    `json:"iin"`
-	Name      string    `json:"name"`
-	Phone     string    `json:"phone"`
-	Email     string    `json:"email"`
-	BirthDate time.Time `json:"birth_date"`
-	Address   string    `json:"address"`
-	Notes     string    `json:"notes"`
-	LastVisit time.Time `json:"last_visit"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int        `json:"id"`
+	IIN       string     `json:"iin"`
+	Name      string     `json:"name"`
+	Phone     string     `json:"phone"`
+	Email     string     `json:"email"`
+	BirthDate time.Time  `json:"birth_date"`
+	Address   string     `json:"address"`
+	Notes     string     `json:"notes"`
+	LastVisit *time.Time `json:"last_visit"` // дата последнего завершенного приема, вычисляется по записям
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 // PatientRepository определяет интерфейс для работы с пациентами
 type PatientRepository interface {
-	GetByID(id int) (*Patient, error)
-	GetAll() ([]*Patient, error)
-	Create(patient *Patient) error
-	Update(patient *Patient) error
-	Delete(id int) error
-	Search(query string) ([]*Patient, error)
-	GetByPhone(phone string) (*Patient, error)
-	GetByIIN(iin string) (*Patient, error)
+	GetByID(ctx context.Context, id int) (*Patient, error)
+	GetAll(ctx context.Context) ([]*Patient, error)
+	List(ctx context.Context, filter PatientFilter) ([]*Patient, int, error)
+	Create(ctx context.Context, patient *Patient) error
+	Update(ctx context.Context, patient *Patient) error
+	Delete(ctx context.Context, id int) error
+	Search(ctx context.Context, query string) ([]*Patient, error)
+	GetByPhone(ctx context.Context, phone string) (*Patient, error)
+	GetByIIN(ctx context.Context, iin string) (*Patient, error)
 }
 
 // PatientService определяет бизнес-логику для работы с пациентами
 type PatientService interface {
-	GetPatient(id int) (*Patient, error)
-	GetAllPatients() ([]*Patient, error)
-	CreatePatient(patient *Patient) error
-	UpdatePatient(patient *Patient) error
-	DeletePatient(id int) error
-	SearchPatients(query string) ([]*Patient, error)
+	GetPatient(ctx context.Context, id int) (*Patient, error)
+	GetAllPatients(ctx context.Context) ([]*Patient, error)
+	ListPatients(ctx context.Context, filter PatientFilter) ([]*Patient, int, error)
+	CreatePatient(ctx context.Context, patient *Patient) error
+	UpdatePatient(ctx context.Context, patient *Patient) error
+	DeletePatient(ctx context.Context, id int) error
+	SearchPatients(ctx context.Context, query string) ([]*Patient, error)
 	ValidatePatient(patient *Patient) error
 }
@@ -0,0 +1,109 @@
+package domain
+
+// Role представляет роль сотрудника клиники
+type Role string
+
+const (
+	RoleAdmin        Role = "admin"
+	RoleDoctor       Role = "doctor"
+	RoleReceptionist Role = "receptionist"
+	RoleAccountant   Role = "accountant"
+)
+
+// Permission представляет право на действие в системе
+type Permission string
+
+const (
+	PermPatientsRead      Permission = "patients.read"
+	PermPatientsWrite     Permission = "patients.write"
+	PermAppointmentsRead  Permission = "appointments.read"
+	PermAppointmentsWrite Permission = "appointments.write"
+	PermServicesRead      Permission = "services.read"
+	PermServicesWrite     Permission = "services.write"
+	PermDoctorsRead       Permission = "doctors.read"
+	PermDoctorsManage     Permission = "doctors.manage"
+	PermScheduleRead      Permission = "schedule.read"     // собственный график
+	PermScheduleReadAll   Permission = "schedule.read_all" // графики всех врачей
+	PermScheduleManage    Permission = "schedule.manage"
+	PermDashboardView     Permission = "dashboard.view"
+	PermFinanceView       Permission = "finance.view" // отчеты и выручка
+	PermAuditView         Permission = "audit.view"   // журнал изменений
+	PermChartRead         Permission = "chart.read"   // зубная формула и ее история
+	PermChartWrite        Permission = "chart.write"
+	PermTreatmentsRead    Permission = "treatments.read" // процедуры приемов
+	PermTreatmentsWrite   Permission = "treatments.write"
+	PermPlansRead         Permission = "plans.read" // планы лечения и их выполнение
+	PermPlansWrite        Permission = "plans.write"
+)
+
+// rolePermissions задает права каждой роли
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermPatientsRead, PermPatientsWrite,
+		PermAppointmentsRead, PermAppointmentsWrite,
+		PermServicesRead, PermServicesWrite,
+		PermDoctorsRead, PermDoctorsManage,
+		PermScheduleRead, PermScheduleReadAll, PermScheduleManage,
+		PermDashboardView, PermFinanceView,
+		PermAuditView,
+		PermChartRead, PermChartWrite,
+		PermTreatmentsRead, PermTreatmentsWrite,
+		PermPlansRead, PermPlansWrite,
+	},
+	RoleDoctor: {
+		PermPatientsRead, PermPatientsWrite,
+		PermAppointmentsRead, PermAppointmentsWrite,
+		PermChartRead, PermChartWrite,
+		PermTreatmentsRead, PermTreatmentsWrite,
+		PermPlansRead, PermPlansWrite,
+		PermServicesRead,
+		PermDoctorsRead,
+		PermScheduleRead,
+		PermDashboardView,
+	},
+	RoleReceptionist: {
+		PermPatientsRead, PermPatientsWrite,
+		PermAppointmentsRead, PermAppointmentsWrite,
+		PermTreatmentsRead,
+		PermPlansRead,
+		PermServicesRead,
+		PermDoctorsRead,
+		PermScheduleRead, PermScheduleReadAll,
+		PermDashboardView,
+	},
+	RoleAccountant: {
+		PermPatientsRead,
+		PermAppointmentsRead,
+		PermTreatmentsRead,
+		PermPlansRead,
+		PermServicesRead,
+		PermDoctorsRead,
+		PermDashboardView, PermFinanceView,
+	},
+}
+
+// Roles возвращает все роли системы
+func Roles() []Role {
+	return []Role{RoleAdmin, RoleDoctor, RoleReceptionist, RoleAccountant}
+}
+
+// Valid проверяет, что роль известна системе
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can проверяет, есть ли у роли право
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions возвращает права роли
+func (r Role) Permissions() []Permission {
+	return append([]Permission(nil), rolePermissions[r]...)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       Role
+		permission Permission
+		want       bool
+	}{
+		{name: "admin views audit log", role: RoleAdmin, permission: PermAuditView, want: true},
+		{name: "doctor cannot view audit log", role: RoleDoctor, permission: PermAuditView},
+		{name: "receptionist cannot view audit log", role: RoleReceptionist, permission: PermAuditView},
+		{name: "accountant cannot view audit log", role: RoleAccountant, permission: PermAuditView},
+		{name: "accountant views finance", role: RoleAccountant, permission: PermFinanceView, want: true},
+		{name: "unknown role", role: Role("guest"), permission: PermPatientsRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.Can(tt.permission))
+		})
+	}
+}
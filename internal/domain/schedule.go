@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduleExceptionType представляет тип исключения из недельного графика врача
+type ScheduleExceptionType string
+
+const (
+	ExceptionDayOff      ScheduleExceptionType = "day_off"
+	ExceptionVacation    ScheduleExceptionType = "vacation"
+	ExceptionSickLeave   ScheduleExceptionType = "sick_leave"
+	ExceptionCustomHours ScheduleExceptionType = "custom_hours" // разовые часы работы вместо шаблона
+)
+
+// WorkingHours представляет интервал работы врача в день недели (недельный шаблон)
+type WorkingHours struct {
+	ID        int          `json:"id"`
+	DoctorID  int          `json:"doctor_id"`
+	Weekday   time.Weekday `json:"weekday"`    // 0 — воскресенье, 6 — суббота
+	StartTime string       `json:"start_time"` // HH:MM
+	EndTime   string       `json:"end_time"`   // HH:MM
+}
+
+// ScheduleBreak представляет перерыв врача в день недели (обед и т.п.)
+type ScheduleBreak struct {
+	ID        int          `json:"id"`
+	DoctorID  int          `json:"doctor_id"`
+	Weekday   time.Weekday `json:"weekday"`
+	StartTime string       `json:"start_time"`
+	EndTime   string       `json:"end_time"`
+	Title     string       `json:"title"`
+}
+
+// ScheduleException представляет разовое исключение из графика: выходной, отпуск, больничный или иные часы
+type ScheduleException struct {
+	ID        int                   `json:"id"`
+	DoctorID  int                   `json:"doctor_id"`
+	Type      ScheduleExceptionType `json:"type"`
+	DateFrom  time.Time             `json:"date_from"`
+	DateTo    time.Time             `json:"date_to"`
+	StartTime string                `json:"start_time,omitempty"` // только для custom_hours
+	EndTime   string                `json:"end_time,omitempty"`
+	Reason    string                `json:"reason"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// ClinicHoliday представляет нерабочий праздничный день клиники
+type ClinicHoliday struct {
+	ID        int       `json:"id"`
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DoctorSchedule представляет график врача на период вместе с рассчитанными рабочими интервалами
+type DoctorSchedule struct {
+	DoctorID     int                  `json:"doctor_id"`
+	WorkingHours []*WorkingHours      `json:"working_hours"`
+	Breaks       []*ScheduleBreak     `json:"breaks"`
+	Exceptions   []*ScheduleException `json:"exceptions"`
+	Holidays     []*ClinicHoliday     `json:"holidays"`
+	Availability []TimeSlot           `json:"availability"`
+}
+
+// ScheduleRepository определяет интерфейс для работы с графиками врачей
+type ScheduleRepository interface {
+	GetWorkingHours(ctx context.Context, doctorID int) ([]*WorkingHours, error)
+	ReplaceWeeklySchedule(ctx context.Context, doctorID int, hours []*WorkingHours, breaks []*ScheduleBreak) error
+	GetBreaks(ctx context.Context, doctorID int) ([]*ScheduleBreak, error)
+	GetExceptions(ctx context.Context, doctorID int, from, to time.Time) ([]*ScheduleException, error)
+	CreateException(ctx context.Context, exception *ScheduleException) error
+	DeleteException(ctx context.Context, doctorID, id int) error
+	GetHolidays(ctx context.Context, from, to time.Time) ([]*ClinicHoliday, error)
+	CreateHoliday(ctx context.Context, holiday *ClinicHoliday) error
+	DeleteHoliday(ctx context.Context, id int) error
+}
+
+// ScheduleService определяет бизнес-логику для работы с графиками врачей
+type ScheduleService interface {
+	GetDoctorSchedule(ctx context.Context, actor *Doctor, doctorID int, from, to time.Time) (*DoctorSchedule, error)
+	UpdateWeeklySchedule(ctx context.Context, doctorID int, hours []*WorkingHours, breaks []*ScheduleBreak) error
+	AddException(ctx context.Context, exception *ScheduleException) error
+	DeleteException(ctx context.Context, doctorID, id int) error
+	GetHolidays(ctx context.Context, from, to time.Time) ([]*ClinicHoliday, error)
+	AddHoliday(ctx context.Context, holiday *ClinicHoliday) error
+	DeleteHoliday(ctx context.Context, id int) error
+}
@@ -1,36 +1,58 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Service представляет услугу в доменной модели (упрощенная схема)
 type Service struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`
-	Notes     string    `json:"notes"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Notes        string    `json:"notes"`
+	Price        Money     `json:"price"`         // базовая цена; ее изменение записывается в историю цен
+	Duration     int       `json:"duration"`      // длительность по умолчанию в минутах
+	CurrentPrice Money     `json:"current_price"` // цена, действующая сейчас
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ServicePrice представляет изменение цены услуги, действующее с указанного момента
+type ServicePrice struct {
+	ID            int       `json:"id"`
+	ServiceID     int       `json:"service_id"`
+	Price         Money     `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 // ServiceRepository определяет интерфейс для работы с услугами
 type ServiceRepository interface {
-	GetByID(id int) (*Service, error)
-	GetAll() ([]*Service, error)
-	Create(service *Service) error
-	Update(service *Service) error
-	Delete(id int) error
-	GetByCategory(category string) ([]*Service, error)
-	Search(query string) ([]*Service, error)
+	GetByID(ctx context.Context, id int) (*Service, error)
+	GetAll(ctx context.Context) ([]*Service, error)
+	List(ctx context.Context, filter ServiceFilter) ([]*Service, int, error)
+	Create(ctx context.Context, service *Service) error
+	Update(ctx context.Context, service *Service) error
+	Delete(ctx context.Context, id int) error
+	GetByCategory(ctx context.Context, category string) ([]*Service, error)
+	Search(ctx context.Context, query string) ([]*Service, error)
+	GetPriceHistory(ctx context.Context, serviceID int) ([]*ServicePrice, error)
+	AddPrice(ctx context.Context, price *ServicePrice) error
+	GetPriceAt(ctx context.Context, serviceID int, at time.Time) (Money, error)
 }
 
 // ServiceService определяет бизнес-логику для работы с услугами
 type ServiceService interface {
-	GetService(id int) (*Service, error)
-	GetAllServices() ([]*Service, error)
-	CreateService(service *Service) error
-	UpdateService(service *Service) error
-	DeleteService(id int) error
-	GetServicesByCategory(category string) ([]*Service, error)
-	SearchServices(query string) ([]*Service, error)
+	GetService(ctx context.Context, id int) (*Service, error)
+	GetAllServices(ctx context.Context) ([]*Service, error)
+	ListServices(ctx context.Context, filter ServiceFilter) ([]*Service, int, error)
+	CreateService(ctx context.Context, service *Service) error
+	UpdateService(ctx context.Context, service *Service) error
+	DeleteService(ctx context.Context, id int) error
+	GetServicesByCategory(ctx context.Context, category string) ([]*Service, error)
+	SearchServices(ctx context.Context, query string) ([]*Service, error)
 	ValidateService(service *Service) error
+	GetPriceHistory(ctx context.Context, serviceID int) ([]*ServicePrice, error)
+	AddPrice(ctx context.Context, price *ServicePrice) error
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Session представляет серверную сессию врача; токены хранятся только в виде SHA-256 хешей
+type Session struct {
+	ID               int        `json:"id"`
+	DoctorID         int        `json:"doctor_id"`
+	TokenHash        string     `json:"-"`
+	RefreshTokenHash string     `json:"-"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+// SessionTokens содержит выданные клиенту токены сессии
+type SessionTokens struct {
+	AccessToken      string    `json:"access_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SessionRepository определяет методы для работы с сессиями
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error)
+	GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*Session, error)
+	Revoke(ctx context.Context, id int) error
+	RevokeAllForDoctor(ctx context.Context, doctorID int) error
+}
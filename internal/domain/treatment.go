@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Treatment представляет процедуру, выполненную на приеме. Цена приема с процедурами равна сумме их цен,
+// а состояние зуба после процедуры переносится в зубную формулу пациента
+type Treatment struct {
+	ID            int            `json:"id"`
+	AppointmentID int            `json:"appointment_id"`
+	ServiceID     int            `json:"service_id"`
+	Service       string         `json:"service"`         // название услуги, только для отображения
+	Tooth         int            `json:"tooth,omitempty"` // номер FDI, 0 — процедура не относится к одному зубу
+	Surfaces      []ToothSurface `json:"surfaces"`
+	ToothStatus   ToothStatus    `json:"tooth_status,omitempty"` // состояние зуба после процедуры
+	Materials     string         `json:"materials"`
+	Anesthesia    string         `json:"anesthesia"`
+	DiagnosisCode string         `json:"diagnosis_code"` // код МКБ-10, например K02.1
+	Comment       string         `json:"comment"`
+	Price         Money          `json:"price"` // 0 — цена услуги из прайс-листа на дату приема
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// TreatmentRepository определяет интерфейс для работы с процедурами приемов
+type TreatmentRepository interface {
+	GetByID(ctx context.Context, id int) (*Treatment, error)
+	GetByAppointmentID(ctx context.Context, appointmentID int) ([]*Treatment, error)
+	Create(ctx context.Context, treatment *Treatment) error
+	Update(ctx context.Context, treatment *Treatment) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TreatmentService определяет бизнес-логику для работы с процедурами приемов
+type TreatmentService interface {
+	ListTreatments(ctx context.Context, appointmentID int) ([]*Treatment, error)
+	AddTreatment(ctx context.Context, treatment *Treatment) error
+	UpdateTreatment(ctx context.Context, treatment *Treatment) error
+	DeleteTreatment(ctx context.Context, appointmentID, id int) error
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TreatmentPlanStatus представляет статус согласования плана лечения с пациентом
+type TreatmentPlanStatus string
+
+const (
+	PlanDraft    TreatmentPlanStatus = "draft"    // план составлен, пациент еще не выбрал вариант
+	PlanAccepted TreatmentPlanStatus = "accepted" // пациент выбрал вариант и подписал согласие
+	PlanDeclined TreatmentPlanStatus = "declined" // пациент отказался от лечения
+)
+
+// Valid проверяет, что статус плана известен системе
+func (s TreatmentPlanStatus) Valid() bool {
+	switch s {
+	case PlanDraft, PlanAccepted, PlanDeclined:
+		return true
+	}
+	return false
+}
+
+// Ошибки планов лечения
+var (
+	ErrTreatmentPlanNotFound    = &Error{Kind: KindNotFound, Code: "treatment_plan_not_found", Message: "treatment plan not found"}
+	ErrTreatmentPlanNotEditable = &Error{Kind: KindConflict, Code: "treatment_plan_not_editable", Message: "only draft treatment plans can be changed"}
+	ErrTreatmentPlanNotAccepted = &Error{Kind: KindConflict, Code: "treatment_plan_not_accepted", Message: "treatment plan has not been accepted"}
+	ErrPlanStepScheduled        = &Error{Kind: KindConflict, Code: "plan_step_scheduled", Message: "plan step already has an appointment"}
+)
+
+// TreatmentPlanStep представляет запланированную процедуру варианта плана
+type TreatmentPlanStep struct {
+	ID             int    `json:"id"`
+	VariantID      int    `json:"variant_id"`
+	Position       int    `json:"position"` // порядок процедуры в варианте, начиная с 1
+	Stage          int    `json:"stage"`    // этап лечения, начиная с 1; процедуры одного этапа можно выполнить за один прием
+	ServiceID      int    `json:"service_id"`
+	Service        string `json:"service"`         // название услуги, только для отображения
+	Tooth          int    `json:"tooth,omitempty"` // номер FDI, 0 — процедура не относится к одному зубу
+	EstimatedPrice Money  `json:"estimated_price"` // 0 — текущая цена услуги из прайс-листа
+	Notes          string `json:"notes"`
+	AppointmentID  int    `json:"appointment_id,omitempty"` // запись, созданная по процедуре
+}
+
+// TreatmentPlanVariant представляет один из альтернативных вариантов плана, например имплант или мост
+type TreatmentPlanVariant struct {
+	ID       int                  `json:"id"`
+	PlanID   int                  `json:"plan_id"`
+	Position int                  `json:"position"`
+	Title    string               `json:"title"`
+	Steps    []*TreatmentPlanStep `json:"steps"`
+	Total    Money                `json:"total"` // предварительная стоимость варианта, только для отображения
+}
+
+// TreatmentPlan представляет план лечения пациента с альтернативными вариантами; после согласия пациента
+// процедуры выбранного варианта переводятся в записи на прием
+type TreatmentPlan struct {
+	ID                int                     `json:"id"`
+	PatientID         int                     `json:"patient_id"`
+	Title             string                  `json:"title"`
+	Notes             string                  `json:"notes"`
+	Status            TreatmentPlanStatus     `json:"status"`
+	DoctorID          int                     `json:"doctor_id,omitempty"` // врач, составивший план
+	Variants          []*TreatmentPlanVariant `json:"variants"`
+	AcceptedVariantID int                     `json:"accepted_variant_id,omitempty"`
+	AcceptedAt        *time.Time              `json:"accepted_at,omitempty"`
+	SignedBy          string                  `json:"signed_by,omitempty"` // кто подписал согласие: пациент или его представитель
+	DeclinedAt        *time.Time              `json:"declined_at,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// Variant возвращает вариант плана по ID или nil
+func (p *TreatmentPlan) Variant(id int) *TreatmentPlanVariant {
+	for _, variant := range p.Variants {
+		if variant.ID == id {
+			return variant
+		}
+	}
+	return nil
+}
+
+// TreatmentPlanProgress показывает, какая часть принятого плана выполнена и оплачена. Оплаченной считается
+// фактическая цена завершенных записей по процедурам плана — так же, как выручка в финансовом отчете
+type TreatmentPlanProgress struct {
+	PlanID            int       `json:"plan_id"`
+	PatientID         int       `json:"patient_id"`
+	PatientName       string    `json:"patient_name"`
+	Title             string    `json:"title"`
+	VariantID         int       `json:"variant_id"`
+	VariantTitle      string    `json:"variant_title"`
+	AcceptedAt        time.Time `json:"accepted_at"`
+	TotalSteps        int       `json:"total_steps"`
+	ScheduledSteps    int       `json:"scheduled_steps"` // процедуры с предстоящей или идущей записью
+	CompletedSteps    int       `json:"completed_steps"`
+	EstimatedTotal    Money     `json:"estimated_total"`
+	CompletedEstimate Money     `json:"completed_estimate"` // предварительная стоимость выполненных процедур
+	PaidAmount        Money     `json:"paid_amount"`
+}
+
+// TreatmentPlanRepository определяет интерфейс для работы с планами лечения
+type TreatmentPlanRepository interface {
+	GetByID(ctx context.Context, id int) (*TreatmentPlan, error)
+	GetByPatientID(ctx context.Context, patientID int) ([]*TreatmentPlan, error)
+	Create(ctx context.Context, plan *TreatmentPlan) error
+	Update(ctx context.Context, plan *TreatmentPlan) error       // название, заметки и варианты черновика
+	UpdateStatus(ctx context.Context, plan *TreatmentPlan) error // статус и сведения о согласии
+	Delete(ctx context.Context, id int) error
+	SetStepAppointment(ctx context.Context, stepID, appointmentID int) error
+	GetProgress(ctx context.Context, patientID int) ([]*TreatmentPlanProgress, error) // patientID = 0 — по всем пациентам
+}
+
+// TreatmentPlanService определяет бизнес-логику для работы с планами лечения
+type TreatmentPlanService interface {
+	GetPlan(ctx context.Context, id int) (*TreatmentPlan, error)
+	GetPatientPlans(ctx context.Context, patientID int) ([]*TreatmentPlan, error)
+	CreatePlan(ctx context.Context, plan *TreatmentPlan) error
+	UpdatePlan(ctx context.Context, plan *TreatmentPlan) error
+	DeletePlan(ctx context.Context, id int) error
+	AcceptPlan(ctx context.Context, id, variantID int, signedBy string) (*TreatmentPlan, error)
+	DeclinePlan(ctx context.Context, id int) (*TreatmentPlan, error)
+	ScheduleStep(ctx context.Context, planID, stepID int, appointment *Appointment) error
+	GetPlanProgress(ctx context.Context, patientID int) ([]*TreatmentPlanProgress, error)
+}
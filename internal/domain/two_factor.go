@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MFAChallenge представляет незавершенный вход, ожидающий кода второго фактора.
+// При Enrollment врач еще не подключил TOTP и подключает его в ходе этого входа.
+type MFAChallenge struct {
+	ID         int        `json:"-"`
+	DoctorID   int        `json:"-"`
+	Token      string     `json:"mfa_token"` // выдается клиенту один раз, в базе хранится только хеш
+	TokenHash  string     `json:"-"`
+	Enrollment bool       `json:"enrollment"`
+	OTPAuthURI string     `json:"otpauth_uri,omitempty"`
+	Secret     string     `json:"secret,omitempty"`
+	Attempts   int        `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"-"`
+}
+
+// TOTPEnrollment содержит данные для подключения приложения-аутентификатора
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorRepository определяет методы для работы со вторым фактором врачей
+type TwoFactorRepository interface {
+	SetTOTPSecret(ctx context.Context, doctorID int, secret string) error
+	EnableTOTP(ctx context.Context, doctorID int) error
+	DisableTOTP(ctx context.Context, doctorID int) error
+	UseTOTPStep(ctx context.Context, doctorID int, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, doctorID int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, doctorID int, codeHash string) (bool, error)
+	CreateChallenge(ctx context.Context, challenge *MFAChallenge) error
+	GetChallenge(ctx context.Context, tokenHash string) (*MFAChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, id int) error
+	MarkChallengeUsed(ctx context.Context, id int) error
+}
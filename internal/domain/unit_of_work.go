@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// UnitOfWork выполняет несколько операций репозиториев атомарно в одной транзакции
+type UnitOfWork interface {
+	// WithinTx выполняет fn в сериализуемой транзакции: репозитории, вызванные с контекстом fn, работают в ней.
+	// При конфликте сериализации fn выполняется заново, поэтому до фиксации она не должна иметь внешних эффектов
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
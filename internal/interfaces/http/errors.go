@@ -0,0 +1,106 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Коды ошибок, которые выставляет сам HTTP слой, по статусу ответа
+var statusErrorCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusMethodNotAllowed:    "method_not_allowed",
+	http.StatusConflict:            "conflict",
+	http.StatusTooManyRequests:     "too_many_requests",
+	http.StatusInternalServerError: "internal_error",
+	http.StatusServiceUnavailable:  "service_unavailable",
+}
+
+// Коды ошибок, которым не соответствует отдельная доменная ошибка
+const (
+	codeValidationFailed    = "validation_failed"
+	codeAppointmentConflict = "appointment_conflict"
+	codeInvalidRequestBody  = "invalid_request_body"
+)
+
+// kindStatuses сопоставляет категориям доменных ошибок HTTP статусы
+var kindStatuses = map[domain.ErrorKind]int{
+	domain.KindValidation:      http.StatusBadRequest,
+	domain.KindNotFound:        http.StatusNotFound,
+	domain.KindConflict:        http.StatusConflict,
+	domain.KindUnauthorized:    http.StatusUnauthorized,
+	domain.KindForbidden:       http.StatusForbidden,
+	domain.KindTooManyRequests: http.StatusTooManyRequests,
+}
+
+// apiError описывает ошибку в ответе API. Code стабилен и служит ключом перевода в интерфейсе,
+// Message — пояснение для разработчика
+type apiError struct {
+	Code      string                `json:"code"`
+	Message   string                `json:"message"`
+	Fields    []domain.FieldError   `json:"fields,omitempty"`
+	Conflicts []*domain.Appointment `json:"conflicts,omitempty"`
+}
+
+// requestTimeoutBody тело ответа 503 при превышении времени обработки запроса
+const requestTimeoutBody = `{"status":"error","error":{"code":"request_timeout","message":"Request timeout"}}`
+
+// writeAPIError записывает ответ с ошибкой в общем формате {"status": "error", "error": {...}}
+func (h *Handler) writeAPIError(w http.ResponseWriter, statusCode int, apiErr apiError) {
+	h.writeJSONResponse(w, statusCode, map[string]interface{}{
+		"status": "error",
+		"error":  apiErr,
+	})
+}
+
+// writeErrorResponse записывает ошибку HTTP слоя (неверный запрос, метод, параметры) с кодом по статусу ответа
+func (h *Handler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	code, ok := statusErrorCodes[statusCode]
+	if !ok {
+		code = statusErrorCodes[http.StatusInternalServerError]
+	}
+	h.writeAPIError(w, statusCode, apiError{Code: code, Message: message})
+}
+
+// writeInvalidBody записывает ответ на тело запроса, которое не удалось разобрать
+func (h *Handler) writeInvalidBody(w http.ResponseWriter, err error) {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) && domainErr.Kind == domain.KindValidation {
+		h.writeAPIError(w, http.StatusBadRequest, apiError{Code: domainErr.Code, Message: domainErr.Message})
+		return
+	}
+	h.writeAPIError(w, http.StatusBadRequest, apiError{Code: codeInvalidRequestBody, Message: "Invalid request body"})
+}
+
+// writeError записывает ошибку use case: доменные ошибки переводятся в HTTP статус и код,
+// остальные записываются в лог и возвращаются клиенту как 500 без подробностей
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *domain.ValidationError
+	var conflictErr *domain.AppointmentConflictError
+	var domainErr *domain.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		h.writeAPIError(w, http.StatusBadRequest, apiError{
+			Code:    codeValidationFailed,
+			Message: validationErr.Error(),
+			Fields:  validationErr.Fields,
+		})
+	case errors.As(err, &conflictErr):
+		h.writeAPIError(w, http.StatusConflict, apiError{
+			Code:      codeAppointmentConflict,
+			Message:   conflictErr.Error(),
+			Conflicts: conflictErr.Conflicts,
+		})
+	case errors.As(err, &domainErr) && domainErr.Kind != domain.KindInternal:
+		h.writeAPIError(w, kindStatuses[domainErr.Kind], apiError{Code: domainErr.Code, Message: domainErr.Message})
+	default:
+		log.Printf("Ошибка обработки запроса %s %s (request_id=%s): %v", r.Method, r.URL.Path, domain.RequestIDFromContext(r.Context()), err)
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,11 +15,20 @@ import (
 
 // Handler содержит все HTTP обработчики
 type Handler struct {
-	patientUseCase     *usecase.PatientUseCase
-	appointmentUseCase *usecase.AppointmentUseCase
-	serviceUseCase     *usecase.ServiceUseCase
-	dashboardUseCase   *usecase.DashboardUseCase
-	doctorUseCase      *usecase.DoctorUseCase
+	patientUseCase       *usecase.PatientUseCase
+	appointmentUseCase   *usecase.AppointmentUseCase
+	serviceUseCase       *usecase.ServiceUseCase
+	dashboardUseCase     *usecase.DashboardUseCase
+	doctorUseCase        *usecase.DoctorUseCase
+	scheduleUseCase      *usecase.ScheduleUseCase
+	sessionUseCase       *usecase.SessionUseCase
+	twoFactorUseCase     *usecase.TwoFactorUseCase
+	auditUseCase         *usecase.AuditUseCase
+	dentalChartUseCase   *usecase.DentalChartUseCase
+	treatmentUseCase     *usecase.TreatmentUseCase
+	treatmentPlanUseCase *usecase.TreatmentPlanUseCase
+	diagnosisUseCase     *usecase.DiagnosisUseCase
+	location             *time.Location
 }
 
 // NewHandler создает новый экземпляр Handler
@@ -27,13 +38,31 @@ func NewHandler(
 	serviceUseCase *usecase.ServiceUseCase,
 	dashboardUseCase *usecase.DashboardUseCase,
 	doctorUseCase *usecase.DoctorUseCase,
+	scheduleUseCase *usecase.ScheduleUseCase,
+	sessionUseCase *usecase.SessionUseCase,
+	twoFactorUseCase *usecase.TwoFactorUseCase,
+	auditUseCase *usecase.AuditUseCase,
+	dentalChartUseCase *usecase.DentalChartUseCase,
+	treatmentUseCase *usecase.TreatmentUseCase,
+	treatmentPlanUseCase *usecase.TreatmentPlanUseCase,
+	diagnosisUseCase *usecase.DiagnosisUseCase,
+	location *time.Location,
 ) *Handler {
 	return &Handler{
-		patientUseCase:     patientUseCase,
-		appointmentUseCase: appointmentUseCase,
-		serviceUseCase:     serviceUseCase,
-		dashboardUseCase:   dashboardUseCase,
-		doctorUseCase:      doctorUseCase,
+		patientUseCase:       patientUseCase,
+		appointmentUseCase:   appointmentUseCase,
+		serviceUseCase:       serviceUseCase,
+		dashboardUseCase:     dashboardUseCase,
+		doctorUseCase:        doctorUseCase,
+		scheduleUseCase:      scheduleUseCase,
+		sessionUseCase:       sessionUseCase,
+		twoFactorUseCase:     twoFactorUseCase,
+		auditUseCase:         auditUseCase,
+		dentalChartUseCase:   dentalChartUseCase,
+		treatmentUseCase:     treatmentUseCase,
+		treatmentPlanUseCase: treatmentPlanUseCase,
+		diagnosisUseCase:     diagnosisUseCase,
+		location:             location,
 	}
 }
 
@@ -42,7 +71,7 @@ func (h *Handler) setCORSHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
 }
 
 // writeJSONResponse записывает JSON ответ
@@ -51,9 +80,22 @@ func (h *Handler) writeJSONResponse(w http.ResponseWriter, statusCode int, data
 	json.NewEncoder(w).Encode(data)
 }
 
-// writeErrorResponse записывает JSON ответ с ошибкой
-func (h *Handler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	h.writeJSONResponse(w, statusCode, map[string]string{"error": message})
+// isReferenceError проверяет, что запись ссылается на несуществующего пациента, услугу или врача
+func isReferenceError(err error) bool {
+	return errors.Is(err, domain.ErrPatientNotFound) ||
+		errors.Is(err, domain.ErrServiceNotFound) ||
+		errors.Is(err, domain.ErrDoctorNotFound)
+}
+
+// writeAppointmentError записывает ошибку создания или изменения записи; ссылка на несуществующего
+// пациента, услугу или врача — ошибка содержимого запроса, поэтому отвечаем 422, а не 404
+func (h *Handler) writeAppointmentError(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *domain.Error
+	if isReferenceError(err) && errors.As(err, &domainErr) {
+		h.writeAPIError(w, http.StatusUnprocessableEntity, apiError{Code: domainErr.Code, Message: domainErr.Message})
+		return
+	}
+	h.writeError(w, r, err)
 }
 
 // writeSuccessResponse записывает JSON ответ с успехом
@@ -87,25 +129,23 @@ func (h *Handler) PatientsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetPatients обрабатывает GET запросы для пациентов
+// handleGetPatients получает страницу пациентов; query ищет по имени, телефону, email и ИИН
 func (h *Handler) handleGetPatients(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("query")
-
-	var patients []*domain.Patient
-	var err error
-
-	if query != "" {
-		patients, err = h.patientUseCase.SearchPatients(query)
-	} else {
-		patients, err = h.patientUseCase.GetAllPatients()
+	params := r.URL.Query()
+	page, err := parsePage(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
 	}
 
+	filter := domain.PatientFilter{Query: params.Get("query"), Sort: parseSort(params), Page: page}
+	patients, total, err := h.patientUseCase.ListPatients(r.Context(), filter)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		h.writeError(w, r, err)
 		return
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, patients)
+	h.writeListResponse(w, "Patients retrieved successfully", patients, len(patients), total, page)
 }
 
 // handleCreatePatient обрабатывает POST запросы для создания пациента
@@ -121,7 +161,7 @@ func (h *Handler) handleCreatePatient(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
@@ -142,12 +182,8 @@ func (h *Handler) handleCreatePatient(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := h.patientUseCase.CreatePatient(patient); err != nil {
-		statusCode := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "уже существует") {
-			statusCode = http.StatusConflict
-		}
-		h.writeErrorResponse(w, statusCode, err.Error())
+	if err := h.patientUseCase.CreatePatient(r.Context(), patient); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -163,21 +199,29 @@ func (h *Handler) PatientHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Извлекаем ID из URL
-	path := r.URL.Path
-	idStr := strings.TrimPrefix(path, "/api/patients/")
+	// Извлекаем ID из URL: /api/patients/{id}[/chart[/history]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/patients/"), "/")
 
-	if idStr == "" {
+	if parts[0] == "" {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Patient ID is required")
 		return
 	}
 
-	id, err := strconv.Atoi(idStr)
+	id, err := strconv.Atoi(parts[0])
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid patient ID")
 		return
 	}
 
+	if len(parts) > 1 {
+		if parts[1] != "chart" {
+			h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+			return
+		}
+		h.handleDentalChart(w, r, id, parts[2:])
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPut:
 		h.handleUpdatePatient(w, r, id)
@@ -188,22 +232,94 @@ func (h *Handler) PatientHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleDentalChart обрабатывает запросы к зубной формуле пациента: /api/patients/{id}/chart[/history]
+func (h *Handler) handleDentalChart(w http.ResponseWriter, r *http.Request, patientID int, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		chart, err := h.dentalChartUseCase.GetChart(r.Context(), patientID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Dental chart retrieved successfully", chart)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		h.handleRecordToothChanges(w, r, patientID)
+	case len(rest) == 1 && rest[0] == "history" && r.Method == http.MethodGet:
+		var tooth int
+		if value := r.URL.Query().Get("tooth"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				h.writeError(w, r, domain.NewValidationError("tooth", domain.FieldInvalid, "Invalid tooth, expected FDI number"))
+				return
+			}
+			tooth = parsed
+		}
+
+		history, err := h.dentalChartUseCase.GetToothHistory(r.Context(), patientID, tooth)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Dental chart history retrieved successfully", history)
+	case len(rest) <= 1:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	default:
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// handleRecordToothChanges записывает новое состояние зубов; appointment_id связывает изменения с приемом
+func (h *Handler) handleRecordToothChanges(w http.ResponseWriter, r *http.Request, patientID int) {
+	var request struct {
+		AppointmentID int                   `json:"appointment_id"`
+		Teeth         []*domain.ToothChange `json:"teeth"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	chart, err := h.dentalChartUseCase.RecordChanges(r.Context(), patientID, request.AppointmentID, request.Teeth)
+	if err != nil {
+		// Прием указан в теле запроса, поэтому его отсутствие — ошибка содержимого, а не 404
+		var domainErr *domain.Error
+		if errors.Is(err, domain.ErrAppointmentNotFound) && errors.As(err, &domainErr) {
+			h.writeAPIError(w, http.StatusUnprocessableEntity, apiError{Code: domainErr.Code, Message: domainErr.Message})
+			return
+		}
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Dental chart updated successfully", chart)
+}
+
 // handleUpdatePatient обрабатывает PUT запросы для обновления пациента
 func (h *Handler) handleUpdatePatient(w http.ResponseWriter, r *http.Request, id int) {
-	var patient domain.Patient
-	if err := json.NewDecoder(r.Body).Decode(&patient); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+	// Дата рождения принимается строкой YYYY-MM-DD, как и при создании, или меткой времени из ответа API
+	var request struct {
+		domain.Patient
+		BirthDate string `json:"birth_date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
 		return
 	}
 
+	patient := request.Patient
+	if request.BirthDate != "" {
+		birthDate, err := time.Parse("2006-01-02", request.BirthDate[:min(len(request.BirthDate), 10)])
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid birth_date, expected YYYY-MM-DD")
+			return
+		}
+		patient.BirthDate = birthDate
+	}
+
 	patient.ID = id
 
-	if err := h.patientUseCase.UpdatePatient(&patient); err != nil {
-		statusCode := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "уже существует") {
-			statusCode = http.StatusConflict
-		}
-		h.writeErrorResponse(w, statusCode, err.Error())
+	if err := h.patientUseCase.UpdatePatient(r.Context(), &patient); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -212,8 +328,8 @@ func (h *Handler) handleUpdatePatient(w http.ResponseWriter, r *http.Request, id
 
 // handleDeletePatient обрабатывает DELETE запросы для удаления пациента
 func (h *Handler) handleDeletePatient(w http.ResponseWriter, r *http.Request, id int) {
-	if err := h.patientUseCase.DeletePatient(id); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+	if err := h.patientUseCase.DeletePatient(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -239,39 +355,51 @@ func (h *Handler) ServicesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetServices получает список услуг
+// handleGetServices получает страницу услуг; query ищет по названию и примечаниям, type отбирает категорию
 func (h *Handler) handleGetServices(w http.ResponseWriter, r *http.Request) {
-	services, err := h.serviceUseCase.GetAllServices()
+	params := r.URL.Query()
+	page, err := parsePage(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter := domain.ServiceFilter{Query: params.Get("query"), Type: params.Get("type"), Sort: parseSort(params), Page: page}
+	services, total, err := h.serviceUseCase.ListServices(r.Context(), filter)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get services")
+		h.writeError(w, r, err)
 		return
 	}
 
-	h.writeSuccessResponse(w, "Services retrieved successfully", services)
+	h.writeListResponse(w, "Services retrieved successfully", services, len(services), total, page)
 }
 
 // handleCreateService создает новую услугу
 func (h *Handler) handleCreateService(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		Name  string `json:"name"`
-		Type  string `json:"type"`
-		Notes string `json:"notes"`
+		Name     string       `json:"name"`
+		Type     string       `json:"type"`
+		Notes    string       `json:"notes"`
+		Price    domain.Money `json:"price"`
+		Duration int          `json:"duration"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
 	// Создаем услугу
 	service := &domain.Service{
-		Name:  request.Name,
-		Type:  request.Type,
-		Notes: request.Notes,
+		Name:     request.Name,
+		Type:     request.Type,
+		Notes:    request.Notes,
+		Price:    request.Price,
+		Duration: request.Duration,
 	}
 
-	if err := h.serviceUseCase.CreateService(service); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create service")
+	if err := h.serviceUseCase.CreateService(r.Context(), service); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -287,14 +415,23 @@ func (h *Handler) ServiceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Извлекаем ID из URL
-	path := strings.TrimPrefix(r.URL.Path, "/api/services/")
-	id, err := strconv.Atoi(path)
+	// Извлекаем ID из URL: /api/services/{id}[/prices]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/services/"), "/")
+	id, err := strconv.Atoi(parts[0])
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid service ID")
 		return
 	}
 
+	if len(parts) > 1 {
+		if len(parts) != 2 || parts[1] != "prices" {
+			h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+			return
+		}
+		h.handleServicePrices(w, r, id)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.handleGetService(w, r, id)
@@ -309,9 +446,9 @@ func (h *Handler) ServiceHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetService получает услугу по ID
 func (h *Handler) handleGetService(w http.ResponseWriter, r *http.Request, id int) {
-	service, err := h.serviceUseCase.GetService(id)
+	service, err := h.serviceUseCase.GetService(r.Context(), id)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusNotFound, "Service not found")
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -322,13 +459,13 @@ func (h *Handler) handleGetService(w http.ResponseWriter, r *http.Request, id in
 func (h *Handler) handleUpdateService(w http.ResponseWriter, r *http.Request, id int) {
 	var service domain.Service
 	if err := json.NewDecoder(r.Body).Decode(&service); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
 	service.ID = id
-	if err := h.serviceUseCase.UpdateService(&service); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update service")
+	if err := h.serviceUseCase.UpdateService(r.Context(), &service); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -337,14 +474,61 @@ func (h *Handler) handleUpdateService(w http.ResponseWriter, r *http.Request, id
 
 // handleDeleteService удаляет услугу
 func (h *Handler) handleDeleteService(w http.ResponseWriter, r *http.Request, id int) {
-	if err := h.serviceUseCase.DeleteService(id); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete service")
+	if err := h.serviceUseCase.DeleteService(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
 	h.writeSuccessResponse(w, "Service deleted successfully", nil)
 }
 
+// handleServicePrices обрабатывает запросы к /api/services/{id}/prices
+func (h *Handler) handleServicePrices(w http.ResponseWriter, r *http.Request, serviceID int) {
+	switch r.Method {
+	case http.MethodGet:
+		prices, err := h.serviceUseCase.GetPriceHistory(r.Context(), serviceID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Price history retrieved successfully", prices)
+	case http.MethodPost:
+		h.handleCreateServicePrice(w, r, serviceID)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleCreateServicePrice добавляет изменение цены услуги
+func (h *Handler) handleCreateServicePrice(w http.ResponseWriter, r *http.Request, serviceID int) {
+	var request struct {
+		Price         domain.Money `json:"price"`
+		EffectiveFrom string       `json:"effective_from"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	price := &domain.ServicePrice{ServiceID: serviceID, Price: request.Price}
+	if request.EffectiveFrom != "" {
+		if effectiveFrom, err := h.parseDateTime(request.EffectiveFrom); err == nil {
+			price.EffectiveFrom = effectiveFrom
+		} else {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid effective_from, expected YYYY-MM-DD or RFC 3339")
+			return
+		}
+	}
+
+	if err := h.serviceUseCase.AddPrice(r.Context(), price); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Service price created successfully", price)
+}
+
 // AppointmentsHandler обрабатывает запросы к /api/appointments
 func (h *Handler) AppointmentsHandler(w http.ResponseWriter, r *http.Request) {
 	h.setCORSHeaders(w)
@@ -364,42 +548,71 @@ func (h *Handler) AppointmentsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetAppointments получает список записей
+// handleGetAppointments получает страницу записей с отбором по пациенту, врачу, услуге, статусу и датам приема
 func (h *Handler) handleGetAppointments(w http.ResponseWriter, r *http.Request) {
-	appointments, err := h.appointmentUseCase.GetAllAppointments()
+	params := r.URL.Query()
+	page, err := parsePage(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter := domain.AppointmentFilter{
+		Status: domain.AppointmentStatus(params.Get("status")),
+		Sort:   parseSort(params),
+		Page:   page,
+	}
+
+	err = parseIntParams(params, []intParam{
+		{"patient_id", &filter.PatientID},
+		{"doctor_id", &filter.DoctorID},
+		{"service_id", &filter.ServiceID},
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter.From, filter.To, err = h.parseDateFilter(params)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get appointments")
+		h.writeError(w, r, err)
 		return
 	}
 
-	h.writeSuccessResponse(w, "Appointments retrieved successfully", appointments)
+	appointments, total, err := h.appointmentUseCase.ListAppointments(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeListResponse(w, "Appointments retrieved successfully", appointments, len(appointments), total, page)
 }
 
 // handleCreateAppointment создает новую запись
 func (h *Handler) handleCreateAppointment(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		PatientID int     `json:"patient_id"`
-		Service   string  `json:"service"`
-		Date      string  `json:"date"`
-		Time      string  `json:"time"`
-		Doctor    string  `json:"doctor"`
-		Status    string  `json:"status"`
-		Price     float64 `json:"price"`
-		Duration  int     `json:"duration"`
-		Notes     string  `json:"notes"`
+		PatientID int          `json:"patient_id"`
+		ServiceID int          `json:"service_id"`
+		Date      string       `json:"date"`
+		Time      string       `json:"time"`
+		DoctorID  int          `json:"doctor_id"`
+		Status    string       `json:"status"`
+		Price     domain.Money `json:"price"`
+		Duration  int          `json:"duration"`
+		Notes     string       `json:"notes"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
 	// Создаем запись
 	appointment := &domain.Appointment{
 		PatientID: request.PatientID,
-		Service:   request.Service,
+		ServiceID: request.ServiceID,
 		Time:      request.Time,
-		Doctor:    request.Doctor,
+		DoctorID:  request.DoctorID,
 		Price:     request.Price,
 		Duration:  request.Duration,
 		Notes:     request.Notes,
@@ -407,9 +620,7 @@ func (h *Handler) handleCreateAppointment(w http.ResponseWriter, r *http.Request
 
 	// Парсим дату, если она указана
 	if request.Date != "" {
-		if date, err := time.Parse("2006-01-02T15:04:05Z", request.Date); err == nil {
-			appointment.Date = date
-		} else if date, err := time.Parse("2006-01-02", request.Date); err == nil {
+		if date, err := h.parseDateTime(request.Date); err == nil {
 			appointment.Date = date
 		}
 	}
@@ -421,14 +632,77 @@ func (h *Handler) handleCreateAppointment(w http.ResponseWriter, r *http.Request
 		appointment.Status = domain.StatusScheduled
 	}
 
-	if err := h.appointmentUseCase.CreateAppointment(appointment); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create appointment")
+	if err := h.appointmentUseCase.CreateAppointment(r.Context(), appointment); err != nil {
+		h.writeAppointmentError(w, r, err)
 		return
 	}
 
 	h.writeSuccessResponse(w, "Appointment created successfully", appointment)
 }
 
+// AppointmentSlotsHandler обрабатывает запросы к /api/appointments/slots
+func (h *Handler) AppointmentSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	query := &domain.SlotQuery{}
+
+	// Числовые параметры необязательны, кроме doctor_id
+	intParams := map[string]*int{
+		"doctor_id":  &query.DoctorID,
+		"service_id": &query.ServiceID,
+		"duration":   &query.Duration,
+		"buffer":     &query.Buffer,
+		"limit":      &query.Limit,
+	}
+	for name, target := range intParams {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid "+name)
+			return
+		}
+		*target = parsed
+	}
+
+	dateFrom, err := h.parseDate(params.Get("date_from"))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date_from, expected YYYY-MM-DD")
+		return
+	}
+	query.From = dateFrom
+
+	if value := params.Get("date_to"); value != "" {
+		dateTo, err := h.parseDate(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date_to, expected YYYY-MM-DD")
+			return
+		}
+		query.To = dateTo
+	}
+
+	slots, err := h.appointmentUseCase.FindFreeSlots(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Free slots retrieved successfully", slots)
+}
+
 // AppointmentHandler обрабатывает запросы к /api/appointments/{id}
 func (h *Handler) AppointmentHandler(w http.ResponseWriter, r *http.Request) {
 	h.setCORSHeaders(w)
@@ -438,14 +712,36 @@ func (h *Handler) AppointmentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Извлекаем ID из URL
-	path := strings.TrimPrefix(r.URL.Path, "/api/appointments/")
-	id, err := strconv.Atoi(path)
+	// Извлекаем ID из URL: /api/appointments/{id}[/{action}|/treatments[/{treatmentID}]|/diagnoses[/{diagnosisID}]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/appointments/"), "/")
+	id, err := strconv.Atoi(parts[0])
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid appointment ID")
 		return
 	}
 
+	if len(parts) > 1 && parts[1] == "treatments" {
+		h.handleTreatments(w, r, id, parts[2:])
+		return
+	}
+	if len(parts) > 1 && parts[1] == "diagnoses" {
+		h.handleAppointmentDiagnoses(w, r, id, parts[2:])
+		return
+	}
+
+	if len(parts) > 1 {
+		if len(parts) != 2 {
+			h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+			return
+		}
+		if r.Method != http.MethodPost {
+			h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.handleAppointmentAction(w, r, id, parts[1])
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.handleGetAppointment(w, r, id)
@@ -460,9 +756,9 @@ func (h *Handler) AppointmentHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetAppointment получает запись по ID
 func (h *Handler) handleGetAppointment(w http.ResponseWriter, r *http.Request, id int) {
-	appointment, err := h.appointmentUseCase.GetAppointment(id)
+	appointment, err := h.appointmentUseCase.GetAppointment(r.Context(), id)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusNotFound, "Appointment not found")
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -471,25 +767,215 @@ func (h *Handler) handleGetAppointment(w http.ResponseWriter, r *http.Request, i
 
 // handleUpdateAppointment обновляет запись
 func (h *Handler) handleUpdateAppointment(w http.ResponseWriter, r *http.Request, id int) {
-	var appointment domain.Appointment
-	if err := json.NewDecoder(r.Body).Decode(&appointment); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+	// Дата принимается строкой, чтобы разобрать время без смещения в часовом поясе клиники
+	var request struct {
+		domain.Appointment
+		Date string `json:"date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
 		return
 	}
 
+	appointment := request.Appointment
+	if request.Date != "" {
+		date, err := h.parseDateTime(request.Date)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD, YYYY-MM-DDTHH:MM or RFC 3339")
+			return
+		}
+		appointment.Date = date
+	}
+
 	appointment.ID = id
-	if err := h.appointmentUseCase.UpdateAppointment(&appointment); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+	if err := h.appointmentUseCase.UpdateAppointment(r.Context(), &appointment); err != nil {
+		h.writeAppointmentError(w, r, err)
 		return
 	}
 
 	h.writeSuccessResponse(w, "Appointment updated successfully", appointment)
 }
 
+// handleAppointmentAction переводит запись в следующий статус жизненного цикла: POST /api/appointments/{id}/{action}
+func (h *Handler) handleAppointmentAction(w http.ResponseWriter, r *http.Request, id int, action string) {
+	var appointment *domain.Appointment
+	var err error
+
+	switch action {
+	case "confirm":
+		appointment, err = h.appointmentUseCase.ConfirmAppointment(r.Context(), id)
+	case "arrive":
+		appointment, err = h.appointmentUseCase.MarkAppointmentArrived(r.Context(), id)
+	case "start":
+		appointment, err = h.appointmentUseCase.StartAppointment(r.Context(), id)
+	case "complete":
+		appointment, err = h.appointmentUseCase.CompleteAppointment(r.Context(), id)
+	case "no-show":
+		appointment, err = h.appointmentUseCase.MarkAppointmentNoShow(r.Context(), id)
+	case "cancel":
+		var request struct {
+			Reason string `json:"reason"`
+		}
+		// Причина необязательна, поэтому пустое тело допустимо
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+			h.writeInvalidBody(w, err)
+			return
+		}
+		appointment, err = h.appointmentUseCase.CancelAppointment(r.Context(), id, request.Reason)
+	case "reschedule":
+		var request struct {
+			Date string `json:"date"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.writeInvalidBody(w, err)
+			return
+		}
+		date, parseErr := h.parseDateTime(request.Date)
+		if parseErr != nil {
+			h.writeError(w, r, domain.NewValidationError("date", domain.FieldInvalid, "Invalid date, expected YYYY-MM-DDTHH:MM or RFC 3339"))
+			return
+		}
+		appointment, err = h.appointmentUseCase.RescheduleAppointment(r.Context(), id, date)
+	default:
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if err != nil {
+		h.writeAppointmentError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Appointment status updated successfully", appointment)
+}
+
+// handleTreatments обрабатывает запросы к процедурам приема: /api/appointments/{id}/treatments[/{treatmentID}]
+func (h *Handler) handleTreatments(w http.ResponseWriter, r *http.Request, appointmentID int, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			treatments, err := h.treatmentUseCase.ListTreatments(r.Context(), appointmentID)
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			h.writeSuccessResponse(w, "Treatments retrieved successfully", treatments)
+		case http.MethodPost:
+			h.handleSaveTreatment(w, r, appointmentID, 0)
+		default:
+			h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if len(rest) != 1 {
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+	id, err := strconv.Atoi(rest[0])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid treatment ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		h.handleSaveTreatment(w, r, appointmentID, id)
+	case http.MethodDelete:
+		if err := h.treatmentUseCase.DeleteTreatment(r.Context(), appointmentID, id); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment deleted successfully", nil)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleSaveTreatment добавляет процедуру к приему (id = 0) или изменяет существующую
+func (h *Handler) handleSaveTreatment(w http.ResponseWriter, r *http.Request, appointmentID, id int) {
+	var treatment domain.Treatment
+	if err := json.NewDecoder(r.Body).Decode(&treatment); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+	treatment.ID = id
+	treatment.AppointmentID = appointmentID
+
+	var err error
+	if id == 0 {
+		err = h.treatmentUseCase.AddTreatment(r.Context(), &treatment)
+	} else {
+		err = h.treatmentUseCase.UpdateTreatment(r.Context(), &treatment)
+	}
+	if err != nil {
+		h.writeAppointmentError(w, r, err)
+		return
+	}
+
+	if id == 0 {
+		h.writeSuccessResponse(w, "Treatment created successfully", treatment)
+		return
+	}
+	h.writeSuccessResponse(w, "Treatment updated successfully", treatment)
+}
+
+// handleAppointmentDiagnoses обрабатывает запросы к диагнозам приема: /api/appointments/{id}/diagnoses[/{diagnosisID}]
+func (h *Handler) handleAppointmentDiagnoses(w http.ResponseWriter, r *http.Request, appointmentID int, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			diagnoses, err := h.diagnosisUseCase.ListAppointmentDiagnoses(r.Context(), appointmentID)
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			h.writeSuccessResponse(w, "Diagnoses retrieved successfully", diagnoses)
+		case http.MethodPost:
+			var diagnosis domain.AppointmentDiagnosis
+			if err := json.NewDecoder(r.Body).Decode(&diagnosis); err != nil {
+				h.writeInvalidBody(w, err)
+				return
+			}
+			diagnosis.ID = 0
+			diagnosis.AppointmentID = appointmentID
+
+			if err := h.diagnosisUseCase.AddAppointmentDiagnosis(r.Context(), &diagnosis); err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			h.writeSuccessResponse(w, "Diagnosis created successfully", diagnosis)
+		default:
+			h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if len(rest) != 1 {
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+	id, err := strconv.Atoi(rest[0])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid diagnosis ID")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.diagnosisUseCase.DeleteAppointmentDiagnosis(r.Context(), appointmentID, id); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeSuccessResponse(w, "Diagnosis deleted successfully", nil)
+}
+
 // handleDeleteAppointment удаляет запись
 func (h *Handler) handleDeleteAppointment(w http.ResponseWriter, r *http.Request, id int) {
-	if err := h.appointmentUseCase.DeleteAppointment(id); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete appointment")
+	if err := h.appointmentUseCase.DeleteAppointment(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -510,12 +996,17 @@ func (h *Handler) DashboardHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats, err := h.dashboardUseCase.GetDashboardStats()
+	stats, err := h.dashboardUseCase.GetDashboardStats(r.Context())
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get dashboard stats")
+		h.writeError(w, r, err)
 		return
 	}
 
+	// Выручка видна только ролям с доступом к финансам
+	if doctor, ok := CurrentDoctor(r.Context()); !ok || !doctor.Can(domain.PermFinanceView) {
+		stats.TodayRevenue = 0
+	}
+
 	h.writeSuccessResponse(w, "Dashboard stats retrieved successfully", stats)
 }
 
@@ -533,17 +1024,17 @@ func (h *Handler) ReportsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	report, err := h.dashboardUseCase.GetFinanceReport()
+	report, err := h.dashboardUseCase.GetFinanceReport(r.Context())
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get finance report")
+		h.writeError(w, r, err)
 		return
 	}
 
 	h.writeSuccessResponse(w, "Finance report retrieved successfully", report)
 }
 
-// DoctorsHandler обрабатывает запросы к /api/doctors
-func (h *Handler) DoctorsHandler(w http.ResponseWriter, r *http.Request) {
+// TreatmentPlansHandler обрабатывает запросы к /api/treatment-plans
+func (h *Handler) TreatmentPlansHandler(w http.ResponseWriter, r *http.Request) {
 	h.setCORSHeaders(w)
 
 	if r.Method == http.MethodOptions {
@@ -553,16 +1044,37 @@ func (h *Handler) DoctorsHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
-		h.handleGetDoctors(w, r)
+		var patientID int
+		if err := parseIntParams(r.URL.Query(), []intParam{{"patient_id", &patientID}}); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		plans, err := h.treatmentPlanUseCase.GetPatientPlans(r.Context(), patientID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment plans retrieved successfully", plans)
 	case http.MethodPost:
-		h.handleCreateDoctor(w, r)
+		var plan domain.TreatmentPlan
+		if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+			h.writeInvalidBody(w, err)
+			return
+		}
+
+		if err := h.treatmentPlanUseCase.CreatePlan(r.Context(), &plan); err != nil {
+			h.writeAppointmentError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment plan created successfully", plan)
 	default:
 		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
-// DoctorHandler обрабатывает запросы к /api/doctors/{id}
-func (h *Handler) DoctorHandler(w http.ResponseWriter, r *http.Request) {
+// TreatmentPlanHandler обрабатывает запросы к /api/treatment-plans/{id}
+func (h *Handler) TreatmentPlanHandler(w http.ResponseWriter, r *http.Request) {
 	h.setCORSHeaders(w)
 
 	if r.Method == http.MethodOptions {
@@ -570,52 +1082,355 @@ func (h *Handler) DoctorHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Извлекаем ID из URL
-	path := strings.TrimPrefix(r.URL.Path, "/api/doctors/")
-	id, err := strconv.Atoi(path)
+	// Извлекаем ID из URL: /api/treatment-plans/{id}[/accept|/decline|/steps/{stepID}/appointment]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/treatment-plans/"), "/")
+	id, err := strconv.Atoi(parts[0])
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid doctor ID")
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid treatment plan ID")
 		return
 	}
 
+	switch {
+	case len(parts) == 1:
+		h.handleTreatmentPlan(w, r, id)
+	case len(parts) == 2 && (parts[1] == "accept" || parts[1] == "decline"):
+		if r.Method != http.MethodPost {
+			h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.handleTreatmentPlanDecision(w, r, id, parts[1])
+	case len(parts) == 4 && parts[1] == "steps" && parts[3] == "appointment":
+		stepID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid plan step ID")
+			return
+		}
+		if r.Method != http.MethodPost {
+			h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.handleSchedulePlanStep(w, r, id, stepID)
+	default:
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// handleTreatmentPlan получает, изменяет или удаляет план лечения
+func (h *Handler) handleTreatmentPlan(w http.ResponseWriter, r *http.Request, id int) {
 	switch r.Method {
 	case http.MethodGet:
-		h.handleGetDoctor(w, r, id)
+		plan, err := h.treatmentPlanUseCase.GetPlan(r.Context(), id)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment plan retrieved successfully", plan)
 	case http.MethodPut:
-		h.handleUpdateDoctor(w, r, id)
+		var plan domain.TreatmentPlan
+		if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+			h.writeInvalidBody(w, err)
+			return
+		}
+
+		plan.ID = id
+		if err := h.treatmentPlanUseCase.UpdatePlan(r.Context(), &plan); err != nil {
+			h.writeAppointmentError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment plan updated successfully", plan)
 	case http.MethodDelete:
-		h.handleDeleteDoctor(w, r, id)
+		if err := h.treatmentPlanUseCase.DeletePlan(r.Context(), id); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writeSuccessResponse(w, "Treatment plan deleted successfully", nil)
 	default:
 		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
-// handleGetDoctors получает всех врачей
-func (h *Handler) handleGetDoctors(w http.ResponseWriter, r *http.Request) {
-	doctors, err := h.doctorUseCase.GetAllDoctors()
+// handleTreatmentPlanDecision записывает согласие или отказ пациента: POST /api/treatment-plans/{id}/{accept|decline}
+func (h *Handler) handleTreatmentPlanDecision(w http.ResponseWriter, r *http.Request, id int, decision string) {
+	var plan *domain.TreatmentPlan
+	var err error
+
+	if decision == "accept" {
+		var request struct {
+			VariantID int    `json:"variant_id"`
+			SignedBy  string `json:"signed_by"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.writeInvalidBody(w, err)
+			return
+		}
+		plan, err = h.treatmentPlanUseCase.AcceptPlan(r.Context(), id, request.VariantID, request.SignedBy)
+	} else {
+		plan, err = h.treatmentPlanUseCase.DeclinePlan(r.Context(), id)
+	}
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get doctors")
+		h.writeError(w, r, err)
 		return
 	}
 
-	// Не отправляем пароли на фронт
-	for _, doctor := range doctors {
-		doctor.Password = ""
-	}
-
-	h.writeSuccessResponse(w, "Doctors retrieved successfully", doctors)
+	h.writeSuccessResponse(w, "Treatment plan status updated successfully", plan)
 }
 
-// handleGetDoctor получает врача по ID
-func (h *Handler) handleGetDoctor(w http.ResponseWriter, r *http.Request, id int) {
-	doctor, err := h.doctorUseCase.GetDoctor(id)
-	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get doctor")
+// handleSchedulePlanStep создает запись по процедуре плана: POST /api/treatment-plans/{id}/steps/{stepID}/appointment
+func (h *Handler) handleSchedulePlanStep(w http.ResponseWriter, r *http.Request, planID, stepID int) {
+	var request struct {
+		Date     string `json:"date"`
+		DoctorID int    `json:"doctor_id"`
+		Duration int    `json:"duration"`
+		Notes    string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	date, err := h.parseDateTime(request.Date)
+	if err != nil {
+		h.writeError(w, r, domain.NewValidationError("date", domain.FieldInvalid, "Invalid date, expected YYYY-MM-DDTHH:MM or RFC 3339"))
+		return
+	}
+
+	appointment := &domain.Appointment{Date: date, DoctorID: request.DoctorID, Duration: request.Duration, Notes: request.Notes}
+	if err := h.treatmentPlanUseCase.ScheduleStep(r.Context(), planID, stepID, appointment); err != nil {
+		h.writeAppointmentError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Appointment created successfully", appointment)
+}
+
+// TreatmentPlanProgressHandler обрабатывает запросы к /api/reports/treatment-plans
+func (h *Handler) TreatmentPlanProgressHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var patientID int
+	if err := parseIntParams(r.URL.Query(), []intParam{{"patient_id", &patientID}}); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	progress, err := h.treatmentPlanUseCase.GetPlanProgress(r.Context(), patientID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Treatment plan progress retrieved successfully", progress)
+}
+
+// DiagnosesHandler обрабатывает запросы к справочнику МКБ-10 /api/diagnoses?query=&limit=
+func (h *Handler) DiagnosesHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	var limit int
+	if err := parseIntParams(params, []intParam{{"limit", &limit}}); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	diagnoses, err := h.diagnosisUseCase.SearchDiagnoses(r.Context(), params.Get("query"), limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Diagnoses retrieved successfully", diagnoses)
+}
+
+// DiagnosisHandler обрабатывает запросы к /api/diagnoses/{code}
+func (h *Handler) DiagnosisHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	code := strings.TrimPrefix(r.URL.Path, "/api/diagnoses/")
+	if code == "" || strings.Contains(code, "/") {
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	diagnosis, err := h.diagnosisUseCase.GetDiagnosis(r.Context(), code)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Diagnosis retrieved successfully", diagnosis)
+}
+
+// DiagnosisReportHandler обрабатывает запросы к /api/reports/diagnoses?date_from=&date_to=&doctor_id=&group=category
+func (h *Handler) DiagnosisReportHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	var filter domain.DiagnosisReportFilter
+	if err := parseIntParams(params, []intParam{{"doctor_id", &filter.DoctorID}}); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	switch params.Get("group") {
+	case "", "code":
+	case "category":
+		filter.ByCategory = true
+	default:
+		h.writeError(w, r, domain.NewValidationError("group", domain.FieldInvalid, "Invalid group, expected code or category"))
+		return
+	}
+
+	var err error
+	filter.From, filter.To, err = h.parseDateFilter(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	report, err := h.diagnosisUseCase.GetDiagnosisReport(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Diagnosis report retrieved successfully", report)
+}
+
+// DoctorsHandler обрабатывает запросы к /api/doctors
+func (h *Handler) DoctorsHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetDoctors(w, r)
+	case http.MethodPost:
+		h.handleCreateDoctor(w, r)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// DoctorHandler обрабатывает запросы к /api/doctors/{id}
+func (h *Handler) DoctorHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Извлекаем ID из URL: /api/doctors/{id}[/schedule/...]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/doctors/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid doctor ID")
+		return
+	}
+
+	if len(parts) > 1 {
+		switch {
+		case parts[1] == "schedule":
+			h.handleDoctorSchedule(w, r, id, parts[2:])
+		case parts[1] == "unlock" && len(parts) == 2 && r.Method == http.MethodPost:
+			h.handleUnlockDoctor(w, r, id)
+		default:
+			h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetDoctor(w, r, id)
+	case http.MethodPut:
+		h.handleUpdateDoctor(w, r, id)
+	case http.MethodDelete:
+		h.handleDeleteDoctor(w, r, id)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleGetDoctors получает страницу врачей; query ищет по имени, логину и email, role отбирает роль
+func (h *Handler) handleGetDoctors(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	page, err := parsePage(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter := domain.DoctorFilter{Query: params.Get("query"), Role: domain.Role(params.Get("role")), Sort: parseSort(params), Page: page}
+	doctors, total, err := h.doctorUseCase.ListDoctors(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	// Не отправляем пароли на фронт
+	for _, doctor := range doctors {
+		doctor.Password = ""
+	}
+
+	h.writeListResponse(w, "Doctors retrieved successfully", doctors, len(doctors), total, page)
+}
+
+// handleGetDoctor получает врача по ID
+func (h *Handler) handleGetDoctor(w http.ResponseWriter, r *http.Request, id int) {
+	doctor, err := h.doctorUseCase.GetDoctor(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
 	if doctor == nil {
-		h.writeErrorResponse(w, http.StatusNotFound, "Doctor not found")
+		h.writeError(w, r, domain.ErrDoctorNotFound)
 		return
 	}
 
@@ -629,12 +1444,12 @@ func (h *Handler) handleGetDoctor(w http.ResponseWriter, r *http.Request, id int
 func (h *Handler) handleCreateDoctor(w http.ResponseWriter, r *http.Request) {
 	var doctor domain.Doctor
 	if err := json.NewDecoder(r.Body).Decode(&doctor); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
-	if err := h.doctorUseCase.CreateDoctor(&doctor); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+	if err := h.doctorUseCase.CreateDoctor(r.Context(), &doctor); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -648,17 +1463,26 @@ func (h *Handler) handleCreateDoctor(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleUpdateDoctor(w http.ResponseWriter, r *http.Request, id int) {
 	var doctor domain.Doctor
 	if err := json.NewDecoder(r.Body).Decode(&doctor); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+		h.writeInvalidBody(w, err)
 		return
 	}
 
 	doctor.ID = id
+	passwordChanged := doctor.Password != ""
 
-	if err := h.doctorUseCase.UpdateDoctor(&doctor); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+	if err := h.doctorUseCase.UpdateDoctor(r.Context(), &doctor); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
+	// После смены пароля все открытые сессии врача закрываются
+	if passwordChanged {
+		if err := h.sessionUseCase.RevokeDoctorSessions(r.Context(), id); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+
 	// Не отправляем пароль на фронт
 	doctor.Password = ""
 
@@ -667,71 +1491,795 @@ func (h *Handler) handleUpdateDoctor(w http.ResponseWriter, r *http.Request, id
 
 // handleDeleteDoctor удаляет врача
 func (h *Handler) handleDeleteDoctor(w http.ResponseWriter, r *http.Request, id int) {
-	if err := h.doctorUseCase.DeleteDoctor(id); err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete doctor")
+	if err := h.doctorUseCase.DeleteDoctor(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
 	h.writeSuccessResponse(w, "Doctor deleted successfully", nil)
 }
 
-// AuthHandler обрабатывает запросы к /api/auth
-func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
-	h.setCORSHeaders(w)
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+// handleUnlockDoctor снимает блокировку входа врача
+func (h *Handler) handleUnlockDoctor(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.doctorUseCase.UnlockDoctor(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
-	if r.Method != http.MethodPost {
+	h.writeSuccessResponse(w, "Doctor unlocked successfully", nil)
+}
+
+// handleDoctorSchedule обрабатывает запросы к /api/doctors/{id}/schedule[/exceptions[/{exceptionId}]]
+func (h *Handler) handleDoctorSchedule(w http.ResponseWriter, r *http.Request, doctorID int, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		h.handleGetDoctorSchedule(w, r, doctorID)
+	case len(rest) == 0 && r.Method == http.MethodPut:
+		h.handleUpdateDoctorSchedule(w, r, doctorID)
+	case len(rest) == 1 && rest[0] == "exceptions" && r.Method == http.MethodPost:
+		h.handleCreateScheduleException(w, r, doctorID)
+	case len(rest) == 2 && rest[0] == "exceptions" && r.Method == http.MethodDelete:
+		exceptionID, err := strconv.Atoi(rest[1])
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid schedule exception ID")
+			return
+		}
+		h.handleDeleteScheduleException(w, r, doctorID, exceptionID)
+	case len(rest) > 0 && rest[0] != "exceptions":
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+	default:
 		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// parseDate разбирает дату YYYY-MM-DD как начало суток в часовом поясе клиники
+func (h *Handler) parseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, h.location)
+}
+
+// parseDateTime разбирает дату и время в RFC 3339 со смещением либо время клиники без смещения:
+// YYYY-MM-DDTHH:MM[:SS] или YYYY-MM-DD
+func (h *Handler) parseDateTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.In(h.location), nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, h.location); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, errors.New("invalid date format")
+}
+
+// parseDateRange разбирает параметры date_from и date_to (YYYY-MM-DD); по умолчанию — неделя с сегодняшнего дня клиники
+func (h *Handler) parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now().In(h.location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, h.location)
+	to := from.AddDate(0, 0, 6)
+
+	if value := r.URL.Query().Get("date_from"); value != "" {
+		parsed, err := h.parseDate(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.NewValidationError("date_from", domain.FieldInvalid, "Invalid date_from, expected YYYY-MM-DD")
+		}
+		from = parsed
+		to = from.AddDate(0, 0, 6)
+	}
+
+	if value := r.URL.Query().Get("date_to"); value != "" {
+		parsed, err := h.parseDate(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.NewValidationError("date_to", domain.FieldInvalid, "Invalid date_to, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	return from, to, nil
+}
+
+// handleGetDoctorSchedule получает график врача на период
+func (h *Handler) handleGetDoctorSchedule(w http.ResponseWriter, r *http.Request, doctorID int) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
-	var authRequest struct {
-		Login    string `json:"login"`
-		Password string `json:"password"`
+	actor, _ := CurrentDoctor(r.Context())
+	schedule, err := h.scheduleUseCase.GetDoctorSchedule(r.Context(), actor, doctorID, from, to)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&authRequest); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON")
+	h.writeSuccessResponse(w, "Doctor schedule retrieved successfully", schedule)
+}
+
+// handleUpdateDoctorSchedule заменяет недельный шаблон графика врача
+func (h *Handler) handleUpdateDoctorSchedule(w http.ResponseWriter, r *http.Request, doctorID int) {
+	var request struct {
+		WorkingHours []*domain.WorkingHours  `json:"working_hours"`
+		Breaks       []*domain.ScheduleBreak `json:"breaks"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	if err := h.scheduleUseCase.UpdateWeeklySchedule(r.Context(), doctorID, request.WorkingHours, request.Breaks); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Doctor schedule updated successfully", request)
+}
+
+// handleCreateScheduleException добавляет исключение из графика врача
+func (h *Handler) handleCreateScheduleException(w http.ResponseWriter, r *http.Request, doctorID int) {
+	var request struct {
+		Type      string `json:"type"`
+		DateFrom  string `json:"date_from"`
+		DateTo    string `json:"date_to"`
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+		Reason    string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
 		return
 	}
 
-	doctor, err := h.doctorUseCase.AuthenticateDoctor(authRequest.Login, authRequest.Password)
+	exception := &domain.ScheduleException{
+		DoctorID:  doctorID,
+		Type:      domain.ScheduleExceptionType(request.Type),
+		StartTime: request.StartTime,
+		EndTime:   request.EndTime,
+		Reason:    request.Reason,
+	}
+
+	dateFrom, err := h.parseDate(request.DateFrom)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusUnauthorized, err.Error())
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date_from, expected YYYY-MM-DD")
+		return
+	}
+	exception.DateFrom = dateFrom
+
+	if request.DateTo != "" {
+		dateTo, err := h.parseDate(request.DateTo)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date_to, expected YYYY-MM-DD")
+			return
+		}
+		exception.DateTo = dateTo
+	}
+
+	if err := h.scheduleUseCase.AddException(r.Context(), exception); err != nil {
+		h.writeError(w, r, err)
 		return
 	}
 
-	h.writeSuccessResponse(w, "Authentication successful", doctor)
+	h.writeSuccessResponse(w, "Schedule exception created successfully", exception)
 }
 
-// SetupRoutes настраивает маршруты
-func (h *Handler) SetupRoutes(mux *http.ServeMux) {
-	// API маршруты для пациентов
-	mux.HandleFunc("/api/patients", h.PatientsHandler)
-	mux.HandleFunc("/api/patients/", h.PatientHandler)
+// handleDeleteScheduleException удаляет исключение из графика врача
+func (h *Handler) handleDeleteScheduleException(w http.ResponseWriter, r *http.Request, doctorID, exceptionID int) {
+	if err := h.scheduleUseCase.DeleteException(r.Context(), doctorID, exceptionID); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
 
-	// API маршруты для услуг
-	mux.HandleFunc("/api/services", h.ServicesHandler)
-	mux.HandleFunc("/api/services/", h.ServiceHandler)
+	h.writeSuccessResponse(w, "Schedule exception deleted successfully", nil)
+}
 
-	// API маршруты для записей
-	mux.HandleFunc("/api/appointments", h.AppointmentsHandler)
-	mux.HandleFunc("/api/appointments/", h.AppointmentHandler)
+// HolidaysHandler обрабатывает запросы к /api/holidays
+func (h *Handler) HolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
 
-	// API маршруты для дашборда
-	mux.HandleFunc("/api/dashboard", h.DashboardHandler)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetHolidays(w, r)
+	case http.MethodPost:
+		h.handleCreateHoliday(w, r)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleGetHolidays получает праздничные дни клиники на период
+func (h *Handler) handleGetHolidays(w http.ResponseWriter, r *http.Request) {
+	from, to, err := h.parseDateRange(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	holidays, err := h.scheduleUseCase.GetHolidays(r.Context(), from, to)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Holidays retrieved successfully", holidays)
+}
+
+// handleCreateHoliday добавляет праздничный день клиники
+func (h *Handler) handleCreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	date, err := h.parseDate(request.Date)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+		return
+	}
+
+	holiday := &domain.ClinicHoliday{Date: date, Name: request.Name}
+	if err := h.scheduleUseCase.AddHoliday(r.Context(), holiday); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Holiday created successfully", holiday)
+}
+
+// HolidayHandler обрабатывает запросы к /api/holidays/{id}
+func (h *Handler) HolidayHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/holidays/"))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.scheduleUseCase.DeleteHoliday(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Holiday deleted successfully", nil)
+}
+
+// AuthHandler обрабатывает запросы к /api/auth
+func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var authRequest struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&authRequest); err != nil {
+		h.writeInvalidBody(w, err)
+		return
+	}
+
+	doctor, err := h.doctorUseCase.AuthenticateDoctor(r.Context(), authRequest.Login, authRequest.Password, clientIP(r))
+	if err != nil {
+		h.writeLoginError(w, r, err)
+		return
+	}
+
+	// При включенном втором факторе сессия открывается только после проверки кода
+	challenge, err := h.twoFactorUseCase.BeginLogin(r.Context(), doctor)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	if challenge != nil {
+		h.writeSuccessResponse(w, "Two-factor authentication required", mfaResponse{MFARequired: true, MFAChallenge: challenge})
+		return
+	}
+
+	h.openSession(w, r, doctor, "Authentication successful", nil)
+}
+
+// writeLoginError отвечает ошибкой входа; при блокировке сообщает клиенту, когда повторить попытку
+func (h *Handler) writeLoginError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrAccountLocked) || errors.Is(err, domain.ErrTooManyLoginAttempts) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(usecase.LoginLockoutDuration.Seconds())))
+	}
+	h.writeError(w, r, err)
+}
+
+// openSession создает сессию врача, записывает cookie и отвечает данными сессии
+func (h *Handler) openSession(w http.ResponseWriter, r *http.Request, doctor *domain.Doctor, message string, recoveryCodes []string) {
+	tokens, err := h.sessionUseCase.CreateSession(r.Context(), doctor)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	setSessionCookies(w, r, tokens)
+	h.writeSuccessResponse(w, message, authResponse{Doctor: doctor, SessionTokens: tokens, RecoveryCodes: recoveryCodes})
+}
+
+// authResponse содержит врача и токены открытой сессии
+type authResponse struct {
+	Doctor *domain.Doctor `json:"doctor"`
+	*domain.SessionTokens
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// mfaResponse сообщает, что для входа нужен код второго фактора
+type mfaResponse struct {
+	MFARequired bool `json:"mfa_required"`
+	*domain.MFAChallenge
+}
+
+// mfaCodeRequest содержит код второго фактора
+type mfaCodeRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// decodeMFACodeRequest разбирает тело запроса с кодом второго фактора
+func (h *Handler) decodeMFACodeRequest(w http.ResponseWriter, r *http.Request) (*mfaCodeRequest, bool) {
+	var request mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeInvalidBody(w, err)
+		return nil, false
+	}
+	return &request, true
+}
+
+// AuthTwoFactorVerifyHandler обрабатывает запросы к /api/auth/2fa/verify: второй шаг входа
+func (h *Handler) AuthTwoFactorVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	request, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	doctor, recoveryCodes, err := h.twoFactorUseCase.VerifyLogin(r.Context(), request.MFAToken, request.Code, clientIP(r))
+	if err != nil {
+		h.writeLoginError(w, r, err)
+		return
+	}
+
+	h.openSession(w, r, doctor, "Authentication successful", recoveryCodes)
+}
+
+// AuthTwoFactorEnrollHandler обрабатывает запросы к /api/auth/2fa/enroll: выдает секрет и otpauth URI
+func (h *Handler) AuthTwoFactorEnrollHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	doctor, _ := CurrentDoctor(r.Context())
+	enrollment, err := h.twoFactorUseCase.Enroll(r.Context(), doctor.ID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Two-factor enrollment started", enrollment)
+}
+
+// AuthTwoFactorConfirmHandler обрабатывает запросы к /api/auth/2fa/confirm: включает TOTP по первому коду
+func (h *Handler) AuthTwoFactorConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	request, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	doctor, _ := CurrentDoctor(r.Context())
+	recoveryCodes, err := h.twoFactorUseCase.ConfirmEnrollment(r.Context(), doctor.ID, request.Code)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Two-factor authentication enabled", map[string][]string{"recovery_codes": recoveryCodes})
+}
+
+// AuthTwoFactorRecoveryCodesHandler обрабатывает запросы к /api/auth/2fa/recovery-codes: выпускает новые коды
+func (h *Handler) AuthTwoFactorRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	request, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	doctor, _ := CurrentDoctor(r.Context())
+	recoveryCodes, err := h.twoFactorUseCase.RegenerateRecoveryCodes(r.Context(), doctor.ID, request.Code)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Recovery codes regenerated", map[string][]string{"recovery_codes": recoveryCodes})
+}
+
+// AuthTwoFactorDisableHandler обрабатывает запросы к /api/auth/2fa/disable
+func (h *Handler) AuthTwoFactorDisableHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	request, ok := h.decodeMFACodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	doctor, _ := CurrentDoctor(r.Context())
+	if err := h.twoFactorUseCase.Disable(r.Context(), doctor.ID, request.Code); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Two-factor authentication disabled", nil)
+}
+
+// AuthRefreshHandler обрабатывает запросы к /api/auth/refresh
+func (h *Handler) AuthRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Токен обновления принимается из тела запроса или из cookie
+	var refreshRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil {
+			h.writeInvalidBody(w, err)
+			return
+		}
+	}
+	if refreshRequest.RefreshToken == "" {
+		refreshRequest.RefreshToken = refreshToken(r)
+	}
+
+	doctor, tokens, err := h.sessionUseCase.Refresh(r.Context(), refreshRequest.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSession) {
+			clearSessionCookies(w)
+		}
+		h.writeError(w, r, err)
+		return
+	}
+
+	setSessionCookies(w, r, tokens)
+	h.writeSuccessResponse(w, "Session refreshed successfully", authResponse{Doctor: doctor, SessionTokens: tokens})
+}
+
+// AuthLogoutHandler обрабатывает запросы к /api/auth/logout
+func (h *Handler) AuthLogoutHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.sessionUseCase.Logout(r.Context(), sessionToken(r)); err != nil && !errors.Is(err, domain.ErrInvalidSession) {
+		h.writeError(w, r, err)
+		return
+	}
+
+	clearSessionCookies(w)
+	h.writeSuccessResponse(w, "Logged out successfully", nil)
+}
+
+// AuthMeHandler обрабатывает запросы к /api/auth/me
+func (h *Handler) AuthMeHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	doctor, _ := CurrentDoctor(r.Context())
+	h.writeSuccessResponse(w, "Current doctor retrieved successfully", doctor)
+}
+
+// LoginAttemptsHandler обрабатывает запросы к /api/auth/attempts?login=&limit=
+func (h *Handler) LoginAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	attempts, err := h.doctorUseCase.GetLoginAttempts(r.Context(), r.URL.Query().Get("login"), limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeSuccessResponse(w, "Login attempts retrieved successfully", attempts)
+}
+
+// AuditHandler обрабатывает запросы к /api/audit?entity=&entity_id=&actor_id=&action=&date_from=&date_to=&limit=
+func (h *Handler) AuditHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	page, err := parsePage(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter := domain.AuditFilter{
+		Entity: domain.AuditEntity(params.Get("entity")),
+		Action: domain.AuditAction(params.Get("action")),
+		Page:   page,
+	}
+
+	err = parseIntParams(params, []intParam{
+		{"entity_id", &filter.EntityID},
+		{"actor_id", &filter.ActorID},
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	filter.From, filter.To, err = h.parseDateFilter(params)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	entries, total, err := h.auditUseCase.GetAuditLog(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeListResponse(w, "Audit log retrieved successfully", entries, len(entries), total, page)
+}
+
+// RolesHandler обрабатывает запросы к /api/roles
+func (h *Handler) RolesHandler(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w)
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	type roleInfo struct {
+		Role        domain.Role         `json:"role"`
+		Permissions []domain.Permission `json:"permissions"`
+	}
+
+	roles := make([]roleInfo, 0, len(domain.Roles()))
+	for _, role := range domain.Roles() {
+		roles = append(roles, roleInfo{Role: role, Permissions: role.Permissions()})
+	}
+
+	h.writeSuccessResponse(w, "Roles retrieved successfully", roles)
+}
+
+// patientsPermission определяет право для /api/patients/: зубная формула защищена отдельными правами
+func patientsPermission(r *http.Request) domain.Permission {
+	if strings.Contains(r.URL.Path, "/chart") {
+		return byMethod(domain.PermChartRead, domain.PermChartWrite)(r)
+	}
+	return byMethod(domain.PermPatientsRead, domain.PermPatientsWrite)(r)
+}
+
+// appointmentsPermission определяет право для /api/appointments/: процедуры и диагнозы приема защищены отдельными правами
+func appointmentsPermission(r *http.Request) domain.Permission {
+	if strings.Contains(r.URL.Path, "/treatments") || strings.Contains(r.URL.Path, "/diagnoses") {
+		return byMethod(domain.PermTreatmentsRead, domain.PermTreatmentsWrite)(r)
+	}
+	return byMethod(domain.PermAppointmentsRead, domain.PermAppointmentsWrite)(r)
+}
+
+// treatmentPlansPermission определяет право для /api/treatment-plans/: запись по процедуре плана создает прием,
+// поэтому защищена правом на записи
+func treatmentPlansPermission(r *http.Request) domain.Permission {
+	if strings.Contains(r.URL.Path, "/steps/") {
+		return byMethod(domain.PermAppointmentsRead, domain.PermAppointmentsWrite)(r)
+	}
+	return byMethod(domain.PermPlansRead, domain.PermPlansWrite)(r)
+}
+
+// doctorsPermission определяет право для /api/doctors/: график врача и профиль врача защищены разными правами
+func doctorsPermission(r *http.Request) domain.Permission {
+	if strings.Contains(r.URL.Path, "/schedule") {
+		return byMethod(domain.PermScheduleRead, domain.PermScheduleManage)(r)
+	}
+	return byMethod(domain.PermDoctorsRead, domain.PermDoctorsManage)(r)
+}
+
+// SetupRoutes настраивает маршруты
+func (h *Handler) SetupRoutes(mux *http.ServeMux) {
+	// protect регистрирует маршрут, доступный только с действующей сессией и нужным правом
+	protect := func(pattern string, handler http.HandlerFunc, permission permissionFunc) {
+		mux.Handle(pattern, h.RequireAuth(h.RequirePermission(permission, handler)))
+	}
+
+	// API маршруты для пациентов
+	protect("/api/patients", h.PatientsHandler, byMethod(domain.PermPatientsRead, domain.PermPatientsWrite))
+	protect("/api/patients/", h.PatientHandler, patientsPermission)
+
+	// API маршруты для услуг
+	protect("/api/services", h.ServicesHandler, byMethod(domain.PermServicesRead, domain.PermServicesWrite))
+	protect("/api/services/", h.ServiceHandler, byMethod(domain.PermServicesRead, domain.PermServicesWrite))
+
+	// API маршруты для записей
+	protect("/api/appointments", h.AppointmentsHandler, byMethod(domain.PermAppointmentsRead, domain.PermAppointmentsWrite))
+	protect("/api/appointments/", h.AppointmentHandler, appointmentsPermission)
+	protect("/api/appointments/slots", h.AppointmentSlotsHandler, byMethod(domain.PermAppointmentsRead, domain.PermAppointmentsWrite))
+
+	// API маршруты для дашборда
+	protect("/api/dashboard", h.DashboardHandler, byMethod(domain.PermDashboardView, domain.PermDashboardView))
 
 	// API маршрут для финансовых отчетов
-	mux.HandleFunc("/api/reports", h.ReportsHandler)
+	protect("/api/reports", h.ReportsHandler, byMethod(domain.PermFinanceView, domain.PermFinanceView))
+	protect("/api/reports/treatment-plans", h.TreatmentPlanProgressHandler, byMethod(domain.PermPlansRead, domain.PermPlansRead))
+	protect("/api/reports/diagnoses", h.DiagnosisReportHandler, byMethod(domain.PermTreatmentsRead, domain.PermTreatmentsRead))
+
+	// API маршруты для справочника диагнозов МКБ-10
+	protect("/api/diagnoses", h.DiagnosesHandler, byMethod(domain.PermTreatmentsRead, domain.PermTreatmentsRead))
+	protect("/api/diagnoses/", h.DiagnosisHandler, byMethod(domain.PermTreatmentsRead, domain.PermTreatmentsRead))
+
+	// API маршруты для планов лечения
+	protect("/api/treatment-plans", h.TreatmentPlansHandler, byMethod(domain.PermPlansRead, domain.PermPlansWrite))
+	protect("/api/treatment-plans/", h.TreatmentPlanHandler, treatmentPlansPermission)
+
+	// API маршруты для врачей, их ролей и графиков
+	protect("/api/doctors", h.DoctorsHandler, byMethod(domain.PermDoctorsRead, domain.PermDoctorsManage))
+	protect("/api/doctors/", h.DoctorHandler, doctorsPermission)
+	mux.Handle("/api/roles", h.RequireAuth(http.HandlerFunc(h.RolesHandler)))
 
-	// API маршруты для врачей
-	mux.HandleFunc("/api/doctors", h.DoctorsHandler)
-	mux.HandleFunc("/api/doctors/", h.DoctorHandler)
+	// API маршруты для праздничных дней клиники
+	protect("/api/holidays", h.HolidaysHandler, byMethod(domain.PermScheduleRead, domain.PermScheduleManage))
+	protect("/api/holidays/", h.HolidayHandler, byMethod(domain.PermScheduleRead, domain.PermScheduleManage))
 
-	// API маршрут для авторизации
+	// API маршруты для авторизации; вход, второй шаг входа, обновление и выход доступны без действующей сессии
 	mux.HandleFunc("/api/auth", h.AuthHandler)
+	mux.HandleFunc("/api/auth/refresh", h.AuthRefreshHandler)
+	mux.HandleFunc("/api/auth/logout", h.AuthLogoutHandler)
+	mux.Handle("/api/auth/me", h.RequireAuth(http.HandlerFunc(h.AuthMeHandler)))
+	mux.HandleFunc("/api/auth/2fa/verify", h.AuthTwoFactorVerifyHandler)
+	mux.Handle("/api/auth/2fa/enroll", h.RequireAuth(http.HandlerFunc(h.AuthTwoFactorEnrollHandler)))
+	mux.Handle("/api/auth/2fa/confirm", h.RequireAuth(http.HandlerFunc(h.AuthTwoFactorConfirmHandler)))
+	mux.Handle("/api/auth/2fa/recovery-codes", h.RequireAuth(http.HandlerFunc(h.AuthTwoFactorRecoveryCodesHandler)))
+	mux.Handle("/api/auth/2fa/disable", h.RequireAuth(http.HandlerFunc(h.AuthTwoFactorDisableHandler)))
+	protect("/api/auth/attempts", h.LoginAttemptsHandler, byMethod(domain.PermDoctorsManage, domain.PermDoctorsManage))
+
+	// API маршрут для журнала аудита
+	protect("/api/audit", h.AuditHandler, byMethod(domain.PermAuditView, domain.PermAuditView))
 }
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/sdk17/crmstom/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_AuditAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.Role
+		wantStatus int
+	}{
+		{name: "admin reads audit log", role: domain.RoleAdmin, wantStatus: http.StatusOK},
+		{name: "doctor is forbidden", role: domain.RoleDoctor, wantStatus: http.StatusForbidden},
+		{name: "receptionist is forbidden", role: domain.RoleReceptionist, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessions := repository.NewMockSessionRepository(ctrl)
+			doctors := repository.NewMockDoctorRepository(ctrl)
+			audit := repository.NewMockAuditRepository(ctrl)
+
+			sessions.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).
+				Return(&domain.Session{ID: 1, DoctorID: 3, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			doctors.EXPECT().GetByID(gomock.Any(), 3).Return(&domain.Doctor{ID: 3, Role: tt.role}, nil)
+			if tt.wantStatus == http.StatusOK {
+				audit.EXPECT().List(gomock.Any(), gomock.Any()).Return([]*domain.AuditEntry{}, 0, nil)
+			}
+
+			h := NewHandler(nil, nil, nil, nil, nil, nil, usecase.NewSessionUseCase(sessions, doctors), nil,
+				usecase.NewAuditUseCase(audit), nil, nil, nil, nil, time.UTC)
+			mux := http.NewServeMux()
+			h.SetupRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/audit", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_DiagnosisReportAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		role domain.Role
+	}{
+		{name: "admin reads diagnosis report", role: domain.RoleAdmin},
+		{name: "doctor reads diagnosis report", role: domain.RoleDoctor},
+		{name: "receptionist reads diagnosis report", role: domain.RoleReceptionist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessions := repository.NewMockSessionRepository(ctrl)
+			doctors := repository.NewMockDoctorRepository(ctrl)
+			diagnoses := repository.NewMockAppointmentDiagnosisRepository(ctrl)
+
+			sessions.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).
+				Return(&domain.Session{ID: 1, DoctorID: 3, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			doctors.EXPECT().GetByID(gomock.Any(), 3).Return(&domain.Doctor{ID: 3, Role: tt.role}, nil)
+			diagnoses.EXPECT().GetReport(gomock.Any(), gomock.Any()).Return([]*domain.DiagnosisReportRow{}, nil)
+
+			h := NewHandler(nil, nil, nil, nil, nil, nil, usecase.NewSessionUseCase(sessions, doctors), nil, nil, nil, nil, nil,
+				usecase.NewDiagnosisUseCase(repository.NewMockDiagnosisRepository(ctrl), diagnoses, nil, nil), time.UTC)
+			mux := http.NewServeMux()
+			h.SetupRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/reports/diagnoses", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Имена cookie сессии
+const (
+	sessionCookieName = "crmstom_session"
+	refreshCookieName = "crmstom_refresh"
+)
+
+// requestIDHeader заголовок с идентификатором запроса для журнала аудита и поиска по логам
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину идентификатора запроса, переданного клиентом
+const maxRequestIDLength = 64
+
+// CurrentDoctor возвращает аутентифицированного врача из контекста запроса
+func CurrentDoctor(ctx context.Context) (*domain.Doctor, bool) {
+	return domain.DoctorFromContext(ctx)
+}
+
+// withDoctor кладет аутентифицированного врача в контекст запроса
+func withDoctor(r *http.Request, doctor *domain.Doctor) *http.Request {
+	return r.WithContext(domain.WithDoctor(r.Context(), doctor))
+}
+
+// RequestID присваивает запросу идентификатор из заголовка X-Request-ID или новый случайный
+// и возвращает его в ответе
+func (h *Handler) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(domain.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// Timeout ограничивает время обработки запроса: по истечении timeout контекст запроса отменяется,
+// запросы к базе данных прерываются, а клиент получает 503. Нулевой timeout отключает ограничение
+func (h *Handler) Timeout(timeout time.Duration, next http.Handler) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.TimeoutHandler(next, timeout, requestTimeoutBody)
+}
+
+// validRequestID проверяет идентификатор запроса от клиента: непустой, не длиннее 64 символов, без пробелов и управляющих символов
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID генерирует случайный идентификатор запроса
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// sessionToken извлекает токен доступа из заголовка Authorization: Bearer или из cookie
+func sessionToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+// clientIP возвращает IP адрес клиента из RemoteAddr.
+// Заголовки X-Forwarded-For не учитываются: без доверенного прокси их легко подделать.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// refreshToken извлекает токен обновления из cookie
+func refreshToken(r *http.Request) string {
+	if cookie, err := r.Cookie(refreshCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// setSessionCookies записывает токены сессии в HttpOnly cookie
+func setSessionCookies(w http.ResponseWriter, r *http.Request, tokens *domain.SessionTokens) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    tokens.AccessToken,
+		Path:     "/",
+		Expires:  tokens.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    tokens.RefreshToken,
+		Path:     "/",
+		Expires:  tokens.RefreshExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clearSessionCookies удаляет cookie сессии
+func clearSessionCookies(w http.ResponseWriter) {
+	for _, name := range []string{sessionCookieName, refreshCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			Expires:  time.Unix(0, 0),
+			MaxAge:   -1,
+			HttpOnly: true,
+		})
+	}
+}
+
+// RequireAuth пропускает к API только запросы с действующей сессией и кладет врача в контекст
+func (h *Handler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		doctor, err := h.sessionUseCase.Authenticate(r.Context(), sessionToken(r))
+		if err != nil {
+			h.setCORSHeaders(w)
+			h.writeError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, withDoctor(r, doctor))
+	})
+}
+
+// permissionFunc определяет право, необходимое для запроса
+type permissionFunc func(r *http.Request) domain.Permission
+
+// byMethod требует право на чтение для GET и право на изменение для остальных методов
+func byMethod(read, write domain.Permission) permissionFunc {
+	return func(r *http.Request) domain.Permission {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return read
+		}
+		return write
+	}
+}
+
+// RequirePermission пропускает запрос, только если у врача из контекста есть необходимое право.
+// Должен вызываться внутри RequireAuth.
+func (h *Handler) RequirePermission(permission permissionFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		doctor, ok := CurrentDoctor(r.Context())
+		if !ok || !doctor.Can(permission(r)) {
+			h.setCORSHeaders(w)
+			h.writeError(w, r, domain.ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePageSession перенаправляет на страницу входа при открытии HTML страниц без действующей сессии.
+// Истекший токен доступа продлевается по токену обновления.
+func (h *Handler) RequirePageSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isProtectedPage(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		doctor, err := h.sessionUseCase.Authenticate(r.Context(), sessionToken(r))
+		if err != nil && refreshToken(r) != "" {
+			var tokens *domain.SessionTokens
+			doctor, tokens, err = h.sessionUseCase.Refresh(r.Context(), refreshToken(r))
+			if err == nil {
+				setSessionCookies(w, r, tokens)
+			}
+		}
+
+		if err != nil {
+			http.Redirect(w, r, "/login.html", http.StatusFound)
+			return
+		}
+
+		next.ServeHTTP(w, withDoctor(r, doctor))
+	})
+}
+
+// isProtectedPage сообщает, что путь ведет на HTML страницу, кроме страницы входа
+func isProtectedPage(path string) bool {
+	if path == "/" {
+		return true
+	}
+	if !strings.HasSuffix(path, ".html") {
+		return false
+	}
+	return path != "/login.html" && path != "/static/login.html"
+}
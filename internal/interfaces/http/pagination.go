@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// cursorPrefix отличает курсор этого API от произвольной строки в base64
+const cursorPrefix = "offset:"
+
+// pagination описывает страницу списка в ответе API. NextCursor пуст на последней странице;
+// чтобы получить следующую страницу, его передают в параметре cursor
+type pagination struct {
+	Total      int    `json:"total"`
+	Offset     int    `json:"offset"`
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// encodeCursor кодирует смещение следующей страницы в непрозрачный курсор
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor возвращает смещение, закодированное в курсоре
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, domain.NewValidationError("cursor", domain.FieldInvalid, "Invalid cursor")
+	}
+
+	value, ok := strings.CutPrefix(string(data), cursorPrefix)
+	if !ok {
+		return 0, domain.NewValidationError("cursor", domain.FieldInvalid, "Invalid cursor")
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, domain.NewValidationError("cursor", domain.FieldInvalid, "Invalid cursor")
+	}
+	return offset, nil
+}
+
+// intParam связывает целочисленный параметр запроса с полем фильтра
+type intParam struct {
+	name   string
+	target *int
+}
+
+// parseIntParams разбирает целочисленные параметры запроса; отсутствующие параметры не меняют target
+func parseIntParams(params url.Values, targets []intParam) error {
+	for _, param := range targets {
+		value := params.Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return domain.NewValidationError(param.name, domain.FieldInvalid, "Invalid "+param.name)
+		}
+		*param.target = parsed
+	}
+	return nil
+}
+
+// parsePage разбирает параметры limit, offset и cursor; cursor имеет приоритет над offset
+func parsePage(params url.Values) (domain.Page, error) {
+	var page domain.Page
+	if err := parseIntParams(params, []intParam{{"limit", &page.Limit}, {"offset", &page.Offset}}); err != nil {
+		return domain.Page{}, err
+	}
+
+	if cursor := params.Get("cursor"); cursor != "" {
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return domain.Page{}, err
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// parseSort разбирает параметр sort: имя поля, с минусом в начале — по убыванию ("-created_at")
+func parseSort(params url.Values) domain.Sort {
+	field := params.Get("sort")
+	if desc, ok := strings.CutPrefix(field, "-"); ok {
+		return domain.Sort{Field: desc, Desc: true}
+	}
+	return domain.Sort{Field: field}
+}
+
+// parseDateFilter разбирает параметры date_from и date_to (YYYY-MM-DD); date_to включается в выборку целиком
+func (h *Handler) parseDateFilter(params url.Values) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if value := params.Get("date_from"); value != "" {
+		parsed, err := h.parseDate(value)
+		if err != nil {
+			return nil, nil, domain.NewValidationError("date_from", domain.FieldInvalid, "Invalid date_from, expected YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+
+	if value := params.Get("date_to"); value != "" {
+		parsed, err := h.parseDate(value)
+		if err != nil {
+			return nil, nil, domain.NewValidationError("date_to", domain.FieldInvalid, "Invalid date_to, expected YYYY-MM-DD")
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		to = &parsed
+	}
+
+	return from, to, nil
+}
+
+// writeListResponse записывает страницу списка в формате writeSuccessResponse с описанием страницы в поле pagination
+func (h *Handler) writeListResponse(w http.ResponseWriter, message string, data interface{}, count, total int, page domain.Page) {
+	info := pagination{Total: total, Offset: page.Offset, Count: count}
+	if next := page.Offset + count; count > 0 && next < total {
+		info.NextCursor = encodeCursor(next)
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"message":    message,
+		"data":       data,
+		"pagination": info,
+	})
+}
@@ -1,13 +1,42 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/sdk17/crmstom/internal/domain"
 )
 
+// Коды ошибок PostgreSQL, которые репозиторий переводит в доменные ошибки
+const (
+	pqExclusionViolation  = "23P01" // нарушение EXCLUDE-ограничения
+	pqForeignKeyViolation = "23503" // ссылка на несуществующую строку
+	pqUniqueViolation     = "23505" // нарушение уникальности
+)
+
+// appointmentSelect общий SELECT для чтения записей вместе с именами пациента, услуги и врача
+const appointmentSelect = `SELECT a.id, a.patient_id, a.service_id, a.doctor_id, a.appointment_date, a.status, a.price, a.duration_minutes, a.notes, a.created_at, a.updated_at,
+			  a.confirmed_at, a.arrived_at, a.started_at, a.completed_at, a.cancelled_at, a.no_show_at, a.rescheduled_at,
+			  a.cancellation_reason, a.rescheduled_from_id,
+			  s.name as service_name, p.name as patient_name, d.name as doctor_name
+			  FROM appointments a
+			  LEFT JOIN services s ON a.service_id = s.id AND s.deleted_at IS NULL
+			  LEFT JOIN patients p ON a.patient_id = p.id AND p.deleted_at IS NULL
+			  LEFT JOIN doctors d ON a.doctor_id = d.id AND d.deleted_at IS NULL`
+
+// appointmentSortColumns сопоставляет полям сортировки из domain.AppointmentSortFields выражения SQL
+var appointmentSortColumns = map[string]string{
+	"date":         "a.appointment_date",
+	"status":       "a.status",
+	"price":        "a.price",
+	"patient_name": "p.name",
+	"created_at":   "a.created_at",
+}
+
 type AppointmentRepository struct {
 	db *sql.DB
 }
@@ -16,69 +45,37 @@ func NewAppointmentRepository(db *sql.DB) *AppointmentRepository {
 	return &AppointmentRepository{db: db}
 }
 
-func (r *AppointmentRepository) Create(appointment *domain.Appointment) error {
-	// Получаем service_id по названию услуги
-	var serviceID int
-	serviceQuery := `SELECT id FROM services WHERE name = $1 AND deleted_at IS NULL LIMIT 1`
-	err := r.db.QueryRow(serviceQuery, appointment.Service).Scan(&serviceID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("услуга '%s' не найдена", appointment.Service)
-		}
-		return err
-	}
-
-	// Получаем doctor_id по имени врача
-	var doctorID sql.NullInt64
-	if appointment.Doctor != "" {
-		doctorQuery := `SELECT id FROM doctors WHERE name = $1 AND deleted_at IS NULL LIMIT 1`
-		var dID int
-		err = r.db.QueryRow(doctorQuery, appointment.Doctor).Scan(&dID)
-		if err != nil && err != sql.ErrNoRows {
-			return err
-		}
-		if err == nil {
-			doctorID.Int64 = int64(dID)
-			doctorID.Valid = true
-		}
-	}
-
-	query := `INSERT INTO appointments (patient_id, service_id, doctor_id, appointment_date, status, price, duration_minutes, notes)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
-
-	err = r.db.QueryRow(query, appointment.PatientID, serviceID, doctorID,
-		appointment.Date, appointment.Status, appointment.Price, appointment.Duration, appointment.Notes).
-		Scan(&appointment.ID, &appointment.CreatedAt, &appointment.UpdatedAt)
-
-	return err
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func (r *AppointmentRepository) GetByID(id int) (*domain.Appointment, error) {
-	query := `SELECT a.id, a.patient_id, a.appointment_date, a.status, a.price, a.duration_minutes, a.notes, a.created_at, a.updated_at,
-			  s.name as service_name, p.name as patient_name, d.name as doctor_name
-			  FROM appointments a
-			  LEFT JOIN services s ON a.service_id = s.id AND s.deleted_at IS NULL
-			  LEFT JOIN patients p ON a.patient_id = p.id AND p.deleted_at IS NULL
-			  LEFT JOIN doctors d ON a.doctor_id = d.id AND d.deleted_at IS NULL
-			  WHERE a.id = $1 AND a.deleted_at IS NULL`
-
+// scanAppointment читает одну запись из результата appointmentSelect
+func scanAppointment(row rowScanner) (*domain.Appointment, error) {
 	appointment := &domain.Appointment{}
 	var serviceName sql.NullString
 	var patientName sql.NullString
 	var doctorName sql.NullString
-	err := r.db.QueryRow(query, id).Scan(
-		&appointment.ID, &appointment.PatientID,
+	var serviceID sql.NullInt64
+	var doctorID sql.NullInt64
+	var rescheduledFromID sql.NullInt64
+	err := row.Scan(
+		&appointment.ID, &appointment.PatientID, &serviceID, &doctorID,
 		&appointment.Date, &appointment.Status, &appointment.Price, &appointment.Duration, &appointment.Notes,
-		&appointment.CreatedAt, &appointment.UpdatedAt, &serviceName, &patientName, &doctorName,
+		&appointment.CreatedAt, &appointment.UpdatedAt,
+		&appointment.ConfirmedAt, &appointment.ArrivedAt, &appointment.StartedAt, &appointment.CompletedAt,
+		&appointment.CancelledAt, &appointment.NoShowAt, &appointment.RescheduledAt,
+		&appointment.CancellationReason, &rescheduledFromID,
+		&serviceName, &patientName, &doctorName,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("запись с ID %d не найдена", id)
-		}
 		return nil, err
 	}
 
+	appointment.ServiceID = int(serviceID.Int64)
+	appointment.DoctorID = int(doctorID.Int64)
+	appointment.RescheduledFromID = int(rescheduledFromID.Int64)
+
 	if serviceName.Valid {
 		appointment.Service = serviceName.String
 	} else {
@@ -100,254 +97,297 @@ func (r *AppointmentRepository) GetByID(id int) (*domain.Appointment, error) {
 	return appointment, nil
 }
 
-func (r *AppointmentRepository) GetAll() ([]*domain.Appointment, error) {
-	query := `SELECT a.id, a.patient_id, a.appointment_date, a.status, a.price, a.duration_minutes, a.notes, a.created_at, a.updated_at,
-			  s.name as service_name, p.name as patient_name, d.name as doctor_name
-			  FROM appointments a
-			  LEFT JOIN services s ON a.service_id = s.id AND s.deleted_at IS NULL
-			  LEFT JOIN patients p ON a.patient_id = p.id AND p.deleted_at IS NULL
-			  LEFT JOIN doctors d ON a.doctor_id = d.id AND d.deleted_at IS NULL
-			  WHERE a.deleted_at IS NULL
-			  ORDER BY a.appointment_date DESC`
-
-	rows, err := r.db.Query(query)
+// queryAppointments выполняет запрос и читает все записи из результата
+func (r *AppointmentRepository) queryAppointments(ctx context.Context, query string, args ...interface{}) ([]*domain.Appointment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var appointments []*domain.Appointment
+	appointments := make([]*domain.Appointment, 0)
 	for rows.Next() {
-		appointment := &domain.Appointment{}
-		var serviceName sql.NullString
-		var patientName sql.NullString
-		var doctorName sql.NullString
-		err := rows.Scan(
-			&appointment.ID, &appointment.PatientID,
-			&appointment.Date, &appointment.Status, &appointment.Price, &appointment.Duration, &appointment.Notes,
-			&appointment.CreatedAt, &appointment.UpdatedAt, &serviceName, &patientName, &doctorName,
-		)
+		appointment, err := scanAppointment(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if serviceName.Valid {
-			appointment.Service = serviceName.String
-		} else {
-			appointment.Service = "Неизвестная услуга"
-		}
-
-		if patientName.Valid {
-			appointment.PatientName = patientName.String
-		} else {
-			appointment.PatientName = "Неизвестно"
-		}
-
-		if doctorName.Valid {
-			appointment.Doctor = doctorName.String
-		}
-
-		appointment.Time = appointment.Date.Format("15:04")
-
 		appointments = append(appointments, appointment)
 	}
 
 	return appointments, rows.Err()
 }
 
-func (r *AppointmentRepository) GetByDateRange(startDate, endDate time.Time) ([]*domain.Appointment, error) {
-	query := `SELECT a.id, a.patient_id, a.appointment_date, a.status, a.price, a.duration_minutes, a.notes, a.created_at, a.updated_at,
-			  s.name as service_name, p.name as patient_name, d.name as doctor_name
-			  FROM appointments a
-			  LEFT JOIN services s ON a.service_id = s.id AND s.deleted_at IS NULL
-			  LEFT JOIN patients p ON a.patient_id = p.id AND p.deleted_at IS NULL
-			  LEFT JOIN doctors d ON a.doctor_id = d.id AND d.deleted_at IS NULL
-			  WHERE a.deleted_at IS NULL AND a.appointment_date BETWEEN $1 AND $2
-			  ORDER BY a.appointment_date`
+// lockAppointment читает и блокирует запись до конца транзакции, чтобы записать ее состояние в аудит
+func lockAppointment(ctx context.Context, tx *sql.Tx, id int) (*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.id = $1 AND a.deleted_at IS NULL
+			  FOR UPDATE OF a`
 
-	rows, err := r.db.Query(query, startDate, endDate)
-	if err != nil {
-		return nil, err
+	appointment, err := scanAppointment(tx.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", id)
 	}
-	defer rows.Close()
+	return appointment, err
+}
 
-	var appointments []*domain.Appointment
-	for rows.Next() {
-		appointment := &domain.Appointment{}
-		var serviceName sql.NullString
-		var patientName sql.NullString
-		var doctorName sql.NullString
-		err := rows.Scan(
-			&appointment.ID, &appointment.PatientID,
-			&appointment.Date, &appointment.Status, &appointment.Price, &appointment.Duration, &appointment.Notes,
-			&appointment.CreatedAt, &appointment.UpdatedAt, &serviceName, &patientName, &doctorName,
-		)
-		if err != nil {
-			return nil, err
+func (r *AppointmentRepository) Create(ctx context.Context, appointment *domain.Appointment) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO appointments (patient_id, service_id, doctor_id, appointment_date, status, price, duration_minutes, notes, rescheduled_from_id)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRowContext(ctx, query, appointment.PatientID, appointment.ServiceID, nullableID(appointment.DoctorID),
+			appointment.Date, appointment.Status, appointment.Price, appointment.Duration, appointment.Notes,
+			nullableID(appointment.RescheduledFromID)).
+			Scan(&appointment.ID, &appointment.CreatedAt, &appointment.UpdatedAt)
+		if isExclusionViolation(err) {
+			return r.conflictError(ctx, appointment)
 		}
-
-		if serviceName.Valid {
-			appointment.Service = serviceName.String
-		} else {
-			appointment.Service = "Неизвестная услуга"
+		if isForeignKeyViolation(err) {
+			return appointmentReferenceError(err, appointment)
 		}
-
-		if patientName.Valid {
-			appointment.PatientName = patientName.String
-		} else {
-			appointment.PatientName = "Неизвестно"
+		if err != nil {
+			return err
 		}
 
-		if doctorName.Valid {
-			appointment.Doctor = doctorName.String
+		created, err := lockAppointment(ctx, tx, appointment.ID)
+		if err != nil {
+			return err
 		}
 
-		appointment.Time = appointment.Date.Format("15:04")
-		appointments = append(appointments, appointment)
-	}
-
-	return appointments, rows.Err()
+		return writeAudit(ctx, tx, domain.AuditEntityAppointment, appointment.ID, domain.AuditActionCreate, nil, created)
+	})
 }
 
-func (r *AppointmentRepository) Update(appointment *domain.Appointment) error {
-	// Lookup service_id by service name
-	var serviceID int
-	serviceQuery := `SELECT id FROM services WHERE name = $1 AND deleted_at IS NULL LIMIT 1`
-	err := r.db.QueryRow(serviceQuery, appointment.Service).Scan(&serviceID)
+func (r *AppointmentRepository) GetByID(ctx context.Context, id int) (*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.id = $1 AND a.deleted_at IS NULL`
+
+	appointment, err := scanAppointment(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("услуга '%s' не найдена", appointment.Service)
+			return nil, domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", id)
 		}
-		return err
+		return nil, err
 	}
 
-	// Lookup doctor_id by doctor name
-	var doctorID sql.NullInt64
-	if appointment.Doctor != "" {
-		doctorQuery := `SELECT id FROM doctors WHERE name = $1 AND deleted_at IS NULL LIMIT 1`
-		var dID int
-		err = r.db.QueryRow(doctorQuery, appointment.Doctor).Scan(&dID)
-		if err != nil && err != sql.ErrNoRows {
-			return err
-		}
-		if err == nil {
-			doctorID.Int64 = int64(dID)
-			doctorID.Valid = true
-		}
-	}
+	return appointment, nil
+}
 
-	query := `UPDATE appointments SET patient_id = $1, service_id = $2, doctor_id = $3, appointment_date = $4,
-			  status = $5, notes = $6, price = $7, duration_minutes = $8, updated_at = CURRENT_TIMESTAMP
-			  WHERE id = $9 AND deleted_at IS NULL`
+func (r *AppointmentRepository) GetAll(ctx context.Context) ([]*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.deleted_at IS NULL
+			  ORDER BY a.appointment_date DESC`
 
-	result, err := r.db.Exec(query, appointment.PatientID, serviceID, doctorID,
-		appointment.Date, appointment.Status, appointment.Notes, appointment.Price, appointment.Duration, appointment.ID)
-	if err != nil {
-		return err
-	}
+	return r.queryAppointments(ctx, query)
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
+// List получает страницу записей по фильтру и общее число подходящих записей
+func (r *AppointmentRepository) List(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	where := &whereBuilder{}
+	where.addRaw("a.deleted_at IS NULL")
+	if filter.PatientID > 0 {
+		where.add("a.patient_id = $%d", filter.PatientID)
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("запись с ID %d не найдена", appointment.ID)
+	if filter.DoctorID > 0 {
+		where.add("a.doctor_id = $%d", filter.DoctorID)
 	}
-
-	return nil
-}
-
-func (r *AppointmentRepository) Delete(id int) error {
-	query := `UPDATE appointments SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
-
-	result, err := r.db.Exec(query, id)
-	if err != nil {
-		return err
+	if filter.ServiceID > 0 {
+		where.add("a.service_id = $%d", filter.ServiceID)
+	}
+	if filter.Status != "" {
+		where.add("a.status = $%d", filter.Status)
+	}
+	if filter.From != nil {
+		where.add("a.appointment_date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("a.appointment_date < $%d", *filter.To)
+	}
+	if filter.RescheduledFromID > 0 {
+		where.add("a.rescheduled_from_id = $%d", filter.RescheduledFromID)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	total, err := countRows(ctx, r.db, "FROM appointments a", where)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("ошибка подсчета записей: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("запись с ID %d не найдена", id)
+	query := appointmentSelect + where.sql() +
+		orderBy(filter.Sort, appointmentSortColumns, domain.Sort{Field: "date", Desc: true}, "a.id") +
+		where.limitOffset(filter.Page)
+	appointments, err := r.queryAppointments(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения списка записей: %w", err)
 	}
 
-	return nil
+	return appointments, total, nil
 }
 
-func (r *AppointmentRepository) GetByPatientID(patientID int) ([]*domain.Appointment, error) {
-	query := `SELECT a.id, a.patient_id, a.appointment_date, a.status, a.price, a.duration_minutes, a.notes, a.created_at, a.updated_at,
-			  s.name as service_name, p.name as patient_name, d.name as doctor_name
-			  FROM appointments a
-			  LEFT JOIN services s ON a.service_id = s.id AND s.deleted_at IS NULL
-			  LEFT JOIN patients p ON a.patient_id = p.id AND p.deleted_at IS NULL
-			  LEFT JOIN doctors d ON a.doctor_id = d.id AND d.deleted_at IS NULL
-			  WHERE a.deleted_at IS NULL AND a.patient_id = $1
-			  ORDER BY a.appointment_date DESC`
+func (r *AppointmentRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.deleted_at IS NULL AND a.appointment_date BETWEEN $1 AND $2
+			  ORDER BY a.appointment_date`
 
-	rows, err := r.db.Query(query, patientID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return r.queryAppointments(ctx, query, startDate, endDate)
+}
 
-	var appointments []*domain.Appointment
-	for rows.Next() {
-		appointment := &domain.Appointment{}
-		var serviceName sql.NullString
-		var patientName sql.NullString
-		var doctorName sql.NullString
-		err := rows.Scan(
-			&appointment.ID, &appointment.PatientID,
-			&appointment.Date, &appointment.Status, &appointment.Price, &appointment.Duration, &appointment.Notes,
-			&appointment.CreatedAt, &appointment.UpdatedAt, &serviceName, &patientName, &doctorName,
-		)
+func (r *AppointmentRepository) Update(ctx context.Context, appointment *domain.Appointment) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockAppointment(ctx, tx, appointment.ID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		// Цена приема с процедурами всегда равна сумме их цен, переданная цена применяется только к приему без процедур
+		query := `UPDATE appointments SET patient_id = $1, service_id = $2, doctor_id = $3, appointment_date = $4,
+				  status = $5, notes = $6, duration_minutes = $8,
+				  price = COALESCE((SELECT SUM(t.price) FROM appointment_treatments t
+				                    WHERE t.appointment_id = $17 AND t.deleted_at IS NULL), $7),
+				  confirmed_at = $9, arrived_at = $10, started_at = $11, completed_at = $12, cancelled_at = $13,
+				  no_show_at = $14, rescheduled_at = $15, cancellation_reason = $16, updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $17 AND deleted_at IS NULL RETURNING price`
+
+		err = tx.QueryRowContext(ctx, query, appointment.PatientID, appointment.ServiceID, nullableID(appointment.DoctorID),
+			appointment.Date, appointment.Status, appointment.Notes, appointment.Price, appointment.Duration,
+			appointment.ConfirmedAt, appointment.ArrivedAt, appointment.StartedAt, appointment.CompletedAt, appointment.CancelledAt,
+			appointment.NoShowAt, appointment.RescheduledAt, appointment.CancellationReason, appointment.ID).Scan(&appointment.Price)
+		if isExclusionViolation(err) {
+			return r.conflictError(ctx, appointment)
+		}
+		if isForeignKeyViolation(err) {
+			return appointmentReferenceError(err, appointment)
+		}
+		if err != nil {
+			return err
 		}
 
-		if serviceName.Valid {
-			appointment.Service = serviceName.String
-		} else {
-			appointment.Service = "Неизвестная услуга"
+		after, err := lockAppointment(ctx, tx, appointment.ID)
+		if err != nil {
+			return err
 		}
 
-		if patientName.Valid {
-			appointment.PatientName = patientName.String
-		} else {
-			appointment.PatientName = "Неизвестно"
+		return writeAudit(ctx, tx, domain.AuditEntityAppointment, appointment.ID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+func (r *AppointmentRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockAppointment(ctx, tx, id)
+		if err != nil {
+			return err
 		}
 
-		if doctorName.Valid {
-			appointment.Doctor = doctorName.String
+		query := `UPDATE appointments SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
 		}
 
-		appointment.Time = appointment.Date.Format("15:04")
-		appointments = append(appointments, appointment)
-	}
+		return writeAudit(ctx, tx, domain.AuditEntityAppointment, id, domain.AuditActionDelete, before, nil)
+	})
+}
 
-	return appointments, rows.Err()
+func (r *AppointmentRepository) GetByPatientID(ctx context.Context, patientID int) ([]*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.deleted_at IS NULL AND a.patient_id = $1
+			  ORDER BY a.appointment_date DESC`
+
+	return r.queryAppointments(ctx, query, patientID)
 }
 
-func (r *AppointmentRepository) GetByDate(date time.Time) ([]*domain.Appointment, error) {
+func (r *AppointmentRepository) GetByDate(ctx context.Context, date time.Time) ([]*domain.Appointment, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	return r.GetByDateRange(startOfDay, endOfDay)
+	return r.GetByDateRange(ctx, startOfDay, endOfDay)
 }
 
-func (r *AppointmentRepository) CheckTimeConflict(date time.Time, timeStr string, excludeID int) (bool, error) {
-	query := `SELECT COUNT(*) FROM appointments
-			  WHERE deleted_at IS NULL AND appointment_date = $1 AND id != $2`
+// activeAppointmentCondition отбирает записи, которые занимают время врача (см. domain.AppointmentStatus.Active)
+const activeAppointmentCondition = `a.status NOT IN ('cancelled', 'no_show', 'rescheduled')`
+
+// FindConflicts возвращает активные записи того же врача, пересекающиеся по времени с appointment.
+// Интервал записи — [appointment_date, appointment_date + duration_minutes).
+func (r *AppointmentRepository) FindConflicts(ctx context.Context, appointment *domain.Appointment) ([]*domain.Appointment, error) {
+	if appointment.DoctorID == 0 {
+		return nil, nil
+	}
+
+	query := appointmentSelect + `
+			  WHERE a.deleted_at IS NULL AND ` + activeAppointmentCondition + ` AND a.id <> $1 AND a.doctor_id = $2
+			  AND a.appointment_date < $4
+			  AND a.appointment_date + COALESCE(a.duration_minutes, 0) * INTERVAL '1 minute' > $3
+			  ORDER BY a.appointment_date`
 
-	var count int
-	err := r.db.QueryRow(query, date, excludeID).Scan(&count)
+	return r.queryAppointments(ctx, query, appointment.ID, appointment.DoctorID,
+		appointment.Date, appointment.EndTime())
+}
+
+// FindOverlapping возвращает активные записи врача, пересекающиеся с интервалом [start, end),
+// включая записи, начавшиеся до start
+func (r *AppointmentRepository) FindOverlapping(ctx context.Context, doctorID int, start, end time.Time) ([]*domain.Appointment, error) {
+	query := appointmentSelect + `
+			  WHERE a.deleted_at IS NULL AND ` + activeAppointmentCondition + ` AND a.doctor_id = $1
+			  AND a.appointment_date < $3
+			  AND a.appointment_date + COALESCE(a.duration_minutes, 0) * INTERVAL '1 minute' > $2
+			  ORDER BY a.appointment_date`
+
+	return r.queryAppointments(ctx, query, doctorID, start, end)
+}
+
+// conflictError собирает ошибку конфликта после срабатывания ограничения в БД.
+// Транзакция после ошибки прервана, поэтому пересечения читаются вне ее
+func (r *AppointmentRepository) conflictError(ctx context.Context, appointment *domain.Appointment) error {
+	conflicts, err := r.FindConflicts(withoutTx(ctx), appointment)
 	if err != nil {
-		return false, err
+		return err
+	}
+	return &domain.AppointmentConflictError{Conflicts: conflicts}
+}
+
+// appointmentReferenceError переводит нарушение внешнего ключа записи в ошибку отсутствующего пациента, услуги, врача
+// или исходной записи переноса; ограничения названы по умолчанию PostgreSQL: <таблица>_<колонка>_fkey
+func appointmentReferenceError(err error, appointment *domain.Appointment) error {
+	switch violatedConstraint(err) {
+	case "appointments_patient_id_fkey":
+		return domain.ErrPatientNotFound.WithMessage("пациент с ID %d не найден", appointment.PatientID)
+	case "appointments_service_id_fkey":
+		return domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", appointment.ServiceID)
+	case "appointments_doctor_id_fkey":
+		return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", appointment.DoctorID)
+	case "appointments_rescheduled_from_id_fkey":
+		return domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", appointment.RescheduledFromID)
 	}
+	return domain.NewValidationError("", domain.FieldInvalid, "referenced patient, service or doctor does not exist")
+}
+
+// isExclusionViolation проверяет, что ошибка вызвана EXCLUDE-ограничением
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqExclusionViolation
+}
+
+// isForeignKeyViolation проверяет, что ошибка вызвана внешним ключом на несуществующую строку
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation
+}
+
+// violatedConstraint возвращает имя ограничения, нарушение которого вызвало ошибку
+func violatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
+
+// isUniqueViolation проверяет, что ошибка вызвана уникальным индексом
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
 
-	return count > 0, nil
+// nullableID возвращает NULL для незаданного (нулевого) идентификатора
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
 }
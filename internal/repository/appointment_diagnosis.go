@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// appointmentDiagnosisSelect общий SELECT для чтения диагнозов приема
+const appointmentDiagnosisSelect = `SELECT id, appointment_id, code, COALESCE(tooth, 0), comment, COALESCE(doctor_id, 0), created_at
+			  FROM appointment_diagnoses`
+
+type AppointmentDiagnosisRepository struct {
+	db *sql.DB
+}
+
+func NewAppointmentDiagnosisRepository(db *sql.DB) *AppointmentDiagnosisRepository {
+	return &AppointmentDiagnosisRepository{db: db}
+}
+
+// scanAppointmentDiagnosis читает один диагноз из результата appointmentDiagnosisSelect
+func scanAppointmentDiagnosis(row rowScanner) (*domain.AppointmentDiagnosis, error) {
+	diagnosis := &domain.AppointmentDiagnosis{}
+	err := row.Scan(&diagnosis.ID, &diagnosis.AppointmentID, &diagnosis.Code, &diagnosis.Tooth, &diagnosis.Comment,
+		&diagnosis.DoctorID, &diagnosis.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return diagnosis, nil
+}
+
+func (r *AppointmentDiagnosisRepository) GetByID(ctx context.Context, id int) (*domain.AppointmentDiagnosis, error) {
+	query := appointmentDiagnosisSelect + ` WHERE id = $1 AND deleted_at IS NULL`
+
+	diagnosis, err := scanAppointmentDiagnosis(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAppointmentDiagnosisNotFound.WithMessage("диагноз с ID %d не найден", id)
+		}
+		return nil, err
+	}
+
+	return diagnosis, nil
+}
+
+// GetByAppointmentID получает диагнозы приема в порядке добавления
+func (r *AppointmentDiagnosisRepository) GetByAppointmentID(ctx context.Context, appointmentID int) ([]*domain.AppointmentDiagnosis, error) {
+	query := appointmentDiagnosisSelect + ` WHERE appointment_id = $1 AND deleted_at IS NULL ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, appointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения диагнозов записи: %w", err)
+	}
+	defer rows.Close()
+
+	diagnoses := make([]*domain.AppointmentDiagnosis, 0)
+	for rows.Next() {
+		diagnosis, err := scanAppointmentDiagnosis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения диагноза: %w", err)
+		}
+		diagnoses = append(diagnoses, diagnosis)
+	}
+
+	return diagnoses, rows.Err()
+}
+
+func (r *AppointmentDiagnosisRepository) Create(ctx context.Context, diagnosis *domain.AppointmentDiagnosis) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO appointment_diagnoses (appointment_id, code, tooth, comment, doctor_id)
+				  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+		err := tx.QueryRowContext(ctx, query, diagnosis.AppointmentID, diagnosis.Code, nullableID(diagnosis.Tooth),
+			diagnosis.Comment, nullableID(diagnosis.DoctorID)).Scan(&diagnosis.ID, &diagnosis.CreatedAt)
+		if isUniqueViolation(err) {
+			return domain.ErrAppointmentDiagnosisExists.WithMessage("диагноз %s уже поставлен на этом приеме", diagnosis.Code)
+		}
+		if isForeignKeyViolation(err) {
+			if violatedConstraint(err) == "appointment_diagnoses_doctor_id_fkey" {
+				return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", diagnosis.DoctorID)
+			}
+			return domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", diagnosis.AppointmentID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityAppointmentDiagnosis, diagnosis.ID, domain.AuditActionCreate, nil, diagnosis)
+	})
+}
+
+func (r *AppointmentDiagnosisRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := appointmentDiagnosisSelect + ` WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+		before, err := scanAppointmentDiagnosis(tx.QueryRowContext(ctx, query, id))
+		if err == sql.ErrNoRows {
+			return domain.ErrAppointmentDiagnosisNotFound.WithMessage("диагноз с ID %d не найден", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE appointment_diagnoses SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1`, id); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityAppointmentDiagnosis, id, domain.AuditActionDelete, before, nil)
+	})
+}
+
+// GetReport считает завершенные приемы и пациентов по кодам диагнозов. Диагноз приема и такой же код процедуры
+// того же приема учитываются один раз
+func (r *AppointmentDiagnosisRepository) GetReport(ctx context.Context, filter domain.DiagnosisReportFilter) ([]*domain.DiagnosisReportRow, error) {
+	group := "c.code"
+	if filter.ByCategory {
+		group = "split_part(c.code, '.', 1)"
+	}
+
+	var where whereBuilder
+	where.addRaw("a.deleted_at IS NULL")
+	where.add("a.status = $%d", domain.StatusCompleted)
+	if filter.From != nil {
+		where.add("a.appointment_date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("a.appointment_date < $%d", *filter.To)
+	}
+	if filter.DoctorID > 0 {
+		where.add("a.doctor_id = $%d", filter.DoctorID)
+	}
+
+	query := `WITH coded AS (
+				  SELECT appointment_id, code FROM appointment_diagnoses WHERE deleted_at IS NULL
+				  UNION
+				  SELECT appointment_id, diagnosis_code FROM appointment_treatments WHERE deleted_at IS NULL AND diagnosis_code <> ''
+			  )
+			  SELECT ` + group + `, COUNT(DISTINCT a.id), COUNT(DISTINCT a.patient_id)
+			  FROM coded c
+			  JOIN appointments a ON a.id = c.appointment_id` + where.sql() + `
+			  GROUP BY 1 ORDER BY 2 DESC, 1`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения отчета по диагнозам: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]*domain.DiagnosisReportRow, 0)
+	for rows.Next() {
+		row := &domain.DiagnosisReportRow{}
+		if err := rows.Scan(&row.Code, &row.Visits, &row.Patients); err != nil {
+			return nil, fmt.Errorf("ошибка чтения отчета по диагнозам: %w", err)
+		}
+		report = append(report, row)
+	}
+
+	return report, rows.Err()
+}
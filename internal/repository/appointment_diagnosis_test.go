@@ -0,0 +1,122 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppointmentDiagnosisRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	patientRepo := NewPatientRepository(testDB.DB)
+	serviceRepo := NewServiceRepository(testDB.DB)
+	appointmentRepo := NewAppointmentRepository(testDB.DB)
+	treatmentRepo := NewTreatmentRepository(testDB.DB)
+	diagnosisRepo := NewAppointmentDiagnosisRepository(testDB.DB)
+
+	createTestAppointment := func(t *testing.T, patientID, serviceID int, date time.Time, status domain.AppointmentStatus) *domain.Appointment {
+		appointment := &domain.Appointment{
+			PatientID: patientID, ServiceID: serviceID, Date: date, Duration: 30, Status: status, Price: domain.Tenge(10000),
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, appointment))
+		return appointment
+	}
+
+	t.Run("CreateAndDelete", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := &domain.Patient{Name: "Diagnosis Patient", Phone: "+7 777 000 0002"}
+		require.NoError(t, patientRepo.Create(ctx, patient))
+		service := &domain.Service{Name: "Exam", Type: "Consultation"}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+		appointment := createTestAppointment(t, patient.ID, service.ID, time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC), domain.StatusInProgress)
+
+		diagnosis := &domain.AppointmentDiagnosis{AppointmentID: appointment.ID, Code: "K04.0", Tooth: 36, Comment: "острый"}
+		require.NoError(t, diagnosisRepo.Create(ctx, diagnosis))
+		assert.Greater(t, diagnosis.ID, 0)
+
+		duplicate := &domain.AppointmentDiagnosis{AppointmentID: appointment.ID, Code: "K04.0", Tooth: 36}
+		assert.ErrorIs(t, diagnosisRepo.Create(ctx, duplicate), domain.ErrAppointmentDiagnosisExists)
+
+		unknownDoctor := &domain.AppointmentDiagnosis{AppointmentID: appointment.ID, Code: "K02.1", DoctorID: 9999}
+		assert.ErrorIs(t, diagnosisRepo.Create(ctx, unknownDoctor), domain.ErrDoctorNotFound)
+
+		found, err := diagnosisRepo.GetByID(ctx, diagnosis.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "K04.0", found.Code)
+		assert.Equal(t, 36, found.Tooth)
+		assert.Equal(t, 0, found.DoctorID)
+
+		require.NoError(t, diagnosisRepo.Delete(ctx, diagnosis.ID))
+		diagnoses, err := diagnosisRepo.GetByAppointmentID(ctx, appointment.ID)
+		require.NoError(t, err)
+		assert.Empty(t, diagnoses)
+
+		_, err = diagnosisRepo.GetByID(ctx, diagnosis.ID)
+		assert.ErrorIs(t, err, domain.ErrAppointmentDiagnosisNotFound)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		first := &domain.Patient{Name: "First Patient", Phone: "+7 777 000 0003"}
+		require.NoError(t, patientRepo.Create(ctx, first))
+		second := &domain.Patient{Name: "Second Patient", Phone: "+7 777 000 0004"}
+		require.NoError(t, patientRepo.Create(ctx, second))
+		service := &domain.Service{Name: "Filling", Type: "Treatment"}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		visit := createTestAppointment(t, first.ID, service.ID, time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC), domain.StatusCompleted)
+		require.NoError(t, diagnosisRepo.Create(ctx, &domain.AppointmentDiagnosis{AppointmentID: visit.ID, Code: "K02.1", Tooth: 36}))
+		require.NoError(t, treatmentRepo.Create(ctx, &domain.Treatment{
+			AppointmentID: visit.ID, ServiceID: service.ID, Tooth: 36, DiagnosisCode: "K02.1", Price: domain.Tenge(20000),
+		}))
+
+		secondVisit := createTestAppointment(t, first.ID, service.ID, time.Date(2026, 10, 8, 10, 0, 0, 0, time.UTC), domain.StatusCompleted)
+		require.NoError(t, diagnosisRepo.Create(ctx, &domain.AppointmentDiagnosis{AppointmentID: secondVisit.ID, Code: "K02.1", Tooth: 37}))
+
+		otherVisit := createTestAppointment(t, second.ID, service.ID, time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC), domain.StatusCompleted)
+		require.NoError(t, treatmentRepo.Create(ctx, &domain.Treatment{
+			AppointmentID: otherVisit.ID, ServiceID: service.ID, DiagnosisCode: "K02.0", Price: domain.Tenge(15000),
+		}))
+		require.NoError(t, diagnosisRepo.Create(ctx, &domain.AppointmentDiagnosis{AppointmentID: otherVisit.ID, Code: "K05.1"}))
+
+		upcoming := createTestAppointment(t, second.ID, service.ID, time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC), domain.StatusScheduled)
+		require.NoError(t, diagnosisRepo.Create(ctx, &domain.AppointmentDiagnosis{AppointmentID: upcoming.ID, Code: "K02.1"}))
+
+		report, err := diagnosisRepo.GetReport(ctx, domain.DiagnosisReportFilter{})
+		require.NoError(t, err)
+		require.Len(t, report, 3)
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K02.1", Visits: 2, Patients: 1}, *report[0])
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K02.0", Visits: 1, Patients: 1}, *report[1])
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K05.1", Visits: 1, Patients: 1}, *report[2])
+
+		byCategory, err := diagnosisRepo.GetReport(ctx, domain.DiagnosisReportFilter{ByCategory: true})
+		require.NoError(t, err)
+		require.Len(t, byCategory, 2)
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K02", Visits: 3, Patients: 2}, *byCategory[0])
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K05", Visits: 1, Patients: 1}, *byCategory[1])
+
+		from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+		period, err := diagnosisRepo.GetReport(ctx, domain.DiagnosisReportFilter{From: &from})
+		require.NoError(t, err)
+		require.Len(t, period, 1)
+		assert.Equal(t, domain.DiagnosisReportRow{Code: "K02.1", Visits: 1, Patients: 1}, *period[0])
+	})
+}
@@ -25,6 +25,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 	patientRepo := NewPatientRepository(testDB.DB)
 	serviceRepo := NewServiceRepository(testDB.DB)
 	appointmentRepo := NewAppointmentRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
 
 	// Helper to create test patient
 	createTestPatient := func(t *testing.T, name string) *domain.Patient {
@@ -32,7 +33,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 			Name:  name,
 			Phone: "+7 777 000 0000",
 		}
-		err := patientRepo.Create(patient)
+		err := patientRepo.Create(ctx, patient)
 		require.NoError(t, err)
 		return patient
 	}
@@ -43,7 +44,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 			Name: name,
 			Type: "Treatment",
 		}
-		err := serviceRepo.Create(service)
+		err := serviceRepo.Create(ctx, service)
 		require.NoError(t, err)
 		return service
 	}
@@ -57,15 +58,15 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      time.Now().Add(24 * time.Hour),
 			Status:    domain.StatusScheduled,
-			Price:     100.00,
+			Price:     domain.Tenge(100),
 			Duration:  60,
 			Notes:     "First appointment",
 		}
 
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
 		require.NoError(t, err)
 		assert.Greater(t, appointment.ID, 0)
 		assert.False(t, appointment.CreatedAt.IsZero())
@@ -80,14 +81,14 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   "Non-existent Service",
+			ServiceID: 9999,
 			Date:      time.Now(),
 			Status:    domain.StatusScheduled,
 		}
 
-		err = appointmentRepo.Create(appointment)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "не найдена")
+		err = appointmentRepo.Create(ctx, appointment)
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+		assert.Contains(t, err.Error(), "услуга с ID 9999 не найдена")
 	})
 
 	t.Run("GetByID", func(t *testing.T) {
@@ -100,21 +101,22 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		appointmentDate := time.Date(2024, 12, 15, 14, 30, 0, 0, time.UTC)
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      appointmentDate,
 			Status:    domain.StatusScheduled,
-			Price:     250.00,
+			Price:     domain.Tenge(250),
 			Duration:  90,
 			Notes:     "Complex procedure",
 		}
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
 		require.NoError(t, err)
 
-		found, err := appointmentRepo.GetByID(appointment.ID)
+		found, err := appointmentRepo.GetByID(ctx, appointment.ID)
 		require.NoError(t, err)
 		assert.Equal(t, appointment.ID, found.ID)
 		assert.Equal(t, appointment.PatientID, found.PatientID)
 		assert.Equal(t, service.Name, found.Service)
+		assert.Equal(t, service.ID, found.ServiceID)
 		assert.Equal(t, appointment.Price, found.Price)
 		assert.Equal(t, appointment.Duration, found.Duration)
 		// Verify patient_name and time are populated
@@ -126,7 +128,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		_, err = appointmentRepo.GetByID(9999)
+		_, err = appointmentRepo.GetByID(ctx, 9999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найдена")
 	})
@@ -141,17 +143,17 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		baseDate := time.Date(2024, 12, 15, 9, 0, 0, 0, time.UTC)
 		appointments := []*domain.Appointment{
-			{PatientID: patient.ID, Service: service1.Name, Date: baseDate, Status: domain.StatusScheduled},
-			{PatientID: patient.ID, Service: service2.Name, Date: baseDate.Add(24 * time.Hour), Status: domain.StatusScheduled},
-			{PatientID: patient.ID, Service: service1.Name, Date: baseDate.Add(48 * time.Hour), Status: domain.StatusCompleted},
+			{PatientID: patient.ID, ServiceID: service1.ID, Date: baseDate, Status: domain.StatusScheduled},
+			{PatientID: patient.ID, ServiceID: service2.ID, Date: baseDate.Add(24 * time.Hour), Status: domain.StatusScheduled},
+			{PatientID: patient.ID, ServiceID: service1.ID, Date: baseDate.Add(48 * time.Hour), Status: domain.StatusCompleted},
 		}
 
 		for _, a := range appointments {
-			err := appointmentRepo.Create(a)
+			err := appointmentRepo.Create(ctx, a)
 			require.NoError(t, err)
 		}
 
-		all, err := appointmentRepo.GetAll()
+		all, err := appointmentRepo.GetAll(ctx)
 		require.NoError(t, err)
 		assert.Len(t, all, 3)
 
@@ -162,6 +164,59 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		}
 	})
 
+	t.Run("List", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient1 := createTestPatient(t, "Patient One")
+		patient2 := createTestPatient(t, "Patient Two")
+		service1 := createTestService(t, "Cleaning")
+		service2 := createTestService(t, "Filling")
+
+		baseDate := time.Date(2024, 12, 15, 9, 0, 0, 0, time.UTC)
+		appointments := []*domain.Appointment{
+			{PatientID: patient1.ID, ServiceID: service1.ID, Date: baseDate, Status: domain.StatusCompleted, Price: domain.Tenge(5000)},
+			{PatientID: patient1.ID, ServiceID: service2.ID, Date: baseDate.Add(24 * time.Hour), Status: domain.StatusScheduled, Price: domain.Tenge(9000)},
+			{PatientID: patient2.ID, ServiceID: service1.ID, Date: baseDate.Add(48 * time.Hour), Status: domain.StatusCompleted, Price: domain.Tenge(7000)},
+			{PatientID: patient2.ID, ServiceID: service2.ID, Date: baseDate.Add(72 * time.Hour), Status: domain.StatusCancelled, Price: domain.Tenge(3000)},
+		}
+		for _, a := range appointments {
+			err := appointmentRepo.Create(ctx, a)
+			require.NoError(t, err)
+		}
+
+		all, total, err := appointmentRepo.List(ctx, domain.AppointmentFilter{Page: domain.Page{Limit: 3}})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, all, 3)
+		assert.Equal(t, appointments[3].ID, all[0].ID)
+
+		byPatient, total, err := appointmentRepo.List(ctx, domain.AppointmentFilter{PatientID: patient1.ID, Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, byPatient, 2)
+
+		completed, total, err := appointmentRepo.List(ctx, domain.AppointmentFilter{
+			Status:    domain.StatusCompleted,
+			ServiceID: service1.ID,
+			Sort:      domain.Sort{Field: "price", Desc: true},
+			Page:      domain.Page{Limit: 10},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, completed, 2)
+		assert.Equal(t, domain.Tenge(7000), completed[0].Price)
+		assert.Equal(t, domain.Tenge(5000), completed[1].Price)
+
+		from := baseDate.Add(24 * time.Hour)
+		to := baseDate.Add(72 * time.Hour)
+		inRange, total, err := appointmentRepo.List(ctx, domain.AppointmentFilter{From: &from, To: &to, Sort: domain.Sort{Field: "date"}, Page: domain.Page{Limit: 1, Offset: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, inRange, 1)
+		assert.Equal(t, appointments[2].ID, inRange[0].ID)
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
@@ -171,28 +226,28 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      time.Now().Add(24 * time.Hour),
 			Status:    domain.StatusScheduled,
-			Price:     100.00,
+			Price:     domain.Tenge(100),
 			Duration:  30,
 			Notes:     "Original note",
 		}
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
 		require.NoError(t, err)
 
 		appointment.Status = domain.StatusCompleted
 		appointment.Notes = "Updated note"
-		appointment.Price = 200.00
+		appointment.Price = domain.Tenge(200)
 		appointment.Duration = 60
-		err = appointmentRepo.Update(appointment)
+		err = appointmentRepo.Update(ctx, appointment)
 		require.NoError(t, err)
 
-		found, err := appointmentRepo.GetByID(appointment.ID)
+		found, err := appointmentRepo.GetByID(ctx, appointment.ID)
 		require.NoError(t, err)
 		assert.Equal(t, domain.StatusCompleted, found.Status)
 		assert.Equal(t, "Updated note", found.Notes)
-		assert.Equal(t, 200.00, found.Price)
+		assert.Equal(t, domain.Tenge(200), found.Price)
 		assert.Equal(t, 60, found.Duration)
 	})
 
@@ -207,9 +262,9 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 			PatientID: 1,
 			Date:      time.Now(),
 			Status:    domain.StatusScheduled,
-			Service:   service.Name,
+			ServiceID: service.ID,
 		}
-		err = appointmentRepo.Update(appointment)
+		err = appointmentRepo.Update(ctx, appointment)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найдена")
 	})
@@ -223,18 +278,18 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      time.Now().Add(24 * time.Hour),
 			Status:    domain.StatusScheduled,
 		}
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
 		require.NoError(t, err)
 
 		// Try to update with non-existent service
-		appointment.Service = "Non-existent Service"
-		err = appointmentRepo.Update(appointment)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "не найдена")
+		appointment.ServiceID = 9999
+		err = appointmentRepo.Update(ctx, appointment)
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+		assert.Contains(t, err.Error(), "услуга с ID 9999 не найдена")
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -246,17 +301,17 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      time.Now().Add(24 * time.Hour),
 			Status:    domain.StatusScheduled,
 		}
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
 		require.NoError(t, err)
 
-		err = appointmentRepo.Delete(appointment.ID)
+		err = appointmentRepo.Delete(ctx, appointment.ID)
 		require.NoError(t, err)
 
-		_, err = appointmentRepo.GetByID(appointment.ID)
+		_, err = appointmentRepo.GetByID(ctx, appointment.ID)
 		assert.Error(t, err)
 	})
 
@@ -264,7 +319,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		err = appointmentRepo.Delete(9999)
+		err = appointmentRepo.Delete(ctx, 9999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найдена")
 	})
@@ -283,26 +338,26 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		for i := 0; i < 3; i++ {
 			a := &domain.Appointment{
 				PatientID: patient1.ID,
-				Service:   service.Name,
+				ServiceID: service.ID,
 				Date:      baseDate.Add(time.Duration(i*24) * time.Hour),
 				Status:    domain.StatusScheduled,
 			}
-			err := appointmentRepo.Create(a)
+			err := appointmentRepo.Create(ctx, a)
 			require.NoError(t, err)
 		}
 
 		// Create appointment for patient2
 		a := &domain.Appointment{
 			PatientID: patient2.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      baseDate.Add(24 * time.Hour),
 			Status:    domain.StatusScheduled,
 		}
-		err = appointmentRepo.Create(a)
+		err = appointmentRepo.Create(ctx, a)
 		require.NoError(t, err)
 
 		// Get patient1's appointments
-		appointments, err := appointmentRepo.GetByPatientID(patient1.ID)
+		appointments, err := appointmentRepo.GetByPatientID(ctx, patient1.ID)
 		require.NoError(t, err)
 		assert.Len(t, appointments, 3)
 		for _, appt := range appointments {
@@ -311,7 +366,7 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		}
 
 		// Get patient2's appointments
-		appointments, err = appointmentRepo.GetByPatientID(patient2.ID)
+		appointments, err = appointmentRepo.GetByPatientID(ctx, patient2.ID)
 		require.NoError(t, err)
 		assert.Len(t, appointments, 1)
 		assert.Equal(t, "Patient2", appointments[0].PatientName)
@@ -332,26 +387,26 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		for i := 0; i < 2; i++ {
 			a := &domain.Appointment{
 				PatientID: patient.ID,
-				Service:   service.Name,
+				ServiceID: service.ID,
 				Date:      today.Add(time.Duration(i) * time.Hour),
 				Status:    domain.StatusScheduled,
 			}
-			err := appointmentRepo.Create(a)
+			err := appointmentRepo.Create(ctx, a)
 			require.NoError(t, err)
 		}
 
 		// Create appointment for tomorrow
 		a := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
 			Date:      tomorrow,
 			Status:    domain.StatusScheduled,
 		}
-		err = appointmentRepo.Create(a)
+		err = appointmentRepo.Create(ctx, a)
 		require.NoError(t, err)
 
 		// Get today's appointments
-		todayAppointments, err := appointmentRepo.GetByDate(today)
+		todayAppointments, err := appointmentRepo.GetByDate(ctx, today)
 		require.NoError(t, err)
 		assert.Len(t, todayAppointments, 2)
 		// Verify patient_name and time are populated
@@ -361,45 +416,172 @@ func TestAppointmentRepository_Integration(t *testing.T) {
 		}
 
 		// Get tomorrow's appointments
-		tomorrowAppointments, err := appointmentRepo.GetByDate(tomorrow)
+		tomorrowAppointments, err := appointmentRepo.GetByDate(ctx, tomorrow)
 		require.NoError(t, err)
 		assert.Len(t, tomorrowAppointments, 1)
 		assert.Equal(t, "Patient Date", tomorrowAppointments[0].PatientName)
 		assert.Equal(t, "11:00", tomorrowAppointments[0].Time)
 	})
 
-	t.Run("CheckTimeConflict", func(t *testing.T) {
+	t.Run("FindConflicts", func(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
 		patient := createTestPatient(t, "Patient Conflict")
 		service := createTestService(t, "Conflict Service")
+		doctor := &domain.Doctor{Name: "Dr. Conflict", Login: "dr_conflict", Password: "secret"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+		otherDoctor := &domain.Doctor{Name: "Dr. Other", Login: "dr_other", Password: "secret"}
+		require.NoError(t, doctorRepo.Create(ctx, otherDoctor))
 
 		appointmentDate := time.Date(2024, 12, 15, 10, 0, 0, 0, time.UTC)
 
 		appointment := &domain.Appointment{
 			PatientID: patient.ID,
-			Service:   service.Name,
+			ServiceID: service.ID,
+			DoctorID:  doctor.ID,
 			Date:      appointmentDate,
+			Duration:  60,
 			Status:    domain.StatusScheduled,
 		}
-		err = appointmentRepo.Create(appointment)
+		err = appointmentRepo.Create(ctx, appointment)
+		require.NoError(t, err)
+
+		conflicts, err := appointmentRepo.FindConflicts(ctx, &domain.Appointment{
+			DoctorID: doctor.ID, Date: appointmentDate.Add(30 * time.Minute), Duration: 30,
+		})
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, appointment.ID, conflicts[0].ID)
+
+		conflicts, err = appointmentRepo.FindConflicts(ctx, appointment)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+
+		conflicts, err = appointmentRepo.FindConflicts(ctx, &domain.Appointment{
+			DoctorID: doctor.ID, Date: appointmentDate.Add(time.Hour), Duration: 30,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+
+		conflicts, err = appointmentRepo.FindConflicts(ctx, &domain.Appointment{
+			DoctorID: otherDoctor.ID, Date: appointmentDate, Duration: 30,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("FindOverlapping", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := createTestPatient(t, "Patient Overlap")
+		service := createTestService(t, "Overlap Service")
+		doctor := &domain.Doctor{Name: "Dr. Overlap", Login: "dr_overlap", Password: "secret"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		day := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+		overnight := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: day.Add(-time.Hour), Duration: 120, Status: domain.StatusScheduled,
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, overnight))
+		cancelled := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: day.Add(10 * time.Hour), Duration: 30, Status: domain.StatusCancelled,
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, cancelled))
+
+		appointments, err := appointmentRepo.FindOverlapping(ctx, doctor.ID, day, day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Len(t, appointments, 1)
+		assert.Equal(t, overnight.ID, appointments[0].ID)
+
+		appointments, err = appointmentRepo.FindOverlapping(ctx, doctor.ID, day.Add(time.Hour), day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Empty(t, appointments)
+	})
+
+	t.Run("Create_OverlapRejectedByConstraint", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := createTestPatient(t, "Patient Overlap")
+		service := createTestService(t, "Overlap Service")
+		doctor := &domain.Doctor{Name: "Dr. Overlap", Login: "dr_overlap", Password: "secret"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		appointmentDate := time.Date(2024, 12, 15, 10, 0, 0, 0, time.UTC)
+		first := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: appointmentDate, Duration: 60, Status: domain.StatusScheduled,
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, first))
+
+		second := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: appointmentDate.Add(45 * time.Minute), Duration: 30, Status: domain.StatusScheduled,
+		}
+		err = appointmentRepo.Create(ctx, second)
+		var conflictErr *domain.AppointmentConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.Len(t, conflictErr.Conflicts, 1)
+		assert.Equal(t, first.ID, conflictErr.Conflicts[0].ID)
+
+		first.Status = domain.StatusCancelled
+		require.NoError(t, appointmentRepo.Update(ctx, first))
+		assert.NoError(t, appointmentRepo.Create(ctx, second))
+	})
+
+	t.Run("Update_Lifecycle", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		// Check for conflict at the same time (excluding none)
-		hasConflict, err := appointmentRepo.CheckTimeConflict(appointmentDate, "10:00", 0)
+		patient := createTestPatient(t, "Patient Lifecycle")
+		service := createTestService(t, "Lifecycle Service")
+		doctor := &domain.Doctor{Name: "Dr. Lifecycle", Login: "dr_lifecycle", Password: "secret"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		appointmentDate := time.Date(2024, 12, 16, 10, 0, 0, 0, time.UTC)
+		original := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: appointmentDate, Duration: 60, Status: domain.StatusScheduled,
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, original))
+
+		at := time.Date(2024, 12, 15, 9, 0, 0, 0, time.UTC)
+		require.NoError(t, original.Transition(domain.StatusConfirmed, at))
+		require.NoError(t, original.Transition(domain.StatusRescheduled, at.Add(time.Hour)))
+		require.NoError(t, appointmentRepo.Update(ctx, original))
+
+		moved := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, DoctorID: doctor.ID,
+			Date: appointmentDate.Add(30 * time.Minute), Duration: 60, Status: domain.StatusScheduled,
+			RescheduledFromID: original.ID,
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, moved))
+
+		found, err := appointmentRepo.GetByID(ctx, original.ID)
 		require.NoError(t, err)
-		assert.True(t, hasConflict)
+		assert.Equal(t, domain.StatusRescheduled, found.Status)
+		require.NotNil(t, found.ConfirmedAt)
+		require.NotNil(t, found.RescheduledAt)
+		assert.True(t, at.Equal(*found.ConfirmedAt))
+		assert.True(t, at.Add(time.Hour).Equal(*found.RescheduledAt))
+		assert.Nil(t, found.CancelledAt)
 
-		// Check for conflict excluding the same appointment
-		hasConflict, err = appointmentRepo.CheckTimeConflict(appointmentDate, "10:00", appointment.ID)
+		found, err = appointmentRepo.GetByID(ctx, moved.ID)
 		require.NoError(t, err)
-		assert.False(t, hasConflict)
+		assert.Equal(t, original.ID, found.RescheduledFromID)
+
+		found.CancellationReason = "пациент заболел"
+		require.NoError(t, found.Transition(domain.StatusCancelled, at.Add(2*time.Hour)))
+		require.NoError(t, appointmentRepo.Update(ctx, found))
 
-		// Check for no conflict at a different time
-		differentDate := time.Date(2024, 12, 16, 10, 0, 0, 0, time.UTC)
-		hasConflict, err = appointmentRepo.CheckTimeConflict(differentDate, "10:00", 0)
+		found, err = appointmentRepo.GetByID(ctx, moved.ID)
 		require.NoError(t, err)
-		assert.False(t, hasConflict)
+		assert.Equal(t, domain.StatusCancelled, found.Status)
+		assert.Equal(t, "пациент заболел", found.CancellationReason)
+		require.NotNil(t, found.CancelledAt)
 	})
 }
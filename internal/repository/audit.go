@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// writeAudit записывает событие журнала аудита в транзакции изменения.
+// Автор и идентификатор запроса берутся из контекста; для создания before равен nil, для удаления after равен nil.
+func writeAudit(ctx context.Context, tx *sql.Tx, entity domain.AuditEntity, entityID int, action domain.AuditAction, before, after interface{}) error {
+	changes, err := domain.AuditChanges(before, after)
+	if err != nil {
+		return fmt.Errorf("ошибка подготовки записи аудита: %w", err)
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("ошибка подготовки записи аудита: %w", err)
+	}
+
+	var actorID sql.NullInt64
+	var actorLogin sql.NullString
+	if doctor, ok := domain.DoctorFromContext(ctx); ok {
+		actorID = nullableID(doctor.ID)
+		actorLogin = sql.NullString{String: doctor.Login, Valid: doctor.Login != ""}
+	}
+
+	requestID := domain.RequestIDFromContext(ctx)
+
+	query := `INSERT INTO audit_log (actor_id, actor_login, entity, entity_id, action, changes, request_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	if _, err := tx.ExecContext(ctx, query, actorID, actorLogin, entity, entityID, action, data,
+		sql.NullString{String: requestID, Valid: requestID != ""}); err != nil {
+		return fmt.Errorf("ошибка записи аудита: %w", err)
+	}
+
+	return nil
+}
+
+// List получает страницу журнала аудита по фильтру, начиная с последних записей, и общее число подходящих записей
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, int, error) {
+	where := &whereBuilder{}
+	if filter.Entity != "" {
+		where.add("entity = $%d", filter.Entity)
+	}
+	if filter.EntityID > 0 {
+		where.add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.ActorID > 0 {
+		where.add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		where.add("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		where.add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("created_at < $%d", *filter.To)
+	}
+
+	total, err := countRows(ctx, r.db, "FROM audit_log", where)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка подсчета записей журнала аудита: %w", err)
+	}
+
+	query := `SELECT id, actor_id, COALESCE(actor_login, ''), entity, entity_id, action, changes, COALESCE(request_id, ''), created_at
+			  FROM audit_log` + where.sql() + ` ORDER BY created_at DESC, id DESC` + where.limitOffset(filter.Page)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения журнала аудита: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.AuditEntry, 0)
+	for rows.Next() {
+		entry := &domain.AuditEntry{}
+		var actorID sql.NullInt64
+		var changes []byte
+		err := rows.Scan(&entry.ID, &actorID, &entry.ActorLogin, &entry.Entity, &entry.EntityID,
+			&entry.Action, &changes, &entry.RequestID, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			entry.ActorID = &id
+		}
+
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, 0, fmt.Errorf("ошибка чтения изменений записи аудита %d: %w", entry.ID, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,138 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	repo := NewAuditRepository(testDB.DB)
+	patientRepo := NewPatientRepository(testDB.DB)
+	serviceRepo := NewServiceRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
+
+	t.Run("PatientLifecycle", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Audit", Login: "auditor", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+		actorCtx := domain.WithRequestID(domain.WithDoctor(ctx, doctor), "req-1")
+
+		patient := &domain.Patient{
+			Name:      "John Doe",
+			Phone:     "+7 777 123 4567",
+			Email:     "john@example.com",
+			BirthDate: time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			Address:   "123 Main St",
+		}
+		require.NoError(t, patientRepo.Create(actorCtx, patient))
+
+		patient.Phone = "+7 777 000 0000"
+		require.NoError(t, patientRepo.Update(actorCtx, patient))
+		require.NoError(t, patientRepo.Delete(ctx, patient.ID))
+
+		entries, _, err := repo.List(ctx, domain.AuditFilter{Entity: domain.AuditEntityPatient, EntityID: patient.ID, Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		deleted, updated, created := entries[0], entries[1], entries[2]
+
+		assert.Equal(t, domain.AuditActionCreate, created.Action)
+		require.NotNil(t, created.ActorID)
+		assert.Equal(t, doctor.ID, *created.ActorID)
+		assert.Equal(t, "auditor", created.ActorLogin)
+		assert.Equal(t, "req-1", created.RequestID)
+		assert.Nil(t, created.Changes["name"].Before)
+		assert.Equal(t, "John Doe", created.Changes["name"].After)
+		assert.NotContains(t, created.Changes, "updated_at")
+
+		assert.Equal(t, domain.AuditActionUpdate, updated.Action)
+		assert.Equal(t, map[string]domain.AuditChange{
+			"phone": {Before: "+7 777 123 4567", After: "+7 777 000 0000"},
+		}, updated.Changes)
+
+		assert.Equal(t, domain.AuditActionDelete, deleted.Action)
+		assert.Nil(t, deleted.ActorID)
+		assert.Empty(t, deleted.RequestID)
+		assert.Equal(t, "+7 777 000 0000", deleted.Changes["phone"].Before)
+		assert.Nil(t, deleted.Changes["phone"].After)
+	})
+
+	t.Run("FailedChangeIsNotAudited", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		err = patientRepo.Update(ctx, &domain.Patient{ID: 9999, Name: "Ghost"})
+		require.Error(t, err)
+
+		entries, _, err := repo.List(ctx, domain.AuditFilter{Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("ServicePrices", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		service := &domain.Service{Name: "Чистка", Type: "therapy", Price: domain.Tenge(5000), Duration: 30}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		effectiveFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, serviceRepo.AddPrice(ctx, &domain.ServicePrice{ServiceID: service.ID, Price: domain.Tenge(6000), EffectiveFrom: effectiveFrom}))
+		require.NoError(t, serviceRepo.AddPrice(ctx, &domain.ServicePrice{ServiceID: service.ID, Price: domain.Tenge(6500), EffectiveFrom: effectiveFrom}))
+
+		entries, _, err := repo.List(ctx, domain.AuditFilter{Entity: domain.AuditEntityServicePrice, Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, domain.AuditActionUpdate, entries[0].Action)
+		assert.Equal(t, map[string]domain.AuditChange{
+			"price": {Before: float64(6000), After: float64(6500)},
+		}, entries[0].Changes)
+		assert.Equal(t, domain.AuditActionCreate, entries[1].Action)
+
+		entries, _, err = repo.List(ctx, domain.AuditFilter{Action: domain.AuditActionCreate, Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		from := time.Now().Add(time.Hour)
+		entries, _, err = repo.List(ctx, domain.AuditFilter{From: &from, Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("AppendOnly", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		service := &domain.Service{Name: "Пломба", Type: "therapy", Price: domain.Tenge(8000), Duration: 30}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		_, err = testDB.DB.ExecContext(ctx, `UPDATE audit_log SET action = 'delete'`)
+		assert.Error(t, err)
+
+		_, err = testDB.DB.ExecContext(ctx, `DELETE FROM audit_log`)
+		assert.Error(t, err)
+
+		entries, _, err := repo.List(ctx, domain.AuditFilter{Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
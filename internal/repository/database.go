@@ -3,34 +3,33 @@ package repository
 import (
 	"database/sql"
 	"fmt"
-	"os"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
+// DatabaseConfig содержит параметры подключения к PostgreSQL и пула соединений
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
-func NewDatabaseConfig() *DatabaseConfig {
-	return &DatabaseConfig{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "crmstom_user"),
-		Password: getEnv("DB_PASSWORD", "crmstom_password"),
-		DBName:   getEnv("DB_NAME", "crmstom"),
-		SSLMode:  getEnv("DB_SSLMODE", "disable"),
-	}
+	Host            string
+	Port            int
+	User            string
+	Password        string
+	DBName          string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	TimeZone        string // часовой пояс сессии, в котором база возвращает timestamptz
 }
 
 func (config *DatabaseConfig) GetConnectionString() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+	if config.TimeZone != "" {
+		connStr += " timezone=" + config.TimeZone
+	}
+	return connStr
 }
 
 func ConnectToDatabase(config *DatabaseConfig) (*sql.DB, error) {
@@ -41,6 +40,11 @@ func ConnectToDatabase(config *DatabaseConfig) (*sql.DB, error) {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
 
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
 	// Проверка подключения
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("ошибка ping базы данных: %w", err)
@@ -48,10 +52,3 @@ func ConnectToDatabase(config *DatabaseConfig) (*sql.DB, error) {
 
 	return db, nil
 }
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
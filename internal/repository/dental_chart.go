@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type DentalChartRepository struct {
+	db *sql.DB
+}
+
+func NewDentalChartRepository(db *sql.DB) *DentalChartRepository {
+	return &DentalChartRepository{db: db}
+}
+
+// joinSurfaces записывает поверхности строкой формулы, например "MOD"
+func joinSurfaces(surfaces []domain.ToothSurface) string {
+	var b strings.Builder
+	for _, surface := range surfaces {
+		b.WriteString(string(surface))
+	}
+	return b.String()
+}
+
+// splitSurfaces разбирает строку формулы на поверхности
+func splitSurfaces(formula string) []domain.ToothSurface {
+	surfaces := make([]domain.ToothSurface, 0, len(formula))
+	for _, r := range formula {
+		surfaces = append(surfaces, domain.ToothSurface(r))
+	}
+	return surfaces
+}
+
+// GetTeeth получает текущее состояние зубов пациента по возрастанию номера
+func (r *DentalChartRepository) GetTeeth(ctx context.Context, patientID int) ([]*domain.ToothState, error) {
+	query := `SELECT tooth, status, surfaces, notes, appointment_id, doctor_id, updated_at
+			  FROM dental_chart_teeth WHERE patient_id = $1 ORDER BY tooth`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения зубной формулы: %w", err)
+	}
+	defer rows.Close()
+
+	teeth := make([]*domain.ToothState, 0)
+	for rows.Next() {
+		state := &domain.ToothState{}
+		var surfaces string
+		var appointmentID, doctorID sql.NullInt64
+		if err := rows.Scan(&state.Tooth, &state.Status, &surfaces, &state.Notes, &appointmentID, &doctorID, &state.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения зубной формулы: %w", err)
+		}
+		state.Surfaces = splitSurfaces(surfaces)
+		state.AppointmentID = int(appointmentID.Int64)
+		state.DoctorID = int(doctorID.Int64)
+		teeth = append(teeth, state)
+	}
+
+	return teeth, rows.Err()
+}
+
+// GetHistory получает историю изменений зубной формулы пациента, начиная с последних; tooth = 0 — по всем зубам
+func (r *DentalChartRepository) GetHistory(ctx context.Context, patientID, tooth int) ([]*domain.ToothChange, error) {
+	where := &whereBuilder{}
+	where.add("patient_id = $%d", patientID)
+	if tooth > 0 {
+		where.add("tooth = $%d", tooth)
+	}
+
+	query := `SELECT id, patient_id, tooth, status, surfaces, notes, appointment_id, doctor_id, created_at
+			  FROM dental_chart_history` + where.sql() + ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории зубной формулы: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.ToothChange, 0)
+	for rows.Next() {
+		change := &domain.ToothChange{}
+		var surfaces string
+		var appointmentID, doctorID sql.NullInt64
+		if err := rows.Scan(&change.ID, &change.PatientID, &change.Tooth, &change.Status, &surfaces, &change.Notes,
+			&appointmentID, &doctorID, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории зубной формулы: %w", err)
+		}
+		change.Surfaces = splitSurfaces(surfaces)
+		change.AppointmentID = int(appointmentID.Int64)
+		change.DoctorID = int(doctorID.Int64)
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// RecordChanges записывает изменения в историю и обновляет текущее состояние зубов в одной транзакции
+func (r *DentalChartRepository) RecordChanges(ctx context.Context, changes []*domain.ToothChange) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, change := range changes {
+			surfaces := joinSurfaces(change.Surfaces)
+
+			query := `INSERT INTO dental_chart_history (patient_id, tooth, status, surfaces, notes, appointment_id, doctor_id)
+					  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+
+			err := tx.QueryRowContext(ctx, query, change.PatientID, change.Tooth, change.Status, surfaces, change.Notes,
+				nullableID(change.AppointmentID), nullableID(change.DoctorID)).Scan(&change.ID, &change.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("ошибка записи истории зуба %d: %w", change.Tooth, err)
+			}
+
+			query = `INSERT INTO dental_chart_teeth (patient_id, tooth, status, surfaces, notes, appointment_id, doctor_id, updated_at)
+					 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+					 ON CONFLICT (patient_id, tooth) DO UPDATE SET
+					 status = EXCLUDED.status, surfaces = EXCLUDED.surfaces, notes = EXCLUDED.notes,
+					 appointment_id = EXCLUDED.appointment_id, doctor_id = EXCLUDED.doctor_id, updated_at = EXCLUDED.updated_at`
+
+			if _, err := tx.ExecContext(ctx, query, change.PatientID, change.Tooth, change.Status, surfaces, change.Notes,
+				nullableID(change.AppointmentID), nullableID(change.DoctorID), change.CreatedAt); err != nil {
+				return fmt.Errorf("ошибка обновления состояния зуба %d: %w", change.Tooth, err)
+			}
+		}
+		return nil
+	})
+}
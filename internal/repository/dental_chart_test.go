@@ -0,0 +1,91 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDentalChartRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	patientRepo := NewPatientRepository(testDB.DB)
+	chartRepo := NewDentalChartRepository(testDB.DB)
+
+	createTestPatient := func(t *testing.T, name string) *domain.Patient {
+		patient := &domain.Patient{Name: name, Phone: "+7 777 000 0000"}
+		require.NoError(t, patientRepo.Create(ctx, patient))
+		return patient
+	}
+
+	t.Run("RecordChanges", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := createTestPatient(t, "Chart Patient")
+
+		changes := []*domain.ToothChange{
+			{PatientID: patient.ID, Tooth: 36, Status: domain.ToothCaries, Surfaces: []domain.ToothSurface{domain.SurfaceOcclusal}},
+			{PatientID: patient.ID, Tooth: 18, Status: domain.ToothMissing, Surfaces: []domain.ToothSurface{}},
+		}
+		require.NoError(t, chartRepo.RecordChanges(ctx, changes))
+		assert.Greater(t, changes[0].ID, 0)
+		assert.False(t, changes[0].CreatedAt.IsZero())
+
+		filled := []*domain.ToothChange{{
+			PatientID: patient.ID, Tooth: 36, Status: domain.ToothFilled, Notes: "composite",
+			Surfaces: []domain.ToothSurface{domain.SurfaceMesial, domain.SurfaceOcclusal, domain.SurfaceDistal},
+		}}
+		require.NoError(t, chartRepo.RecordChanges(ctx, filled))
+
+		teeth, err := chartRepo.GetTeeth(ctx, patient.ID)
+		require.NoError(t, err)
+		require.Len(t, teeth, 2)
+		assert.Equal(t, 18, teeth[0].Tooth)
+		assert.Equal(t, domain.ToothMissing, teeth[0].Status)
+		assert.Empty(t, teeth[0].Surfaces)
+		assert.Equal(t, 36, teeth[1].Tooth)
+		assert.Equal(t, domain.ToothFilled, teeth[1].Status)
+		assert.Equal(t, filled[0].Surfaces, teeth[1].Surfaces)
+		assert.Equal(t, "composite", teeth[1].Notes)
+	})
+
+	t.Run("GetHistory", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := createTestPatient(t, "History Patient")
+		other := createTestPatient(t, "Other Patient")
+
+		require.NoError(t, chartRepo.RecordChanges(ctx, []*domain.ToothChange{
+			{PatientID: patient.ID, Tooth: 46, Status: domain.ToothCaries, Surfaces: []domain.ToothSurface{domain.SurfaceOcclusal}},
+			{PatientID: patient.ID, Tooth: 11, Status: domain.ToothCrown},
+		}))
+		require.NoError(t, chartRepo.RecordChanges(ctx, []*domain.ToothChange{
+			{PatientID: patient.ID, Tooth: 46, Status: domain.ToothRootCanal},
+			{PatientID: other.ID, Tooth: 46, Status: domain.ToothImplant},
+		}))
+
+		history, err := chartRepo.GetHistory(ctx, patient.ID, 46)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, domain.ToothRootCanal, history[0].Status)
+		assert.Equal(t, domain.ToothCaries, history[1].Status)
+
+		history, err = chartRepo.GetHistory(ctx, patient.ID, 0)
+		require.NoError(t, err)
+		assert.Len(t, history, 3)
+	})
+}
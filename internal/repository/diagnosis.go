@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Ограничения выдачи поиска по справочнику диагнозов
+const (
+	defaultDiagnosisSearchLimit = 20
+	maxDiagnosisSearchLimit     = 200
+)
+
+// icd10Dental блок K00-K14 МКБ-10 «Болезни полости рта, слюнных желез и челюстей»: строки «код<TAB>наименование»,
+// строки с # — комментарии
+//
+//go:embed data/icd10_k00_k14.tsv
+var icd10Dental []byte
+
+// DiagnosisRepository справочник диагнозов МКБ-10; справочник не меняется во время работы, поэтому хранится в памяти
+type DiagnosisRepository struct {
+	diagnoses []*domain.Diagnosis // в порядке файла: рубрика, затем ее подрубрики
+	byCode    map[string]*domain.Diagnosis
+}
+
+// NewDiagnosisRepository загружает встроенный справочник стоматологических диагнозов МКБ-10
+func NewDiagnosisRepository() (*DiagnosisRepository, error) {
+	return loadDiagnoses(icd10Dental)
+}
+
+// loadDiagnoses разбирает справочник; подрубрика должна следовать за своей рубрикой
+func loadDiagnoses(data []byte) (*DiagnosisRepository, error) {
+	r := &DiagnosisRepository{byCode: make(map[string]*domain.Diagnosis)}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		code, name, ok := strings.Cut(text, "\t")
+		code, name = strings.TrimSpace(code), strings.TrimSpace(name)
+		if !ok || code == "" || name == "" {
+			return nil, fmt.Errorf("ошибка чтения справочника МКБ-10, строка %d: ожидается код и наименование", line)
+		}
+		if _, exists := r.byCode[code]; exists {
+			return nil, fmt.Errorf("ошибка чтения справочника МКБ-10, строка %d: код %s повторяется", line, code)
+		}
+
+		diagnosis := &domain.Diagnosis{Code: code, Name: name, Category: code}
+		if category, _, isSubcode := strings.Cut(code, "."); isSubcode {
+			parent, exists := r.byCode[category]
+			if !exists {
+				return nil, fmt.Errorf("ошибка чтения справочника МКБ-10, строка %d: рубрика %s не найдена", line, category)
+			}
+			parent.HasSubcodes = true
+			diagnosis.Category = category
+		}
+
+		r.diagnoses = append(r.diagnoses, diagnosis)
+		r.byCode[code] = diagnosis
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения справочника МКБ-10: %w", err)
+	}
+
+	return r, nil
+}
+
+func (r *DiagnosisRepository) GetByCode(ctx context.Context, code string) (*domain.Diagnosis, error) {
+	diagnosis, ok := r.byCode[code]
+	if !ok {
+		return nil, domain.ErrDiagnosisNotFound.WithMessage("диагноз с кодом %s не найден в справочнике МКБ-10", code)
+	}
+
+	found := *diagnosis
+	return &found, nil
+}
+
+// Search ищет диагнозы по началу кода или части названия без учета регистра; пустой запрос возвращает начало справочника
+func (r *DiagnosisRepository) Search(ctx context.Context, query string, limit int) ([]*domain.Diagnosis, error) {
+	if limit <= 0 {
+		limit = defaultDiagnosisSearchLimit
+	}
+	limit = min(limit, maxDiagnosisSearchLimit)
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	diagnoses := make([]*domain.Diagnosis, 0)
+	for _, diagnosis := range r.diagnoses {
+		if len(diagnoses) == limit {
+			break
+		}
+		if strings.HasPrefix(strings.ToLower(diagnosis.Code), query) || strings.Contains(strings.ToLower(diagnosis.Name), query) {
+			found := *diagnosis
+			diagnoses = append(diagnoses, &found)
+		}
+	}
+
+	return diagnoses, nil
+}
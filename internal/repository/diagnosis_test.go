@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosisRepository_Catalog(t *testing.T) {
+	ctx := context.Background()
+	catalog, err := NewDiagnosisRepository()
+	require.NoError(t, err)
+
+	t.Run("GetByCode", func(t *testing.T) {
+		caries, err := catalog.GetByCode(ctx, "K02")
+		require.NoError(t, err)
+		assert.Equal(t, "Кариес зубов", caries.Name)
+		assert.True(t, caries.HasSubcodes)
+
+		dentin, err := catalog.GetByCode(ctx, "K02.1")
+		require.NoError(t, err)
+		assert.Equal(t, "Кариес дентина", dentin.Name)
+		assert.Equal(t, "K02", dentin.Category)
+		assert.False(t, dentin.HasSubcodes)
+
+		_, err = catalog.GetByCode(ctx, "J06.9")
+		assert.ErrorIs(t, err, domain.ErrDiagnosisNotFound)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			query     string
+			limit     int
+			wantCount int
+			wantCodes []string
+		}{
+			{name: "code prefix", query: "k04.", limit: 3, wantCount: 3, wantCodes: []string{"K04.0", "K04.1", "K04.2"}},
+			{name: "name substring", query: "ПУЛЬПИТ", wantCount: 1, wantCodes: []string{"K04.0"}},
+			{name: "default limit", query: "", wantCount: defaultDiagnosisSearchLimit},
+			{name: "no match", query: "грипп", limit: 10},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				diagnoses, err := catalog.Search(ctx, tt.query, tt.limit)
+				require.NoError(t, err)
+
+				require.Len(t, diagnoses, tt.wantCount)
+				for i, code := range tt.wantCodes {
+					assert.Equal(t, code, diagnoses[i].Code)
+				}
+			})
+		}
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		tests := []struct {
+			name string
+			data string
+		}{
+			{name: "missing name", data: "K02\n"},
+			{name: "duplicate code", data: "K02\tКариес зубов\nK02\tКариес зубов\n"},
+			{name: "subcode without category", data: "K02.1\tКариес дентина\n"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := loadDiagnoses([]byte(tt.data))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
@@ -15,64 +17,126 @@ func NewDoctorRepository(db *sql.DB) *DoctorRepository {
 	return &DoctorRepository{db: db}
 }
 
+const doctorSelect = `
+	SELECT id, name, email, login, password, role, created_at, updated_at, failed_login_attempts, locked_until,
+		COALESCE(totp_secret, ''), totp_enabled
+	FROM doctors`
+
+// doctorSortColumns сопоставляет полям сортировки из domain.DoctorSortFields выражения SQL
+var doctorSortColumns = map[string]string{
+	"name":       "name",
+	"login":      "login",
+	"role":       "role",
+	"created_at": "created_at",
+}
+
+// scanDoctor сканирует строку врача; IsAdmin вычисляется из роли
+func scanDoctor(row rowScanner) (*domain.Doctor, error) {
+	doctor := &domain.Doctor{}
+	var lockedUntil sql.NullTime
+	err := row.Scan(
+		&doctor.ID,
+		&doctor.Name,
+		&doctor.Email,
+		&doctor.Login,
+		&doctor.Password,
+		&doctor.Role,
+		&doctor.CreatedAt,
+		&doctor.UpdatedAt,
+		&doctor.FailedLoginAttempts,
+		&lockedUntil,
+		&doctor.TOTPSecret,
+		&doctor.TOTPEnabled,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	doctor.IsAdmin = doctor.Role == domain.RoleAdmin
+	if lockedUntil.Valid {
+		doctor.LockedUntil = &lockedUntil.Time
+	}
+
+	return doctor, nil
+}
+
+// queryDoctor получает одного врача; отсутствие врача не является ошибкой
+func (r *DoctorRepository) queryDoctor(ctx context.Context, query string, args ...interface{}) (*domain.Doctor, error) {
+	doctor, err := scanDoctor(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doctor, nil
+}
+
 // Create создает нового врача
-func (r *DoctorRepository) Create(doctor *domain.Doctor) error {
+func (r *DoctorRepository) Create(ctx context.Context, doctor *domain.Doctor) error {
 	query := `
-		INSERT INTO doctors (name, email, login, password, is_admin, created_at, updated_at)
+		INSERT INTO doctors (name, email, login, password, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id`
 
 	now := time.Now()
-	return r.db.QueryRow(
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
 		query,
 		doctor.Name,
 		doctor.Email,
 		doctor.Login,
 		doctor.Password,
-		doctor.IsAdmin,
+		doctor.EffectiveRole(),
 		now,
 		now,
 	).Scan(&doctor.ID)
+	if isUniqueViolation(err) {
+		return domain.ErrDoctorLoginExists.WithMessage("врач с логином %s уже существует", doctor.Login)
+	}
+	return err
 }
 
 // GetByID получает врача по ID
-func (r *DoctorRepository) GetByID(id int) (*domain.Doctor, error) {
-	query := `
-		SELECT id, name, email, login, password, is_admin, created_at, updated_at
-		FROM doctors
-		WHERE id = $1 AND deleted_at IS NULL`
+func (r *DoctorRepository) GetByID(ctx context.Context, id int) (*domain.Doctor, error) {
+	return r.queryDoctor(ctx, doctorSelect+` WHERE id = $1 AND deleted_at IS NULL`, id)
+}
 
-	doctor := &domain.Doctor{}
-	err := r.db.QueryRow(query, id).Scan(
-		&doctor.ID,
-		&doctor.Name,
-		&doctor.Email,
-		&doctor.Login,
-		&doctor.Password,
-		&doctor.IsAdmin,
-		&doctor.CreatedAt,
-		&doctor.UpdatedAt,
-	)
+// GetAll получает всех врачей
+func (r *DoctorRepository) GetAll(ctx context.Context) ([]*domain.Doctor, error) {
+	return r.queryDoctors(ctx, doctorSelect+` WHERE deleted_at IS NULL ORDER BY name`)
+}
 
-	if err == sql.ErrNoRows {
-		return nil, nil
+// List получает страницу врачей по фильтру и общее число подходящих врачей
+func (r *DoctorRepository) List(ctx context.Context, filter domain.DoctorFilter) ([]*domain.Doctor, int, error) {
+	where := &whereBuilder{}
+	where.addRaw("deleted_at IS NULL")
+	if filter.Query != "" {
+		where.add("(name ILIKE $%[1]d OR login ILIKE $%[1]d OR email ILIKE $%[1]d)", "%"+filter.Query+"%")
+	}
+	if filter.Role != "" {
+		where.add("role = $%d", filter.Role)
 	}
+
+	total, err := countRows(ctx, r.db, "FROM doctors", where)
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("ошибка подсчета врачей: %w", err)
 	}
 
-	return doctor, nil
-}
+	query := doctorSelect + where.sql() +
+		orderBy(filter.Sort, doctorSortColumns, domain.Sort{Field: "name"}, "id") +
+		where.limitOffset(filter.Page)
+	doctors, err := r.queryDoctors(ctx, query, where.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения списка врачей: %w", err)
+	}
 
-// GetAll получает всех врачей
-func (r *DoctorRepository) GetAll() ([]*domain.Doctor, error) {
-	query := `
-		SELECT id, name, email, login, password, is_admin, created_at, updated_at
-		FROM doctors
-		WHERE deleted_at IS NULL
-		ORDER BY name`
+	return doctors, total, nil
+}
 
-	rows, err := r.db.Query(query)
+// queryDoctors выполняет запрос и читает всех врачей из результата
+func (r *DoctorRepository) queryDoctors(ctx context.Context, query string, args ...interface{}) ([]*domain.Doctor, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,17 +144,7 @@ func (r *DoctorRepository) GetAll() ([]*domain.Doctor, error) {
 
 	doctors := make([]*domain.Doctor, 0)
 	for rows.Next() {
-		doctor := &domain.Doctor{}
-		err := rows.Scan(
-			&doctor.ID,
-			&doctor.Name,
-			&doctor.Email,
-			&doctor.Login,
-			&doctor.Password,
-			&doctor.IsAdmin,
-			&doctor.CreatedAt,
-			&doctor.UpdatedAt,
-		)
+		doctor, err := scanDoctor(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -100,23 +154,27 @@ func (r *DoctorRepository) GetAll() ([]*domain.Doctor, error) {
 	return doctors, rows.Err()
 }
 
-// Update обновляет врача
-func (r *DoctorRepository) Update(doctor *domain.Doctor) error {
+// Update обновляет врача; пустой пароль сохраняет текущий
+func (r *DoctorRepository) Update(ctx context.Context, doctor *domain.Doctor) error {
 	query := `
 		UPDATE doctors
-		SET name = $1, email = $2, login = $3, password = $4, is_admin = $5, updated_at = $6
+		SET name = $1, email = $2, login = $3, password = COALESCE(NULLIF($4, ''), password), role = $5, updated_at = $6
 		WHERE id = $7 AND deleted_at IS NULL`
 
-	result, err := r.db.Exec(
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
 		query,
 		doctor.Name,
 		doctor.Email,
 		doctor.Login,
 		doctor.Password,
-		doctor.IsAdmin,
+		doctor.EffectiveRole(),
 		time.Now(),
 		doctor.ID,
 	)
+	if isUniqueViolation(err) {
+		return domain.ErrDoctorLoginExists.WithMessage("врач с логином %s уже существует", doctor.Login)
+	}
 	if err != nil {
 		return err
 	}
@@ -127,19 +185,19 @@ func (r *DoctorRepository) Update(doctor *domain.Doctor) error {
 	}
 
 	if rowsAffected == 0 {
-		return sql.ErrNoRows
+		return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", doctor.ID)
 	}
 
 	return nil
 }
 
-// Delete удаляет врача (soft delete)
-func (r *DoctorRepository) Delete(id int) error {
-	query := `UPDATE doctors SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+// UpdatePassword заменяет хеш пароля врача
+func (r *DoctorRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	query := `UPDATE doctors SET password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
 
-	result, err := r.db.Exec(query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, passwordHash, time.Now(), id)
 	if err != nil {
-		return err
+		return fmt.Errorf("ошибка обновления пароля врача: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -148,37 +206,81 @@ func (r *DoctorRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return sql.ErrNoRows
+		return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", id)
 	}
 
 	return nil
 }
 
-// GetByLogin получает врача по логину
-func (r *DoctorRepository) GetByLogin(login string) (*domain.Doctor, error) {
+// IncrementFailedLogins увеличивает счетчик неудачных входов врача и возвращает новое значение
+func (r *DoctorRepository) IncrementFailedLogins(ctx context.Context, id int) (int, error) {
 	query := `
-		SELECT id, name, email, login, password, is_admin, created_at, updated_at
-		FROM doctors
-		WHERE login = $1 AND deleted_at IS NULL`
+		UPDATE doctors SET failed_login_attempts = failed_login_attempts + 1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING failed_login_attempts`
 
-	doctor := &domain.Doctor{}
-	err := r.db.QueryRow(query, login).Scan(
-		&doctor.ID,
-		&doctor.Name,
-		&doctor.Email,
-		&doctor.Login,
-		&doctor.Password,
-		&doctor.IsAdmin,
-		&doctor.CreatedAt,
-		&doctor.UpdatedAt,
-	)
+	var attempts int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&attempts); err != nil {
+		return 0, fmt.Errorf("ошибка учета неудачного входа: %w", err)
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, nil
+	return attempts, nil
+}
+
+// LockUntil блокирует вход врача до указанного времени
+func (r *DoctorRepository) LockUntil(ctx context.Context, id int, until time.Time) error {
+	query := `UPDATE doctors SET locked_until = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, until, id); err != nil {
+		return fmt.Errorf("ошибка блокировки входа врача: %w", err)
 	}
+
+	return nil
+}
+
+// ResetFailedLogins сбрасывает счетчик неудачных входов и снимает блокировку
+func (r *DoctorRepository) ResetFailedLogins(ctx context.Context, id int) error {
+	query := `UPDATE doctors SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("ошибка разблокировки входа врача: %w", err)
 	}
 
-	return doctor, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", id)
+	}
+
+	return nil
+}
+
+// Delete удаляет врача (soft delete)
+func (r *DoctorRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE doctors SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", id)
+	}
+
+	return nil
+}
+
+// GetByLogin получает врача по логину
+func (r *DoctorRepository) GetByLogin(ctx context.Context, login string) (*domain.Doctor, error) {
+	return r.queryDoctor(ctx, doctorSelect+` WHERE login = $1 AND deleted_at IS NULL`, login)
 }
@@ -4,8 +4,8 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +36,7 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			IsAdmin:  false,
 		}
 
-		err = repo.Create(doctor)
+		err = repo.Create(ctx, doctor)
 		require.NoError(t, err)
 		assert.Greater(t, doctor.ID, 0)
 	})
@@ -50,7 +50,7 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Login:    "same_login",
 			Password: "pass123",
 		}
-		err = repo.Create(doctor1)
+		err = repo.Create(ctx, doctor1)
 		require.NoError(t, err)
 
 		doctor2 := &domain.Doctor{
@@ -58,8 +58,13 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Login:    "same_login",
 			Password: "pass456",
 		}
-		err = repo.Create(doctor2)
-		assert.Error(t, err) // Should fail due to unique constraint
+		err = repo.Create(ctx, doctor2)
+		assert.ErrorIs(t, err, domain.ErrDoctorLoginExists)
+
+		doctor2.Login = "other_login"
+		require.NoError(t, repo.Create(ctx, doctor2))
+		doctor2.Login = "same_login"
+		assert.ErrorIs(t, repo.Update(ctx, doctor2), domain.ErrDoctorLoginExists)
 	})
 
 	t.Run("GetByID", func(t *testing.T) {
@@ -73,10 +78,10 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Password: "pass789",
 			IsAdmin:  true,
 		}
-		err = repo.Create(doctor)
+		err = repo.Create(ctx, doctor)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(doctor.ID)
+		found, err := repo.GetByID(ctx, doctor.ID)
 		require.NoError(t, err)
 		require.NotNil(t, found)
 		assert.Equal(t, doctor.ID, found.ID)
@@ -90,7 +95,7 @@ func TestDoctorRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(9999)
+		found, err := repo.GetByID(ctx, 9999)
 		require.NoError(t, err) // Returns nil, nil for not found
 		assert.Nil(t, found)
 	})
@@ -106,11 +111,11 @@ func TestDoctorRepository_Integration(t *testing.T) {
 		}
 
 		for _, d := range doctors {
-			err := repo.Create(d)
+			err := repo.Create(ctx, d)
 			require.NoError(t, err)
 		}
 
-		all, err := repo.GetAll()
+		all, err := repo.GetAll(ctx)
 		require.NoError(t, err)
 		assert.Len(t, all, 3)
 	})
@@ -125,20 +130,109 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Password: "oldpass",
 			IsAdmin:  false,
 		}
-		err = repo.Create(doctor)
+		err = repo.Create(ctx, doctor)
 		require.NoError(t, err)
 
 		doctor.Name = "Dr. Updated"
 		doctor.Email = "updated@clinic.com"
 		doctor.IsAdmin = true
-		err = repo.Update(doctor)
+		err = repo.Update(ctx, doctor)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(doctor.ID)
+		found, err := repo.GetByID(ctx, doctor.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "Dr. Updated", found.Name)
 		assert.Equal(t, "updated@clinic.com", found.Email)
 		assert.True(t, found.IsAdmin)
+		assert.Equal(t, "oldpass", found.Password)
+
+		doctor.Password = ""
+		err = repo.Update(ctx, doctor)
+		require.NoError(t, err)
+
+		found, err = repo.GetByID(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "oldpass", found.Password)
+	})
+
+	t.Run("Role", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Reception", Login: "reception", Password: "pass", Role: domain.RoleReceptionist}
+		err = repo.Create(ctx, doctor)
+		require.NoError(t, err)
+
+		found, err := repo.GetByID(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleReceptionist, found.Role)
+		assert.False(t, found.IsAdmin)
+
+		found.Role = domain.RoleAdmin
+		err = repo.Update(ctx, found)
+		require.NoError(t, err)
+
+		found, err = repo.GetByLogin(ctx, "reception")
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleAdmin, found.Role)
+		assert.True(t, found.IsAdmin)
+	})
+
+	t.Run("LoginLockout", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Locked", Login: "locked", Password: "pass"}
+		err = repo.Create(ctx, doctor)
+		require.NoError(t, err)
+
+		attempts, err := repo.IncrementFailedLogins(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempts)
+		attempts, err = repo.IncrementFailedLogins(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+
+		until := time.Now().Add(15 * time.Minute).Truncate(time.Second)
+		err = repo.LockUntil(ctx, doctor.ID, until)
+		require.NoError(t, err)
+
+		found, err := repo.GetByLogin(ctx, "locked")
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.FailedLoginAttempts)
+		require.NotNil(t, found.LockedUntil)
+		assert.True(t, found.IsLocked(time.Now()))
+
+		err = repo.ResetFailedLogins(ctx, doctor.ID)
+		require.NoError(t, err)
+
+		found, err = repo.GetByID(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Zero(t, found.FailedLoginAttempts)
+		assert.Nil(t, found.LockedUntil)
+
+		_, err = repo.IncrementFailedLogins(ctx, 9999)
+		assert.Error(t, err)
+		assert.ErrorIs(t, repo.ResetFailedLogins(ctx, 9999), domain.ErrDoctorNotFound)
+	})
+
+	t.Run("UpdatePassword", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Hash", Login: "hash", Password: "plain"}
+		err = repo.Create(ctx, doctor)
+		require.NoError(t, err)
+
+		err = repo.UpdatePassword(ctx, doctor.ID, "$2a$10$hash")
+		require.NoError(t, err)
+
+		found, err := repo.GetByLogin(ctx, "hash")
+		require.NoError(t, err)
+		assert.Equal(t, "$2a$10$hash", found.Password)
+
+		err = repo.UpdatePassword(ctx, 9999, "$2a$10$hash")
+		assert.ErrorIs(t, err, domain.ErrDoctorNotFound)
 	})
 
 	t.Run("Update_NotFound", func(t *testing.T) {
@@ -151,8 +245,8 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Login:    "nonexistent",
 			Password: "pass",
 		}
-		err = repo.Update(doctor)
-		assert.ErrorIs(t, err, sql.ErrNoRows)
+		err = repo.Update(ctx, doctor)
+		assert.ErrorIs(t, err, domain.ErrDoctorNotFound)
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -164,13 +258,13 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Login:    "todelete",
 			Password: "pass",
 		}
-		err = repo.Create(doctor)
+		err = repo.Create(ctx, doctor)
 		require.NoError(t, err)
 
-		err = repo.Delete(doctor.ID)
+		err = repo.Delete(ctx, doctor.ID)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(doctor.ID)
+		found, err := repo.GetByID(ctx, doctor.ID)
 		require.NoError(t, err)
 		assert.Nil(t, found)
 	})
@@ -179,8 +273,8 @@ func TestDoctorRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		err = repo.Delete(9999)
-		assert.ErrorIs(t, err, sql.ErrNoRows)
+		err = repo.Delete(ctx, 9999)
+		assert.ErrorIs(t, err, domain.ErrDoctorNotFound)
 	})
 
 	t.Run("GetByLogin", func(t *testing.T) {
@@ -192,10 +286,10 @@ func TestDoctorRepository_Integration(t *testing.T) {
 			Login:    "unique_login",
 			Password: "testpass",
 		}
-		err = repo.Create(doctor)
+		err = repo.Create(ctx, doctor)
 		require.NoError(t, err)
 
-		found, err := repo.GetByLogin("unique_login")
+		found, err := repo.GetByLogin(ctx, "unique_login")
 		require.NoError(t, err)
 		require.NotNil(t, found)
 		assert.Equal(t, doctor.ID, found.ID)
@@ -206,7 +300,7 @@ func TestDoctorRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		found, err := repo.GetByLogin("nonexistent_login")
+		found, err := repo.GetByLogin(ctx, "nonexistent_login")
 		require.NoError(t, err) // Returns nil, nil for not found
 		assert.Nil(t, found)
 	})
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// whereBuilder собирает условия WHERE с позиционными параметрами $1, $2, ...
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add добавляет условие; %[1]d в условии заменяется номером параметра arg
+func (b *whereBuilder) add(condition string, arg interface{}) {
+	b.args = append(b.args, arg)
+	b.conditions = append(b.conditions, fmt.Sprintf(condition, len(b.args)))
+}
+
+// addRaw добавляет условие без параметров
+func (b *whereBuilder) addRaw(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// sql возвращает предложение WHERE или пустую строку, если условий нет
+func (b *whereBuilder) sql() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// orderBy строит ORDER BY по колонке из columns; пустое или неизвестное поле заменяется defaultSort.
+// Последним добавляется tieBreaker, чтобы соседние страницы не пересекались при равных значениях
+func orderBy(sort domain.Sort, columns map[string]string, defaultSort domain.Sort, tieBreaker string) string {
+	column, ok := columns[sort.Field]
+	if !ok {
+		sort = defaultSort
+		column = columns[defaultSort.Field]
+	}
+
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s NULLS LAST, %s %s", column, direction, tieBreaker, direction)
+}
+
+// limitOffset добавляет к запросу LIMIT и OFFSET страницы
+func (b *whereBuilder) limitOffset(page domain.Page) string {
+	b.args = append(b.args, page.Limit, page.Offset)
+	return fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(b.args)-1, len(b.args))
+}
+
+// countRows возвращает число строк, подходящих под условия, без учета страницы
+func countRows(ctx context.Context, db *sql.DB, from string, where *whereBuilder) (int, error) {
+	var total int
+	err := conn(ctx, db).QueryRowContext(ctx, "SELECT COUNT(*) "+from+where.sql(), where.args...).Scan(&total)
+	return total, err
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Create записывает попытку входа в журнал
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	query := `
+		INSERT INTO login_attempts (login, doctor_id, ip, success, reason)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		attempt.Login,
+		attempt.DoctorID,
+		attempt.IP,
+		attempt.Success,
+		string(attempt.Reason),
+	).Scan(&attempt.ID, &attempt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка записи попытки входа: %w", err)
+	}
+
+	return nil
+}
+
+// CountFailuresByIP считает неудачные попытки входа с IP адреса начиная с since
+func (r *LoginAttemptRepository) CountFailuresByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM login_attempts WHERE ip = $1 AND NOT success AND created_at >= $2`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, ip, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("ошибка подсчета попыток входа: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetRecent получает последние попытки входа, при непустом login — только по этому логину
+func (r *LoginAttemptRepository) GetRecent(ctx context.Context, login string, limit int) ([]*domain.LoginAttempt, error) {
+	query := `
+		SELECT id, login, doctor_id, ip, success, COALESCE(reason, ''), created_at
+		FROM login_attempts
+		WHERE $1 = '' OR login = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, login, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения попыток входа: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := make([]*domain.LoginAttempt, 0)
+	for rows.Next() {
+		attempt := &domain.LoginAttempt{}
+		var doctorID sql.NullInt64
+		if err := rows.Scan(&attempt.ID, &attempt.Login, &doctorID, &attempt.IP, &attempt.Success, &attempt.Reason, &attempt.CreatedAt); err != nil {
+			return nil, err
+		}
+		if doctorID.Valid {
+			id := int(doctorID.Int64)
+			attempt.DoctorID = &id
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	repo := NewLoginAttemptRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
+
+	t.Run("CreateAndCount", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Audit", Login: "audit", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		since := time.Now().Add(-time.Minute)
+
+		require.NoError(t, repo.Create(ctx, &domain.LoginAttempt{Login: "audit", DoctorID: &doctor.ID, IP: "10.0.0.1", Reason: domain.LoginReasonInvalidPassword}))
+		require.NoError(t, repo.Create(ctx, &domain.LoginAttempt{Login: "ghost", IP: "10.0.0.1", Reason: domain.LoginReasonUnknownLogin}))
+		require.NoError(t, repo.Create(ctx, &domain.LoginAttempt{Login: "audit", DoctorID: &doctor.ID, IP: "10.0.0.1", Success: true}))
+		require.NoError(t, repo.Create(ctx, &domain.LoginAttempt{Login: "audit", IP: "10.0.0.2", Reason: domain.LoginReasonInvalidPassword}))
+
+		count, err := repo.CountFailuresByIP(ctx, "10.0.0.1", since)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		count, err = repo.CountFailuresByIP(ctx, "10.0.0.1", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		attempts, err := repo.GetRecent(ctx, "audit", 10)
+		require.NoError(t, err)
+		require.Len(t, attempts, 3)
+		assert.Equal(t, "10.0.0.2", attempts[0].IP)
+		assert.Nil(t, attempts[0].DoctorID)
+		assert.True(t, attempts[1].Success)
+		assert.Empty(t, attempts[1].Reason)
+		require.NotNil(t, attempts[1].DoctorID)
+		assert.Equal(t, doctor.ID, *attempts[1].DoctorID)
+
+		attempts, err = repo.GetRecent(ctx, "", 2)
+		require.NoError(t, err)
+		assert.Len(t, attempts, 2)
+	})
+}
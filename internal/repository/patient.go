@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -8,6 +9,20 @@ import (
 	"github.com/sdk17/crmstom/internal/domain"
 )
 
+// patientSelect общий SELECT для чтения пациентов; последний визит — дата последнего завершенного приема
+const patientSelect = `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, notes, created_at, updated_at,
+			  (SELECT MAX(a.appointment_date) FROM appointments a
+			   WHERE a.patient_id = patients.id AND a.status = 'completed' AND a.deleted_at IS NULL) AS last_visit
+			  FROM patients`
+
+// patientSortColumns сопоставляет полям сортировки из domain.PatientSortFields выражения SQL
+var patientSortColumns = map[string]string{
+	"name":       "name",
+	"birth_date": "birth_date",
+	"last_visit": "last_visit",
+	"created_at": "created_at",
+}
+
 type PatientRepository struct {
 	db *sql.DB
 }
@@ -16,118 +31,168 @@ func NewPatientRepository(db *sql.DB) *PatientRepository {
 	return &PatientRepository{db: db}
 }
 
-func (r *PatientRepository) Create(patient *domain.Patient) error {
-	query := `INSERT INTO patients (iin, name, phone, email, birth_date, address) 
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
-
-	err := r.db.QueryRow(query, patient.IIN, patient.Name, patient.Phone, patient.Email, patient.BirthDate, patient.Address).
-		Scan(&patient.ID, &patient.CreatedAt, &patient.UpdatedAt)
-
-	return err
-}
-
-func (r *PatientRepository) GetByID(id int) (*domain.Patient, error) {
-	query := `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, created_at, updated_at
-			  FROM patients WHERE id = $1 AND deleted_at IS NULL`
-
+// scanPatient читает одного пациента из результата patientSelect
+func scanPatient(row rowScanner) (*domain.Patient, error) {
 	patient := &domain.Patient{}
-	err := r.db.QueryRow(query, id).Scan(
+	var lastVisit sql.NullTime
+	err := row.Scan(
 		&patient.ID, &patient.IIN, &patient.Name, &patient.Phone, &patient.Email,
-		&patient.BirthDate, &patient.Address, &patient.CreatedAt, &patient.UpdatedAt,
+		&patient.BirthDate, &patient.Address, &patient.Notes, &patient.CreatedAt, &patient.UpdatedAt, &lastVisit,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("пациент с ID %d не найден", id)
-		}
 		return nil, err
 	}
-
+	if lastVisit.Valid {
+		patient.LastVisit = &lastVisit.Time
+	}
 	return patient, nil
 }
 
-func (r *PatientRepository) GetAll() ([]*domain.Patient, error) {
-	query := `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, created_at, updated_at
-			  FROM patients WHERE deleted_at IS NULL ORDER BY created_at DESC`
-
-	rows, err := r.db.Query(query)
+// queryPatients выполняет запрос и читает всех пациентов из результата
+func (r *PatientRepository) queryPatients(ctx context.Context, query string, args ...interface{}) ([]*domain.Patient, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var patients []*domain.Patient
+	patients := make([]*domain.Patient, 0)
 	for rows.Next() {
-		patient := &domain.Patient{}
-		err := rows.Scan(
-			&patient.ID, &patient.IIN, &patient.Name, &patient.Phone, &patient.Email,
-			&patient.BirthDate, &patient.Address, &patient.CreatedAt, &patient.UpdatedAt,
-		)
+		patient, err := scanPatient(rows)
 		if err != nil {
 			return nil, err
 		}
 		patients = append(patients, patient)
 	}
 
-	return patients, nil
+	return patients, rows.Err()
 }
 
-func (r *PatientRepository) Update(patient *domain.Patient) error {
-	query := `UPDATE patients SET iin = $1, name = $2, phone = $3, email = $4, birth_date = $5,
-			  address = $6, updated_at = CURRENT_TIMESTAMP
-			  WHERE id = $7 AND deleted_at IS NULL`
-
-	result, err := r.db.Exec(query, patient.IIN, patient.Name, patient.Phone, patient.Email,
-		patient.BirthDate, patient.Address, patient.ID)
-	if err != nil {
-		return err
+// lockPatient читает и блокирует пациента до конца транзакции, чтобы записать его состояние в аудит
+func lockPatient(ctx context.Context, tx *sql.Tx, id int) (*domain.Patient, error) {
+	patient, err := scanPatient(tx.QueryRowContext(ctx, patientSelect+` WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPatientNotFound.WithMessage("пациент с ID %d не найден", id)
 	}
+	return patient, err
+}
+
+func (r *PatientRepository) Create(ctx context.Context, patient *domain.Patient) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO patients (iin, name, phone, email, birth_date, address, notes)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRowContext(ctx, query, patient.IIN, patient.Name, patient.Phone, patient.Email, patient.BirthDate, patient.Address, patient.Notes).
+			Scan(&patient.ID, &patient.CreatedAt, &patient.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		patient.LastVisit = nil
+
+		created, err := lockPatient(ctx, tx, patient.ID)
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := result.RowsAffected()
+		return writeAudit(ctx, tx, domain.AuditEntityPatient, patient.ID, domain.AuditActionCreate, nil, created)
+	})
+}
+
+func (r *PatientRepository) GetByID(ctx context.Context, id int) (*domain.Patient, error) {
+	query := patientSelect + ` WHERE id = $1 AND deleted_at IS NULL`
+
+	patient, err := scanPatient(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPatientNotFound.WithMessage("пациент с ID %d не найден", id)
+		}
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("пациент с ID %d не найден", patient.ID)
-	}
+	return patient, nil
+}
 
-	return nil
+func (r *PatientRepository) GetAll(ctx context.Context) ([]*domain.Patient, error) {
+	query := patientSelect + ` WHERE deleted_at IS NULL ORDER BY created_at DESC`
+
+	return r.queryPatients(ctx, query)
 }
 
-func (r *PatientRepository) Delete(id int) error {
-	query := `UPDATE patients SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+// List получает страницу пациентов по фильтру и общее число подходящих пациентов
+func (r *PatientRepository) List(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
+	where := &whereBuilder{}
+	where.addRaw("deleted_at IS NULL")
+	if filter.Query != "" {
+		where.add("(COALESCE(iin, '') ILIKE $%[1]d OR name ILIKE $%[1]d OR phone ILIKE $%[1]d OR email ILIKE $%[1]d)", "%"+filter.Query+"%")
+	}
 
-	result, err := r.db.Exec(query, id)
+	total, err := countRows(ctx, r.db, "FROM patients", where)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("ошибка подсчета пациентов: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := patientSelect + where.sql() +
+		orderBy(filter.Sort, patientSortColumns, domain.Sort{Field: "created_at", Desc: true}, "id") +
+		where.limitOffset(filter.Page)
+	patients, err := r.queryPatients(ctx, query, where.args...)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("ошибка получения списка пациентов: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("пациент с ID %d не найден", id)
-	}
+	return patients, total, nil
+}
 
-	return nil
+func (r *PatientRepository) Update(ctx context.Context, patient *domain.Patient) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockPatient(ctx, tx, patient.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE patients SET iin = $1, name = $2, phone = $3, email = $4, birth_date = $5,
+				  address = $6, notes = $7, updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $8 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, patient.IIN, patient.Name, patient.Phone, patient.Email,
+			patient.BirthDate, patient.Address, patient.Notes, patient.ID); err != nil {
+			return err
+		}
+
+		after, err := lockPatient(ctx, tx, patient.ID)
+		if err != nil {
+			return err
+		}
+		// Последний визит вычисляется по записям и не меняется через карточку пациента
+		patient.LastVisit = after.LastVisit
+
+		return writeAudit(ctx, tx, domain.AuditEntityPatient, patient.ID, domain.AuditActionUpdate, before, after)
+	})
 }
 
-func (r *PatientRepository) GetByPhone(phone string) (*domain.Patient, error) {
-	query := `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, created_at, updated_at
-			  FROM patients WHERE phone = $1 AND deleted_at IS NULL`
+func (r *PatientRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockPatient(ctx, tx, id)
+		if err != nil {
+			return err
+		}
 
-	patient := &domain.Patient{}
-	err := r.db.QueryRow(query, phone).Scan(
-		&patient.ID, &patient.IIN, &patient.Name, &patient.Phone, &patient.Email,
-		&patient.BirthDate, &patient.Address, &patient.CreatedAt, &patient.UpdatedAt,
-	)
+		query := `UPDATE patients SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityPatient, id, domain.AuditActionDelete, before, nil)
+	})
+}
 
+func (r *PatientRepository) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
+	query := patientSelect + ` WHERE phone = $1 AND deleted_at IS NULL`
+
+	patient, err := scanPatient(conn(ctx, r.db).QueryRowContext(ctx, query, phone))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("пациент с телефоном %s не найден", phone)
+			return nil, domain.ErrPatientNotFound.WithMessage("пациент с телефоном %s не найден", phone)
 		}
 		return nil, err
 	}
@@ -135,19 +200,13 @@ func (r *PatientRepository) GetByPhone(phone string) (*domain.Patient, error) {
 	return patient, nil
 }
 
-func (r *PatientRepository) GetByIIN(iin string) (*domain.Patient, error) {
-	query := `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, created_at, updated_at
-			  FROM patients WHERE iin = $1 AND deleted_at IS NULL`
-
-	patient := &domain.Patient{}
-	err := r.db.QueryRow(query, iin).Scan(
-		&patient.ID, &patient.IIN, &patient.Name, &patient.Phone, &patient.Email,
-		&patient.BirthDate, &patient.Address, &patient.CreatedAt, &patient.UpdatedAt,
-	)
+func (r *PatientRepository) GetByIIN(ctx context.Context, iin string) (*domain.Patient, error) {
+	query := patientSelect + ` WHERE iin = $1 AND deleted_at IS NULL`
 
+	patient, err := scanPatient(conn(ctx, r.db).QueryRowContext(ctx, query, iin))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("пациент с ИИН %s не найден", iin)
+			return nil, domain.ErrPatientNotFound.WithMessage("пациент с ИИН %s не найден", iin)
 		}
 		return nil, err
 	}
@@ -155,29 +214,9 @@ func (r *PatientRepository) GetByIIN(iin string) (*domain.Patient, error) {
 	return patient, nil
 }
 
-func (r *PatientRepository) Search(query string) ([]*domain.Patient, error) {
-	searchQuery := `SELECT id, COALESCE(iin, ''), name, phone, email, birth_date, address, created_at, updated_at
-					FROM patients WHERE deleted_at IS NULL AND (COALESCE(iin, '') ILIKE $1 OR name ILIKE $1 OR phone ILIKE $1 OR email ILIKE $1)
+func (r *PatientRepository) Search(ctx context.Context, query string) ([]*domain.Patient, error) {
+	searchQuery := patientSelect + ` WHERE deleted_at IS NULL AND (COALESCE(iin, '') ILIKE $1 OR name ILIKE $1 OR phone ILIKE $1 OR email ILIKE $1)
 					ORDER BY name`
 
-	rows, err := r.db.Query(searchQuery, "%"+query+"%")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var patients []*domain.Patient
-	for rows.Next() {
-		patient := &domain.Patient{}
-		err := rows.Scan(
-			&patient.ID, &patient.IIN, &patient.Name, &patient.Phone, &patient.Email,
-			&patient.BirthDate, &patient.Address, &patient.CreatedAt, &patient.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		patients = append(patients, patient)
-	}
-
-	return patients, nil
+	return r.queryPatients(ctx, searchQuery, "%"+query+"%")
 }
@@ -36,7 +36,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Address:   "123 Main St",
 		}
 
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 		assert.Greater(t, patient.ID, 0)
 		assert.False(t, patient.CreatedAt.IsZero())
@@ -54,10 +54,10 @@ func TestPatientRepository_Integration(t *testing.T) {
 			BirthDate: time.Date(1985, 3, 20, 0, 0, 0, 0, time.UTC),
 			Address:   "456 Oak Ave",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(patient.ID)
+		found, err := repo.GetByID(ctx, patient.ID)
 		require.NoError(t, err)
 		assert.Equal(t, patient.ID, found.ID)
 		assert.Equal(t, patient.Name, found.Name)
@@ -69,7 +69,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		_, err = repo.GetByID(9999)
+		_, err = repo.GetByID(ctx, 9999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найден")
 	})
@@ -85,15 +85,43 @@ func TestPatientRepository_Integration(t *testing.T) {
 		}
 
 		for _, p := range patients {
-			err := repo.Create(p)
+			err := repo.Create(ctx, p)
 			require.NoError(t, err)
 		}
 
-		all, err := repo.GetAll()
+		all, err := repo.GetAll(ctx)
 		require.NoError(t, err)
 		assert.Len(t, all, 3)
 	})
 
+	t.Run("List", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		for _, name := range []string{"Борис", "Анна", "Вера", "Алексей"} {
+			err := repo.Create(ctx, &domain.Patient{Name: name, Phone: "+7 777 111 1111"})
+			require.NoError(t, err)
+		}
+
+		page, total, err := repo.List(ctx, domain.PatientFilter{Sort: domain.Sort{Field: "name"}, Page: domain.Page{Limit: 2, Offset: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, page, 2)
+		assert.Equal(t, "Анна", page[0].Name)
+		assert.Equal(t, "Борис", page[1].Name)
+
+		found, total, err := repo.List(ctx, domain.PatientFilter{Query: "лекс", Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, found, 1)
+		assert.Equal(t, "Алексей", found[0].Name)
+
+		empty, total, err := repo.List(ctx, domain.PatientFilter{Page: domain.Page{Limit: 10, Offset: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Empty(t, empty)
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
@@ -102,15 +130,15 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "Original Name",
 			Phone: "+7 777 444 4444",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
 		patient.Name = "Updated Name"
 		patient.Email = "updated@example.com"
-		err = repo.Update(patient)
+		err = repo.Update(ctx, patient)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(patient.ID)
+		found, err := repo.GetByID(ctx, patient.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "Updated Name", found.Name)
 		assert.Equal(t, "updated@example.com", found.Email)
@@ -125,7 +153,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "Non-existent",
 			Phone: "+7 777 555 5555",
 		}
-		err = repo.Update(patient)
+		err = repo.Update(ctx, patient)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найден")
 	})
@@ -138,13 +166,13 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "To Delete",
 			Phone: "+7 777 666 6666",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
-		err = repo.Delete(patient.ID)
+		err = repo.Delete(ctx, patient.ID)
 		require.NoError(t, err)
 
-		_, err = repo.GetByID(patient.ID)
+		_, err = repo.GetByID(ctx, patient.ID)
 		assert.Error(t, err)
 	})
 
@@ -152,7 +180,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		err = repo.Delete(9999)
+		err = repo.Delete(ctx, 9999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найден")
 	})
@@ -165,10 +193,10 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "Phone Test",
 			Phone: "+7 777 777 7777",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
-		found, err := repo.GetByPhone("+7 777 777 7777")
+		found, err := repo.GetByPhone(ctx, "+7 777 777 7777")
 		require.NoError(t, err)
 		assert.Equal(t, patient.ID, found.ID)
 		assert.Equal(t, patient.Name, found.Name)
@@ -178,7 +206,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		_, err = repo.GetByPhone("+7 777 999 9999")
+		_, err = repo.GetByPhone(ctx, "+7 777 999 9999")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найден")
 	})
@@ -194,22 +222,22 @@ func TestPatientRepository_Integration(t *testing.T) {
 		}
 
 		for _, p := range patients {
-			err := repo.Create(p)
+			err := repo.Create(ctx, p)
 			require.NoError(t, err)
 		}
 
 		// Search by name
-		results, err := repo.Search("Smith")
+		results, err := repo.Search(ctx, "Smith")
 		require.NoError(t, err)
 		assert.Len(t, results, 2)
 
 		// Search by email domain
-		results, err = repo.Search("test.com")
+		results, err = repo.Search(ctx, "test.com")
 		require.NoError(t, err)
 		assert.Len(t, results, 2)
 
 		// Search by phone
-		results, err = repo.Search("100 0003")
+		results, err = repo.Search(ctx, "100 0003")
 		require.NoError(t, err)
 		assert.Len(t, results, 1)
 		assert.Equal(t, "Bob Johnson", results[0].Name)
@@ -228,11 +256,11 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Address:   "123 Main St",
 		}
 
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 		assert.Greater(t, patient.ID, 0)
 
-		found, err := repo.GetByID(patient.ID)
+		found, err := repo.GetByID(ctx, patient.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "123456789012", found.IIN)
 	})
@@ -246,10 +274,10 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "IIN Test Patient",
 			Phone: "+7 777 888 8888",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
-		found, err := repo.GetByIIN("987654321012")
+		found, err := repo.GetByIIN(ctx, "987654321012")
 		require.NoError(t, err)
 		assert.Equal(t, patient.ID, found.ID)
 		assert.Equal(t, patient.Name, found.Name)
@@ -260,7 +288,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		_, err = repo.GetByIIN("000000000000")
+		_, err = repo.GetByIIN(ctx, "000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найден")
 	})
@@ -274,15 +302,15 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "Original Name",
 			Phone: "+7 777 444 4444",
 		}
-		err = repo.Create(patient)
+		err = repo.Create(ctx, patient)
 		require.NoError(t, err)
 
 		patient.IIN = "222222222222"
 		patient.Name = "Updated Name"
-		err = repo.Update(patient)
+		err = repo.Update(ctx, patient)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(patient.ID)
+		found, err := repo.GetByID(ctx, patient.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "222222222222", found.IIN)
 		assert.Equal(t, "Updated Name", found.Name)
@@ -299,15 +327,15 @@ func TestPatientRepository_Integration(t *testing.T) {
 		}
 
 		for _, p := range patients {
-			err := repo.Create(p)
+			err := repo.Create(ctx, p)
 			require.NoError(t, err)
 		}
 
-		results, err := repo.Search("111")
+		results, err := repo.Search(ctx, "111")
 		require.NoError(t, err)
 		assert.Len(t, results, 2)
 
-		results, err = repo.Search("555666777888")
+		results, err = repo.Search(ctx, "555666777888")
 		require.NoError(t, err)
 		assert.Len(t, results, 1)
 		assert.Equal(t, "Patient Two", results[0].Name)
@@ -322,7 +350,7 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "First Patient",
 			Phone: "+7 777 100 0001",
 		}
-		err = repo.Create(patient1)
+		err = repo.Create(ctx, patient1)
 		require.NoError(t, err)
 
 		patient2 := &domain.Patient{
@@ -330,7 +358,74 @@ func TestPatientRepository_Integration(t *testing.T) {
 			Name:  "Second Patient",
 			Phone: "+7 777 100 0002",
 		}
-		err = repo.Create(patient2)
+		err = repo.Create(ctx, patient2)
 		assert.Error(t, err)
 	})
+
+	t.Run("Notes", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := &domain.Patient{
+			Name:  "Patient With Notes",
+			Phone: "+7 777 100 0003",
+			Notes: "Аллергия на лидокаин",
+		}
+		require.NoError(t, repo.Create(ctx, patient))
+
+		found, err := repo.GetByID(ctx, patient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Аллергия на лидокаин", found.Notes)
+
+		patient.Notes = "Аллергия на лидокаин, артикаин без ограничений"
+		require.NoError(t, repo.Update(ctx, patient))
+
+		found, err = repo.GetByID(ctx, patient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Аллергия на лидокаин, артикаин без ограничений", found.Notes)
+	})
+
+	t.Run("LastVisit", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		serviceRepo := NewServiceRepository(testDB.DB)
+		appointmentRepo := NewAppointmentRepository(testDB.DB)
+
+		patient := &domain.Patient{Name: "Returning Patient", Phone: "+7 777 100 0004"}
+		require.NoError(t, repo.Create(ctx, patient))
+		assert.Nil(t, patient.LastVisit)
+
+		service := &domain.Service{Name: "Осмотр", Type: "Терапия"}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		visits := []struct {
+			date   time.Time
+			status domain.AppointmentStatus
+		}{
+			{date: time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC), status: domain.StatusCompleted},
+			{date: time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC), status: domain.StatusCompleted},
+			{date: time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC), status: domain.StatusCancelled},
+			{date: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), status: domain.StatusScheduled},
+		}
+		for _, visit := range visits {
+			require.NoError(t, appointmentRepo.Create(ctx, &domain.Appointment{
+				PatientID: patient.ID,
+				ServiceID: service.ID,
+				Date:      visit.date,
+				Status:    visit.status,
+				Duration:  30,
+			}))
+		}
+
+		found, err := repo.GetByID(ctx, patient.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.LastVisit)
+		assert.True(t, found.LastVisit.Equal(time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)))
+
+		patient.LastVisit = nil
+		require.NoError(t, repo.Update(ctx, patient))
+		require.NotNil(t, patient.LastVisit)
+		assert.True(t, patient.LastVisit.Equal(*found.LastVisit))
+	})
 }
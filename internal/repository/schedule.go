@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// GetWorkingHours получает недельный шаблон рабочих часов врача
+func (r *ScheduleRepository) GetWorkingHours(ctx context.Context, doctorID int) ([]*domain.WorkingHours, error) {
+	query := `SELECT id, doctor_id, weekday, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI')
+			  FROM doctor_working_hours WHERE doctor_id = $1 ORDER BY weekday, start_time`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, doctorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := make([]*domain.WorkingHours, 0)
+	for rows.Next() {
+		h := &domain.WorkingHours{}
+		if err := rows.Scan(&h.ID, &h.DoctorID, &h.Weekday, &h.StartTime, &h.EndTime); err != nil {
+			return nil, err
+		}
+		hours = append(hours, h)
+	}
+
+	return hours, rows.Err()
+}
+
+// GetBreaks получает перерывы врача по дням недели
+func (r *ScheduleRepository) GetBreaks(ctx context.Context, doctorID int) ([]*domain.ScheduleBreak, error) {
+	query := `SELECT id, doctor_id, weekday, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), COALESCE(title, '')
+			  FROM doctor_breaks WHERE doctor_id = $1 ORDER BY weekday, start_time`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, doctorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breaks := make([]*domain.ScheduleBreak, 0)
+	for rows.Next() {
+		b := &domain.ScheduleBreak{}
+		if err := rows.Scan(&b.ID, &b.DoctorID, &b.Weekday, &b.StartTime, &b.EndTime, &b.Title); err != nil {
+			return nil, err
+		}
+		breaks = append(breaks, b)
+	}
+
+	return breaks, rows.Err()
+}
+
+// ReplaceWeeklySchedule заменяет недельный шаблон рабочих часов и перерывов врача в одной транзакции
+func (r *ScheduleRepository) ReplaceWeeklySchedule(ctx context.Context, doctorID int, hours []*domain.WorkingHours, breaks []*domain.ScheduleBreak) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM doctor_working_hours WHERE doctor_id = $1`, doctorID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM doctor_breaks WHERE doctor_id = $1`, doctorID); err != nil {
+			return err
+		}
+
+		for _, h := range hours {
+			h.DoctorID = doctorID
+			err := tx.QueryRowContext(ctx, `INSERT INTO doctor_working_hours (doctor_id, weekday, start_time, end_time)
+				  VALUES ($1, $2, $3, $4) RETURNING id`, doctorID, h.Weekday, h.StartTime, h.EndTime).Scan(&h.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, b := range breaks {
+			b.DoctorID = doctorID
+			err := tx.QueryRowContext(ctx, `INSERT INTO doctor_breaks (doctor_id, weekday, start_time, end_time, title)
+				  VALUES ($1, $2, $3, $4, $5) RETURNING id`, doctorID, b.Weekday, b.StartTime, b.EndTime, b.Title).Scan(&b.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetExceptions получает исключения из графика врача, пересекающиеся с периодом [from, to]
+func (r *ScheduleRepository) GetExceptions(ctx context.Context, doctorID int, from, to time.Time) ([]*domain.ScheduleException, error) {
+	query := `SELECT id, doctor_id, type, date_from, date_to,
+			  COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+			  COALESCE(reason, ''), created_at
+			  FROM doctor_schedule_exceptions
+			  WHERE doctor_id = $1 AND date_from <= $3::date AND date_to >= $2::date
+			  ORDER BY date_from`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exceptions := make([]*domain.ScheduleException, 0)
+	for rows.Next() {
+		e := &domain.ScheduleException{}
+		err := rows.Scan(&e.ID, &e.DoctorID, &e.Type, &e.DateFrom, &e.DateTo,
+			&e.StartTime, &e.EndTime, &e.Reason, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, e)
+	}
+
+	return exceptions, rows.Err()
+}
+
+// CreateException создает исключение из графика врача
+func (r *ScheduleRepository) CreateException(ctx context.Context, exception *domain.ScheduleException) error {
+	query := `INSERT INTO doctor_schedule_exceptions (doctor_id, type, date_from, date_to, start_time, end_time, reason)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, '')::time, NULLIF($6, '')::time, $7) RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, exception.DoctorID, exception.Type, exception.DateFrom, exception.DateTo,
+		exception.StartTime, exception.EndTime, exception.Reason).
+		Scan(&exception.ID, &exception.CreatedAt)
+}
+
+// DeleteException удаляет исключение из графика врача
+func (r *ScheduleRepository) DeleteException(ctx context.Context, doctorID, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM doctor_schedule_exceptions WHERE id = $1 AND doctor_id = $2`, id, doctorID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrScheduleExceptionNotFound.WithMessage("исключение графика с ID %d не найдено", id)
+	}
+
+	return nil
+}
+
+// GetHolidays получает праздничные дни клиники в периоде [from, to]
+func (r *ScheduleRepository) GetHolidays(ctx context.Context, from, to time.Time) ([]*domain.ClinicHoliday, error) {
+	query := `SELECT id, holiday_date, name, created_at FROM clinic_holidays
+			  WHERE holiday_date BETWEEN $1::date AND $2::date ORDER BY holiday_date`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := make([]*domain.ClinicHoliday, 0)
+	for rows.Next() {
+		h := &domain.ClinicHoliday{}
+		if err := rows.Scan(&h.ID, &h.Date, &h.Name, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+
+	return holidays, rows.Err()
+}
+
+// CreateHoliday создает праздничный день клиники
+func (r *ScheduleRepository) CreateHoliday(ctx context.Context, holiday *domain.ClinicHoliday) error {
+	query := `INSERT INTO clinic_holidays (holiday_date, name) VALUES ($1, $2) RETURNING id, created_at`
+
+	return conn(ctx, r.db).QueryRowContext(ctx, query, holiday.Date, holiday.Name).Scan(&holiday.ID, &holiday.CreatedAt)
+}
+
+// DeleteHoliday удаляет праздничный день клиники
+func (r *ScheduleRepository) DeleteHoliday(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM clinic_holidays WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrHolidayNotFound.WithMessage("праздничный день с ID %d не найден", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	repo := NewScheduleRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
+
+	createDoctor := func(t *testing.T) *domain.Doctor {
+		doctor := &domain.Doctor{Name: "Dr. Schedule", Login: "dr_schedule", Password: "pass123"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+		return doctor
+	}
+
+	t.Run("ReplaceWeeklySchedule", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+		doctor := createDoctor(t)
+
+		err = repo.ReplaceWeeklySchedule(ctx, doctor.ID,
+			[]*domain.WorkingHours{{Weekday: time.Monday, StartTime: "09:00", EndTime: "13:00"}},
+			[]*domain.ScheduleBreak{{Weekday: time.Monday, StartTime: "11:00", EndTime: "11:15", Title: "Кофе"}},
+		)
+		require.NoError(t, err)
+
+		err = repo.ReplaceWeeklySchedule(ctx, doctor.ID,
+			[]*domain.WorkingHours{
+				{Weekday: time.Tuesday, StartTime: "10:00", EndTime: "19:00"},
+				{Weekday: time.Thursday, StartTime: "08:30", EndTime: "14:00"},
+			},
+			[]*domain.ScheduleBreak{{Weekday: time.Tuesday, StartTime: "13:00", EndTime: "14:00", Title: "Обед"}},
+		)
+		require.NoError(t, err)
+
+		hours, err := repo.GetWorkingHours(ctx, doctor.ID)
+		require.NoError(t, err)
+		require.Len(t, hours, 2)
+		assert.Equal(t, time.Tuesday, hours[0].Weekday)
+		assert.Equal(t, "10:00", hours[0].StartTime)
+		assert.Equal(t, "08:30", hours[1].StartTime)
+
+		breaks, err := repo.GetBreaks(ctx, doctor.ID)
+		require.NoError(t, err)
+		require.Len(t, breaks, 1)
+		assert.Equal(t, "Обед", breaks[0].Title)
+	})
+
+	t.Run("Exceptions", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+		doctor := createDoctor(t)
+
+		day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+		vacation := &domain.ScheduleException{
+			DoctorID: doctor.ID,
+			Type:     domain.ExceptionVacation,
+			DateFrom: day,
+			DateTo:   day.AddDate(0, 0, 4),
+			Reason:   "Отпуск",
+		}
+		require.NoError(t, repo.CreateException(ctx, vacation))
+		assert.Greater(t, vacation.ID, 0)
+
+		custom := &domain.ScheduleException{
+			DoctorID:  doctor.ID,
+			Type:      domain.ExceptionCustomHours,
+			DateFrom:  day.AddDate(0, 0, 10),
+			DateTo:    day.AddDate(0, 0, 10),
+			StartTime: "12:00",
+			EndTime:   "16:00",
+		}
+		require.NoError(t, repo.CreateException(ctx, custom))
+
+		exceptions, err := repo.GetExceptions(ctx, doctor.ID, day.AddDate(0, 0, 2), day.AddDate(0, 0, 3))
+		require.NoError(t, err)
+		require.Len(t, exceptions, 1)
+		assert.Equal(t, domain.ExceptionVacation, exceptions[0].Type)
+		assert.Empty(t, exceptions[0].StartTime)
+
+		exceptions, err = repo.GetExceptions(ctx, doctor.ID, day, day.AddDate(0, 0, 30))
+		require.NoError(t, err)
+		require.Len(t, exceptions, 2)
+		assert.Equal(t, "12:00", exceptions[1].StartTime)
+		assert.Equal(t, "16:00", exceptions[1].EndTime)
+
+		require.NoError(t, repo.DeleteException(ctx, doctor.ID, vacation.ID))
+		assert.Error(t, repo.DeleteException(ctx, doctor.ID, vacation.ID))
+		assert.Error(t, repo.DeleteException(ctx, doctor.ID+1, custom.ID))
+	})
+
+	t.Run("Holidays", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		day := time.Date(2030, 3, 22, 0, 0, 0, 0, time.UTC)
+		holiday := &domain.ClinicHoliday{Date: day, Name: "Наурыз"}
+		require.NoError(t, repo.CreateHoliday(ctx, holiday))
+		assert.Greater(t, holiday.ID, 0)
+
+		assert.Error(t, repo.CreateHoliday(ctx, &domain.ClinicHoliday{Date: day, Name: "Дубль"}))
+
+		holidays, err := repo.GetHolidays(ctx, day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Len(t, holidays, 1)
+		assert.Equal(t, "Наурыз", holidays[0].Name)
+
+		holidays, err = repo.GetHolidays(ctx, day.AddDate(0, 0, 1), day.AddDate(0, 0, 5))
+		require.NoError(t, err)
+		assert.Empty(t, holidays)
+
+		require.NoError(t, repo.DeleteHoliday(ctx, holiday.ID))
+		assert.Error(t, repo.DeleteHoliday(ctx, holiday.ID))
+	})
+}
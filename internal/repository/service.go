@@ -1,12 +1,35 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
 )
 
+// serviceSelect общий SELECT для чтения услуг вместе с действующей сейчас ценой
+const serviceSelect = `SELECT id, name, type, notes, price, duration_minutes,
+			  COALESCE((SELECT sp.price FROM service_prices sp
+			            WHERE sp.service_id = services.id AND sp.effective_from <= CURRENT_TIMESTAMP
+			            ORDER BY sp.effective_from DESC LIMIT 1), price) AS current_price,
+			  created_at, updated_at
+			  FROM services`
+
+// servicePriceSelect общий SELECT для чтения истории цен услуг
+const servicePriceSelect = `SELECT id, service_id, price, effective_from, created_at FROM service_prices`
+
+// serviceSortColumns сопоставляет полям сортировки из domain.ServiceSortFields выражения SQL;
+// цена сортируется по действующей сейчас цене
+var serviceSortColumns = map[string]string{
+	"name":       "name",
+	"type":       "type",
+	"price":      "current_price",
+	"duration":   "duration_minutes",
+	"created_at": "created_at",
+}
+
 type ServiceRepository struct {
 	db *sql.DB
 }
@@ -15,147 +38,278 @@ func NewServiceRepository(db *sql.DB) *ServiceRepository {
 	return &ServiceRepository{db: db}
 }
 
-func (r *ServiceRepository) Create(service *domain.Service) error {
-	query := `INSERT INTO services (name, type, notes) 
-			  VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
-
-	err := r.db.QueryRow(query, service.Name, service.Type, service.Notes).
-		Scan(&service.ID, &service.CreatedAt, &service.UpdatedAt)
-
-	return err
-}
-
-func (r *ServiceRepository) GetByID(id int) (*domain.Service, error) {
-	query := `SELECT id, name, type, notes, created_at, updated_at
-			  FROM services WHERE id = $1 AND deleted_at IS NULL`
-
+// scanService читает одну услугу из результата serviceSelect
+func scanService(row rowScanner) (*domain.Service, error) {
 	service := &domain.Service{}
-	err := r.db.QueryRow(query, id).Scan(
-		&service.ID, &service.Name, &service.Type, &service.Notes, &service.CreatedAt, &service.UpdatedAt,
+	var notes sql.NullString
+	err := row.Scan(
+		&service.ID, &service.Name, &service.Type, &notes, &service.Price, &service.Duration,
+		&service.CurrentPrice, &service.CreatedAt, &service.UpdatedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("услуга с ID %d не найдена", id)
-		}
 		return nil, err
 	}
-
+	service.Notes = notes.String
 	return service, nil
 }
 
-func (r *ServiceRepository) GetAll() ([]*domain.Service, error) {
-	query := `SELECT id, name, type, notes, created_at, updated_at
-			  FROM services WHERE deleted_at IS NULL ORDER BY name`
+// scanServicePrice читает одно изменение цены из результата servicePriceSelect
+func scanServicePrice(row rowScanner) (*domain.ServicePrice, error) {
+	price := &domain.ServicePrice{}
+	if err := row.Scan(&price.ID, &price.ServiceID, &price.Price, &price.EffectiveFrom, &price.CreatedAt); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
 
-	rows, err := r.db.Query(query)
+// queryServices выполняет запрос и читает все услуги из результата
+func (r *ServiceRepository) queryServices(ctx context.Context, query string, args ...interface{}) ([]*domain.Service, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var services []*domain.Service
+	services := make([]*domain.Service, 0)
 	for rows.Next() {
-		service := &domain.Service{}
-		err := rows.Scan(
-			&service.ID, &service.Name, &service.Type, &service.Notes, &service.CreatedAt, &service.UpdatedAt,
-		)
+		service, err := scanService(rows)
 		if err != nil {
 			return nil, err
 		}
 		services = append(services, service)
 	}
 
-	return services, nil
+	return services, rows.Err()
 }
 
-func (r *ServiceRepository) Update(service *domain.Service) error {
-	query := `UPDATE services SET name = $1, type = $2, notes = $3, updated_at = CURRENT_TIMESTAMP
-			  WHERE id = $4 AND deleted_at IS NULL`
-
-	result, err := r.db.Exec(query, service.Name, service.Type, service.Notes, service.ID)
-	if err != nil {
-		return err
+// lockService читает и блокирует услугу до конца транзакции, чтобы записать ее состояние в аудит
+func lockService(ctx context.Context, tx *sql.Tx, id int) (*domain.Service, error) {
+	service, err := scanService(tx.QueryRowContext(ctx, serviceSelect+` WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", id)
 	}
+	return service, err
+}
 
-	rowsAffected, err := result.RowsAffected()
+func (r *ServiceRepository) Create(ctx context.Context, service *domain.Service) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO services (name, type, notes, price, duration_minutes)
+				  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRowContext(ctx, query, service.Name, service.Type, service.Notes, service.Price, service.Duration).
+			Scan(&service.ID, &service.CreatedAt, &service.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		service.CurrentPrice = service.Price
+
+		// Базовая цена становится первой записью истории, чтобы цены прошлых дат не зависели от ее правок
+		history := `INSERT INTO service_prices (service_id, price, effective_from)
+					SELECT id, price, created_at FROM services WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, history, service.ID); err != nil {
+			return fmt.Errorf("ошибка сохранения истории цен услуги: %w", err)
+		}
+
+		created, err := lockService(ctx, tx, service.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityService, service.ID, domain.AuditActionCreate, nil, created)
+	})
+}
+
+func (r *ServiceRepository) GetByID(ctx context.Context, id int) (*domain.Service, error) {
+	query := serviceSelect + ` WHERE id = $1 AND deleted_at IS NULL`
+
+	service, err := scanService(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", id)
+		}
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("услуга с ID %d не найдена", service.ID)
-	}
+	return service, nil
+}
+
+func (r *ServiceRepository) GetAll(ctx context.Context) ([]*domain.Service, error) {
+	query := serviceSelect + ` WHERE deleted_at IS NULL ORDER BY name`
 
-	return nil
+	return r.queryServices(ctx, query)
 }
 
-func (r *ServiceRepository) Delete(id int) error {
-	query := `UPDATE services SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+// List получает страницу услуг по фильтру и общее число подходящих услуг
+func (r *ServiceRepository) List(ctx context.Context, filter domain.ServiceFilter) ([]*domain.Service, int, error) {
+	where := &whereBuilder{}
+	where.addRaw("deleted_at IS NULL")
+	if filter.Query != "" {
+		where.add("(name ILIKE $%[1]d OR notes ILIKE $%[1]d)", "%"+filter.Query+"%")
+	}
+	if filter.Type != "" {
+		where.add("type = $%d", filter.Type)
+	}
 
-	result, err := r.db.Exec(query, id)
+	total, err := countRows(ctx, r.db, "FROM services", where)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("ошибка подсчета услуг: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := serviceSelect + where.sql() +
+		orderBy(filter.Sort, serviceSortColumns, domain.Sort{Field: "name"}, "id") +
+		where.limitOffset(filter.Page)
+	services, err := r.queryServices(ctx, query, where.args...)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("ошибка получения списка услуг: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("услуга с ID %d не найдена", id)
-	}
+	return services, total, nil
+}
+
+func (r *ServiceRepository) Update(ctx context.Context, service *domain.Service) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockService(ctx, tx, service.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE services SET name = $1, type = $2, notes = $3, price = $4, duration_minutes = $5,
+				  updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $6 AND deleted_at IS NULL`
 
-	return nil
+		if _, err := tx.ExecContext(ctx, query, service.Name, service.Type, service.Notes, service.Price, service.Duration, service.ID); err != nil {
+			return err
+		}
+
+		// Изменение базовой цены действует с текущего момента и не меняет цены прошлых дат
+		if service.Price != before.Price {
+			history := `INSERT INTO service_prices (service_id, price, effective_from)
+						VALUES ($1, $2, CURRENT_TIMESTAMP)
+						ON CONFLICT (service_id, effective_from) DO UPDATE SET price = EXCLUDED.price`
+			if _, err := tx.ExecContext(ctx, history, service.ID, service.Price); err != nil {
+				return fmt.Errorf("ошибка сохранения истории цен услуги: %w", err)
+			}
+		}
+
+		after, err := lockService(ctx, tx, service.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityService, service.ID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+func (r *ServiceRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockService(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE services SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityService, id, domain.AuditActionDelete, before, nil)
+	})
 }
 
-func (r *ServiceRepository) GetByCategory(category string) ([]*domain.Service, error) {
-	query := `SELECT id, name, type, notes, created_at, updated_at
-			  FROM services WHERE deleted_at IS NULL AND type = $1 ORDER BY name`
+func (r *ServiceRepository) GetByCategory(ctx context.Context, category string) ([]*domain.Service, error) {
+	query := serviceSelect + ` WHERE deleted_at IS NULL AND type = $1 ORDER BY name`
+
+	return r.queryServices(ctx, query, category)
+}
 
-	rows, err := r.db.Query(query, category)
+func (r *ServiceRepository) Search(ctx context.Context, query string) ([]*domain.Service, error) {
+	searchQuery := serviceSelect + ` WHERE deleted_at IS NULL AND (name ILIKE $1 OR notes ILIKE $1) ORDER BY name`
+
+	return r.queryServices(ctx, searchQuery, "%"+query+"%")
+}
+
+// GetPriceHistory получает историю изменений цены услуги, начиная с последнего
+func (r *ServiceRepository) GetPriceHistory(ctx context.Context, serviceID int) ([]*domain.ServicePrice, error) {
+	query := servicePriceSelect + ` WHERE service_id = $1 ORDER BY effective_from DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, serviceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var services []*domain.Service
+	prices := make([]*domain.ServicePrice, 0)
 	for rows.Next() {
-		service := &domain.Service{}
-		err := rows.Scan(
-			&service.ID, &service.Name, &service.Type, &service.Notes, &service.CreatedAt, &service.UpdatedAt,
-		)
+		price, err := scanServicePrice(rows)
 		if err != nil {
 			return nil, err
 		}
-		services = append(services, service)
+		prices = append(prices, price)
 	}
 
-	return services, nil
+	return prices, rows.Err()
 }
 
-func (r *ServiceRepository) Search(query string) ([]*domain.Service, error) {
-	searchQuery := `SELECT id, name, type, notes, created_at, updated_at
-					FROM services WHERE deleted_at IS NULL AND (name ILIKE $1 OR notes ILIKE $1) ORDER BY name`
+// AddPrice добавляет изменение цены услуги; изменение на тот же момент заменяет предыдущее
+func (r *ServiceRepository) AddPrice(ctx context.Context, price *domain.ServicePrice) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Прежняя цена на тот же момент попадает в аудит как значение до изменения
+		before, err := scanServicePrice(tx.QueryRowContext(ctx, servicePriceSelect+` WHERE service_id = $1 AND effective_from = $2 FOR UPDATE`,
+			price.ServiceID, price.EffectiveFrom))
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
 
-	rows, err := r.db.Query(searchQuery, "%"+query+"%")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+		query := `INSERT INTO service_prices (service_id, price, effective_from)
+				  SELECT id, $2, $3 FROM services WHERE id = $1 AND deleted_at IS NULL
+				  ON CONFLICT (service_id, effective_from) DO UPDATE SET price = EXCLUDED.price
+				  RETURNING id, created_at`
 
-	var services []*domain.Service
-	for rows.Next() {
-		service := &domain.Service{}
-		err := rows.Scan(
-			&service.ID, &service.Name, &service.Type, &service.Notes, &service.CreatedAt, &service.UpdatedAt,
-		)
+		err = tx.QueryRowContext(ctx, query, price.ServiceID, price.Price, price.EffectiveFrom).Scan(&price.ID, &price.CreatedAt)
+		if err == sql.ErrNoRows {
+			return domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", price.ServiceID)
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
-		services = append(services, service)
+
+		after, err := scanServicePrice(tx.QueryRowContext(ctx, servicePriceSelect+` WHERE id = $1`, price.ID))
+		if err != nil {
+			return err
+		}
+
+		action := domain.AuditActionCreate
+		if before != nil {
+			action = domain.AuditActionUpdate
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityServicePrice, price.ID, action, before, after)
+	})
+}
+
+// GetPriceAt получает цену услуги, действовавшую в момент at; для моментов раньше всей истории
+// берется самая ранняя цена из истории
+func (r *ServiceRepository) GetPriceAt(ctx context.Context, serviceID int, at time.Time) (domain.Money, error) {
+	query := `SELECT COALESCE(
+			      (SELECT sp.price FROM service_prices sp
+			       WHERE sp.service_id = services.id AND sp.effective_from <= $2
+			       ORDER BY sp.effective_from DESC LIMIT 1),
+			      (SELECT sp.price FROM service_prices sp
+			       WHERE sp.service_id = services.id
+			       ORDER BY sp.effective_from LIMIT 1))
+			  FROM services WHERE id = $1 AND deleted_at IS NULL`
+
+	var price sql.Null[domain.Money]
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, serviceID, at).Scan(&price)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", serviceID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !price.Valid {
+		return 0, fmt.Errorf("у услуги с ID %d нет истории цен", serviceID)
 	}
 
-	return services, nil
+	return price.V, nil
 }
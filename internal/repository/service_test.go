@@ -5,6 +5,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -33,7 +34,7 @@ func TestServiceRepository_Integration(t *testing.T) {
 			Notes: "Professional teeth cleaning",
 		}
 
-		err = repo.Create(service)
+		err = repo.Create(ctx, service)
 		require.NoError(t, err)
 		assert.Greater(t, service.ID, 0)
 		assert.False(t, service.CreatedAt.IsZero())
@@ -49,10 +50,10 @@ func TestServiceRepository_Integration(t *testing.T) {
 			Type:  "Treatment",
 			Notes: "Endodontic treatment",
 		}
-		err = repo.Create(service)
+		err = repo.Create(ctx, service)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(service.ID)
+		found, err := repo.GetByID(ctx, service.ID)
 		require.NoError(t, err)
 		assert.Equal(t, service.ID, found.ID)
 		assert.Equal(t, service.Name, found.Name)
@@ -64,7 +65,7 @@ func TestServiceRepository_Integration(t *testing.T) {
 		err := testDB.TruncateTables(ctx)
 		require.NoError(t, err)
 
-		_, err = repo.GetByID(9999)
+		_, err = repo.GetByID(ctx, 9999)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "не найдена")
 	})
@@ -80,11 +81,11 @@ func TestServiceRepository_Integration(t *testing.T) {
 		}
 
 		for _, s := range services {
-			err := repo.Create(s)
+			err := repo.Create(ctx, s)
 			require.NoError(t, err)
 		}
 
-		all, err := repo.GetAll()
+		all, err := repo.GetAll(ctx)
 		require.NoError(t, err)
 		assert.Len(t, all, 3)
 	})
@@ -98,16 +99,16 @@ func TestServiceRepository_Integration(t *testing.T) {
 			Type:  "Original Type",
 			Notes: "Original notes",
 		}
-		err = repo.Create(service)
+		err = repo.Create(ctx, service)
 		require.NoError(t, err)
 
 		service.Name = "Updated Service"
 		service.Type = "Updated Type"
 		service.Notes = "Updated notes"
-		err = repo.Update(service)
+		err = repo.Update(ctx, service)
 		require.NoError(t, err)
 
-		found, err := repo.GetByID(service.ID)
+		found, err := repo.GetByID(ctx, service.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "Updated Service", found.Name)
 		assert.Equal(t, "Updated Type", found.Type)
@@ -122,13 +123,13 @@ func TestServiceRepository_Integration(t *testing.T) {
 			Name: "To Delete",
 			Type: "Temporary",
 		}
-		err = repo.Create(service)
+		err = repo.Create(ctx, service)
 		require.NoError(t, err)
 
-		err = repo.Delete(service.ID)
+		err = repo.Delete(ctx, service.ID)
 		require.NoError(t, err)
 
-		_, err = repo.GetByID(service.ID)
+		_, err = repo.GetByID(ctx, service.ID)
 		assert.Error(t, err)
 	})
 
@@ -144,15 +145,15 @@ func TestServiceRepository_Integration(t *testing.T) {
 		}
 
 		for _, s := range services {
-			err := repo.Create(s)
+			err := repo.Create(ctx, s)
 			require.NoError(t, err)
 		}
 
-		hygieneServices, err := repo.GetByCategory("Hygiene")
+		hygieneServices, err := repo.GetByCategory(ctx, "Hygiene")
 		require.NoError(t, err)
 		assert.Len(t, hygieneServices, 2)
 
-		surgeryServices, err := repo.GetByCategory("Surgery")
+		surgeryServices, err := repo.GetByCategory(ctx, "Surgery")
 		require.NoError(t, err)
 		assert.Len(t, surgeryServices, 1)
 		assert.Equal(t, "Extraction", surgeryServices[0].Name)
@@ -169,24 +170,82 @@ func TestServiceRepository_Integration(t *testing.T) {
 		}
 
 		for _, s := range services {
-			err := repo.Create(s)
+			err := repo.Create(ctx, s)
 			require.NoError(t, err)
 		}
 
 		// Search by name
-		results, err := repo.Search("Cleaning")
+		results, err := repo.Search(ctx, "Cleaning")
 		require.NoError(t, err)
 		assert.Len(t, results, 2)
 
 		// Search by notes
-		results, err = repo.Search("Complex")
+		results, err = repo.Search(ctx, "Complex")
 		require.NoError(t, err)
 		assert.Len(t, results, 1)
 		assert.Equal(t, "Root Canal", results[0].Name)
 
 		// Case insensitive search
-		results, err = repo.Search("cleaning")
+		results, err = repo.Search(ctx, "cleaning")
 		require.NoError(t, err)
 		assert.Len(t, results, 2)
 	})
+	t.Run("PriceHistory", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		service := &domain.Service{Name: "Implant", Type: "Surgery", Price: domain.Tenge(100000), Duration: 90}
+		require.NoError(t, repo.Create(ctx, service))
+
+		changeAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		futureAt := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+		require.NoError(t, repo.AddPrice(ctx, &domain.ServicePrice{ServiceID: service.ID, Price: domain.Tenge(120000), EffectiveFrom: changeAt}))
+		require.NoError(t, repo.AddPrice(ctx, &domain.ServicePrice{ServiceID: service.ID, Price: domain.Tenge(150000), EffectiveFrom: futureAt}))
+
+		history, err := repo.GetPriceHistory(ctx, service.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, domain.Tenge(150000), history[0].Price)
+		assert.Equal(t, domain.Tenge(100000), history[2].Price)
+
+		price, err := repo.GetPriceAt(ctx, service.ID, changeAt.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(100000), price)
+
+		price, err = repo.GetPriceAt(ctx, service.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(120000), price)
+
+		price, err = repo.GetPriceAt(ctx, service.ID, futureAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(150000), price)
+
+		found, err := repo.GetByID(ctx, service.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(100000), found.Price)
+		assert.Equal(t, domain.Tenge(120000), found.CurrentPrice)
+		assert.Equal(t, 90, found.Duration)
+
+		found.Price = domain.Tenge(110000)
+		require.NoError(t, repo.Update(ctx, found))
+
+		history, err = repo.GetPriceHistory(ctx, service.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 4)
+
+		price, err = repo.GetPriceAt(ctx, service.ID, changeAt.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(100000), price)
+
+		price, err = repo.GetPriceAt(ctx, service.ID, changeAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(120000), price)
+
+		price, err = repo.GetPriceAt(ctx, service.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(110000), price)
+
+		err = repo.AddPrice(ctx, &domain.ServicePrice{ServiceID: 9999, Price: domain.Tenge(1), EffectiveFrom: changeAt})
+		assert.Error(t, err)
+	})
 }
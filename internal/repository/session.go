@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionSelect = `
+	SELECT id, doctor_id, token_hash, refresh_token_hash, expires_at, refresh_expires_at, created_at, revoked_at
+	FROM sessions`
+
+// scanSession сканирует строку сессии
+func scanSession(row rowScanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.DoctorID,
+		&session.TokenHash,
+		&session.RefreshTokenHash,
+		&session.ExpiresAt,
+		&session.RefreshExpiresAt,
+		&session.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return session, nil
+}
+
+// querySession получает одну сессию; отсутствие сессии не является ошибкой
+func (r *SessionRepository) querySession(ctx context.Context, query string, args ...interface{}) (*domain.Session, error) {
+	session, err := scanSession(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сессии: %w", err)
+	}
+	return session, nil
+}
+
+// Create создает сессию
+func (r *SessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	query := `
+		INSERT INTO sessions (doctor_id, token_hash, refresh_token_hash, expires_at, refresh_expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		session.DoctorID,
+		session.TokenHash,
+		session.RefreshTokenHash,
+		session.ExpiresAt,
+		session.RefreshExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания сессии: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash получает сессию по хешу токена доступа
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	return r.querySession(ctx, sessionSelect+` WHERE token_hash = $1`, tokenHash)
+}
+
+// GetByRefreshTokenHash получает сессию по хешу токена обновления
+func (r *SessionRepository) GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*domain.Session, error) {
+	return r.querySession(ctx, sessionSelect+` WHERE refresh_token_hash = $1`, refreshTokenHash)
+}
+
+// Revoke отзывает сессию
+func (r *SessionRepository) Revoke(ctx context.Context, id int) error {
+	query := `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("ошибка отзыва сессии: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidSession
+	}
+
+	return nil
+}
+
+// RevokeAllForDoctor отзывает все активные сессии врача
+func (r *SessionRepository) RevokeAllForDoctor(ctx context.Context, doctorID int) error {
+	query := `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE doctor_id = $1 AND revoked_at IS NULL`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, doctorID); err != nil {
+		return fmt.Errorf("ошибка отзыва сессий врача: %w", err)
+	}
+
+	return nil
+}
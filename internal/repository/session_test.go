@@ -0,0 +1,90 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	repo := NewSessionRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
+
+	createSession := func(t *testing.T, doctorID int, suffix string) *domain.Session {
+		session := &domain.Session{
+			DoctorID:         doctorID,
+			TokenHash:        strings.Repeat("a", 63) + suffix,
+			RefreshTokenHash: strings.Repeat("b", 63) + suffix,
+			ExpiresAt:        time.Now().Add(time.Hour),
+			RefreshExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+		require.NoError(t, repo.Create(ctx, session))
+		return session
+	}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Session", Login: "session", Password: "hash"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		session := createSession(t, doctor.ID, "1")
+		assert.Greater(t, session.ID, 0)
+
+		found, err := repo.GetByTokenHash(ctx, session.TokenHash)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, doctor.ID, found.DoctorID)
+		assert.Nil(t, found.RevokedAt)
+
+		found, err = repo.GetByRefreshTokenHash(ctx, session.RefreshTokenHash)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, session.ID, found.ID)
+
+		found, err = repo.GetByTokenHash(ctx, strings.Repeat("c", 64))
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. Session", Login: "session", Password: "hash"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		first := createSession(t, doctor.ID, "1")
+		second := createSession(t, doctor.ID, "2")
+
+		require.NoError(t, repo.Revoke(ctx, first.ID))
+		assert.ErrorIs(t, repo.Revoke(ctx, first.ID), domain.ErrInvalidSession)
+
+		found, err := repo.GetByTokenHash(ctx, first.TokenHash)
+		require.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+
+		require.NoError(t, repo.RevokeAllForDoctor(ctx, doctor.ID))
+
+		found, err = repo.GetByTokenHash(ctx, second.TokenHash)
+		require.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+	})
+}
@@ -96,7 +96,7 @@ func runMigrations(db *sql.DB) error {
 
 // TruncateTables clears all data from tables (useful between tests)
 func (t *TestDB) TruncateTables(ctx context.Context) error {
-	tables := []string{"appointments", "doctors", "services", "patients"}
+	tables := []string{"appointments", "doctors", "services", "patients", "clinic_holidays", "sessions", "login_attempts", "doctor_recovery_codes", "mfa_challenges", "audit_log", "dental_chart_history", "appointment_treatments", "treatment_plans", "treatment_plan_variants", "treatment_plan_steps", "appointment_diagnoses"}
 	for _, table := range tables {
 		if _, err := t.DB.ExecContext(ctx, fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table)); err != nil {
 			return fmt.Errorf("failed to truncate %s: %w", table, err)
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// treatmentSelect общий SELECT для чтения процедур приема вместе с названием услуги
+const treatmentSelect = `SELECT t.id, t.appointment_id, t.service_id, COALESCE(s.name, ''), COALESCE(t.tooth, 0), t.surfaces, t.tooth_status,
+			  t.materials, t.anesthesia, t.diagnosis_code, t.comment, t.price, t.created_at, t.updated_at
+			  FROM appointment_treatments t
+			  LEFT JOIN services s ON t.service_id = s.id`
+
+type TreatmentRepository struct {
+	db *sql.DB
+}
+
+func NewTreatmentRepository(db *sql.DB) *TreatmentRepository {
+	return &TreatmentRepository{db: db}
+}
+
+// scanTreatment читает одну процедуру из результата treatmentSelect
+func scanTreatment(row rowScanner) (*domain.Treatment, error) {
+	treatment := &domain.Treatment{}
+	var surfaces string
+	err := row.Scan(
+		&treatment.ID, &treatment.AppointmentID, &treatment.ServiceID, &treatment.Service, &treatment.Tooth, &surfaces,
+		&treatment.ToothStatus, &treatment.Materials, &treatment.Anesthesia, &treatment.DiagnosisCode, &treatment.Comment,
+		&treatment.Price, &treatment.CreatedAt, &treatment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	treatment.Surfaces = splitSurfaces(surfaces)
+	return treatment, nil
+}
+
+// lockTreatment читает и блокирует процедуру до конца транзакции, чтобы записать ее состояние в аудит
+func lockTreatment(ctx context.Context, tx *sql.Tx, id int) (*domain.Treatment, error) {
+	treatment, err := scanTreatment(tx.QueryRowContext(ctx, treatmentSelect+` WHERE t.id = $1 AND t.deleted_at IS NULL FOR UPDATE OF t`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTreatmentNotFound.WithMessage("процедура с ID %d не найдена", id)
+	}
+	return treatment, err
+}
+
+// syncAppointmentPrice пересчитывает цену приема как сумму цен его процедур. После удаления последней процедуры
+// приему возвращается цена услуги из прайс-листа на дату приема, как в ServiceRepository.GetPriceAt
+func syncAppointmentPrice(ctx context.Context, tx *sql.Tx, appointmentID int) error {
+	query := `UPDATE appointments SET price = totals.total, updated_at = CURRENT_TIMESTAMP
+			  FROM (SELECT COALESCE(
+			            (SELECT SUM(t.price) FROM appointment_treatments t
+			             WHERE t.appointment_id = a.id AND t.deleted_at IS NULL),
+			            (SELECT sp.price FROM service_prices sp
+			             WHERE sp.service_id = a.service_id AND sp.effective_from <= a.appointment_date
+			             ORDER BY sp.effective_from DESC LIMIT 1),
+			            (SELECT sp.price FROM service_prices sp
+			             WHERE sp.service_id = a.service_id
+			             ORDER BY sp.effective_from LIMIT 1),
+			            0) AS total
+			        FROM appointments a WHERE a.id = $1) totals
+			  WHERE id = $1 AND price IS DISTINCT FROM totals.total`
+
+	if _, err := tx.ExecContext(ctx, query, appointmentID); err != nil {
+		return fmt.Errorf("ошибка пересчета цены записи: %w", err)
+	}
+	return nil
+}
+
+// treatmentReferenceError переводит нарушение внешнего ключа процедуры в ошибку отсутствующей записи или услуги
+func treatmentReferenceError(err error, treatment *domain.Treatment) error {
+	switch violatedConstraint(err) {
+	case "appointment_treatments_appointment_id_fkey":
+		return domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", treatment.AppointmentID)
+	case "appointment_treatments_service_id_fkey":
+		return domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", treatment.ServiceID)
+	}
+	return domain.NewValidationError("", domain.FieldInvalid, "referenced appointment or service does not exist")
+}
+
+func (r *TreatmentRepository) GetByID(ctx context.Context, id int) (*domain.Treatment, error) {
+	treatment, err := scanTreatment(conn(ctx, r.db).QueryRowContext(ctx, treatmentSelect+` WHERE t.id = $1 AND t.deleted_at IS NULL`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTreatmentNotFound.WithMessage("процедура с ID %d не найдена", id)
+		}
+		return nil, err
+	}
+
+	return treatment, nil
+}
+
+// GetByAppointmentID получает процедуры приема в порядке добавления
+func (r *TreatmentRepository) GetByAppointmentID(ctx context.Context, appointmentID int) ([]*domain.Treatment, error) {
+	query := treatmentSelect + ` WHERE t.appointment_id = $1 AND t.deleted_at IS NULL ORDER BY t.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, appointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения процедур записи: %w", err)
+	}
+	defer rows.Close()
+
+	treatments := make([]*domain.Treatment, 0)
+	for rows.Next() {
+		treatment, err := scanTreatment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения процедуры: %w", err)
+		}
+		treatments = append(treatments, treatment)
+	}
+
+	return treatments, rows.Err()
+}
+
+func (r *TreatmentRepository) Create(ctx context.Context, treatment *domain.Treatment) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO appointment_treatments (appointment_id, service_id, tooth, surfaces, tooth_status,
+				  materials, anesthesia, diagnosis_code, comment, price)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRowContext(ctx, query, treatment.AppointmentID, treatment.ServiceID, nullableID(treatment.Tooth),
+			joinSurfaces(treatment.Surfaces), treatment.ToothStatus, treatment.Materials, treatment.Anesthesia,
+			treatment.DiagnosisCode, treatment.Comment, treatment.Price).
+			Scan(&treatment.ID, &treatment.CreatedAt, &treatment.UpdatedAt)
+		if isForeignKeyViolation(err) {
+			return treatmentReferenceError(err, treatment)
+		}
+		if err != nil {
+			return err
+		}
+
+		created, err := lockTreatment(ctx, tx, treatment.ID)
+		if err != nil {
+			return err
+		}
+		if err := syncAppointmentPrice(ctx, tx, treatment.AppointmentID); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityTreatment, treatment.ID, domain.AuditActionCreate, nil, created)
+	})
+}
+
+func (r *TreatmentRepository) Update(ctx context.Context, treatment *domain.Treatment) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockTreatment(ctx, tx, treatment.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE appointment_treatments SET service_id = $1, tooth = $2, surfaces = $3, tooth_status = $4,
+				  materials = $5, anesthesia = $6, diagnosis_code = $7, comment = $8, price = $9, updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $10 AND deleted_at IS NULL RETURNING updated_at`
+
+		err = tx.QueryRowContext(ctx, query, treatment.ServiceID, nullableID(treatment.Tooth), joinSurfaces(treatment.Surfaces),
+			treatment.ToothStatus, treatment.Materials, treatment.Anesthesia, treatment.DiagnosisCode, treatment.Comment,
+			treatment.Price, treatment.ID).Scan(&treatment.UpdatedAt)
+		if isForeignKeyViolation(err) {
+			return treatmentReferenceError(err, treatment)
+		}
+		if err != nil {
+			return err
+		}
+
+		after, err := lockTreatment(ctx, tx, treatment.ID)
+		if err != nil {
+			return err
+		}
+		if err := syncAppointmentPrice(ctx, tx, before.AppointmentID); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityTreatment, treatment.ID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+func (r *TreatmentRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockTreatment(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE appointment_treatments SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+		if err := syncAppointmentPrice(ctx, tx, before.AppointmentID); err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityTreatment, id, domain.AuditActionDelete, before, nil)
+	})
+}
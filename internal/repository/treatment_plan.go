@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// treatmentPlanSelect общий SELECT для чтения планов лечения без вариантов
+const treatmentPlanSelect = `SELECT id, patient_id, title, notes, status, COALESCE(doctor_id, 0), COALESCE(accepted_variant_id, 0),
+			  accepted_at, signed_by, declined_at, created_at, updated_at
+			  FROM treatment_plans`
+
+type TreatmentPlanRepository struct {
+	db *sql.DB
+}
+
+func NewTreatmentPlanRepository(db *sql.DB) *TreatmentPlanRepository {
+	return &TreatmentPlanRepository{db: db}
+}
+
+// scanTreatmentPlan читает один план из результата treatmentPlanSelect
+func scanTreatmentPlan(row rowScanner) (*domain.TreatmentPlan, error) {
+	plan := &domain.TreatmentPlan{}
+	err := row.Scan(
+		&plan.ID, &plan.PatientID, &plan.Title, &plan.Notes, &plan.Status, &plan.DoctorID, &plan.AcceptedVariantID,
+		&plan.AcceptedAt, &plan.SignedBy, &plan.DeclinedAt, &plan.CreatedAt, &plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// loadPlanVariants читает варианты плана с процедурами в порядке их следования и считает стоимость вариантов
+func loadPlanVariants(ctx context.Context, db dbExecutor, plan *domain.TreatmentPlan) error {
+	rows, err := db.QueryContext(ctx, `SELECT id, plan_id, position, title FROM treatment_plan_variants
+									   WHERE plan_id = $1 ORDER BY position`, plan.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения вариантов плана лечения: %w", err)
+	}
+	defer rows.Close()
+
+	plan.Variants = make([]*domain.TreatmentPlanVariant, 0)
+	variants := make(map[int]*domain.TreatmentPlanVariant)
+	for rows.Next() {
+		variant := &domain.TreatmentPlanVariant{Steps: make([]*domain.TreatmentPlanStep, 0)}
+		if err := rows.Scan(&variant.ID, &variant.PlanID, &variant.Position, &variant.Title); err != nil {
+			return fmt.Errorf("ошибка чтения варианта плана лечения: %w", err)
+		}
+		plan.Variants = append(plan.Variants, variant)
+		variants[variant.ID] = variant
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	query := `SELECT st.id, st.variant_id, st.position, st.stage, st.service_id, COALESCE(s.name, ''), COALESCE(st.tooth, 0),
+			  st.estimated_price, st.notes, COALESCE(st.appointment_id, 0)
+			  FROM treatment_plan_steps st
+			  JOIN treatment_plan_variants v ON st.variant_id = v.id
+			  LEFT JOIN services s ON st.service_id = s.id
+			  WHERE v.plan_id = $1 ORDER BY v.position, st.position`
+
+	rows, err = db.QueryContext(ctx, query, plan.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения процедур плана лечения: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		step := &domain.TreatmentPlanStep{}
+		if err := rows.Scan(&step.ID, &step.VariantID, &step.Position, &step.Stage, &step.ServiceID, &step.Service,
+			&step.Tooth, &step.EstimatedPrice, &step.Notes, &step.AppointmentID); err != nil {
+			return fmt.Errorf("ошибка чтения процедуры плана лечения: %w", err)
+		}
+		if variant, ok := variants[step.VariantID]; ok {
+			variant.Steps = append(variant.Steps, step)
+			variant.Total += step.EstimatedPrice
+		}
+	}
+
+	return rows.Err()
+}
+
+// insertPlanVariants сохраняет варианты плана с процедурами; позиции назначаются по порядку в плане
+func insertPlanVariants(ctx context.Context, tx *sql.Tx, plan *domain.TreatmentPlan) error {
+	for i, variant := range plan.Variants {
+		variant.PlanID = plan.ID
+		variant.Position = i + 1
+
+		err := tx.QueryRowContext(ctx, `INSERT INTO treatment_plan_variants (plan_id, position, title) VALUES ($1, $2, $3) RETURNING id`,
+			variant.PlanID, variant.Position, variant.Title).Scan(&variant.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения варианта плана лечения: %w", err)
+		}
+
+		variant.Total = 0
+		for j, step := range variant.Steps {
+			step.VariantID = variant.ID
+			step.Position = j + 1
+
+			query := `INSERT INTO treatment_plan_steps (variant_id, position, stage, service_id, tooth, estimated_price, notes)
+					  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+			err := tx.QueryRowContext(ctx, query, step.VariantID, step.Position, step.Stage, step.ServiceID,
+				nullableID(step.Tooth), step.EstimatedPrice, step.Notes).Scan(&step.ID)
+			if isForeignKeyViolation(err) {
+				return domain.ErrServiceNotFound.WithMessage("услуга с ID %d не найдена", step.ServiceID)
+			}
+			if err != nil {
+				return fmt.Errorf("ошибка сохранения процедуры плана лечения: %w", err)
+			}
+			variant.Total += step.EstimatedPrice
+		}
+	}
+	return nil
+}
+
+// lockTreatmentPlan читает и блокирует план вместе с вариантами до конца транзакции, чтобы записать его состояние в аудит
+func lockTreatmentPlan(ctx context.Context, tx *sql.Tx, id int) (*domain.TreatmentPlan, error) {
+	plan, err := scanTreatmentPlan(tx.QueryRowContext(ctx, treatmentPlanSelect+` WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTreatmentPlanNotFound.WithMessage("план лечения с ID %d не найден", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadPlanVariants(ctx, tx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (r *TreatmentPlanRepository) GetByID(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	db := conn(ctx, r.db)
+
+	plan, err := scanTreatmentPlan(db.QueryRowContext(ctx, treatmentPlanSelect+` WHERE id = $1 AND deleted_at IS NULL`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTreatmentPlanNotFound.WithMessage("план лечения с ID %d не найден", id)
+		}
+		return nil, err
+	}
+	if err := loadPlanVariants(ctx, db, plan); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// GetByPatientID получает планы лечения пациента, начиная с последних
+func (r *TreatmentPlanRepository) GetByPatientID(ctx context.Context, patientID int) ([]*domain.TreatmentPlan, error) {
+	db := conn(ctx, r.db)
+	query := treatmentPlanSelect + ` WHERE patient_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC, id DESC`
+
+	rows, err := db.QueryContext(ctx, query, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения планов лечения: %w", err)
+	}
+	defer rows.Close()
+
+	plans := make([]*domain.TreatmentPlan, 0)
+	for rows.Next() {
+		plan, err := scanTreatmentPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения плана лечения: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, plan := range plans {
+		if err := loadPlanVariants(ctx, db, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	return plans, nil
+}
+
+func (r *TreatmentPlanRepository) Create(ctx context.Context, plan *domain.TreatmentPlan) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO treatment_plans (patient_id, title, notes, status, doctor_id)
+				  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+
+		err := tx.QueryRowContext(ctx, query, plan.PatientID, plan.Title, plan.Notes, plan.Status, nullableID(plan.DoctorID)).
+			Scan(&plan.ID, &plan.CreatedAt, &plan.UpdatedAt)
+		if isForeignKeyViolation(err) {
+			if violatedConstraint(err) == "treatment_plans_doctor_id_fkey" {
+				return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", plan.DoctorID)
+			}
+			return domain.ErrPatientNotFound.WithMessage("пациент с ID %d не найден", plan.PatientID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := insertPlanVariants(ctx, tx, plan); err != nil {
+			return err
+		}
+
+		created, err := lockTreatmentPlan(ctx, tx, plan.ID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityTreatmentPlan, plan.ID, domain.AuditActionCreate, nil, created)
+	})
+}
+
+// Update изменяет название и заметки плана и заменяет его варианты новыми
+func (r *TreatmentPlanRepository) Update(ctx context.Context, plan *domain.TreatmentPlan) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockTreatmentPlan(ctx, tx, plan.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE treatment_plans SET title = $1, notes = $2, updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $3 AND deleted_at IS NULL RETURNING updated_at`
+
+		if err := tx.QueryRowContext(ctx, query, plan.Title, plan.Notes, plan.ID).Scan(&plan.UpdatedAt); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM treatment_plan_variants WHERE plan_id = $1`, plan.ID); err != nil {
+			return fmt.Errorf("ошибка удаления вариантов плана лечения: %w", err)
+		}
+		if err := insertPlanVariants(ctx, tx, plan); err != nil {
+			return err
+		}
+
+		after, err := lockTreatmentPlan(ctx, tx, plan.ID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityTreatmentPlan, plan.ID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+// UpdateStatus записывает статус плана и сведения о согласии или отказе пациента
+func (r *TreatmentPlanRepository) UpdateStatus(ctx context.Context, plan *domain.TreatmentPlan) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockTreatmentPlan(ctx, tx, plan.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE treatment_plans SET status = $1, accepted_variant_id = $2, accepted_at = $3, signed_by = $4,
+				  declined_at = $5, updated_at = CURRENT_TIMESTAMP
+				  WHERE id = $6 AND deleted_at IS NULL RETURNING updated_at`
+
+		err = tx.QueryRowContext(ctx, query, plan.Status, nullableID(plan.AcceptedVariantID), plan.AcceptedAt, plan.SignedBy,
+			plan.DeclinedAt, plan.ID).Scan(&plan.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("ошибка изменения статуса плана лечения: %w", err)
+		}
+
+		after, err := lockTreatmentPlan(ctx, tx, plan.ID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityTreatmentPlan, plan.ID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+func (r *TreatmentPlanRepository) Delete(ctx context.Context, id int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := lockTreatmentPlan(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE treatment_plans SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityTreatmentPlan, id, domain.AuditActionDelete, before, nil)
+	})
+}
+
+// SetStepAppointment связывает процедуру плана с созданной по ней записью
+func (r *TreatmentPlanRepository) SetStepAppointment(ctx context.Context, stepID, appointmentID int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var planID int
+		err := tx.QueryRowContext(ctx, `SELECT v.plan_id FROM treatment_plan_steps st
+										JOIN treatment_plan_variants v ON st.variant_id = v.id WHERE st.id = $1`, stepID).Scan(&planID)
+		if err == sql.ErrNoRows {
+			return domain.ErrTreatmentPlanNotFound.WithMessage("процедура плана лечения с ID %d не найдена", stepID)
+		}
+		if err != nil {
+			return err
+		}
+
+		before, err := lockTreatmentPlan(ctx, tx, planID)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE treatment_plan_steps SET appointment_id = $1 WHERE id = $2`
+
+		_, err = tx.ExecContext(ctx, query, nullableID(appointmentID), stepID)
+		if isForeignKeyViolation(err) {
+			return domain.ErrAppointmentNotFound.WithMessage("запись с ID %d не найдена", appointmentID)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE treatment_plans SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, planID); err != nil {
+			return err
+		}
+
+		after, err := lockTreatmentPlan(ctx, tx, planID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, domain.AuditEntityTreatmentPlan, planID, domain.AuditActionUpdate, before, after)
+	})
+}
+
+// GetProgress считает выполнение принятых планов лечения; patientID = 0 — по всем пациентам.
+// Перенесенная запись процедуры заменяется записью, созданной при переносе, поэтому учитывается последняя запись цепочки
+func (r *TreatmentPlanRepository) GetProgress(ctx context.Context, patientID int) ([]*domain.TreatmentPlanProgress, error) {
+	where := &whereBuilder{}
+	where.addRaw("p.status = 'accepted'")
+	where.addRaw("p.deleted_at IS NULL")
+	if patientID > 0 {
+		where.add("p.patient_id = $%d", patientID)
+	}
+
+	query := `WITH RECURSIVE step_appointments AS (
+				  SELECT st.id AS step_id, st.appointment_id
+				  FROM treatment_plan_steps st WHERE st.appointment_id IS NOT NULL
+				  UNION ALL
+				  SELECT sa.step_id, a.id
+				  FROM step_appointments sa JOIN appointments a ON a.rescheduled_from_id = sa.appointment_id
+			  ), latest AS (
+				  SELECT DISTINCT ON (sa.step_id) sa.step_id, a.status, a.price
+				  FROM step_appointments sa JOIN appointments a ON a.id = sa.appointment_id AND a.deleted_at IS NULL
+				  ORDER BY sa.step_id, a.id DESC
+			  )
+			  SELECT p.id, p.patient_id, COALESCE(pt.name, ''), p.title, v.id, v.title, p.accepted_at,
+			  COUNT(st.id),
+			  COUNT(st.id) FILTER (WHERE l.status IN ('scheduled', 'confirmed', 'arrived', 'in_progress')),
+			  COUNT(st.id) FILTER (WHERE l.status = 'completed'),
+			  COALESCE(SUM(st.estimated_price), 0),
+			  COALESCE(SUM(st.estimated_price) FILTER (WHERE l.status = 'completed'), 0),
+			  COALESCE(SUM(l.price) FILTER (WHERE l.status = 'completed'), 0)
+			  FROM treatment_plans p
+			  JOIN treatment_plan_variants v ON p.accepted_variant_id = v.id
+			  LEFT JOIN treatment_plan_steps st ON st.variant_id = v.id
+			  LEFT JOIN latest l ON l.step_id = st.id
+			  LEFT JOIN patients pt ON p.patient_id = pt.id` + where.sql() + `
+			  GROUP BY p.id, pt.name, v.id
+			  ORDER BY p.accepted_at DESC, p.id DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения выполнения планов лечения: %w", err)
+	}
+	defer rows.Close()
+
+	progress := make([]*domain.TreatmentPlanProgress, 0)
+	for rows.Next() {
+		item := &domain.TreatmentPlanProgress{}
+		if err := rows.Scan(&item.PlanID, &item.PatientID, &item.PatientName, &item.Title, &item.VariantID, &item.VariantTitle,
+			&item.AcceptedAt, &item.TotalSteps, &item.ScheduledSteps, &item.CompletedSteps,
+			&item.EstimatedTotal, &item.CompletedEstimate, &item.PaidAmount); err != nil {
+			return nil, fmt.Errorf("ошибка чтения выполнения плана лечения: %w", err)
+		}
+		progress = append(progress, item)
+	}
+
+	return progress, rows.Err()
+}
@@ -0,0 +1,154 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreatmentPlanRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	patientRepo := NewPatientRepository(testDB.DB)
+	serviceRepo := NewServiceRepository(testDB.DB)
+	appointmentRepo := NewAppointmentRepository(testDB.DB)
+	planRepo := NewTreatmentPlanRepository(testDB.DB)
+
+	createTestPlan := func(t *testing.T) (*domain.TreatmentPlan, *domain.Service) {
+		patient := &domain.Patient{Name: "Plan Patient", Phone: "+7 777 000 0001"}
+		require.NoError(t, patientRepo.Create(ctx, patient))
+		service := &domain.Service{Name: "Implant", Type: "Surgery"}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		plan := &domain.TreatmentPlan{
+			PatientID: patient.ID, Title: "Восстановление 36", Status: domain.PlanDraft,
+			Variants: []*domain.TreatmentPlanVariant{
+				{Title: "Имплант", Steps: []*domain.TreatmentPlanStep{
+					{Stage: 1, ServiceID: service.ID, Tooth: 36, EstimatedPrice: domain.Tenge(250000)},
+					{Stage: 2, ServiceID: service.ID, Tooth: 36, EstimatedPrice: domain.Tenge(90000)},
+				}},
+				{Title: "Мост", Steps: []*domain.TreatmentPlanStep{
+					{Stage: 1, ServiceID: service.ID, EstimatedPrice: domain.Tenge(180000)},
+				}},
+			},
+		}
+		require.NoError(t, planRepo.Create(ctx, plan))
+		return plan, service
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		plan, _ := createTestPlan(t)
+		assert.Greater(t, plan.ID, 0)
+
+		found, err := planRepo.GetByID(ctx, plan.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PlanDraft, found.Status)
+		require.Len(t, found.Variants, 2)
+		assert.Equal(t, "Имплант", found.Variants[0].Title)
+		assert.Equal(t, domain.Tenge(340000), found.Variants[0].Total)
+		require.Len(t, found.Variants[0].Steps, 2)
+		assert.Equal(t, "Implant", found.Variants[0].Steps[0].Service)
+		assert.Equal(t, 36, found.Variants[0].Steps[0].Tooth)
+		assert.Equal(t, 2, found.Variants[0].Steps[1].Position)
+		assert.Equal(t, 0, found.Variants[1].Steps[0].Tooth)
+
+		plans, err := planRepo.GetByPatientID(ctx, plan.PatientID)
+		require.NoError(t, err)
+		require.Len(t, plans, 1)
+		assert.Len(t, plans[0].Variants, 2)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		plan, _ := createTestPlan(t)
+		plan.Title = "Восстановление 36 и 37"
+		plan.Variants = plan.Variants[1:]
+		require.NoError(t, planRepo.Update(ctx, plan))
+
+		found, err := planRepo.GetByID(ctx, plan.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Восстановление 36 и 37", found.Title)
+		require.Len(t, found.Variants, 1)
+		assert.Equal(t, "Мост", found.Variants[0].Title)
+		assert.Equal(t, 1, found.Variants[0].Position)
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		plan, service := createTestPlan(t)
+		acceptedAt := time.Now()
+		plan.Status = domain.PlanAccepted
+		plan.AcceptedVariantID = plan.Variants[0].ID
+		plan.AcceptedAt = &acceptedAt
+		plan.SignedBy = "Plan Patient"
+		require.NoError(t, planRepo.UpdateStatus(ctx, plan))
+
+		completed := &domain.Appointment{
+			PatientID: plan.PatientID, ServiceID: service.ID, Date: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+			Duration: 60, Status: domain.StatusCompleted, Price: domain.Tenge(240000),
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, completed))
+		require.NoError(t, planRepo.SetStepAppointment(ctx, plan.Variants[0].Steps[0].ID, completed.ID))
+
+		upcoming := &domain.Appointment{
+			PatientID: plan.PatientID, ServiceID: service.ID, Date: time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC),
+			Duration: 60, Status: domain.StatusScheduled, Price: domain.Tenge(90000),
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, upcoming))
+		require.NoError(t, planRepo.SetStepAppointment(ctx, plan.Variants[0].Steps[1].ID, upcoming.ID))
+
+		progress, err := planRepo.GetProgress(ctx, plan.PatientID)
+		require.NoError(t, err)
+		require.Len(t, progress, 1)
+		assert.Equal(t, plan.ID, progress[0].PlanID)
+		assert.Equal(t, "Plan Patient", progress[0].PatientName)
+		assert.Equal(t, "Имплант", progress[0].VariantTitle)
+		assert.Equal(t, 2, progress[0].TotalSteps)
+		assert.Equal(t, 1, progress[0].ScheduledSteps)
+		assert.Equal(t, 1, progress[0].CompletedSteps)
+		assert.Equal(t, domain.Tenge(340000), progress[0].EstimatedTotal)
+		assert.Equal(t, domain.Tenge(250000), progress[0].CompletedEstimate)
+		assert.Equal(t, domain.Tenge(240000), progress[0].PaidAmount)
+
+		found, err := planRepo.GetByID(ctx, plan.ID)
+		require.NoError(t, err)
+		assert.Equal(t, completed.ID, found.Variants[0].Steps[0].AppointmentID)
+	})
+
+	t.Run("Create_PatientNotFound", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		err = planRepo.Create(ctx, &domain.TreatmentPlan{PatientID: 9999, Title: "Без пациента", Status: domain.PlanDraft})
+		assert.ErrorIs(t, err, domain.ErrPatientNotFound)
+		assert.Contains(t, err.Error(), "пациент с ID 9999 не найден")
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		_, err = planRepo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, domain.ErrTreatmentPlanNotFound)
+	})
+}
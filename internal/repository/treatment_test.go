@@ -0,0 +1,139 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreatmentRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	patientRepo := NewPatientRepository(testDB.DB)
+	serviceRepo := NewServiceRepository(testDB.DB)
+	appointmentRepo := NewAppointmentRepository(testDB.DB)
+	treatmentRepo := NewTreatmentRepository(testDB.DB)
+
+	createTestAppointment := func(t *testing.T) (*domain.Appointment, *domain.Service) {
+		patient := &domain.Patient{Name: "Treatment Patient", Phone: "+7 777 000 0000"}
+		require.NoError(t, patientRepo.Create(ctx, patient))
+		service := &domain.Service{Name: "Filling", Type: "Treatment"}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+
+		appointment := &domain.Appointment{
+			PatientID: patient.ID, ServiceID: service.ID, Date: time.Date(2024, 12, 16, 10, 0, 0, 0, time.UTC),
+			Duration: 60, Status: domain.StatusInProgress, Price: domain.Tenge(10000),
+		}
+		require.NoError(t, appointmentRepo.Create(ctx, appointment))
+		return appointment, service
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		appointment, service := createTestAppointment(t)
+
+		treatment := &domain.Treatment{
+			AppointmentID: appointment.ID, ServiceID: service.ID, Tooth: 36,
+			Surfaces:    []domain.ToothSurface{domain.SurfaceMesial, domain.SurfaceOcclusal},
+			ToothStatus: domain.ToothFilled, Materials: "Filtek Z550", Anesthesia: "Ультракаин 1.7 мл",
+			DiagnosisCode: "K02.1", Comment: "глубокий кариес", Price: domain.Tenge(25000),
+		}
+		require.NoError(t, treatmentRepo.Create(ctx, treatment))
+		assert.Greater(t, treatment.ID, 0)
+
+		found, err := treatmentRepo.GetByID(ctx, treatment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Filling", found.Service)
+		assert.Equal(t, 36, found.Tooth)
+		assert.Equal(t, treatment.Surfaces, found.Surfaces)
+		assert.Equal(t, domain.ToothFilled, found.ToothStatus)
+		assert.Equal(t, "K02.1", found.DiagnosisCode)
+		assert.Equal(t, domain.Tenge(25000), found.Price)
+	})
+
+	t.Run("AppointmentPrice", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		appointment, service := createTestAppointment(t)
+
+		first := &domain.Treatment{AppointmentID: appointment.ID, ServiceID: service.ID, Price: domain.Tenge(25000)}
+		second := &domain.Treatment{AppointmentID: appointment.ID, ServiceID: service.ID, Price: domain.Tenge(5000)}
+		require.NoError(t, treatmentRepo.Create(ctx, first))
+		require.NoError(t, treatmentRepo.Create(ctx, second))
+
+		found, err := appointmentRepo.GetByID(ctx, appointment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(30000), found.Price)
+
+		found.Price = domain.Tenge(1)
+		require.NoError(t, appointmentRepo.Update(ctx, found))
+		assert.Equal(t, domain.Tenge(30000), found.Price)
+
+		second.Price = domain.Tenge(7000)
+		require.NoError(t, treatmentRepo.Update(ctx, second))
+		require.NoError(t, treatmentRepo.Delete(ctx, first.ID))
+
+		found, err = appointmentRepo.GetByID(ctx, appointment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(7000), found.Price)
+
+		treatments, err := treatmentRepo.GetByAppointmentID(ctx, appointment.ID)
+		require.NoError(t, err)
+		require.Len(t, treatments, 1)
+		assert.Equal(t, second.ID, treatments[0].ID)
+	})
+
+	t.Run("AppointmentPrice_LastTreatmentDeleted", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		appointment, _ := createTestAppointment(t)
+		service := &domain.Service{Name: "Crown", Type: "Prosthetics", Price: domain.Tenge(12000)}
+		require.NoError(t, serviceRepo.Create(ctx, service))
+		appointment.ServiceID = service.ID
+		require.NoError(t, appointmentRepo.Update(ctx, appointment))
+
+		treatment := &domain.Treatment{AppointmentID: appointment.ID, ServiceID: service.ID, Price: domain.Tenge(25000)}
+		require.NoError(t, treatmentRepo.Create(ctx, treatment))
+		require.NoError(t, treatmentRepo.Delete(ctx, treatment.ID))
+
+		found, err := appointmentRepo.GetByID(ctx, appointment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tenge(12000), found.Price)
+	})
+
+	t.Run("Create_ServiceNotFound", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		appointment, _ := createTestAppointment(t)
+
+		err = treatmentRepo.Create(ctx, &domain.Treatment{AppointmentID: appointment.ID, ServiceID: 9999})
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+		assert.Contains(t, err.Error(), "услуга с ID 9999 не найдена")
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		_, err = treatmentRepo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, domain.ErrTreatmentNotFound)
+	})
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SetTOTPSecret сохраняет секрет TOTP для незавершенного подключения
+func (r *TwoFactorRepository) SetTOTPSecret(ctx context.Context, doctorID int, secret string) error {
+	query := `UPDATE doctors SET totp_secret = $1, totp_enabled = FALSE, totp_last_step = NULL WHERE id = $2 AND deleted_at IS NULL`
+	return r.execSingleUpdate(ctx, "ошибка сохранения секрета TOTP", domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", doctorID), query, secret, doctorID)
+}
+
+// EnableTOTP включает второй фактор врача
+func (r *TwoFactorRepository) EnableTOTP(ctx context.Context, doctorID int) error {
+	query := `UPDATE doctors SET totp_enabled = TRUE WHERE id = $1 AND totp_secret IS NOT NULL AND deleted_at IS NULL`
+	return r.execSingleUpdate(ctx, "ошибка включения TOTP", domain.ErrTOTPNotEnrolled, query, doctorID)
+}
+
+// DisableTOTP отключает второй фактор врача и удаляет коды восстановления
+func (r *TwoFactorRepository) DisableTOTP(ctx context.Context, doctorID int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `UPDATE doctors SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = NULL WHERE id = $1 AND deleted_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, doctorID)
+		if err != nil {
+			return fmt.Errorf("ошибка отключения TOTP: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return domain.ErrDoctorNotFound.WithMessage("врач с ID %d не найден", doctorID)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM doctor_recovery_codes WHERE doctor_id = $1`, doctorID); err != nil {
+			return fmt.Errorf("ошибка удаления кодов восстановления: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UseTOTPStep отмечает шаг времени TOTP использованным; повторное использование того же или более раннего шага отклоняется
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, doctorID int, step int64) (bool, error) {
+	query := `
+		UPDATE doctors SET totp_last_step = $1
+		WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, step, doctorID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка учета кода TOTP: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes заменяет коды восстановления врача новыми в одной транзакции
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, doctorID int, codeHashes []string) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM doctor_recovery_codes WHERE doctor_id = $1`, doctorID); err != nil {
+			return fmt.Errorf("ошибка удаления кодов восстановления: %w", err)
+		}
+
+		for _, hash := range codeHashes {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO doctor_recovery_codes (doctor_id, code_hash) VALUES ($1, $2)`, doctorID, hash); err != nil {
+				return fmt.Errorf("ошибка сохранения кода восстановления: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// UseRecoveryCode погашает неиспользованный код восстановления врача
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, doctorID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE doctor_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE doctor_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, doctorID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("ошибка использования кода восстановления: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CreateChallenge создает ожидание кода второго фактора
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, challenge *domain.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (doctor_id, token_hash, enrollment, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, challenge.DoctorID, challenge.TokenHash, challenge.Enrollment, challenge.ExpiresAt).Scan(&challenge.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка создания проверки второго фактора: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallenge получает ожидание кода второго фактора по хешу токена
+func (r *TwoFactorRepository) GetChallenge(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error) {
+	query := `
+		SELECT id, doctor_id, token_hash, enrollment, attempts, expires_at, used_at
+		FROM mfa_challenges
+		WHERE token_hash = $1`
+
+	challenge := &domain.MFAChallenge{}
+	var usedAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.DoctorID,
+		&challenge.TokenHash,
+		&challenge.Enrollment,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&usedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения проверки второго фактора: %w", err)
+	}
+
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+
+	return challenge, nil
+}
+
+// IncrementChallengeAttempts учитывает неверный код второго фактора
+func (r *TwoFactorRepository) IncrementChallengeAttempts(ctx context.Context, id int) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("ошибка учета попытки второго фактора: %w", err)
+	}
+	return nil
+}
+
+// MarkChallengeUsed отмечает ожидание второго фактора завершенным
+func (r *TwoFactorRepository) MarkChallengeUsed(ctx context.Context, id int) error {
+	query := `UPDATE mfa_challenges SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+	return r.execSingleUpdate(ctx, "ошибка завершения проверки второго фактора", domain.ErrInvalidMFAChallenge, query, id)
+}
+
+// execSingleUpdate выполняет обновление одной строки и возвращает notFound, если строка не найдена
+func (r *TwoFactorRepository) execSingleUpdate(ctx context.Context, errMsg string, notFound error, query string, args ...interface{}) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	repo := NewTwoFactorRepository(testDB.DB)
+	doctorRepo := NewDoctorRepository(testDB.DB)
+
+	t.Run("EnrollAndDisable", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. TOTP", Login: "totp", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		assert.Error(t, repo.EnableTOTP(ctx, doctor.ID))
+
+		require.NoError(t, repo.SetTOTPSecret(ctx, doctor.ID, "SECRET"))
+		require.NoError(t, repo.EnableTOTP(ctx, doctor.ID))
+
+		got, err := doctorRepo.GetByID(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "SECRET", got.TOTPSecret)
+		assert.True(t, got.TOTPEnabled)
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(ctx, doctor.ID, []string{"hash1"}))
+		require.NoError(t, repo.DisableTOTP(ctx, doctor.ID))
+
+		got, err = doctorRepo.GetByID(ctx, doctor.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.TOTPSecret)
+		assert.False(t, got.TOTPEnabled)
+
+		used, err := repo.UseRecoveryCode(ctx, doctor.ID, "hash1")
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		assert.ErrorIs(t, repo.SetTOTPSecret(ctx, 999, "SECRET"), domain.ErrDoctorNotFound)
+	})
+
+	t.Run("UseTOTPStep", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. TOTP", Login: "totp", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+		require.NoError(t, repo.SetTOTPSecret(ctx, doctor.ID, "SECRET"))
+
+		ok, err := repo.UseTOTPStep(ctx, doctor.ID, 100)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.UseTOTPStep(ctx, doctor.ID, 100)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.UseTOTPStep(ctx, doctor.ID, 99)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.UseTOTPStep(ctx, doctor.ID, 101)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("RecoveryCodes", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. TOTP", Login: "totp", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(ctx, doctor.ID, []string{"hash1", "hash2"}))
+
+		used, err := repo.UseRecoveryCode(ctx, doctor.ID, "hash1")
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		used, err = repo.UseRecoveryCode(ctx, doctor.ID, "hash1")
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(ctx, doctor.ID, []string{"hash3"}))
+
+		used, err = repo.UseRecoveryCode(ctx, doctor.ID, "hash2")
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		used, err = repo.UseRecoveryCode(ctx, doctor.ID, "hash3")
+		require.NoError(t, err)
+		assert.True(t, used)
+	})
+
+	t.Run("Challenges", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		doctor := &domain.Doctor{Name: "Dr. TOTP", Login: "totp", Password: "pass"}
+		require.NoError(t, doctorRepo.Create(ctx, doctor))
+
+		challenge := &domain.MFAChallenge{
+			DoctorID:   doctor.ID,
+			TokenHash:  "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			Enrollment: true,
+			ExpiresAt:  time.Now().Add(5 * time.Minute),
+		}
+		require.NoError(t, repo.CreateChallenge(ctx, challenge))
+		assert.NotZero(t, challenge.ID)
+
+		require.NoError(t, repo.IncrementChallengeAttempts(ctx, challenge.ID))
+
+		got, err := repo.GetChallenge(ctx, challenge.TokenHash)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, doctor.ID, got.DoctorID)
+		assert.True(t, got.Enrollment)
+		assert.Equal(t, 1, got.Attempts)
+		assert.Nil(t, got.UsedAt)
+
+		require.NoError(t, repo.MarkChallengeUsed(ctx, challenge.ID))
+		assert.ErrorIs(t, repo.MarkChallengeUsed(ctx, challenge.ID), domain.ErrInvalidMFAChallenge)
+
+		got, err = repo.GetChallenge(ctx, challenge.TokenHash)
+		require.NoError(t, err)
+		assert.NotNil(t, got.UsedAt)
+
+		got, err = repo.GetChallenge(ctx, "missing")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Коды ошибок PostgreSQL, после которых сериализуемую транзакцию можно повторить
+const (
+	pqSerializationFailure = "40001" // конфликт сериализации
+	pqDeadlockDetected     = "40P01" // взаимная блокировка
+)
+
+// Повторы транзакции при конфликте сериализации
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+type txContextKey struct{}
+
+// dbExecutor общий интерфейс для *sql.DB и *sql.Tx
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// WithinTx выполняет fn в сериализуемой транзакции и повторяет ее при конфликте сериализации.
+// Вложенный вызов присоединяется к уже начатой транзакции
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := u.run(ctx, fn)
+		if !isSerializationFailure(err) {
+			return err
+		}
+		if attempt == maxTxAttempts {
+			return fmt.Errorf("%w: %v", domain.ErrConcurrentUpdate, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+}
+
+// run выполняет одну попытку транзакции
+func (u *UnitOfWork) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// txFromContext возвращает транзакцию единицы работы из контекста
+func txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+// withoutTx возвращает контекст, запросы с которым выполняются вне транзакции единицы работы
+func withoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txContextKey{}, (*sql.Tx)(nil))
+}
+
+// conn возвращает транзакцию единицы работы из контекста, а без нее — пул соединений
+func conn(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx выполняет fn в транзакции единицы работы из контекста, а без нее — в собственной транзакции
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isSerializationFailure проверяет, что транзакция прервана конфликтом сериализации или взаимной блокировкой
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected)
+}
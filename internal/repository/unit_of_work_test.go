@@ -0,0 +1,126 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	testDB, err := SetupTestDatabase(ctx)
+	require.NoError(t, err)
+	defer testDB.Teardown(ctx)
+
+	uow := NewUnitOfWork(testDB.DB)
+	patientRepo := NewPatientRepository(testDB.DB)
+	serviceRepo := NewServiceRepository(testDB.DB)
+	auditRepo := NewAuditRepository(testDB.DB)
+
+	t.Run("Commit", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		err = uow.WithinTx(ctx, func(ctx context.Context) error {
+			if err := patientRepo.Create(ctx, &domain.Patient{Name: "John Doe", Phone: "+7 777 123 4567"}); err != nil {
+				return err
+			}
+			return serviceRepo.Create(ctx, &domain.Service{Name: "Консультация", Type: "Терапия", Price: domain.Tenge(5000), Duration: 30})
+		})
+		require.NoError(t, err)
+
+		patients, err := patientRepo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, patients, 1)
+
+		services, err := serviceRepo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, services, 1)
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		wantErr := errors.New("check failed")
+		err = uow.WithinTx(ctx, func(ctx context.Context) error {
+			if err := patientRepo.Create(ctx, &domain.Patient{Name: "John Doe", Phone: "+7 777 123 4567"}); err != nil {
+				return err
+			}
+			return wantErr
+		})
+		assert.ErrorIs(t, err, wantErr)
+
+		patients, err := patientRepo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, patients)
+
+		entries, _, err := auditRepo.List(ctx, domain.AuditFilter{Page: domain.Page{Limit: 10}})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("NestedJoinsOuterTransaction", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		wantErr := errors.New("outer failed")
+		err = uow.WithinTx(ctx, func(ctx context.Context) error {
+			err := uow.WithinTx(ctx, func(ctx context.Context) error {
+				return patientRepo.Create(ctx, &domain.Patient{Name: "John Doe", Phone: "+7 777 123 4567"})
+			})
+			if err != nil {
+				return err
+			}
+			return wantErr
+		})
+		assert.ErrorIs(t, err, wantErr)
+
+		patients, err := patientRepo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, patients)
+	})
+
+	t.Run("RetryOnSerializationFailure", func(t *testing.T) {
+		err := testDB.TruncateTables(ctx)
+		require.NoError(t, err)
+
+		patient := &domain.Patient{Name: "John Doe", Phone: "+7 777 123 4567"}
+		require.NoError(t, patientRepo.Create(ctx, patient))
+
+		attempts := 0
+		err = uow.WithinTx(ctx, func(ctx context.Context) error {
+			attempts++
+
+			current, err := patientRepo.GetByID(ctx, patient.ID)
+			if err != nil {
+				return err
+			}
+
+			if attempts == 1 {
+				_, err := testDB.DB.ExecContext(context.Background(), `UPDATE patients SET address = 'Concurrent' WHERE id = $1`, patient.ID)
+				require.NoError(t, err)
+			}
+
+			current.Name = "Jane Doe"
+			return patientRepo.Update(ctx, current)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+
+		updated, err := patientRepo.GetByID(ctx, patient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Doe", updated.Name)
+		assert.Equal(t, "Concurrent", updated.Address)
+	})
+}
@@ -1,154 +1,633 @@
 package usecase
 
 import (
-	"errors"
+	"context"
+	"strings"
 	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
 )
 
+// Ограничения поиска свободных окон
+const (
+	slotRounding      = 5 * time.Minute
+	defaultSlotLimit  = 10
+	maxSlotLimit      = 100
+	maxSlotSearchDays = 31
+)
+
+// maxCancellationReasonLength максимальная длина причины отмены в символах
+const maxCancellationReasonLength = 500
+
 type AppointmentUseCase struct {
 	appointmentRepo domain.AppointmentRepository
 	patientRepo     domain.PatientRepository
 	serviceRepo     domain.ServiceRepository
+	doctorRepo      domain.DoctorRepository
+	scheduleRepo    domain.ScheduleRepository
+	uow             domain.UnitOfWork
+	location        *time.Location
 }
 
 func NewAppointmentUseCase(
 	appointmentRepo domain.AppointmentRepository,
 	patientRepo domain.PatientRepository,
 	serviceRepo domain.ServiceRepository,
+	doctorRepo domain.DoctorRepository,
+	scheduleRepo domain.ScheduleRepository,
+	uow domain.UnitOfWork,
+	location *time.Location,
 ) *AppointmentUseCase {
 	return &AppointmentUseCase{
 		appointmentRepo: appointmentRepo,
 		patientRepo:     patientRepo,
 		serviceRepo:     serviceRepo,
+		doctorRepo:      doctorRepo,
+		scheduleRepo:    scheduleRepo,
+		uow:             uow,
+		location:        location,
 	}
 }
 
 // GetAppointment получает запись по ID
-func (u *AppointmentUseCase) GetAppointment(id int) (*domain.Appointment, error) {
+func (u *AppointmentUseCase) GetAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid appointment ID")
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid appointment ID")
 	}
-	return u.appointmentRepo.GetByID(id)
+	return u.appointmentRepo.GetByID(ctx, id)
 }
 
 // GetAllAppointments получает все записи
-func (u *AppointmentUseCase) GetAllAppointments() ([]*domain.Appointment, error) {
-	return u.appointmentRepo.GetAll()
+func (u *AppointmentUseCase) GetAllAppointments(ctx context.Context) ([]*domain.Appointment, error) {
+	return u.appointmentRepo.GetAll(ctx)
 }
 
-// CreateAppointment создает новую запись
-func (u *AppointmentUseCase) CreateAppointment(appointment *domain.Appointment) error {
-	if err := u.ValidateAppointment(appointment); err != nil {
-		return err
+// ListAppointments получает страницу записей по фильтру и общее число подходящих записей
+func (u *AppointmentUseCase) ListAppointments(ctx context.Context, filter domain.AppointmentFilter) ([]*domain.Appointment, int, error) {
+	if filter.PatientID < 0 {
+		return nil, 0, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
 	}
 
-	// Проверяем, что пациент существует
-	patient, err := u.patientRepo.GetByID(appointment.PatientID)
-	if err != nil {
-		return errors.New("patient not found")
+	if filter.DoctorID < 0 {
+		return nil, 0, domain.NewValidationError("doctor_id", domain.FieldInvalid, "invalid doctor ID")
 	}
-	appointment.PatientName = patient.Name
 
-	appointment.Status = domain.StatusScheduled
-	appointment.CreatedAt = time.Now()
-	appointment.UpdatedAt = time.Now()
+	if filter.ServiceID < 0 {
+		return nil, 0, domain.NewValidationError("service_id", domain.FieldInvalid, "invalid service ID")
+	}
 
-	return u.appointmentRepo.Create(appointment)
+	if filter.RescheduledFromID < 0 {
+		return nil, 0, domain.NewValidationError("rescheduled_from_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, 0, domain.NewValidationError("status", domain.FieldInvalid, "invalid appointment status")
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, domain.NewValidationError("date_to", domain.FieldRange, "date_from must be before date_to")
+	}
+
+	if err := normalizeList(filter.Sort, &filter.Page, domain.AppointmentSortFields); err != nil {
+		return nil, 0, err
+	}
+
+	return u.appointmentRepo.List(ctx, filter)
 }
 
-// UpdateAppointment обновляет запись
-func (u *AppointmentUseCase) UpdateAppointment(appointment *domain.Appointment) error {
-	if err := u.ValidateAppointment(appointment); err != nil {
+// CreateAppointment создает новую запись.
+// Проверки и вставка выполняются в одной транзакции, чтобы параллельная запись не заняла то же время
+func (u *AppointmentUseCase) CreateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	u.toClinicTime(appointment)
+	if err := validateAppointmentFields(appointment); err != nil {
 		return err
 	}
 
-	// Проверяем, что пациент существует
-	patient, err := u.patientRepo.GetByID(appointment.PatientID)
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		return u.create(ctx, appointment)
+	})
+}
+
+// create проверяет и сохраняет новую запись в транзакции вызывающего
+func (u *AppointmentUseCase) create(ctx context.Context, appointment *domain.Appointment) error {
+	service, err := u.resolveReferences(ctx, appointment)
 	if err != nil {
-		return errors.New("patient not found")
+		return err
 	}
-	appointment.PatientName = patient.Name
 
-	// Проверяем конфликт времени (исключая текущую запись)
-	hasConflict, err := u.appointmentRepo.CheckTimeConflict(appointment.Date, appointment.Time, appointment.ID)
-	if err != nil {
+	if err := u.applyPriceList(ctx, appointment, service); err != nil {
 		return err
 	}
-	if hasConflict {
-		return errors.New("time slot is already occupied")
+
+	appointment.Status = domain.StatusScheduled
+	if err := u.checkDoctorSchedule(ctx, appointment); err != nil {
+		return err
+	}
+
+	if err := prepareSchedule(appointment); err != nil {
+		return err
+	}
+
+	// Проверяем, что врач свободен в это время
+	if err := u.checkConflicts(ctx, appointment); err != nil {
+		return err
 	}
 
+	appointment.CreatedAt = time.Now()
 	appointment.UpdatedAt = time.Now()
 
-	return u.appointmentRepo.Update(appointment)
+	return u.appointmentRepo.Create(ctx, appointment)
+}
+
+// UpdateAppointment обновляет запись; проверки и изменение выполняются в одной транзакции
+func (u *AppointmentUseCase) UpdateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	u.toClinicTime(appointment)
+	if err := validateAppointmentFields(appointment); err != nil {
+		return err
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		stored, err := u.appointmentRepo.GetByID(ctx, appointment.ID)
+		if err != nil {
+			return err
+		}
+
+		// Статус меняется только по правилам жизненного цикла, время переходов хранится сервером
+		next, reason := appointment.Status, appointment.CancellationReason
+		appointment.RestoreLifecycle(stored)
+		if next != "" && next != stored.Status {
+			if err := appointment.Transition(next, time.Now()); err != nil {
+				return err
+			}
+			if next == domain.StatusCancelled {
+				appointment.CancellationReason = reason
+			}
+		}
+
+		if _, err := u.resolveReferences(ctx, appointment); err != nil {
+			return err
+		}
+
+		if err := prepareSchedule(appointment); err != nil {
+			return err
+		}
+
+		// У закрытой записи время, врач, услуга и длительность — история приема; график врача для нее не проверяется
+		if stored.Status.Closed() {
+			if !appointment.Date.Equal(stored.Date) || appointment.DoctorID != stored.DoctorID ||
+				appointment.ServiceID != stored.ServiceID || appointment.Duration != stored.Duration {
+				return domain.ErrAppointmentClosed.WithMessage("у записи в статусе %s нельзя менять время, врача, услугу и длительность", stored.Status)
+			}
+		} else if err := u.checkDoctorSchedule(ctx, appointment); err != nil {
+			return err
+		}
+
+		// Проверяем конфликт времени (исключая текущую запись); отмененная или перенесенная запись время не занимает
+		if appointment.Status.Active() {
+			if err := u.checkConflicts(ctx, appointment); err != nil {
+				return err
+			}
+		}
+
+		appointment.UpdatedAt = time.Now()
+
+		return u.appointmentRepo.Update(ctx, appointment)
+	})
 }
 
 // DeleteAppointment удаляет запись
-func (u *AppointmentUseCase) DeleteAppointment(id int) error {
+func (u *AppointmentUseCase) DeleteAppointment(ctx context.Context, id int) error {
 	if id <= 0 {
-		return errors.New("invalid appointment ID")
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid appointment ID")
 	}
-	return u.appointmentRepo.Delete(id)
+	return u.appointmentRepo.Delete(ctx, id)
 }
 
 // GetAppointmentsByPatient получает записи по пациенту
-func (u *AppointmentUseCase) GetAppointmentsByPatient(patientID int) ([]*domain.Appointment, error) {
+func (u *AppointmentUseCase) GetAppointmentsByPatient(ctx context.Context, patientID int) ([]*domain.Appointment, error) {
 	if patientID <= 0 {
-		return nil, errors.New("invalid patient ID")
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
 	}
-	return u.appointmentRepo.GetByPatientID(patientID)
+	return u.appointmentRepo.GetByPatientID(ctx, patientID)
 }
 
 // GetAppointmentsByDate получает записи по дате
-func (u *AppointmentUseCase) GetAppointmentsByDate(date time.Time) ([]*domain.Appointment, error) {
-	return u.appointmentRepo.GetByDate(date)
+func (u *AppointmentUseCase) GetAppointmentsByDate(ctx context.Context, date time.Time) ([]*domain.Appointment, error) {
+	return u.appointmentRepo.GetByDate(ctx, date.In(u.location))
+}
+
+// ConfirmAppointment отмечает, что пациент подтвердил запись
+func (u *AppointmentUseCase) ConfirmAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	return u.changeStatus(ctx, id, domain.StatusConfirmed, "")
+}
+
+// MarkAppointmentArrived отмечает, что пациент пришел на прием
+func (u *AppointmentUseCase) MarkAppointmentArrived(ctx context.Context, id int) (*domain.Appointment, error) {
+	return u.changeStatus(ctx, id, domain.StatusArrived, "")
+}
+
+// StartAppointment отмечает начало приема
+func (u *AppointmentUseCase) StartAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	return u.changeStatus(ctx, id, domain.StatusInProgress, "")
 }
 
 // CompleteAppointment завершает запись
-func (u *AppointmentUseCase) CompleteAppointment(id int) error {
-	appointment, err := u.appointmentRepo.GetByID(id)
-	if err != nil {
-		return err
-	}
+func (u *AppointmentUseCase) CompleteAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	return u.changeStatus(ctx, id, domain.StatusCompleted, "")
+}
 
-	appointment.Status = domain.StatusCompleted
-	appointment.UpdatedAt = time.Now()
+// CancelAppointment отменяет запись с указанием причины
+func (u *AppointmentUseCase) CancelAppointment(ctx context.Context, id int, reason string) (*domain.Appointment, error) {
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxCancellationReasonLength {
+		return nil, domain.NewValidationError("reason", domain.FieldTooLong, "cancellation reason is too long")
+	}
+	return u.changeStatus(ctx, id, domain.StatusCancelled, reason)
+}
 
-	return u.appointmentRepo.Update(appointment)
+// MarkAppointmentNoShow отмечает, что пациент не пришел на прием
+func (u *AppointmentUseCase) MarkAppointmentNoShow(ctx context.Context, id int) (*domain.Appointment, error) {
+	return u.changeStatus(ctx, id, domain.StatusNoShow, "")
 }
 
-// CancelAppointment отменяет запись
-func (u *AppointmentUseCase) CancelAppointment(id int) error {
-	appointment, err := u.appointmentRepo.GetByID(id)
+// RescheduleAppointment переносит запись на другое время: исходная запись получает статус rescheduled,
+// вместо нее создается новая с теми же пациентом, услугой, врачом и ценой
+func (u *AppointmentUseCase) RescheduleAppointment(ctx context.Context, id int, date time.Time) (*domain.Appointment, error) {
+	if id <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid appointment ID")
+	}
+
+	if date.IsZero() {
+		return nil, domain.NewValidationError("date", domain.FieldRequired, "date is required")
+	}
+
+	var rescheduled *domain.Appointment
+	err := u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		original, err := u.appointmentRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := original.Transition(domain.StatusRescheduled, time.Now()); err != nil {
+			return err
+		}
+		original.UpdatedAt = time.Now()
+
+		// Исходная запись освобождает время раньше, чем создается новая, чтобы перенос на пересекающееся время не считался конфликтом
+		if err := u.appointmentRepo.Update(ctx, original); err != nil {
+			return err
+		}
+
+		rescheduled = &domain.Appointment{
+			PatientID:         original.PatientID,
+			ServiceID:         original.ServiceID,
+			DoctorID:          original.DoctorID,
+			Date:              date.In(u.location),
+			Price:             original.Price,
+			Duration:          original.Duration,
+			Notes:             original.Notes,
+			RescheduledFromID: original.ID,
+		}
+		return u.create(ctx, rescheduled)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	appointment.Status = domain.StatusCancelled
-	appointment.UpdatedAt = time.Now()
+	return rescheduled, nil
+}
+
+// changeStatus переводит запись в статус next; проверка перехода и сохранение выполняются в одной транзакции
+func (u *AppointmentUseCase) changeStatus(ctx context.Context, id int, next domain.AppointmentStatus, reason string) (*domain.Appointment, error) {
+	if id <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid appointment ID")
+	}
+
+	var appointment *domain.Appointment
+	err := u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		appointment, err = u.appointmentRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	return u.appointmentRepo.Update(appointment)
+		if err := appointment.Transition(next, time.Now()); err != nil {
+			return err
+		}
+		if next == domain.StatusCancelled {
+			appointment.CancellationReason = reason
+		}
+		appointment.UpdatedAt = time.Now()
+
+		return u.appointmentRepo.Update(ctx, appointment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
 }
 
 // ValidateAppointment валидирует данные записи
-func (u *AppointmentUseCase) ValidateAppointment(appointment *domain.Appointment) error {
+func (u *AppointmentUseCase) ValidateAppointment(ctx context.Context, appointment *domain.Appointment) error {
+	u.toClinicTime(appointment)
+	if err := validateAppointmentFields(appointment); err != nil {
+		return err
+	}
+	return u.checkDoctorSchedule(ctx, appointment)
+}
+
+// toClinicTime переводит дату записи в часовой пояс клиники: время приема HH:MM и границы дня считаются в нем
+func (u *AppointmentUseCase) toClinicTime(appointment *domain.Appointment) {
+	if appointment != nil {
+		appointment.Date = appointment.Date.In(u.location)
+	}
+}
+
+// validateAppointmentFields проверяет обязательные поля записи без обращения к хранилищу
+func validateAppointmentFields(appointment *domain.Appointment) error {
 	if appointment == nil {
-		return errors.New("appointment cannot be nil")
+		return domain.NewValidationError("", domain.FieldRequired, "appointment cannot be nil")
 	}
 
 	if appointment.PatientID <= 0 {
-		return errors.New("patient ID is required")
+		return domain.NewValidationError("patient_id", domain.FieldRequired, "patient ID is required")
 	}
 
 	if appointment.Date.IsZero() {
-		return errors.New("date is required")
+		return domain.NewValidationError("date", domain.FieldRequired, "date is required")
+	}
+
+	if appointment.ServiceID <= 0 {
+		return domain.NewValidationError("service_id", domain.FieldRequired, "service is required")
+	}
+
+	if appointment.DoctorID < 0 {
+		return domain.NewValidationError("doctor_id", domain.FieldInvalid, "invalid doctor ID")
+	}
+
+	if appointment.Price < 0 || appointment.Duration < 0 {
+		return domain.NewValidationError("price", domain.FieldNegative, "price and duration must not be negative")
+	}
+
+	if appointment.Status != "" && !appointment.Status.Valid() {
+		return domain.NewValidationError("status", domain.FieldInvalid, "invalid appointment status")
+	}
+
+	if len([]rune(appointment.CancellationReason)) > maxCancellationReasonLength {
+		return domain.NewValidationError("cancellation_reason", domain.FieldTooLong, "cancellation reason is too long")
 	}
 
-	if appointment.Service == "" {
-		return errors.New("service is required")
+	_, err := scheduledStart(appointment)
+	return err
+}
+
+// checkDoctorSchedule проверяет, что предстоящий прием укладывается в рабочее время врача
+func (u *AppointmentUseCase) checkDoctorSchedule(ctx context.Context, appointment *domain.Appointment) error {
+	if appointment.DoctorID == 0 || !appointment.Status.Upcoming() {
+		return nil
 	}
 
+	start, err := scheduledStart(appointment)
+	if err != nil {
+		return err
+	}
+
+	return u.checkAvailability(ctx, appointment.DoctorID, start, start.Add(scheduledDuration(appointment)))
+}
+
+// applyPriceList подставляет цену на дату приема и длительность услуги, если они не указаны
+func (u *AppointmentUseCase) applyPriceList(ctx context.Context, appointment *domain.Appointment, service *domain.Service) error {
+	if appointment.Duration == 0 && service.Duration > 0 {
+		appointment.Duration = service.Duration
+	}
+
+	if appointment.Price == 0 {
+		start, err := scheduledStart(appointment)
+		if err != nil {
+			return err
+		}
+
+		price, err := u.serviceRepo.GetPriceAt(ctx, service.ID, start)
+		if err != nil {
+			return err
+		}
+		appointment.Price = price
+	}
+
+	return nil
+}
+
+// resolveReferences проверяет, что пациент, услуга и врач записи существуют, и заполняет их имена для отображения
+func (u *AppointmentUseCase) resolveReferences(ctx context.Context, appointment *domain.Appointment) (*domain.Service, error) {
+	patient, err := u.patientRepo.GetByID(ctx, appointment.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	appointment.PatientName = patient.Name
+
+	service, err := u.serviceRepo.GetByID(ctx, appointment.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	appointment.Service = service.Name
+
+	appointment.Doctor = ""
+	if appointment.DoctorID > 0 {
+		doctor, err := u.doctorRepo.GetByID(ctx, appointment.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		if doctor == nil {
+			return nil, domain.ErrDoctorNotFound
+		}
+		appointment.Doctor = doctor.Name
+	}
+
+	return service, nil
+}
+
+// checkAvailability проверяет, что прием укладывается в рабочее время врача
+func (u *AppointmentUseCase) checkAvailability(ctx context.Context, doctorID int, start, end time.Time) error {
+	day := startOfDay(start)
+	intervals, err := workingIntervals(ctx, u.scheduleRepo, doctorID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	if !coversInterval(intervals, start, end) {
+		return domain.ErrDoctorUnavailable
+	}
+
+	return nil
+}
+
+// FindFreeSlots подбирает ближайшие свободные окна врача в диапазоне дат [From, To]
+func (u *AppointmentUseCase) FindFreeSlots(ctx context.Context, query *domain.SlotQuery) ([]domain.TimeSlot, error) {
+	if query == nil || query.DoctorID <= 0 {
+		return nil, domain.NewValidationError("doctor_id", domain.FieldRequired, "doctor ID is required")
+	}
+
+	if query.From.IsZero() {
+		return nil, domain.NewValidationError("date_from", domain.FieldRequired, "date from is required")
+	}
+
+	to := query.To
+	if to.IsZero() {
+		to = query.From
+	}
+	if to.Before(query.From) {
+		return nil, domain.NewValidationError("date_to", domain.FieldRange, "date to must not be before date from")
+	}
+
+	from := startOfDay(query.From.In(u.location))
+	end := startOfDay(to.In(u.location)).AddDate(0, 0, 1)
+	if end.Sub(from) > maxSlotSearchDays*24*time.Hour {
+		return nil, domain.NewValidationError("date_to", domain.FieldRange, "date range is too long")
+	}
+
+	if query.Duration < 0 || query.Buffer < 0 {
+		return nil, domain.NewValidationError("duration", domain.FieldNegative, "duration and buffer must not be negative")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSlotLimit
+	}
+	if limit > maxSlotLimit {
+		limit = maxSlotLimit
+	}
+
+	doctor, err := u.doctorRepo.GetByID(ctx, query.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+	if doctor == nil {
+		return nil, domain.ErrDoctorNotFound
+	}
+
+	duration := query.Duration
+	if query.ServiceID > 0 {
+		service, err := u.serviceRepo.GetByID(ctx, query.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if duration == 0 {
+			duration = service.Duration
+		}
+	}
+	if duration == 0 {
+		duration = domain.DefaultAppointmentDuration
+	}
+
+	// Записи ищутся по пересечению, чтобы учесть прием, начавшийся до from; окно расширено на перерыв
+	buffer := time.Duration(query.Buffer) * time.Minute
+	appointments, err := u.appointmentRepo.FindOverlapping(ctx, doctor.ID, from.Add(-buffer), end.Add(buffer))
+	if err != nil {
+		return nil, err
+	}
+
+	// Занятые интервалы врача, расширенные на перерыв до и после приема
+	var busy []domain.TimeSlot
+	for _, appointment := range appointments {
+		if appointment.DoctorID != doctor.ID || !appointment.Status.Active() {
+			continue
+		}
+		busy = append(busy, domain.TimeSlot{
+			Start: appointment.Date.Add(-buffer),
+			End:   appointment.EndTime().Add(buffer),
+		})
+	}
+
+	intervals, err := workingIntervals(ctx, u.scheduleRepo, doctor.ID, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	length := time.Duration(duration) * time.Minute
+	now := time.Now()
+	slots := make([]domain.TimeSlot, 0, limit)
+	for _, interval := range intervals {
+		start := interval.Start
+		for !start.Add(length).After(interval.End) && len(slots) < limit {
+			if start.Before(now) {
+				start = now.Truncate(slotRounding).Add(slotRounding)
+				continue
+			}
+
+			slot := domain.TimeSlot{Start: start, End: start.Add(length)}
+			if blocker := findOverlap(busy, slot); blocker != nil {
+				start = blocker.End
+				continue
+			}
+
+			slots = append(slots, slot)
+			start = slot.End.Add(buffer)
+		}
+	}
+
+	return slots, nil
+}
+
+// findOverlap возвращает первый занятый интервал, пересекающийся со слотом
+func findOverlap(busy []domain.TimeSlot, slot domain.TimeSlot) *domain.TimeSlot {
+	for i := range busy {
+		if busy[i].Start.Before(slot.End) && busy[i].End.After(slot.Start) {
+			return &busy[i]
+		}
+	}
+	return nil
+}
+
+// startOfDay возвращает начало суток для указанного времени
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// checkConflicts проверяет, что у врача нет пересекающихся записей
+func (u *AppointmentUseCase) checkConflicts(ctx context.Context, appointment *domain.Appointment) error {
+	conflicts, err := u.appointmentRepo.FindConflicts(ctx, appointment)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &domain.AppointmentConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// prepareSchedule переносит время приема (HH:MM) в дату записи и подставляет длительность по умолчанию
+func prepareSchedule(appointment *domain.Appointment) error {
+	start, err := scheduledStart(appointment)
+	if err != nil {
+		return err
+	}
+
+	appointment.Date = start
+	appointment.Time = start.Format("15:04")
+	appointment.Duration = int(scheduledDuration(appointment) / time.Minute)
+
 	return nil
 }
+
+// scheduledStart возвращает начало приема: дату записи со временем из поля Time, если оно указано
+func scheduledStart(appointment *domain.Appointment) (time.Time, error) {
+	if appointment.Time == "" {
+		return appointment.Date, nil
+	}
+
+	clock, err := parseClock(appointment.Time)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return atClock(appointment.Date, clock), nil
+}
+
+// scheduledDuration возвращает длительность приема с учетом значения по умолчанию
+func scheduledDuration(appointment *domain.Appointment) time.Duration {
+	if appointment.Duration <= 0 {
+		return domain.DefaultAppointmentDuration * time.Minute
+	}
+	return time.Duration(appointment.Duration) * time.Minute
+}
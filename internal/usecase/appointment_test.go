@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +14,14 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+func newTestUnitOfWork(ctrl *gomock.Controller) *repository.MockUnitOfWork {
+	uow := repository.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return uow
+}
+
 func TestAppointmentUseCase_GetAppointment(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -24,10 +34,10 @@ func TestAppointmentUseCase_GetAppointment(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Appointment{
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
 					ID:        1,
 					PatientID: 1,
-					Service:   "Консультация",
+					ServiceID: 1,
 					Status:    domain.StatusScheduled,
 				}, nil)
 			},
@@ -51,7 +61,7 @@ func TestAppointmentUseCase_GetAppointment(t *testing.T) {
 			name: "appointment not found",
 			id:   999,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("appointment not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("appointment not found"))
 			},
 			wantErr: true,
 			errMsg:  "appointment not found",
@@ -66,10 +76,12 @@ func TestAppointmentUseCase_GetAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			appointment, err := uc.GetAppointment(tt.id)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			appointment, err := uc.GetAppointment(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -93,9 +105,9 @@ func TestAppointmentUseCase_GetAllAppointments(t *testing.T) {
 		{
 			name: "success with appointments",
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, PatientID: 1, Service: "Консультация"},
-					{ID: 2, PatientID: 2, Service: "Лечение"},
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, PatientID: 1, ServiceID: 1, Service: "Консультация"},
+					{ID: 2, PatientID: 2, ServiceID: 2, Service: "Лечение"},
 				}, nil)
 			},
 			want:    2,
@@ -104,7 +116,7 @@ func TestAppointmentUseCase_GetAllAppointments(t *testing.T) {
 		{
 			name: "success empty list",
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Appointment{}, nil)
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -112,7 +124,7 @@ func TestAppointmentUseCase_GetAllAppointments(t *testing.T) {
 		{
 			name: "repository error",
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				m.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -126,10 +138,12 @@ func TestAppointmentUseCase_GetAllAppointments(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			appointments, err := uc.GetAllAppointments()
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			appointments, err := uc.GetAllAppointments(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -141,15 +155,117 @@ func TestAppointmentUseCase_GetAllAppointments(t *testing.T) {
 	}
 }
 
+func TestAppointmentUseCase_ListAppointments(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    domain.AppointmentFilter
+		setup     func(*repository.MockAppointmentRepository)
+		wantTotal int
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name: "typed filters",
+			filter: domain.AppointmentFilter{
+				PatientID: 1, DoctorID: 2, ServiceID: 3, Status: domain.StatusCompleted, From: &from, To: &to,
+				Sort: domain.Sort{Field: "price", Desc: true},
+			},
+			setup: func(m *repository.MockAppointmentRepository) {
+				m.EXPECT().List(gomock.Any(), domain.AppointmentFilter{
+					PatientID: 1, DoctorID: 2, ServiceID: 3, Status: domain.StatusCompleted, From: &from, To: &to,
+					Sort: domain.Sort{Field: "price", Desc: true},
+					Page: domain.Page{Limit: domain.DefaultPageLimit},
+				}).Return([]*domain.Appointment{{ID: 1}}, 1, nil)
+			},
+			wantTotal: 1,
+		},
+		{
+			name:    "unknown status",
+			filter:  domain.AppointmentFilter{Status: "archived"},
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "invalid appointment status",
+		},
+		{
+			name:    "negative doctor ID",
+			filter:  domain.AppointmentFilter{DoctorID: -1},
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "invalid doctor ID",
+		},
+		{
+			name:    "reversed date range",
+			filter:  domain.AppointmentFilter{From: &to, To: &from},
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "date_from must be before date_to",
+		},
+		{
+			name:    "unknown sort field",
+			filter:  domain.AppointmentFilter{Sort: domain.Sort{Field: "a.id; DROP TABLE appointments"}},
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "invalid sort field",
+		},
+		{
+			name:    "negative limit",
+			filter:  domain.AppointmentFilter{Page: domain.Page{Limit: -10}},
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "limit must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+			tt.setup(mockAppointmentRepo)
+
+			uc := NewAppointmentUseCase(mockAppointmentRepo, repository.NewMockPatientRepository(ctrl), repository.NewMockServiceRepository(ctrl),
+				repository.NewMockDoctorRepository(ctrl), repository.NewMockScheduleRepository(ctrl), newTestUnitOfWork(ctrl), time.UTC)
+			appointments, total, err := uc.ListAppointments(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, appointments)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
 func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 	futureDate := time.Now().Add(24 * time.Hour)
+	defaultSchedule := func(sc *repository.MockScheduleRepository, doctorID int) {
+		sc.EXPECT().GetWorkingHours(gomock.Any(), doctorID).Return(nil, nil)
+		sc.EXPECT().GetBreaks(gomock.Any(), doctorID).Return(nil, nil)
+		sc.EXPECT().GetExceptions(gomock.Any(), doctorID, gomock.Any(), gomock.Any()).Return(nil, nil)
+		sc.EXPECT().GetHolidays(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	}
+	references := func(p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository) {
+		p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
+		s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация", Price: domain.Tenge(4000), Duration: 45}, nil)
+		d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2, Name: "Dr. Smith"}, nil)
+	}
 
 	tests := []struct {
-		name        string
-		appointment *domain.Appointment
-		setup       func(*repository.MockAppointmentRepository, *repository.MockPatientRepository, *repository.MockServiceRepository)
-		wantErr     bool
-		errMsg      string
+		name         string
+		appointment  *domain.Appointment
+		setup        func(*repository.MockAppointmentRepository, *repository.MockPatientRepository, *repository.MockServiceRepository, *repository.MockDoctorRepository, *repository.MockScheduleRepository)
+		wantPrice    domain.Money
+		wantDuration int
+		wantErr      bool
+		errMsg       string
 	}{
 		{
 			name: "success",
@@ -157,19 +273,45 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "10:00",
-				Service:   "Консультация",
-				Doctor:    "Dr. Smith",
+				ServiceID: 1,
+				DoctorID:  2,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
-				a.EXPECT().Create(gomock.Any()).Return(nil)
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				defaultSchedule(sc, 2)
+				references(p, s, d)
+				s.EXPECT().GetPriceAt(gomock.Any(), 1, gomock.Any()).Return(domain.Tenge(5000), nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 			},
-			wantErr: false,
+			wantPrice:    domain.Tenge(5000),
+			wantDuration: 45,
+			wantErr:      false,
+		},
+		{
+			name: "explicit price and duration are kept",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				DoctorID:  2,
+				Price:     domain.Tenge(7000),
+				Duration:  60,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				defaultSchedule(sc, 2)
+				references(p, s, d)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantPrice:    domain.Tenge(7000),
+			wantDuration: 60,
+			wantErr:      false,
 		},
 		{
 			name:        "nil appointment",
 			appointment: nil,
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
 			},
 			wantErr: true,
 			errMsg:  "appointment cannot be nil",
@@ -179,9 +321,9 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 			appointment: &domain.Appointment{
 				PatientID: 0,
 				Date:      futureDate,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
 			},
 			wantErr: true,
 			errMsg:  "patient ID is required",
@@ -190,9 +332,9 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 			name: "missing date",
 			appointment: &domain.Appointment{
 				PatientID: 1,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
 			},
 			wantErr: true,
 			errMsg:  "date is required",
@@ -203,7 +345,7 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
 			},
 			wantErr: true,
 			errMsg:  "service is required",
@@ -213,24 +355,141 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 			appointment: &domain.Appointment{
 				PatientID: 999,
 				Date:      futureDate,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(999).Return(nil, errors.New("patient not found"))
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("patient not found"))
 			},
 			wantErr: true,
 			errMsg:  "patient not found",
 		},
+		{
+			name: "time conflict with doctor appointment",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				DoctorID:  2,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				defaultSchedule(sc, 2)
+				references(p, s, d)
+				s.EXPECT().GetPriceAt(gomock.Any(), 1, gomock.Any()).Return(domain.Tenge(5000), nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return([]*domain.Appointment{{ID: 7, Doctor: "Dr. Smith"}}, nil)
+			},
+			wantErr: true,
+			errMsg:  "time slot is already occupied",
+		},
+		{
+			name: "outside doctor working hours",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "20:00",
+				ServiceID: 1,
+				DoctorID:  2,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				defaultSchedule(sc, 2)
+				references(p, s, d)
+				s.EXPECT().GetPriceAt(gomock.Any(), 1, gomock.Any()).Return(domain.Tenge(5000), nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor is not available at this time",
+		},
+		{
+			name: "doctor on day off",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				DoctorID:  2,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				references(p, s, d)
+				s.EXPECT().GetPriceAt(gomock.Any(), 1, gomock.Any()).Return(domain.Tenge(5000), nil)
+				sc.EXPECT().GetWorkingHours(gomock.Any(), 2).Return(nil, nil)
+				sc.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+				sc.EXPECT().GetExceptions(gomock.Any(), 2, gomock.Any(), gomock.Any()).Return([]*domain.ScheduleException{
+					{DoctorID: 2, Type: domain.ExceptionDayOff, DateFrom: futureDate, DateTo: futureDate},
+				}, nil)
+				sc.EXPECT().GetHolidays(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor is not available at this time",
+		},
+		{
+			name: "unknown doctor",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				DoctorID:  99,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				d.EXPECT().GetByID(gomock.Any(), 99).Return(nil, nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor not found",
+		},
+		{
+			name: "unknown service",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 99,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
+		{
+			name: "patient lookup error is not reported as not found",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 1,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.New("pq: could not serialize access"))
+			},
+			wantErr: true,
+			errMsg:  "could not serialize access",
+		},
+		{
+			name: "invalid time format",
+			appointment: &domain.Appointment{
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "25:99",
+				ServiceID: 1,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+			},
+			wantErr: true,
+			errMsg:  "invalid time format",
+		},
 		{
 			name: "repository create error",
 			appointment: &domain.Appointment{
 				PatientID: 1,
 				Date:      futureDate,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
-			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
-				a.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				s.EXPECT().GetPriceAt(gomock.Any(), 1, gomock.Any()).Return(domain.Tenge(5000), nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -245,10 +504,12 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
-			tt.setup(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			tt.setup(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			err := uc.CreateAppointment(tt.appointment)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			err := uc.CreateAppointment(context.Background(), tt.appointment)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -257,14 +518,46 @@ func TestAppointmentUseCase_CreateAppointment(t *testing.T) {
 				require.NoError(t, err)
 				assert.Equal(t, domain.StatusScheduled, tt.appointment.Status)
 				assert.Equal(t, "John Doe", tt.appointment.PatientName)
+				assert.Equal(t, "Консультация", tt.appointment.Service)
+				assert.Equal(t, "Dr. Smith", tt.appointment.Doctor)
 				assert.False(t, tt.appointment.CreatedAt.IsZero())
+				assert.Equal(t, "10:00", tt.appointment.Date.Format("15:04"))
+				assert.Equal(t, tt.wantPrice, tt.appointment.Price)
+				assert.Equal(t, tt.wantDuration, tt.appointment.Duration)
 			}
 		})
 	}
 }
 
+func TestAppointmentUseCase_CreateAppointmentConcurrentUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uow := repository.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(domain.ErrConcurrentUpdate)
+
+	uc := NewAppointmentUseCase(
+		repository.NewMockAppointmentRepository(ctrl),
+		repository.NewMockPatientRepository(ctrl),
+		repository.NewMockServiceRepository(ctrl),
+		repository.NewMockDoctorRepository(ctrl),
+		repository.NewMockScheduleRepository(ctrl),
+		uow,
+		time.UTC,
+	)
+
+	err := uc.CreateAppointment(context.Background(), &domain.Appointment{
+		PatientID: 1,
+		ServiceID: 1,
+		Date:      time.Now().Add(24 * time.Hour),
+	})
+
+	assert.ErrorIs(t, err, domain.ErrConcurrentUpdate)
+}
+
 func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 	futureDate := time.Now().Add(24 * time.Hour)
+	pastDate := time.Date(2024, 12, 16, 10, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name        string
@@ -287,7 +580,6 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				ID:        1,
 				PatientID: 1,
 				Date:      futureDate,
-				Service:   "",
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
 			},
@@ -301,13 +593,15 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "10:00",
-				Service:   "Консультация",
+				ServiceID: 1,
 				Status:    domain.StatusScheduled,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
-				a.EXPECT().CheckTimeConflict(futureDate, "10:00", 1).Return(false, nil)
-				a.EXPECT().Update(gomock.Any()).Return(nil)
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -318,18 +612,21 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "14:00",
-				Service:   "Лечение",
+				ServiceID: 2,
 				Status:    domain.StatusScheduled,
-				Price:     15000.00,
+				Price:     domain.Tenge(15000),
 				Duration:  60,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "Jane Doe"}, nil)
-				a.EXPECT().CheckTimeConflict(futureDate, "14:00", 1).Return(false, nil)
-				a.EXPECT().Update(gomock.Any()).DoAndReturn(func(apt *domain.Appointment) error {
-					assert.Equal(t, 15000.00, apt.Price)
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "Jane Doe"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Лечение"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+					assert.Equal(t, domain.Tenge(15000), apt.Price)
 					assert.Equal(t, 60, apt.Duration)
 					assert.Equal(t, "Jane Doe", apt.PatientName)
+					assert.Equal(t, "Лечение", apt.Service)
 					return nil
 				})
 			},
@@ -342,29 +639,205 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "10:00",
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
-				a.EXPECT().CheckTimeConflict(futureDate, "10:00", 1).Return(true, nil)
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return([]*domain.Appointment{{ID: 2, Doctor: "Dr. Smith"}}, nil)
 			},
 			wantErr: true,
 			errMsg:  "time slot is already occupied",
 		},
+		{
+			name: "cancelled appointment skips conflict check",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				Status:    domain.StatusCancelled,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+					assert.Equal(t, domain.StatusCancelled, apt.Status)
+					assert.NotNil(t, apt.CancelledAt)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "status change keeps stored lifecycle",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				Time:      "10:00",
+				ServiceID: 1,
+				Status:    domain.StatusArrived,
+				ArrivedAt: &futureDate,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				confirmedAt := time.Now().Add(-time.Hour)
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusConfirmed, ConfirmedAt: &confirmedAt}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+					assert.Equal(t, domain.StatusArrived, apt.Status)
+					assert.Equal(t, &confirmedAt, apt.ConfirmedAt)
+					require.NotNil(t, apt.ArrivedAt)
+					assert.NotEqual(t, futureDate, *apt.ArrivedAt)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "completed appointment keeps schedule",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      pastDate,
+				ServiceID: 1,
+				Duration:  45,
+				Status:    domain.StatusCompleted,
+				Notes:     "контрольный снимок через месяц",
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
+					ID: 1, Date: pastDate, ServiceID: 1, Duration: 45, Status: domain.StatusCompleted,
+				}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+					assert.Equal(t, "контрольный снимок через месяц", apt.Notes)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "completed appointment cannot be moved",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 1,
+				Duration:  45,
+				Status:    domain.StatusCompleted,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
+					ID: 1, Date: pastDate, ServiceID: 1, Duration: 45, Status: domain.StatusCompleted,
+				}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+			},
+			wantErr: true,
+			errMsg:  "у записи в статусе completed нельзя менять время",
+		},
+		{
+			name: "cancelled appointment cannot change service",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      pastDate,
+				ServiceID: 2,
+				Duration:  30,
+				Status:    domain.StatusCancelled,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
+					ID: 1, Date: pastDate, ServiceID: 1, Duration: 30, Status: domain.StatusCancelled,
+				}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Лечение"}, nil)
+			},
+			wantErr: true,
+			errMsg:  "у записи в статусе cancelled нельзя менять",
+		},
+		{
+			name: "illegal status transition",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 1,
+				Status:    domain.StatusScheduled,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusCompleted}, nil)
+			},
+			wantErr: true,
+			errMsg:  "cannot change appointment status from completed to scheduled",
+		},
+		{
+			name: "invalid status",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 1,
+				Status:    "done",
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			},
+			wantErr: true,
+			errMsg:  "invalid appointment status",
+		},
+		{
+			name: "appointment not found",
+			appointment: &domain.Appointment{
+				ID:        7,
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 1,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 7).Return(nil, domain.ErrAppointmentNotFound)
+			},
+			wantErr: true,
+			errMsg:  "appointment not found",
+		},
 		{
 			name: "patient not found on update",
 			appointment: &domain.Appointment{
 				ID:        1,
 				PatientID: 999,
 				Date:      futureDate,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(999).Return(nil, errors.New("patient not found"))
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("patient not found"))
 			},
 			wantErr: true,
 			errMsg:  "patient not found",
 		},
+		{
+			name: "service not found on update",
+			appointment: &domain.Appointment{
+				ID:        1,
+				PatientID: 1,
+				Date:      futureDate,
+				ServiceID: 99,
+			},
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
 		{
 			name: "check time conflict error",
 			appointment: &domain.Appointment{
@@ -372,11 +845,13 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "10:00",
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
-				a.EXPECT().CheckTimeConflict(futureDate, "10:00", 1).Return(false, errors.New("database error"))
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -388,12 +863,14 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 				PatientID: 1,
 				Date:      futureDate,
 				Time:      "10:00",
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
-				a.EXPECT().CheckTimeConflict(futureDate, "10:00", 1).Return(false, nil)
-				a.EXPECT().Update(gomock.Any()).Return(errors.New("update failed"))
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("update failed"))
 			},
 			wantErr: true,
 			errMsg:  "update failed",
@@ -408,10 +885,12 @@ func TestAppointmentUseCase_UpdateAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			err := uc.UpdateAppointment(tt.appointment)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			err := uc.UpdateAppointment(context.Background(), tt.appointment)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -436,7 +915,7 @@ func TestAppointmentUseCase_DeleteAppointment(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().Delete(1).Return(nil)
+				m.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -458,7 +937,7 @@ func TestAppointmentUseCase_DeleteAppointment(t *testing.T) {
 			name: "not found",
 			id:   999,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().Delete(999).Return(errors.New("appointment not found"))
+				m.EXPECT().Delete(gomock.Any(), 999).Return(errors.New("appointment not found"))
 			},
 			wantErr: true,
 			errMsg:  "appointment not found",
@@ -473,10 +952,12 @@ func TestAppointmentUseCase_DeleteAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			err := uc.DeleteAppointment(tt.id)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			err := uc.DeleteAppointment(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -501,7 +982,7 @@ func TestAppointmentUseCase_GetAppointmentsByPatient(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByPatientID(1).Return([]*domain.Appointment{
+				m.EXPECT().GetByPatientID(gomock.Any(), 1).Return([]*domain.Appointment{
 					{ID: 1, PatientID: 1},
 					{ID: 2, PatientID: 1},
 				}, nil)
@@ -520,7 +1001,7 @@ func TestAppointmentUseCase_GetAppointmentsByPatient(t *testing.T) {
 			name: "empty result",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByPatientID(1).Return([]*domain.Appointment{}, nil)
+				m.EXPECT().GetByPatientID(gomock.Any(), 1).Return([]*domain.Appointment{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -535,10 +1016,12 @@ func TestAppointmentUseCase_GetAppointmentsByPatient(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			appointments, err := uc.GetAppointmentsByPatient(tt.id)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			appointments, err := uc.GetAppointmentsByPatient(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -565,7 +1048,7 @@ func TestAppointmentUseCase_GetAppointmentsByDate(t *testing.T) {
 			name: "success with appointments",
 			date: testDate,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByDate(testDate).Return([]*domain.Appointment{
+				m.EXPECT().GetByDate(gomock.Any(), testDate).Return([]*domain.Appointment{
 					{ID: 1, Date: testDate},
 					{ID: 2, Date: testDate},
 				}, nil)
@@ -577,7 +1060,7 @@ func TestAppointmentUseCase_GetAppointmentsByDate(t *testing.T) {
 			name: "empty result",
 			date: testDate,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByDate(testDate).Return([]*domain.Appointment{}, nil)
+				m.EXPECT().GetByDate(gomock.Any(), testDate).Return([]*domain.Appointment{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -586,7 +1069,7 @@ func TestAppointmentUseCase_GetAppointmentsByDate(t *testing.T) {
 			name: "repository error",
 			date: testDate,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByDate(testDate).Return(nil, errors.New("database error"))
+				m.EXPECT().GetByDate(gomock.Any(), testDate).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -600,10 +1083,12 @@ func TestAppointmentUseCase_GetAppointmentsByDate(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			appointments, err := uc.GetAppointmentsByDate(tt.date)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			appointments, err := uc.GetAppointmentsByDate(context.Background(), tt.date)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -627,22 +1112,39 @@ func TestAppointmentUseCase_CompleteAppointment(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Appointment{
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
 					ID:     1,
-					Status: domain.StatusScheduled,
+					Status: domain.StatusInProgress,
 				}, nil)
-				m.EXPECT().Update(gomock.Any()).DoAndReturn(func(apt *domain.Appointment) error {
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
 					assert.Equal(t, domain.StatusCompleted, apt.Status)
+					assert.NotNil(t, apt.CompletedAt)
 					return nil
 				})
 			},
 			wantErr: false,
 		},
+		{
+			name: "appointment not started",
+			id:   1,
+			setup: func(m *repository.MockAppointmentRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
+			},
+			wantErr: true,
+			errMsg:  "cannot change appointment status from scheduled to completed",
+		},
+		{
+			name:    "invalid ID",
+			id:      0,
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "invalid appointment ID",
+		},
 		{
 			name: "appointment not found",
 			id:   999,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("not found"))
 			},
 			wantErr: true,
 			errMsg:  "not found",
@@ -651,8 +1153,8 @@ func TestAppointmentUseCase_CompleteAppointment(t *testing.T) {
 			name: "update error",
 			id:   1,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Appointment{ID: 1, Status: domain.StatusScheduled}, nil)
-				m.EXPECT().Update(gomock.Any()).Return(errors.New("update failed"))
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusArrived}, nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("update failed"))
 			},
 			wantErr: true,
 			errMsg:  "update failed",
@@ -667,16 +1169,19 @@ func TestAppointmentUseCase_CompleteAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			err := uc.CompleteAppointment(tt.id)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			appointment, err := uc.CompleteAppointment(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, domain.StatusCompleted, appointment.Status)
 			}
 		})
 	}
@@ -686,30 +1191,51 @@ func TestAppointmentUseCase_CancelAppointment(t *testing.T) {
 	tests := []struct {
 		name    string
 		id      int
+		reason  string
 		setup   func(*repository.MockAppointmentRepository)
 		wantErr bool
 		errMsg  string
 	}{
 		{
-			name: "success",
-			id:   1,
+			name:   "success",
+			id:     1,
+			reason: "  пациент заболел ",
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Appointment{
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
 					ID:     1,
 					Status: domain.StatusScheduled,
 				}, nil)
-				m.EXPECT().Update(gomock.Any()).DoAndReturn(func(apt *domain.Appointment) error {
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
 					assert.Equal(t, domain.StatusCancelled, apt.Status)
+					assert.Equal(t, "пациент заболел", apt.CancellationReason)
+					assert.NotNil(t, apt.CancelledAt)
 					return nil
 				})
 			},
 			wantErr: false,
 		},
+		{
+			name: "already completed",
+			id:   1,
+			setup: func(m *repository.MockAppointmentRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusCompleted}, nil)
+			},
+			wantErr: true,
+			errMsg:  "cannot change appointment status from completed to cancelled",
+		},
+		{
+			name:    "reason too long",
+			id:      1,
+			reason:  strings.Repeat("а", 501),
+			setup:   func(m *repository.MockAppointmentRepository) {},
+			wantErr: true,
+			errMsg:  "cancellation reason is too long",
+		},
 		{
 			name: "appointment not found",
 			id:   999,
 			setup: func(m *repository.MockAppointmentRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("not found"))
 			},
 			wantErr: true,
 			errMsg:  "not found",
@@ -724,16 +1250,186 @@ func TestAppointmentUseCase_CancelAppointment(t *testing.T) {
 			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
-			err := uc.CancelAppointment(tt.id)
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			_, err := uc.CancelAppointment(context.Background(), tt.id, tt.reason)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAppointmentUseCase_StatusActions(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   domain.AppointmentStatus
+		action func(*AppointmentUseCase) (*domain.Appointment, error)
+		want   domain.AppointmentStatus
+		at     func(*domain.Appointment) *time.Time
+	}{
+		{
+			name: "confirm",
+			from: domain.StatusScheduled,
+			action: func(uc *AppointmentUseCase) (*domain.Appointment, error) {
+				return uc.ConfirmAppointment(context.Background(), 1)
+			},
+			want: domain.StatusConfirmed,
+			at:   func(a *domain.Appointment) *time.Time { return a.ConfirmedAt },
+		},
+		{
+			name: "arrive",
+			from: domain.StatusConfirmed,
+			action: func(uc *AppointmentUseCase) (*domain.Appointment, error) {
+				return uc.MarkAppointmentArrived(context.Background(), 1)
+			},
+			want: domain.StatusArrived,
+			at:   func(a *domain.Appointment) *time.Time { return a.ArrivedAt },
+		},
+		{
+			name: "start",
+			from: domain.StatusArrived,
+			action: func(uc *AppointmentUseCase) (*domain.Appointment, error) {
+				return uc.StartAppointment(context.Background(), 1)
+			},
+			want: domain.StatusInProgress,
+			at:   func(a *domain.Appointment) *time.Time { return a.StartedAt },
+		},
+		{
+			name: "no show",
+			from: domain.StatusConfirmed,
+			action: func(uc *AppointmentUseCase) (*domain.Appointment, error) {
+				return uc.MarkAppointmentNoShow(context.Background(), 1)
+			},
+			want: domain.StatusNoShow,
+			at:   func(a *domain.Appointment) *time.Time { return a.NoShowAt },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+			mockAppointmentRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: tt.from}, nil)
+			mockAppointmentRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+			uc := NewAppointmentUseCase(mockAppointmentRepo, repository.NewMockPatientRepository(ctrl), repository.NewMockServiceRepository(ctrl),
+				repository.NewMockDoctorRepository(ctrl), repository.NewMockScheduleRepository(ctrl), newTestUnitOfWork(ctrl), time.UTC)
+			appointment, err := tt.action(uc)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, appointment.Status)
+			assert.NotNil(t, tt.at(appointment))
+		})
+	}
+}
+
+func TestAppointmentUseCase_RescheduleAppointment(t *testing.T) {
+	newDate := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+
+	tests := []struct {
+		name    string
+		id      int
+		date    time.Time
+		setup   func(*repository.MockAppointmentRepository, *repository.MockPatientRepository, *repository.MockServiceRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "success",
+			id:   1,
+			date: newDate,
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{
+					ID: 1, PatientID: 2, ServiceID: 3, Status: domain.StatusConfirmed,
+					Date: newDate.Add(-24 * time.Hour), Price: domain.Tenge(5000), Duration: 45, Notes: "повторный прием",
+				}, nil)
+				gomock.InOrder(
+					a.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+						assert.Equal(t, domain.StatusRescheduled, apt.Status)
+						assert.NotNil(t, apt.RescheduledAt)
+						return nil
+					}),
+					a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apt *domain.Appointment) error {
+						assert.Equal(t, 1, apt.RescheduledFromID)
+						assert.Equal(t, domain.StatusScheduled, apt.Status)
+						assert.Equal(t, newDate, apt.Date)
+						assert.Equal(t, domain.Tenge(5000), apt.Price)
+						assert.Equal(t, 45, apt.Duration)
+						assert.Equal(t, "повторный прием", apt.Notes)
+						apt.ID = 5
+						return nil
+					}),
+				)
+				p.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Patient{ID: 2, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 3).Return(&domain.Service{ID: 3, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "completed appointment",
+			id:   1,
+			date: newDate,
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, Status: domain.StatusCompleted}, nil)
+			},
+			wantErr: true,
+			errMsg:  "cannot change appointment status from completed to rescheduled",
+		},
+		{
+			name: "new time is occupied",
+			id:   1,
+			date: newDate,
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+				a.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Appointment{ID: 1, PatientID: 2, ServiceID: 3, Status: domain.StatusScheduled, Price: domain.Tenge(5000)}, nil)
+				a.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				p.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Patient{ID: 2, Name: "John"}, nil)
+				s.EXPECT().GetByID(gomock.Any(), 3).Return(&domain.Service{ID: 3, Name: "Консультация"}, nil)
+				a.EXPECT().FindConflicts(gomock.Any(), gomock.Any()).Return([]*domain.Appointment{{ID: 4}}, nil)
+			},
+			wantErr: true,
+			errMsg:  "time slot is already occupied",
+		},
+		{
+			name: "missing date",
+			id:   1,
+			setup: func(a *repository.MockAppointmentRepository, p *repository.MockPatientRepository, s *repository.MockServiceRepository) {
+			},
+			wantErr: true,
+			errMsg:  "date is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
+			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			tt.setup(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
+
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, repository.NewMockDoctorRepository(ctrl),
+				repository.NewMockScheduleRepository(ctrl), newTestUnitOfWork(ctrl), time.UTC)
+			appointment, err := uc.RescheduleAppointment(context.Background(), tt.id, tt.date)
 
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, 5, appointment.ID)
 			}
 		})
 	}
@@ -746,7 +1442,9 @@ func TestAppointmentUseCase_ValidateAppointment(t *testing.T) {
 	mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 	mockPatientRepo := repository.NewMockPatientRepository(ctrl)
 	mockServiceRepo := repository.NewMockServiceRepository(ctrl)
-	uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo)
+	mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+	mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+	uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
 
 	futureDate := time.Now().Add(24 * time.Hour)
 
@@ -767,15 +1465,15 @@ func TestAppointmentUseCase_ValidateAppointment(t *testing.T) {
 			appointment: &domain.Appointment{
 				PatientID: 1,
 				Date:      futureDate,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			wantErr: false,
 		},
 		{
 			name: "missing patient id",
 			appointment: &domain.Appointment{
-				Date:    futureDate,
-				Service: "Консультация",
+				Date:      futureDate,
+				ServiceID: 1,
 			},
 			wantErr: true,
 			errMsg:  "patient ID is required",
@@ -784,7 +1482,7 @@ func TestAppointmentUseCase_ValidateAppointment(t *testing.T) {
 			name: "missing date",
 			appointment: &domain.Appointment{
 				PatientID: 1,
-				Service:   "Консультация",
+				ServiceID: 1,
 			},
 			wantErr: true,
 			errMsg:  "date is required",
@@ -802,13 +1500,297 @@ func TestAppointmentUseCase_ValidateAppointment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := uc.ValidateAppointment(tt.appointment)
+			err := uc.ValidateAppointment(context.Background(), tt.appointment)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAppointmentUseCase_ValidateAppointmentClinicTimezone(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+
+	clinicDay := time.Date(2030, 3, 5, 0, 0, 0, 0, almaty)
+
+	tests := []struct {
+		name    string
+		time    string
+		wantErr bool
+	}{
+		{
+			name: "morning of the next clinic day",
+			time: "10:00",
+		},
+		{
+			name:    "after clinic working hours",
+			time:    "19:00",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			mockScheduleRepo.EXPECT().GetWorkingHours(gomock.Any(), 1).Return(nil, nil)
+			mockScheduleRepo.EXPECT().GetBreaks(gomock.Any(), 1).Return(nil, nil)
+			mockScheduleRepo.EXPECT().GetExceptions(gomock.Any(), 1, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int, from, to time.Time) ([]*domain.ScheduleException, error) {
+					assert.True(t, from.Equal(clinicDay))
+					assert.True(t, to.Equal(clinicDay.AddDate(0, 0, 1)))
+					return nil, nil
+				})
+			mockScheduleRepo.EXPECT().GetHolidays(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			uc := NewAppointmentUseCase(
+				repository.NewMockAppointmentRepository(ctrl),
+				repository.NewMockPatientRepository(ctrl),
+				repository.NewMockServiceRepository(ctrl),
+				repository.NewMockDoctorRepository(ctrl),
+				mockScheduleRepo,
+				newTestUnitOfWork(ctrl),
+				almaty,
+			)
+
+			appointment := &domain.Appointment{
+				PatientID: 1,
+				ServiceID: 1,
+				DoctorID:  1,
+				Date:      time.Date(2030, 3, 4, 20, 0, 0, 0, time.UTC),
+				Time:      tt.time,
+			}
+			err := uc.ValidateAppointment(context.Background(), appointment)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "doctor is not available")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, almaty, appointment.Date.Location())
+		})
+	}
+}
+
+func TestAppointmentUseCase_FindFreeSlots(t *testing.T) {
+	day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	doctor := &domain.Doctor{ID: 2, Name: "Dr. Smith"}
+	defaultSchedule := func(sc *repository.MockScheduleRepository, end time.Time) {
+		sc.EXPECT().GetWorkingHours(gomock.Any(), 2).Return(nil, nil)
+		sc.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+		sc.EXPECT().GetExceptions(gomock.Any(), 2, day, end).Return(nil, nil)
+		sc.EXPECT().GetHolidays(gomock.Any(), day, end).Return(nil, nil)
+	}
+
+	tests := []struct {
+		name      string
+		query     *domain.SlotQuery
+		setup     func(*repository.MockAppointmentRepository, *repository.MockServiceRepository, *repository.MockDoctorRepository, *repository.MockScheduleRepository)
+		wantStart []time.Time
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:  "free day starts at opening time",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, Limit: 3},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return(nil, nil)
+				defaultSchedule(sc, day.AddDate(0, 0, 1))
+			},
+			wantStart: []time.Time{at(9, 0), at(9, 30), at(10, 0)},
+		},
+		{
+			name:  "working hours and breaks from weekly schedule",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, Duration: 60, Limit: 4},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return(nil, nil)
+				sc.EXPECT().GetWorkingHours(gomock.Any(), 2).Return([]*domain.WorkingHours{
+					{DoctorID: 2, Weekday: time.Monday, StartTime: "10:00", EndTime: "14:30"},
+					{DoctorID: 2, Weekday: time.Tuesday, StartTime: "08:00", EndTime: "12:00"},
+				}, nil)
+				sc.EXPECT().GetBreaks(gomock.Any(), 2).Return([]*domain.ScheduleBreak{
+					{DoctorID: 2, Weekday: time.Monday, StartTime: "12:00", EndTime: "13:00"},
+				}, nil)
+				sc.EXPECT().GetExceptions(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return(nil, nil)
+				sc.EXPECT().GetHolidays(gomock.Any(), day, day.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			wantStart: []time.Time{at(10, 0), at(11, 0), at(13, 0)},
+		},
+		{
+			name:  "vacation and holiday days are skipped",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, To: day.AddDate(0, 0, 2), Limit: 1},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 3)).Return(nil, nil)
+				sc.EXPECT().GetWorkingHours(gomock.Any(), 2).Return(nil, nil)
+				sc.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+				sc.EXPECT().GetExceptions(gomock.Any(), 2, day, day.AddDate(0, 0, 3)).Return([]*domain.ScheduleException{
+					{DoctorID: 2, Type: domain.ExceptionVacation, DateFrom: day, DateTo: day},
+				}, nil)
+				sc.EXPECT().GetHolidays(gomock.Any(), day, day.AddDate(0, 0, 3)).Return([]*domain.ClinicHoliday{
+					{Date: day.AddDate(0, 0, 1), Name: "Наурыз"},
+				}, nil)
+			},
+			wantStart: []time.Time{day.AddDate(0, 0, 2).Add(9 * time.Hour)},
+		},
+		{
+			name:  "custom hours replace weekly schedule",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, Duration: 60, Limit: 5},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return(nil, nil)
+				sc.EXPECT().GetWorkingHours(gomock.Any(), 2).Return([]*domain.WorkingHours{
+					{DoctorID: 2, Weekday: time.Monday, StartTime: "09:00", EndTime: "18:00"},
+				}, nil)
+				sc.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+				sc.EXPECT().GetExceptions(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return([]*domain.ScheduleException{
+					{DoctorID: 2, Type: domain.ExceptionCustomHours, DateFrom: day, DateTo: day, StartTime: "15:00", EndTime: "17:00"},
+				}, nil)
+				sc.EXPECT().GetHolidays(gomock.Any(), day, day.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			wantStart: []time.Time{at(15, 0), at(16, 0)},
+		},
+		{
+			name:  "busy time and buffer are skipped",
+			query: &domain.SlotQuery{DoctorID: 2, ServiceID: 1, From: day, Duration: 60, Buffer: 10, Limit: 2},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1}, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day.Add(-10*time.Minute), day.AddDate(0, 0, 1).Add(10*time.Minute)).Return([]*domain.Appointment{
+					{ID: 1, DoctorID: 2, Doctor: "Dr. Smith", Date: at(9, 30), Duration: 30, Status: domain.StatusScheduled},
+					{ID: 2, DoctorID: 3, Doctor: "Dr. Jones", Date: at(10, 10), Duration: 60, Status: domain.StatusScheduled},
+					{ID: 3, DoctorID: 2, Doctor: "Dr. Smith", Date: at(11, 20), Duration: 60, Status: domain.StatusCancelled},
+				}, nil)
+				defaultSchedule(sc, day.AddDate(0, 0, 1))
+			},
+			wantStart: []time.Time{at(10, 10), at(11, 20)},
+		},
+		{
+			name:  "fully booked day moves to next day",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, To: day.AddDate(0, 0, 1), Limit: 1},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 2)).Return([]*domain.Appointment{
+					{ID: 1, DoctorID: 2, Doctor: "Dr. Smith", Date: at(9, 0), Duration: 540, Status: domain.StatusScheduled},
+				}, nil)
+				defaultSchedule(sc, day.AddDate(0, 0, 2))
+			},
+			wantStart: []time.Time{day.AddDate(0, 0, 1).Add(9 * time.Hour)},
+		},
+		{
+			name:  "appointment started before search window",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, Limit: 1},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, day, day.AddDate(0, 0, 1)).Return([]*domain.Appointment{
+					{ID: 1, DoctorID: 2, Doctor: "Dr. Smith", Date: day.Add(-time.Hour), Duration: 660, Status: domain.StatusInProgress},
+				}, nil)
+				defaultSchedule(sc, day.AddDate(0, 0, 1))
+			},
+			wantStart: []time.Time{at(10, 0)},
+		},
+		{
+			name:  "missing doctor id",
+			query: &domain.SlotQuery{From: day},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+			},
+			wantErr: true,
+			errMsg:  "doctor ID is required",
+		},
+		{
+			name:  "date to before date from",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, To: day.AddDate(0, 0, -1)},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+			},
+			wantErr: true,
+			errMsg:  "date to must not be before date from",
+		},
+		{
+			name:  "date range too long",
+			query: &domain.SlotQuery{DoctorID: 2, From: day, To: day.AddDate(0, 2, 0)},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+			},
+			wantErr: true,
+			errMsg:  "date range is too long",
+		},
+		{
+			name:  "doctor not found",
+			query: &domain.SlotQuery{DoctorID: 99, From: day},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 99).Return(nil, nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor not found",
+		},
+		{
+			name:  "service not found",
+			query: &domain.SlotQuery{DoctorID: 2, ServiceID: 99, From: day},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				s.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
+		{
+			name:  "service lookup error",
+			query: &domain.SlotQuery{DoctorID: 2, ServiceID: 1, From: day},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(nil, context.Canceled)
+			},
+			wantErr: true,
+			errMsg:  "context canceled",
+		},
+		{
+			name:  "repository error",
+			query: &domain.SlotQuery{DoctorID: 2, From: day},
+			setup: func(a *repository.MockAppointmentRepository, s *repository.MockServiceRepository, d *repository.MockDoctorRepository, sc *repository.MockScheduleRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(doctor, nil)
+				a.EXPECT().FindOverlapping(gomock.Any(), 2, gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+			mockPatientRepo := repository.NewMockPatientRepository(ctrl)
+			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			tt.setup(mockAppointmentRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo)
+
+			uc := NewAppointmentUseCase(mockAppointmentRepo, mockPatientRepo, mockServiceRepo, mockDoctorRepo, mockScheduleRepo, newTestUnitOfWork(ctrl), time.UTC)
+			slots, err := uc.FindFreeSlots(context.Background(), tt.query)
 
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
 			} else {
 				require.NoError(t, err)
+				require.Len(t, slots, len(tt.wantStart))
+				for i, start := range tt.wantStart {
+					assert.Equal(t, start, slots[i].Start)
+				}
 			}
 		})
 	}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Ограничения выборки журнала аудита
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditUseCase struct {
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditUseCase(auditRepo domain.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditLog получает страницу журнала аудита по фильтру, начиная с последних записей, и общее число записей
+func (u *AuditUseCase) GetAuditLog(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, int, error) {
+	if filter.Entity != "" && !filter.Entity.Valid() {
+		return nil, 0, domain.NewValidationError("entity", domain.FieldInvalid, "invalid audit entity")
+	}
+
+	if filter.Action != "" && !filter.Action.Valid() {
+		return nil, 0, domain.NewValidationError("action", domain.FieldInvalid, "invalid audit action")
+	}
+
+	if filter.EntityID < 0 {
+		return nil, 0, domain.NewValidationError("entity_id", domain.FieldInvalid, "invalid entity ID")
+	}
+
+	if filter.ActorID < 0 {
+		return nil, 0, domain.NewValidationError("actor_id", domain.FieldInvalid, "invalid actor ID")
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, domain.NewValidationError("date_to", domain.FieldRange, "date_from must be before date_to")
+	}
+
+	if err := normalizePage(&filter.Page, defaultAuditLimit, maxAuditLimit); err != nil {
+		return nil, 0, err
+	}
+
+	return u.auditRepo.List(ctx, filter)
+}
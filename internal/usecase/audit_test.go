@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditUseCase_GetAuditLog(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  domain.AuditFilter
+		setup   func(*repository.MockAuditRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:   "default limit",
+			filter: domain.AuditFilter{Entity: domain.AuditEntityPatient, EntityID: 1},
+			setup: func(m *repository.MockAuditRepository) {
+				m.EXPECT().List(gomock.Any(), domain.AuditFilter{Entity: domain.AuditEntityPatient, EntityID: 1, Page: domain.Page{Limit: defaultAuditLimit}}).
+					Return([]*domain.AuditEntry{{ID: 1}}, 1, nil)
+			},
+		},
+		{
+			name:   "limit is capped",
+			filter: domain.AuditFilter{Page: domain.Page{Limit: 100000}},
+			setup: func(m *repository.MockAuditRepository) {
+				m.EXPECT().List(gomock.Any(), domain.AuditFilter{Page: domain.Page{Limit: maxAuditLimit}}).Return([]*domain.AuditEntry{}, 0, nil)
+			},
+		},
+		{
+			name:   "date range",
+			filter: domain.AuditFilter{From: &from, To: &to, Action: domain.AuditActionUpdate, Page: domain.Page{Limit: 5, Offset: 10}},
+			setup: func(m *repository.MockAuditRepository) {
+				m.EXPECT().List(gomock.Any(), domain.AuditFilter{From: &from, To: &to, Action: domain.AuditActionUpdate, Page: domain.Page{Limit: 5, Offset: 10}}).
+					Return([]*domain.AuditEntry{}, 12, nil)
+			},
+		},
+		{
+			name:    "unknown entity",
+			filter:  domain.AuditFilter{Entity: "doctor"},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "invalid audit entity",
+		},
+		{
+			name:    "unknown action",
+			filter:  domain.AuditFilter{Action: "archive"},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "invalid audit action",
+		},
+		{
+			name:    "negative entity ID",
+			filter:  domain.AuditFilter{EntityID: -1},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "invalid entity ID",
+		},
+		{
+			name:    "negative actor ID",
+			filter:  domain.AuditFilter{ActorID: -1},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "invalid actor ID",
+		},
+		{
+			name:    "reversed date range",
+			filter:  domain.AuditFilter{From: &to, To: &from},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "date_from must be before date_to",
+		},
+		{
+			name:    "negative limit",
+			filter:  domain.AuditFilter{Page: domain.Page{Limit: -1}},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "limit must not be negative",
+		},
+		{
+			name:    "negative offset",
+			filter:  domain.AuditFilter{Page: domain.Page{Offset: -1}},
+			setup:   func(m *repository.MockAuditRepository) {},
+			wantErr: true,
+			errMsg:  "offset must not be negative",
+		},
+		{
+			name:   "repository error",
+			filter: domain.AuditFilter{},
+			setup: func(m *repository.MockAuditRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockAuditRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewAuditUseCase(mockRepo)
+
+			entries, _, err := uc.GetAuditLog(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, entries)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, entries)
+		})
+	}
+}
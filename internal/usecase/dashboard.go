@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,42 +12,47 @@ type DashboardUseCase struct {
 	patientRepo     domain.PatientRepository
 	appointmentRepo domain.AppointmentRepository
 	serviceRepo     domain.ServiceRepository
+	location        *time.Location
+	now             func() time.Time
 }
 
 func NewDashboardUseCase(
 	patientRepo domain.PatientRepository,
 	appointmentRepo domain.AppointmentRepository,
 	serviceRepo domain.ServiceRepository,
+	location *time.Location,
 ) *DashboardUseCase {
 	return &DashboardUseCase{
 		patientRepo:     patientRepo,
 		appointmentRepo: appointmentRepo,
 		serviceRepo:     serviceRepo,
+		location:        location,
+		now:             time.Now,
 	}
 }
 
 // GetDashboardStats получает статистику дашборда
-func (u *DashboardUseCase) GetDashboardStats() (*domain.DashboardStats, error) {
+func (u *DashboardUseCase) GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error) {
 	// Получаем всех пациентов
-	patients, err := u.patientRepo.GetAll()
+	patients, err := u.patientRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Получаем все записи
-	appointments, err := u.appointmentRepo.GetAll()
+	appointments, err := u.appointmentRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Подсчитываем статистику
-	today := time.Now().Truncate(24 * time.Hour)
+	// Подсчитываем статистику; сутки считаются по часовому поясу клиники
+	today := startOfDay(u.now().In(u.location))
 	totalPatients := len(patients)
 	todayAppointments := 0
-	todayRevenue := 0.0
+	var todayRevenue domain.Money
 
 	for _, appointment := range appointments {
-		appointmentDate := appointment.Date.Truncate(24 * time.Hour)
+		appointmentDate := startOfDay(appointment.Date.In(u.location))
 		if appointmentDate.Equal(today) {
 			todayAppointments++
 			// Доход за сегодня (только завершенные записи)
@@ -60,32 +66,34 @@ func (u *DashboardUseCase) GetDashboardStats() (*domain.DashboardStats, error) {
 		TodayAppointments: todayAppointments,
 		TodayRevenue:      todayRevenue,
 		TotalPatients:     totalPatients,
+		Currency:          domain.ClinicCurrency,
 	}, nil
 }
 
 // GetFinanceReport получает финансовый отчет
-func (u *DashboardUseCase) GetFinanceReport() (*domain.FinanceReport, error) {
-	appointments, err := u.appointmentRepo.GetAll()
+func (u *DashboardUseCase) GetFinanceReport(ctx context.Context) (*domain.FinanceReport, error) {
+	appointments, err := u.appointmentRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Группируем доходы по дням и неделям
-	dayIncome := make(map[string]float64)
-	weekIncome := make(map[string]float64)
-	totalIncome := 0.0
+	dayIncome := make(map[string]domain.Money)
+	weekIncome := make(map[string]domain.Money)
+	var totalIncome domain.Money
 
 	for _, appointment := range appointments {
 		if appointment.Status == domain.StatusCompleted {
 			income := appointment.Price
 			totalIncome += income
+			date := appointment.Date.In(u.location)
 
 			// Доход по дням
-			dateStr := appointment.Date.Format("2006-01-02")
+			dateStr := date.Format("2006-01-02")
 			dayIncome[dateStr] += income
 
 			// Доход по неделям
-			year, week := appointment.Date.ISOWeek()
+			year, week := date.ISOWeek()
 			weekKey := formatWeekKey(year, week)
 			weekIncome[weekKey] += income
 		}
@@ -113,6 +121,7 @@ func (u *DashboardUseCase) GetFinanceReport() (*domain.FinanceReport, error) {
 		TotalIncome: totalIncome,
 		ByDay:       byDay,
 		ByWeek:      byWeek,
+		Currency:    domain.ClinicCurrency,
 	}, nil
 }
 
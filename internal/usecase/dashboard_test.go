@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -20,38 +21,38 @@ func TestDashboardUseCase_GetDashboardStats(t *testing.T) {
 		name                  string
 		setup                 func(*repository.MockPatientRepository, *repository.MockAppointmentRepository, *repository.MockServiceRepository)
 		wantTodayAppointments int
-		wantTodayRevenue      float64
+		wantTodayRevenue      domain.Money
 		wantTotalPatients     int
 		wantErr               bool
 	}{
 		{
 			name: "success with data",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return([]*domain.Patient{
+				p.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{
 					{ID: 1, Name: "John"},
 					{ID: 2, Name: "Jane"},
 					{ID: 3, Name: "Bob"},
 				}, nil)
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: today, Status: domain.StatusScheduled, Price: 1000},
-					{ID: 2, Date: today, Status: domain.StatusCompleted, Price: 2000},
-					{ID: 3, Date: today, Status: domain.StatusCompleted, Price: 3000},
-					{ID: 4, Date: yesterday, Status: domain.StatusCompleted, Price: 5000},
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: today, Status: domain.StatusScheduled, Price: domain.Tenge(1000)},
+					{ID: 2, Date: today, Status: domain.StatusCompleted, Price: domain.Tenge(2000)},
+					{ID: 3, Date: today, Status: domain.StatusCompleted, Price: domain.Tenge(3000)},
+					{ID: 4, Date: yesterday, Status: domain.StatusCompleted, Price: domain.Tenge(5000)},
 				}, nil)
 			},
 			wantTodayAppointments: 3,
-			wantTodayRevenue:      5000,
+			wantTodayRevenue:      domain.Tenge(5000),
 			wantTotalPatients:     3,
 			wantErr:               false,
 		},
 		{
 			name: "success with no today appointments",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return([]*domain.Patient{
+				p.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{
 					{ID: 1, Name: "John"},
 				}, nil)
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: yesterday, Status: domain.StatusCompleted, Price: 5000},
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: yesterday, Status: domain.StatusCompleted, Price: domain.Tenge(5000)},
 				}, nil)
 			},
 			wantTodayAppointments: 0,
@@ -62,8 +63,8 @@ func TestDashboardUseCase_GetDashboardStats(t *testing.T) {
 		{
 			name: "success empty data",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return([]*domain.Patient{}, nil)
-				a.EXPECT().GetAll().Return([]*domain.Appointment{}, nil)
+				p.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{}, nil)
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{}, nil)
 			},
 			wantTodayAppointments: 0,
 			wantTodayRevenue:      0,
@@ -73,10 +74,10 @@ func TestDashboardUseCase_GetDashboardStats(t *testing.T) {
 		{
 			name: "only scheduled appointments today - no revenue",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return([]*domain.Patient{{ID: 1}}, nil)
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: today, Status: domain.StatusScheduled, Price: 1000},
-					{ID: 2, Date: today, Status: domain.StatusCancelled, Price: 2000},
+				p.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{{ID: 1}}, nil)
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: today, Status: domain.StatusScheduled, Price: domain.Tenge(1000)},
+					{ID: 2, Date: today, Status: domain.StatusCancelled, Price: domain.Tenge(2000)},
 				}, nil)
 			},
 			wantTodayAppointments: 2,
@@ -87,15 +88,15 @@ func TestDashboardUseCase_GetDashboardStats(t *testing.T) {
 		{
 			name: "patient repository error",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				p.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
 		{
 			name: "appointment repository error",
 			setup: func(p *repository.MockPatientRepository, a *repository.MockAppointmentRepository, s *repository.MockServiceRepository) {
-				p.EXPECT().GetAll().Return([]*domain.Patient{}, nil)
-				a.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				p.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{}, nil)
+				a.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -111,8 +112,8 @@ func TestDashboardUseCase_GetDashboardStats(t *testing.T) {
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
 			tt.setup(mockPatientRepo, mockAppointmentRepo, mockServiceRepo)
 
-			uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo)
-			stats, err := uc.GetDashboardStats()
+			uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo, time.UTC)
+			stats, err := uc.GetDashboardStats(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -136,7 +137,7 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 	tests := []struct {
 		name            string
 		setup           func(*repository.MockAppointmentRepository)
-		wantTotalIncome float64
+		wantTotalIncome domain.Money
 		wantDayCount    int
 		wantWeekCount   int
 		wantErr         bool
@@ -144,14 +145,14 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 		{
 			name: "success with completed appointments",
 			setup: func(a *repository.MockAppointmentRepository) {
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: date1, Status: domain.StatusCompleted, Price: 1000},
-					{ID: 2, Date: date1, Status: domain.StatusCompleted, Price: 2000},
-					{ID: 3, Date: date2, Status: domain.StatusCompleted, Price: 3000},
-					{ID: 4, Date: date3, Status: domain.StatusCompleted, Price: 4000},
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: date1, Status: domain.StatusCompleted, Price: domain.Tenge(1000)},
+					{ID: 2, Date: date1, Status: domain.StatusCompleted, Price: domain.Tenge(2000)},
+					{ID: 3, Date: date2, Status: domain.StatusCompleted, Price: domain.Tenge(3000)},
+					{ID: 4, Date: date3, Status: domain.StatusCompleted, Price: domain.Tenge(4000)},
 				}, nil)
 			},
-			wantTotalIncome: 10000,
+			wantTotalIncome: domain.Tenge(10000),
 			wantDayCount:    3,
 			wantWeekCount:   2,
 			wantErr:         false,
@@ -159,13 +160,13 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 		{
 			name: "only completed appointments counted",
 			setup: func(a *repository.MockAppointmentRepository) {
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: date1, Status: domain.StatusCompleted, Price: 1000},
-					{ID: 2, Date: date1, Status: domain.StatusScheduled, Price: 2000},
-					{ID: 3, Date: date2, Status: domain.StatusCancelled, Price: 3000},
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: date1, Status: domain.StatusCompleted, Price: domain.Tenge(1000)},
+					{ID: 2, Date: date1, Status: domain.StatusScheduled, Price: domain.Tenge(2000)},
+					{ID: 3, Date: date2, Status: domain.StatusCancelled, Price: domain.Tenge(3000)},
 				}, nil)
 			},
-			wantTotalIncome: 1000,
+			wantTotalIncome: domain.Tenge(1000),
 			wantDayCount:    1,
 			wantWeekCount:   1,
 			wantErr:         false,
@@ -173,7 +174,7 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 		{
 			name: "empty appointments",
 			setup: func(a *repository.MockAppointmentRepository) {
-				a.EXPECT().GetAll().Return([]*domain.Appointment{}, nil)
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{}, nil)
 			},
 			wantTotalIncome: 0,
 			wantDayCount:    0,
@@ -183,9 +184,9 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 		{
 			name: "all appointments not completed",
 			setup: func(a *repository.MockAppointmentRepository) {
-				a.EXPECT().GetAll().Return([]*domain.Appointment{
-					{ID: 1, Date: date1, Status: domain.StatusScheduled, Price: 1000},
-					{ID: 2, Date: date2, Status: domain.StatusCancelled, Price: 2000},
+				a.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+					{ID: 1, Date: date1, Status: domain.StatusScheduled, Price: domain.Tenge(1000)},
+					{ID: 2, Date: date2, Status: domain.StatusCancelled, Price: domain.Tenge(2000)},
 				}, nil)
 			},
 			wantTotalIncome: 0,
@@ -196,7 +197,7 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 		{
 			name: "repository error",
 			setup: func(a *repository.MockAppointmentRepository) {
-				a.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				a.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -212,8 +213,8 @@ func TestDashboardUseCase_GetFinanceReport(t *testing.T) {
 			mockServiceRepo := repository.NewMockServiceRepository(ctrl)
 			tt.setup(mockAppointmentRepo)
 
-			uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo)
-			report, err := uc.GetFinanceReport()
+			uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo, time.UTC)
+			report, err := uc.GetFinanceReport(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -239,20 +240,20 @@ func TestDashboardUseCase_GetFinanceReport_IncomeAggregation(t *testing.T) {
 	mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
 	mockServiceRepo := repository.NewMockServiceRepository(ctrl)
 
-	mockAppointmentRepo.EXPECT().GetAll().Return([]*domain.Appointment{
-		{ID: 1, Date: date, Status: domain.StatusCompleted, Price: 1000},
-		{ID: 2, Date: date, Status: domain.StatusCompleted, Price: 2000},
-		{ID: 3, Date: date, Status: domain.StatusCompleted, Price: 3000},
+	mockAppointmentRepo.EXPECT().GetAll(gomock.Any()).Return([]*domain.Appointment{
+		{ID: 1, Date: date, Status: domain.StatusCompleted, Price: domain.Tenge(1000)},
+		{ID: 2, Date: date, Status: domain.StatusCompleted, Price: domain.Tenge(2000)},
+		{ID: 3, Date: date, Status: domain.StatusCompleted, Price: domain.Tenge(3000)},
 	}, nil)
 
-	uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo)
-	report, err := uc.GetFinanceReport()
+	uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo, time.UTC)
+	report, err := uc.GetFinanceReport(context.Background())
 
 	require.NoError(t, err)
 	assert.NotNil(t, report)
-	assert.Equal(t, 6000.0, report.TotalIncome)
+	assert.Equal(t, domain.Tenge(6000), report.TotalIncome)
 	assert.Len(t, report.ByDay, 1)
-	assert.Equal(t, 6000.0, report.ByDay[0].Income)
+	assert.Equal(t, domain.Tenge(6000), report.ByDay[0].Income)
 	assert.Equal(t, "2025-01-15", report.ByDay[0].Date)
 }
 
@@ -276,3 +277,66 @@ func TestFormatWeekKey(t *testing.T) {
 	}
 }
 
+func TestDashboardUseCase_ClinicTimezone(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPatientRepo := repository.NewMockPatientRepository(ctrl)
+	mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+	mockServiceRepo := repository.NewMockServiceRepository(ctrl)
+
+	appointments := []*domain.Appointment{
+		{ID: 1, Date: time.Date(2025, 1, 19, 20, 0, 0, 0, time.UTC), Status: domain.StatusCompleted, Price: domain.Tenge(1000)},
+		{ID: 2, Date: time.Date(2025, 1, 19, 12, 0, 0, 0, time.UTC), Status: domain.StatusCompleted, Price: domain.Tenge(2000)},
+	}
+	mockPatientRepo.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{}, nil)
+	mockAppointmentRepo.EXPECT().GetAll(gomock.Any()).Return(appointments, nil).Times(2)
+
+	uc := NewDashboardUseCase(mockPatientRepo, mockAppointmentRepo, mockServiceRepo, almaty)
+	uc.now = func() time.Time { return time.Date(2025, 1, 19, 21, 30, 0, 0, time.UTC) }
+
+	stats, err := uc.GetDashboardStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TodayAppointments)
+	assert.Equal(t, domain.Tenge(1000), stats.TodayRevenue)
+
+	report, err := uc.GetFinanceReport(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.DayIncome{
+		{Date: "2025-01-20", Income: domain.Tenge(1000)},
+		{Date: "2025-01-19", Income: domain.Tenge(2000)},
+	}, report.ByDay)
+	assert.ElementsMatch(t, []domain.WeekIncome{
+		{Week: "2025-W04", Income: domain.Tenge(1000)},
+		{Week: "2025-W03", Income: domain.Tenge(2000)},
+	}, report.ByWeek)
+}
+
+func TestDashboardUseCase_GetFinanceReport_ExactTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	price, err := domain.ParseMoney("1500.10")
+	require.NoError(t, err)
+
+	appointments := make([]*domain.Appointment, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		appointments = append(appointments, &domain.Appointment{ID: i + 1, Date: date, Status: domain.StatusCompleted, Price: price})
+	}
+
+	mockAppointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+	mockAppointmentRepo.EXPECT().GetAll(gomock.Any()).Return(appointments, nil)
+
+	uc := NewDashboardUseCase(repository.NewMockPatientRepository(ctrl), mockAppointmentRepo, repository.NewMockServiceRepository(ctrl), time.UTC)
+	report, err := uc.GetFinanceReport(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "4500300.00", report.TotalIncome.String())
+	assert.Equal(t, report.TotalIncome, report.ByDay[0].Income)
+	assert.Equal(t, report.TotalIncome, report.ByWeek[0].Income)
+	assert.Equal(t, domain.ClinicCurrency, report.Currency)
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// maxToothNotesLength максимальная длина заметки к зубу в символах
+const maxToothNotesLength = 1000
+
+type DentalChartUseCase struct {
+	chartRepo       domain.DentalChartRepository
+	patientRepo     domain.PatientRepository
+	appointmentRepo domain.AppointmentRepository
+	uow             domain.UnitOfWork
+}
+
+func NewDentalChartUseCase(
+	chartRepo domain.DentalChartRepository,
+	patientRepo domain.PatientRepository,
+	appointmentRepo domain.AppointmentRepository,
+	uow domain.UnitOfWork,
+) *DentalChartUseCase {
+	return &DentalChartUseCase{
+		chartRepo:       chartRepo,
+		patientRepo:     patientRepo,
+		appointmentRepo: appointmentRepo,
+		uow:             uow,
+	}
+}
+
+// GetChart получает зубную формулу пациента
+func (u *DentalChartUseCase) GetChart(ctx context.Context, patientID int) (*domain.DentalChart, error) {
+	if patientID <= 0 {
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
+	}
+	if _, err := u.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, err
+	}
+
+	teeth, err := u.chartRepo.GetTeeth(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DentalChart{PatientID: patientID, Teeth: teeth}, nil
+}
+
+// GetToothHistory получает историю изменений зубной формулы пациента; tooth = 0 — по всем зубам
+func (u *DentalChartUseCase) GetToothHistory(ctx context.Context, patientID, tooth int) ([]*domain.ToothChange, error) {
+	if patientID <= 0 {
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
+	}
+	if tooth != 0 && !domain.ValidToothNumber(tooth) {
+		return nil, domain.NewValidationError("tooth", domain.FieldInvalid, "invalid FDI tooth number")
+	}
+	if _, err := u.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, err
+	}
+
+	return u.chartRepo.GetHistory(ctx, patientID, tooth)
+}
+
+// RecordChanges записывает новое состояние зубов пациента, установленное на приеме appointmentID (0 — вне приема),
+// и возвращает обновленную зубную формулу. Автором изменений считается врач из контекста
+func (u *DentalChartUseCase) RecordChanges(ctx context.Context, patientID, appointmentID int, changes []*domain.ToothChange) (*domain.DentalChart, error) {
+	if patientID <= 0 {
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
+	}
+	if appointmentID < 0 {
+		return nil, domain.NewValidationError("appointment_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+	if err := validateToothChanges(changes); err != nil {
+		return nil, err
+	}
+
+	var doctorID int
+	if doctor, ok := domain.DoctorFromContext(ctx); ok {
+		doctorID = doctor.ID
+	}
+
+	var chart *domain.DentalChart
+	err := u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := u.patientRepo.GetByID(ctx, patientID); err != nil {
+			return err
+		}
+
+		if appointmentID > 0 {
+			appointment, err := u.appointmentRepo.GetByID(ctx, appointmentID)
+			if err != nil {
+				return err
+			}
+			if appointment.PatientID != patientID {
+				return domain.NewValidationError("appointment_id", domain.FieldInvalid, "appointment belongs to another patient")
+			}
+			if !appointment.Status.Active() {
+				return domain.NewValidationError("appointment_id", domain.FieldInvalid, "appointment was cancelled, missed or rescheduled")
+			}
+		}
+
+		for _, change := range changes {
+			change.PatientID = patientID
+			change.AppointmentID = appointmentID
+			change.DoctorID = doctorID
+		}
+		if err := u.chartRepo.RecordChanges(ctx, changes); err != nil {
+			return err
+		}
+
+		teeth, err := u.chartRepo.GetTeeth(ctx, patientID)
+		if err != nil {
+			return err
+		}
+		chart = &domain.DentalChart{PatientID: patientID, Teeth: teeth}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chart, nil
+}
+
+// validateToothChanges проверяет номера зубов, состояния и поверхности и приводит поверхности к порядку записи в формуле
+func validateToothChanges(changes []*domain.ToothChange) error {
+	if len(changes) == 0 {
+		return domain.NewValidationError("teeth", domain.FieldRequired, "at least one tooth is required")
+	}
+
+	seen := make(map[int]bool, len(changes))
+	for i, change := range changes {
+		field := fmt.Sprintf("teeth[%d]", i)
+
+		if !domain.ValidToothNumber(change.Tooth) {
+			return domain.NewValidationError(field+".tooth", domain.FieldInvalid, "invalid FDI tooth number")
+		}
+		if seen[change.Tooth] {
+			return domain.NewValidationError(field+".tooth", domain.FieldInvalid, "tooth is listed more than once")
+		}
+		seen[change.Tooth] = true
+
+		if !change.Status.Valid() {
+			return domain.NewValidationError(field+".status", domain.FieldInvalid, "invalid tooth status")
+		}
+
+		if err := checkSurfacesForStatus(change.Status, change.Surfaces); err != nil {
+			return domain.NewValidationError(field+".surfaces", domain.FieldInvalid, err.Error())
+		}
+		surfaces, err := normalizeSurfaces(change.Tooth, change.Surfaces)
+		if err != nil {
+			return domain.NewValidationError(field+".surfaces", domain.FieldInvalid, err.Error())
+		}
+		change.Surfaces = surfaces
+
+		change.Notes = strings.TrimSpace(change.Notes)
+		if len([]rune(change.Notes)) > maxToothNotesLength {
+			return domain.NewValidationError(field+".notes", domain.FieldTooLong, "notes are too long")
+		}
+	}
+
+	return nil
+}
+
+// checkSurfacesForStatus проверяет, что поверхности указаны для кариеса и пломб и только для них
+func checkSurfacesForStatus(status domain.ToothStatus, surfaces []domain.ToothSurface) error {
+	if !status.HasSurfaces() && len(surfaces) > 0 {
+		return errors.New("surfaces are only allowed for caries and fillings")
+	}
+	if status.HasSurfaces() && len(surfaces) == 0 {
+		return errors.New("at least one surface is required for caries and fillings")
+	}
+	return nil
+}
+
+// normalizeSurfaces проверяет поверхности зуба, убирает повторы и сортирует их в порядке domain.ToothSurfaces
+func normalizeSurfaces(tooth int, surfaces []domain.ToothSurface) ([]domain.ToothSurface, error) {
+	order := make(map[domain.ToothSurface]int, len(domain.ToothSurfaces))
+	for i, surface := range domain.ToothSurfaces {
+		order[surface] = i
+	}
+
+	unique := make(map[domain.ToothSurface]bool, len(surfaces))
+	normalized := make([]domain.ToothSurface, 0, len(surfaces))
+	for _, surface := range surfaces {
+		surface = domain.ToothSurface(strings.ToUpper(string(surface)))
+		if !surface.ValidFor(tooth) {
+			return nil, fmt.Errorf("tooth %d has no surface %q", tooth, surface)
+		}
+		if !unique[surface] {
+			unique[surface] = true
+			normalized = append(normalized, surface)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return order[normalized[i]] < order[normalized[j]] })
+
+	return normalized, nil
+}
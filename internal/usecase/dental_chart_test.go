@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type dentalChartMocks struct {
+	chart        *repository.MockDentalChartRepository
+	patients     *repository.MockPatientRepository
+	appointments *repository.MockAppointmentRepository
+}
+
+func TestDentalChartUseCase_GetChart(t *testing.T) {
+	tests := []struct {
+		name      string
+		patientID int
+		setup     func(*repository.MockDentalChartRepository, *repository.MockPatientRepository)
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "success",
+			patientID: 1,
+			setup: func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				c.EXPECT().GetTeeth(gomock.Any(), 1).Return([]*domain.ToothState{{Tooth: 36, Status: domain.ToothFilled}}, nil)
+			},
+		},
+		{
+			name:      "invalid patient id",
+			patientID: 0,
+			setup:     func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {},
+			wantErr:   true,
+			errMsg:    "invalid patient ID",
+		},
+		{
+			name:      "patient not found",
+			patientID: 999,
+			setup: func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 999).Return(nil, domain.ErrPatientNotFound)
+			},
+			wantErr: true,
+			errMsg:  "patient not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			chartRepo := repository.NewMockDentalChartRepository(ctrl)
+			patientRepo := repository.NewMockPatientRepository(ctrl)
+			tt.setup(chartRepo, patientRepo)
+			uc := NewDentalChartUseCase(chartRepo, patientRepo, repository.NewMockAppointmentRepository(ctrl), newTestUnitOfWork(ctrl))
+
+			chart, err := uc.GetChart(context.Background(), tt.patientID)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, chart)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.patientID, chart.PatientID)
+				assert.Len(t, chart.Teeth, 1)
+			}
+		})
+	}
+}
+
+func TestDentalChartUseCase_GetToothHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		tooth   int
+		setup   func(*repository.MockDentalChartRepository, *repository.MockPatientRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:  "all teeth",
+			tooth: 0,
+			setup: func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				c.EXPECT().GetHistory(gomock.Any(), 1, 0).Return([]*domain.ToothChange{{ID: 1, Tooth: 36}}, nil)
+			},
+		},
+		{
+			name:  "single tooth",
+			tooth: 36,
+			setup: func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {
+				p.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				c.EXPECT().GetHistory(gomock.Any(), 1, 36).Return([]*domain.ToothChange{{ID: 1, Tooth: 36}}, nil)
+			},
+		},
+		{
+			name:    "invalid tooth",
+			tooth:   39,
+			setup:   func(c *repository.MockDentalChartRepository, p *repository.MockPatientRepository) {},
+			wantErr: true,
+			errMsg:  "invalid FDI tooth number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			chartRepo := repository.NewMockDentalChartRepository(ctrl)
+			patientRepo := repository.NewMockPatientRepository(ctrl)
+			tt.setup(chartRepo, patientRepo)
+			uc := NewDentalChartUseCase(chartRepo, patientRepo, repository.NewMockAppointmentRepository(ctrl), newTestUnitOfWork(ctrl))
+
+			history, err := uc.GetToothHistory(context.Background(), 1, tt.tooth)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, history, 1)
+			}
+		})
+	}
+}
+
+func TestDentalChartUseCase_RecordChanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		appointmentID int
+		changes       []*domain.ToothChange
+		setup         func(dentalChartMocks)
+		wantErr       bool
+		errMsg        string
+		wantSurfaces  []domain.ToothSurface
+	}{
+		{
+			name:          "success with appointment",
+			appointmentID: 5,
+			changes:       []*domain.ToothChange{{Tooth: 36, Status: domain.ToothFilled, Surfaces: []domain.ToothSurface{"d", "M", "O", "M"}}},
+			setup: func(m dentalChartMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Appointment{ID: 5, PatientID: 1, Status: domain.StatusInProgress}, nil)
+				m.chart.EXPECT().RecordChanges(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, changes []*domain.ToothChange) error {
+					assert.Equal(t, 1, changes[0].PatientID)
+					assert.Equal(t, 5, changes[0].AppointmentID)
+					assert.Equal(t, 3, changes[0].DoctorID)
+					return nil
+				})
+				m.chart.EXPECT().GetTeeth(gomock.Any(), 1).Return([]*domain.ToothState{{Tooth: 36, Status: domain.ToothFilled}}, nil)
+			},
+			wantSurfaces: []domain.ToothSurface{domain.SurfaceMesial, domain.SurfaceOcclusal, domain.SurfaceDistal},
+		},
+		{
+			name:    "success without appointment",
+			changes: []*domain.ToothChange{{Tooth: 18, Status: domain.ToothMissing}},
+			setup: func(m dentalChartMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.chart.EXPECT().RecordChanges(gomock.Any(), gomock.Any()).Return(nil)
+				m.chart.EXPECT().GetTeeth(gomock.Any(), 1).Return([]*domain.ToothState{{Tooth: 18, Status: domain.ToothMissing}}, nil)
+			},
+			wantSurfaces: []domain.ToothSurface{},
+		},
+		{
+			name:    "no teeth",
+			changes: nil,
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "at least one tooth is required",
+		},
+		{
+			name:    "invalid tooth number",
+			changes: []*domain.ToothChange{{Tooth: 59, Status: domain.ToothCaries, Surfaces: []domain.ToothSurface{"O"}}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "invalid FDI tooth number",
+		},
+		{
+			name:    "duplicate tooth",
+			changes: []*domain.ToothChange{{Tooth: 11, Status: domain.ToothCrown}, {Tooth: 11, Status: domain.ToothVeneer}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "tooth is listed more than once",
+		},
+		{
+			name:    "invalid status",
+			changes: []*domain.ToothChange{{Tooth: 11, Status: "broken"}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "invalid tooth status",
+		},
+		{
+			name:    "occlusal surface on incisor",
+			changes: []*domain.ToothChange{{Tooth: 21, Status: domain.ToothCaries, Surfaces: []domain.ToothSurface{"O"}}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  `tooth 21 has no surface "O"`,
+		},
+		{
+			name:    "caries without surfaces",
+			changes: []*domain.ToothChange{{Tooth: 46, Status: domain.ToothCaries}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "at least one surface is required",
+		},
+		{
+			name:    "surfaces on crown",
+			changes: []*domain.ToothChange{{Tooth: 46, Status: domain.ToothCrown, Surfaces: []domain.ToothSurface{"O"}}},
+			setup:   func(m dentalChartMocks) {},
+			wantErr: true,
+			errMsg:  "surfaces are only allowed for caries and fillings",
+		},
+		{
+			name:          "appointment of another patient",
+			appointmentID: 5,
+			changes:       []*domain.ToothChange{{Tooth: 46, Status: domain.ToothCrown}},
+			setup: func(m dentalChartMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Appointment{ID: 5, PatientID: 2, Status: domain.StatusCompleted}, nil)
+			},
+			wantErr: true,
+			errMsg:  "appointment belongs to another patient",
+		},
+		{
+			name:          "cancelled appointment",
+			appointmentID: 5,
+			changes:       []*domain.ToothChange{{Tooth: 46, Status: domain.ToothCrown}},
+			setup: func(m dentalChartMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Appointment{ID: 5, PatientID: 1, Status: domain.StatusCancelled}, nil)
+			},
+			wantErr: true,
+			errMsg:  "appointment was cancelled",
+		},
+		{
+			name:    "repository error",
+			changes: []*domain.ToothChange{{Tooth: 46, Status: domain.ToothCrown}},
+			setup: func(m dentalChartMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.chart.EXPECT().RecordChanges(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			chartRepo := repository.NewMockDentalChartRepository(ctrl)
+			patientRepo := repository.NewMockPatientRepository(ctrl)
+			appointmentRepo := repository.NewMockAppointmentRepository(ctrl)
+			tt.setup(dentalChartMocks{chart: chartRepo, patients: patientRepo, appointments: appointmentRepo})
+			uc := NewDentalChartUseCase(chartRepo, patientRepo, appointmentRepo, newTestUnitOfWork(ctrl))
+
+			ctx := domain.WithDoctor(context.Background(), &domain.Doctor{ID: 3})
+			chart, err := uc.RecordChanges(ctx, 1, tt.appointmentID, tt.changes)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, chart)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 1, chart.PatientID)
+				assert.Equal(t, tt.wantSurfaces, tt.changes[0].Surfaces)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// maxDiagnosisCommentLength максимальная длина комментария к диагнозу в символах
+const maxDiagnosisCommentLength = 1000
+
+// diagnosisCodePattern формат кода МКБ-10: буква, две цифры и необязательное уточнение, например K02 или K02.1
+var diagnosisCodePattern = regexp.MustCompile(`^[A-Z][0-9]{2}(\.[0-9]{1,2})?$`)
+
+type DiagnosisUseCase struct {
+	catalog         domain.DiagnosisRepository
+	diagnosisRepo   domain.AppointmentDiagnosisRepository
+	appointmentRepo domain.AppointmentRepository
+	uow             domain.UnitOfWork
+}
+
+func NewDiagnosisUseCase(
+	catalog domain.DiagnosisRepository,
+	diagnosisRepo domain.AppointmentDiagnosisRepository,
+	appointmentRepo domain.AppointmentRepository,
+	uow domain.UnitOfWork,
+) *DiagnosisUseCase {
+	return &DiagnosisUseCase{
+		catalog:         catalog,
+		diagnosisRepo:   diagnosisRepo,
+		appointmentRepo: appointmentRepo,
+		uow:             uow,
+	}
+}
+
+// SearchDiagnoses ищет диагнозы в справочнике МКБ-10 по началу кода или части названия
+func (u *DiagnosisUseCase) SearchDiagnoses(ctx context.Context, query string, limit int) ([]*domain.Diagnosis, error) {
+	if limit < 0 {
+		return nil, domain.NewValidationError("limit", domain.FieldInvalid, "limit cannot be negative")
+	}
+	return u.catalog.Search(ctx, query, limit)
+}
+
+// GetDiagnosis получает диагноз справочника по коду
+func (u *DiagnosisUseCase) GetDiagnosis(ctx context.Context, code string) (*domain.Diagnosis, error) {
+	return u.catalog.GetByCode(ctx, normalizeDiagnosisCode(code))
+}
+
+// ListAppointmentDiagnoses получает диагнозы приема с названиями по справочнику
+func (u *DiagnosisUseCase) ListAppointmentDiagnoses(ctx context.Context, appointmentID int) ([]*domain.AppointmentDiagnosis, error) {
+	if appointmentID <= 0 {
+		return nil, domain.NewValidationError("appointment_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+	if _, err := u.appointmentRepo.GetByID(ctx, appointmentID); err != nil {
+		return nil, err
+	}
+
+	diagnoses, err := u.diagnosisRepo.GetByAppointmentID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, diagnosis := range diagnoses {
+		diagnosis.Name = u.diagnosisName(ctx, diagnosis.Code)
+	}
+	return diagnoses, nil
+}
+
+// AddAppointmentDiagnosis ставит диагноз на приеме; автором диагноза считается врач из контекста
+func (u *DiagnosisUseCase) AddAppointmentDiagnosis(ctx context.Context, diagnosis *domain.AppointmentDiagnosis) error {
+	if diagnosis.AppointmentID <= 0 {
+		return domain.NewValidationError("appointment_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+	if diagnosis.Tooth != 0 && !domain.ValidToothNumber(diagnosis.Tooth) {
+		return domain.NewValidationError("tooth", domain.FieldInvalid, "invalid FDI tooth number")
+	}
+	diagnosis.Comment = strings.TrimSpace(diagnosis.Comment)
+	if len([]rune(diagnosis.Comment)) > maxDiagnosisCommentLength {
+		return domain.NewValidationError("comment", domain.FieldTooLong, "comment is too long")
+	}
+
+	diagnosis.Code = normalizeDiagnosisCode(diagnosis.Code)
+	if diagnosis.Code == "" {
+		return domain.NewValidationError("code", domain.FieldRequired, "ICD-10 code is required")
+	}
+	entry, err := catalogDiagnosis(ctx, u.catalog, "code", diagnosis.Code)
+	if err != nil {
+		return err
+	}
+	diagnosis.Name = entry.Name
+
+	if doctor, ok := domain.DoctorFromContext(ctx); ok {
+		diagnosis.DoctorID = doctor.ID
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := heldAppointment(ctx, u.appointmentRepo, diagnosis.AppointmentID); err != nil {
+			return err
+		}
+
+		existing, err := u.diagnosisRepo.GetByAppointmentID(ctx, diagnosis.AppointmentID)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.Code == diagnosis.Code && other.Tooth == diagnosis.Tooth {
+				return domain.ErrAppointmentDiagnosisExists.WithMessage("диагноз %s уже поставлен на этом приеме", diagnosis.Code)
+			}
+		}
+
+		return u.diagnosisRepo.Create(ctx, diagnosis)
+	})
+}
+
+// DeleteAppointmentDiagnosis удаляет диагноз приема
+func (u *DiagnosisUseCase) DeleteAppointmentDiagnosis(ctx context.Context, appointmentID, id int) error {
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid diagnosis ID")
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := u.diagnosisRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing.AppointmentID != appointmentID {
+			return domain.ErrAppointmentDiagnosisNotFound.WithMessage("диагноз с ID %d не найден в записи %d", id, appointmentID)
+		}
+		return u.diagnosisRepo.Delete(ctx, id)
+	})
+}
+
+// GetDiagnosisReport считает завершенные приемы и пациентов по диагнозам за период для статистической отчетности
+func (u *DiagnosisUseCase) GetDiagnosisReport(ctx context.Context, filter domain.DiagnosisReportFilter) ([]*domain.DiagnosisReportRow, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.NewValidationError("date_to", domain.FieldInvalid, "date_to must not be before date_from")
+	}
+	if filter.DoctorID < 0 {
+		return nil, domain.NewValidationError("doctor_id", domain.FieldInvalid, "invalid doctor ID")
+	}
+
+	report, err := u.diagnosisRepo.GetReport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range report {
+		row.Name = u.diagnosisName(ctx, row.Code)
+	}
+	return report, nil
+}
+
+// diagnosisName возвращает название диагноза по справочнику; коды вне справочника, записанные до его появления,
+// остаются без названия
+func (u *DiagnosisUseCase) diagnosisName(ctx context.Context, code string) string {
+	diagnosis, err := u.catalog.GetByCode(ctx, code)
+	if err != nil {
+		return ""
+	}
+	return diagnosis.Name
+}
+
+// normalizeDiagnosisCode приводит код МКБ-10 к каноническому виду: без пробелов, заглавными буквами
+func normalizeDiagnosisCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// catalogDiagnosis проверяет, что код есть в справочнике и достаточно точен для кодирования: рубрику с подрубриками
+// нужно уточнить, например K02 до K02.1
+func catalogDiagnosis(ctx context.Context, catalog domain.DiagnosisRepository, field, code string) (*domain.Diagnosis, error) {
+	if !diagnosisCodePattern.MatchString(code) {
+		return nil, domain.NewValidationError(field, domain.FieldInvalid, "invalid ICD-10 code")
+	}
+
+	diagnosis, err := catalog.GetByCode(ctx, code)
+	if errors.Is(err, domain.ErrDiagnosisNotFound) {
+		return nil, domain.NewValidationError(field, domain.FieldInvalid, "ICD-10 code is not in the dental diagnosis catalog")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if diagnosis.HasSubcodes {
+		return nil, domain.NewValidationError(field, domain.FieldInvalid, "use a more specific ICD-10 code")
+	}
+	return diagnosis, nil
+}
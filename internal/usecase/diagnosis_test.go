@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type diagnosisMocks struct {
+	catalog      *repository.MockDiagnosisRepository
+	diagnoses    *repository.MockAppointmentDiagnosisRepository
+	appointments *repository.MockAppointmentRepository
+}
+
+func newDiagnosisTestUseCase(ctrl *gomock.Controller, setup func(diagnosisMocks)) *DiagnosisUseCase {
+	m := diagnosisMocks{
+		catalog:      repository.NewMockDiagnosisRepository(ctrl),
+		diagnoses:    repository.NewMockAppointmentDiagnosisRepository(ctrl),
+		appointments: repository.NewMockAppointmentRepository(ctrl),
+	}
+	setup(m)
+	return NewDiagnosisUseCase(m.catalog, m.diagnoses, m.appointments, newTestUnitOfWork(ctrl))
+}
+
+func TestDiagnosisUseCase_AddAppointmentDiagnosis(t *testing.T) {
+	appointment := &domain.Appointment{ID: 5, PatientID: 1, Status: domain.StatusInProgress}
+	pulpitis := &domain.Diagnosis{Code: "K04.0", Name: "Пульпит", Category: "K04"}
+
+	tests := []struct {
+		name      string
+		diagnosis *domain.AppointmentDiagnosis
+		setup     func(diagnosisMocks)
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "tooth diagnosis",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: " k04.0 ", Tooth: 36, Comment: " острый "},
+			setup: func(m diagnosisMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K04.0").Return(pulpitis, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.diagnoses.EXPECT().GetByAppointmentID(gomock.Any(), 5).Return([]*domain.AppointmentDiagnosis{{ID: 1, Code: "K04.0", Tooth: 37}}, nil)
+				m.diagnoses.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, diagnosis *domain.AppointmentDiagnosis) error {
+					assert.Equal(t, "K04.0", diagnosis.Code)
+					assert.Equal(t, "острый", diagnosis.Comment)
+					assert.Equal(t, 3, diagnosis.DoctorID)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "missing code",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5},
+			setup:     func(m diagnosisMocks) {},
+			wantErr:   true,
+			errMsg:    "ICD-10 code is required",
+		},
+		{
+			name:      "malformed code",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "pulpitis"},
+			setup:     func(m diagnosisMocks) {},
+			wantErr:   true,
+			errMsg:    "invalid ICD-10 code",
+		},
+		{
+			name:      "code outside catalog",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "J06.9"},
+			setup: func(m diagnosisMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "J06.9").Return(nil, domain.ErrDiagnosisNotFound)
+			},
+			wantErr: true,
+			errMsg:  "not in the dental diagnosis catalog",
+		},
+		{
+			name:      "category with subcodes",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "K04"},
+			setup: func(m diagnosisMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K04").Return(&domain.Diagnosis{Code: "K04", Category: "K04", HasSubcodes: true}, nil)
+			},
+			wantErr: true,
+			errMsg:  "use a more specific ICD-10 code",
+		},
+		{
+			name:      "invalid tooth",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "K04.0", Tooth: 19},
+			setup:     func(m diagnosisMocks) {},
+			wantErr:   true,
+			errMsg:    "invalid FDI tooth number",
+		},
+		{
+			name:      "cancelled appointment",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "K04.0"},
+			setup: func(m diagnosisMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K04.0").Return(pulpitis, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Appointment{ID: 5, Status: domain.StatusCancelled}, nil)
+			},
+			wantErr: true,
+			errMsg:  "appointment was cancelled",
+		},
+		{
+			name:      "duplicate diagnosis",
+			diagnosis: &domain.AppointmentDiagnosis{AppointmentID: 5, Code: "K04.0", Tooth: 36},
+			setup: func(m diagnosisMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K04.0").Return(pulpitis, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.diagnoses.EXPECT().GetByAppointmentID(gomock.Any(), 5).Return([]*domain.AppointmentDiagnosis{{ID: 1, Code: "K04.0", Tooth: 36}}, nil)
+			},
+			wantErr: true,
+			errMsg:  "уже поставлен",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newDiagnosisTestUseCase(ctrl, tt.setup)
+			ctx := domain.WithDoctor(context.Background(), &domain.Doctor{ID: 3})
+			err := uc.AddAppointmentDiagnosis(ctx, tt.diagnosis)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "Пульпит", tt.diagnosis.Name)
+			}
+		})
+	}
+}
+
+func TestDiagnosisUseCase_DeleteAppointmentDiagnosis(t *testing.T) {
+	tests := []struct {
+		name          string
+		appointmentID int
+		id            int
+		setup         func(diagnosisMocks)
+		wantErr       bool
+		errMsg        string
+	}{
+		{
+			name:          "success",
+			appointmentID: 5,
+			id:            7,
+			setup: func(m diagnosisMocks) {
+				m.diagnoses.EXPECT().GetByID(gomock.Any(), 7).Return(&domain.AppointmentDiagnosis{ID: 7, AppointmentID: 5}, nil)
+				m.diagnoses.EXPECT().Delete(gomock.Any(), 7).Return(nil)
+			},
+		},
+		{
+			name:          "diagnosis of another appointment",
+			appointmentID: 6,
+			id:            7,
+			setup: func(m diagnosisMocks) {
+				m.diagnoses.EXPECT().GetByID(gomock.Any(), 7).Return(&domain.AppointmentDiagnosis{ID: 7, AppointmentID: 5}, nil)
+			},
+			wantErr: true,
+			errMsg:  "не найден в записи 6",
+		},
+		{
+			name:          "invalid id",
+			appointmentID: 5,
+			setup:         func(m diagnosisMocks) {},
+			wantErr:       true,
+			errMsg:        "invalid diagnosis ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newDiagnosisTestUseCase(ctrl, tt.setup)
+			err := uc.DeleteAppointmentDiagnosis(context.Background(), tt.appointmentID, tt.id)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiagnosisUseCase_GetDiagnosisReport(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    domain.DiagnosisReportFilter
+		setup     func(diagnosisMocks)
+		wantErr   bool
+		errMsg    string
+		wantNames []string
+	}{
+		{
+			name:   "names from catalog",
+			filter: domain.DiagnosisReportFilter{From: &from, To: &to, ByCategory: true},
+			setup: func(m diagnosisMocks) {
+				m.diagnoses.EXPECT().GetReport(gomock.Any(), gomock.Any()).Return([]*domain.DiagnosisReportRow{
+					{Code: "K02", Visits: 3, Patients: 2},
+					{Code: "Z01", Visits: 1, Patients: 1},
+				}, nil)
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K02").Return(&domain.Diagnosis{Code: "K02", Name: "Кариес зубов"}, nil)
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "Z01").Return(nil, domain.ErrDiagnosisNotFound)
+			},
+			wantNames: []string{"Кариес зубов", ""},
+		},
+		{
+			name:    "inverted period",
+			filter:  domain.DiagnosisReportFilter{From: &to, To: &from},
+			setup:   func(m diagnosisMocks) {},
+			wantErr: true,
+			errMsg:  "date_to must not be before date_from",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newDiagnosisTestUseCase(ctrl, tt.setup)
+			report, err := uc.GetDiagnosisReport(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, report, len(tt.wantNames))
+			for i, name := range tt.wantNames {
+				assert.Equal(t, name, report[i].Name)
+			}
+		})
+	}
+}
@@ -1,78 +1,187 @@
 package usecase
 
 import (
-	"errors"
+	"context"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Параметры защиты входа от перебора паролей
+const (
+	MaxFailedLogins      = 5                // неудачных попыток подряд до блокировки учетной записи
+	LoginLockoutDuration = 15 * time.Minute // длительность блокировки учетной записи
+	MaxFailedLoginsPerIP = 20               // неудачных попыток с одного IP за окно LoginAttemptWindow
+	LoginAttemptWindow   = 15 * time.Minute
+
+	loginBaseDelay       = 500 * time.Millisecond
+	loginMaxDelay        = 8 * time.Second
+	maxLoginAttemptsPage = 200
 )
 
 type DoctorUseCase struct {
-	doctorRepo domain.DoctorRepository
+	doctorRepo       domain.DoctorRepository
+	loginAttemptRepo domain.LoginAttemptRepository
+	sleep            func(time.Duration)
 }
 
-func NewDoctorUseCase(doctorRepo domain.DoctorRepository) *DoctorUseCase {
+func NewDoctorUseCase(doctorRepo domain.DoctorRepository, loginAttemptRepo domain.LoginAttemptRepository) *DoctorUseCase {
 	return &DoctorUseCase{
-		doctorRepo: doctorRepo,
+		doctorRepo:       doctorRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		sleep:            time.Sleep,
 	}
 }
 
 // CreateDoctor создает нового врача
-func (u *DoctorUseCase) CreateDoctor(doctor *domain.Doctor) error {
+func (u *DoctorUseCase) CreateDoctor(ctx context.Context, doctor *domain.Doctor) error {
 	if err := u.ValidateDoctor(doctor); err != nil {
 		return err
 	}
 
-	return u.doctorRepo.Create(doctor)
+	hash, err := hashPassword(doctor.Password)
+	if err != nil {
+		return err
+	}
+	doctor.Password = hash
+	normalizeRole(doctor)
+
+	return u.doctorRepo.Create(ctx, doctor)
 }
 
 // GetDoctor получает врача по ID
-func (u *DoctorUseCase) GetDoctor(id int) (*domain.Doctor, error) {
+func (u *DoctorUseCase) GetDoctor(ctx context.Context, id int) (*domain.Doctor, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid doctor ID")
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid doctor ID")
 	}
-	return u.doctorRepo.GetByID(id)
+	return u.doctorRepo.GetByID(ctx, id)
 }
 
 // GetAllDoctors получает всех врачей
-func (u *DoctorUseCase) GetAllDoctors() ([]*domain.Doctor, error) {
-	return u.doctorRepo.GetAll()
+func (u *DoctorUseCase) GetAllDoctors(ctx context.Context) ([]*domain.Doctor, error) {
+	return u.doctorRepo.GetAll(ctx)
 }
 
-// UpdateDoctor обновляет врача
-func (u *DoctorUseCase) UpdateDoctor(doctor *domain.Doctor) error {
-	if err := u.ValidateDoctor(doctor); err != nil {
+// ListDoctors получает страницу врачей по фильтру и общее число подходящих врачей
+func (u *DoctorUseCase) ListDoctors(ctx context.Context, filter domain.DoctorFilter) ([]*domain.Doctor, int, error) {
+	if filter.Role != "" && !filter.Role.Valid() {
+		return nil, 0, domain.NewValidationError("role", domain.FieldInvalid, "invalid doctor role")
+	}
+
+	if err := normalizeList(filter.Sort, &filter.Page, domain.DoctorSortFields); err != nil {
+		return nil, 0, err
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+	return u.doctorRepo.List(ctx, filter)
+}
+
+// UpdateDoctor обновляет врача; пустой пароль оставляет текущий без изменений
+func (u *DoctorUseCase) UpdateDoctor(ctx context.Context, doctor *domain.Doctor) error {
+	if err := u.validateDoctorProfile(doctor); err != nil {
 		return err
 	}
 
-	return u.doctorRepo.Update(doctor)
+	if doctor.Password != "" {
+		if err := validateDoctorPassword(doctor.Password); err != nil {
+			return err
+		}
+
+		hash, err := hashPassword(doctor.Password)
+		if err != nil {
+			return err
+		}
+		doctor.Password = hash
+	}
+	normalizeRole(doctor)
+
+	return u.doctorRepo.Update(ctx, doctor)
 }
 
 // DeleteDoctor удаляет врача
-func (u *DoctorUseCase) DeleteDoctor(id int) error {
+func (u *DoctorUseCase) DeleteDoctor(ctx context.Context, id int) error {
 	if id <= 0 {
-		return errors.New("invalid doctor ID")
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid doctor ID")
 	}
-	return u.doctorRepo.Delete(id)
+	return u.doctorRepo.Delete(ctx, id)
 }
 
-// AuthenticateDoctor аутентифицирует врача
-func (u *DoctorUseCase) AuthenticateDoctor(login, password string) (*domain.Doctor, error) {
+// AuthenticateDoctor аутентифицирует врача с защитой от перебора паролей:
+// блокирует IP после серии неудач, временно блокирует учетную запись и записывает каждую попытку в журнал
+func (u *DoctorUseCase) AuthenticateDoctor(ctx context.Context, login, password, ip string) (*domain.Doctor, error) {
 	if login == "" || password == "" {
-		return nil, errors.New("login and password are required")
+		return nil, domain.NewValidationError("login", domain.FieldRequired, "login and password are required")
 	}
 
-	doctor, err := u.doctorRepo.GetByLogin(login)
+	now := time.Now()
+
+	ipFailures, err := u.loginAttemptRepo.CountFailuresByIP(ctx, ip, now.Add(-LoginAttemptWindow))
+	if err != nil {
+		return nil, err
+	}
+	if ipFailures >= MaxFailedLoginsPerIP {
+		recordLoginAttempt(ctx, u.loginAttemptRepo, login, nil, ip, domain.LoginReasonIPBlocked)
+		return nil, domain.ErrTooManyLoginAttempts
+	}
+
+	doctor, err := u.doctorRepo.GetByLogin(ctx, login)
 	if err != nil {
 		return nil, err
 	}
 
 	if doctor == nil {
-		return nil, errors.New("invalid login or password")
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		recordLoginAttempt(ctx, u.loginAttemptRepo, login, nil, ip, domain.LoginReasonUnknownLogin)
+		u.sleep(loginDelay(ipFailures + 1))
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if doctor.IsLocked(now) {
+		recordLoginAttempt(ctx, u.loginAttemptRepo, login, doctor, ip, domain.LoginReasonAccountLocked)
+		return nil, domain.ErrAccountLocked
 	}
 
-	// Простая проверка пароля (в реальном приложении используйте bcrypt)
-	if doctor.Password != password {
-		return nil, errors.New("invalid login or password")
+	// После истечения блокировки счетчик начинается заново, иначе первая же ошибка снова заблокировала бы вход
+	if doctor.LockedUntil != nil {
+		if err := u.doctorRepo.ResetFailedLogins(ctx, doctor.ID); err != nil {
+			return nil, err
+		}
+		doctor.FailedLoginAttempts = 0
+		doctor.LockedUntil = nil
+	}
+
+	ok, needsRehash := checkPassword(doctor.Password, password)
+	if !ok {
+		failures, err := registerLoginFailure(ctx, u.doctorRepo, doctor.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		recordLoginAttempt(ctx, u.loginAttemptRepo, login, doctor, ip, domain.LoginReasonInvalidPassword)
+		u.sleep(loginDelay(failures))
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	// При включенном втором факторе счетчик сбрасывается только после проверки кода,
+	// иначе повторный вход по паролю обнулял бы неудачные попытки ввода кода
+	if !doctor.TOTPEnabled && (doctor.FailedLoginAttempts > 0 || doctor.LockedUntil != nil) {
+		if err := u.doctorRepo.ResetFailedLogins(ctx, doctor.ID); err != nil {
+			return nil, err
+		}
+		doctor.FailedLoginAttempts = 0
+		doctor.LockedUntil = nil
+	}
+	recordLoginAttempt(ctx, u.loginAttemptRepo, login, doctor, ip, "")
+
+	// Пароли, сохраненные открытым текстом, перехешируются при первом успешном входе
+	if needsRehash {
+		if hash, err := hashPassword(password); err != nil {
+			log.Printf("Failed to hash password for doctor %d: %v", doctor.ID, err)
+		} else if err := u.doctorRepo.UpdatePassword(ctx, doctor.ID, hash); err != nil {
+			log.Printf("Failed to rehash password for doctor %d: %v", doctor.ID, err)
+		}
 	}
 
 	// Не возвращаем пароль на фронт
@@ -81,30 +190,131 @@ func (u *DoctorUseCase) AuthenticateDoctor(login, password string) (*domain.Doct
 	return doctor, nil
 }
 
-// ValidateDoctor валидирует данные врача
+// UnlockDoctor снимает блокировку входа врача и сбрасывает счетчик неудачных попыток
+func (u *DoctorUseCase) UnlockDoctor(ctx context.Context, id int) error {
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid doctor ID")
+	}
+
+	doctor, err := u.doctorRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if doctor == nil {
+		return domain.ErrDoctorNotFound
+	}
+
+	return u.doctorRepo.ResetFailedLogins(ctx, id)
+}
+
+// GetLoginAttempts получает журнал последних попыток входа, при непустом login — только по этому логину
+func (u *DoctorUseCase) GetLoginAttempts(ctx context.Context, login string, limit int) ([]*domain.LoginAttempt, error) {
+	if limit <= 0 || limit > maxLoginAttemptsPage {
+		limit = maxLoginAttemptsPage
+	}
+	return u.loginAttemptRepo.GetRecent(ctx, login, limit)
+}
+
+// registerLoginFailure учитывает неудачную попытку входа врача и блокирует учетную запись
+// после MaxFailedLogins неудач подряд; возвращает число неудач подряд
+func registerLoginFailure(ctx context.Context, doctorRepo domain.DoctorRepository, doctorID int, now time.Time) (int, error) {
+	failures, err := doctorRepo.IncrementFailedLogins(ctx, doctorID)
+	if err != nil {
+		return 0, err
+	}
+	if failures >= MaxFailedLogins {
+		if err := doctorRepo.LockUntil(ctx, doctorID, now.Add(LoginLockoutDuration)); err != nil {
+			return 0, err
+		}
+	}
+	return failures, nil
+}
+
+// recordLoginAttempt записывает попытку входа в журнал; пустая причина означает успешный вход.
+// Ошибка записи журнала не меняет результат входа.
+func recordLoginAttempt(ctx context.Context, loginAttemptRepo domain.LoginAttemptRepository, login string, doctor *domain.Doctor, ip string, reason domain.LoginAttemptReason) {
+	attempt := &domain.LoginAttempt{
+		Login:   login,
+		IP:      ip,
+		Success: reason == "",
+		Reason:  reason,
+	}
+	if doctor != nil {
+		attempt.DoctorID = &doctor.ID
+	}
+
+	// Попытка записывается и после отмены запроса клиентом, чтобы не терять события безопасности
+	if err := loginAttemptRepo.Create(context.WithoutCancel(ctx), attempt); err != nil {
+		log.Printf("Failed to record login attempt for %q: %v", login, err)
+	}
+}
+
+// loginDelay возвращает прогрессивную задержку ответа после failures неудачных попыток подряд
+func loginDelay(failures int) time.Duration {
+	if failures <= 1 {
+		return 0
+	}
+
+	delay := loginBaseDelay
+	for i := 2; i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, loginMaxDelay)
+}
+
+// ValidateDoctor валидирует данные нового врача, включая обязательный пароль
 func (u *DoctorUseCase) ValidateDoctor(doctor *domain.Doctor) error {
+	if err := u.validateDoctorProfile(doctor); err != nil {
+		return err
+	}
+
+	if doctor.Password == "" {
+		return domain.NewValidationError("password", domain.FieldRequired, "doctor password is required")
+	}
+
+	return validateDoctorPassword(doctor.Password)
+}
+
+// validateDoctorProfile валидирует профиль врача без пароля
+func (u *DoctorUseCase) validateDoctorProfile(doctor *domain.Doctor) error {
 	if doctor.Name == "" {
-		return errors.New("doctor name is required")
+		return domain.NewValidationError("name", domain.FieldRequired, "doctor name is required")
 	}
 
 	if len(doctor.Name) > 255 {
-		return errors.New("doctor name is too long")
+		return domain.NewValidationError("name", domain.FieldTooLong, "doctor name is too long")
 	}
 
 	if doctor.Login == "" {
-		return errors.New("doctor login is required")
+		return domain.NewValidationError("login", domain.FieldRequired, "doctor login is required")
 	}
 
 	if len(doctor.Login) > 100 {
-		return errors.New("doctor login is too long")
+		return domain.NewValidationError("login", domain.FieldTooLong, "doctor login is too long")
 	}
 
-	if doctor.Password == "" {
-		return errors.New("doctor password is required")
+	if doctor.Role != "" && !doctor.Role.Valid() {
+		return domain.NewValidationError("role", domain.FieldInvalid, "invalid doctor role")
+	}
+
+	return nil
+}
+
+// normalizeRole проставляет роль по признаку IsAdmin для старых клиентов и синхронизирует IsAdmin с ролью
+func normalizeRole(doctor *domain.Doctor) {
+	doctor.Role = doctor.EffectiveRole()
+	doctor.IsAdmin = doctor.Role == domain.RoleAdmin
+}
+
+// validateDoctorPassword проверяет длину пароля; bcrypt учитывает только первые 72 байта
+func validateDoctorPassword(password string) error {
+	if len(password) < 4 {
+		return domain.NewValidationError("password", domain.FieldTooShort, "doctor password is too short")
 	}
 
-	if len(doctor.Password) < 4 {
-		return errors.New("doctor password is too short")
+	if len(password) > 72 {
+		return domain.NewValidationError("password", domain.FieldTooLong, "doctor password is too long")
 	}
 
 	return nil
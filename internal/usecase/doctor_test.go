@@ -1,14 +1,17 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/sdk17/crmstom/gen/mocks/repository"
 	"github.com/sdk17/crmstom/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestDoctorUseCase_GetDoctor(t *testing.T) {
@@ -23,7 +26,7 @@ func TestDoctorUseCase_GetDoctor(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Doctor{
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Doctor{
 					ID:    1,
 					Name:  "Dr. Smith",
 					Login: "drsmith",
@@ -49,7 +52,7 @@ func TestDoctorUseCase_GetDoctor(t *testing.T) {
 			name: "doctor not found",
 			id:   999,
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("doctor not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("doctor not found"))
 			},
 			wantErr: true,
 			errMsg:  "doctor not found",
@@ -63,9 +66,9 @@ func TestDoctorUseCase_GetDoctor(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
-			doctor, err := uc.GetDoctor(tt.id)
+			doctor, err := uc.GetDoctor(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -89,7 +92,7 @@ func TestDoctorUseCase_GetAllDoctors(t *testing.T) {
 		{
 			name: "success with doctors",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Doctor{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Doctor{
 					{ID: 1, Name: "Dr. Smith"},
 					{ID: 2, Name: "Dr. Jones"},
 				}, nil)
@@ -100,7 +103,7 @@ func TestDoctorUseCase_GetAllDoctors(t *testing.T) {
 		{
 			name: "success empty list",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Doctor{}, nil)
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Doctor{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -108,7 +111,7 @@ func TestDoctorUseCase_GetAllDoctors(t *testing.T) {
 		{
 			name: "repository error",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				m.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -121,9 +124,9 @@ func TestDoctorUseCase_GetAllDoctors(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
-			doctors, err := uc.GetAllDoctors()
+			doctors, err := uc.GetAllDoctors(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -135,6 +138,65 @@ func TestDoctorUseCase_GetAllDoctors(t *testing.T) {
 	}
 }
 
+func TestDoctorUseCase_ListDoctors(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    domain.DoctorFilter
+		setup     func(*repository.MockDoctorRepository)
+		wantTotal int
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:   "role and query",
+			filter: domain.DoctorFilter{Role: domain.RoleDoctor, Query: " ivan ", Sort: domain.Sort{Field: "login"}},
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().List(gomock.Any(), domain.DoctorFilter{
+					Role: domain.RoleDoctor, Query: "ivan", Sort: domain.Sort{Field: "login"}, Page: domain.Page{Limit: domain.DefaultPageLimit},
+				}).Return([]*domain.Doctor{{ID: 1}}, 1, nil)
+			},
+			wantTotal: 1,
+		},
+		{
+			name:    "unknown role",
+			filter:  domain.DoctorFilter{Role: "superuser"},
+			setup:   func(m *repository.MockDoctorRepository) {},
+			wantErr: true,
+			errMsg:  "invalid doctor role",
+		},
+		{
+			name:    "unknown sort field",
+			filter:  domain.DoctorFilter{Sort: domain.Sort{Field: "password", Desc: true}},
+			setup:   func(m *repository.MockDoctorRepository) {},
+			wantErr: true,
+			errMsg:  "invalid sort field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
+
+			doctors, total, err := uc.ListDoctors(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, doctors)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
 func TestDoctorUseCase_CreateDoctor(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -152,7 +214,43 @@ func TestDoctorUseCase_CreateDoctor(t *testing.T) {
 				Password: "password123",
 			},
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doctor *domain.Doctor) error {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(doctor.Password), []byte("password123")))
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "legacy admin flag becomes admin role",
+			doctor: &domain.Doctor{
+				Name:     "Dr. Admin",
+				Login:    "dradmin",
+				Password: "password123",
+				IsAdmin:  true,
+			},
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doctor *domain.Doctor) error {
+					assert.Equal(t, domain.RoleAdmin, doctor.Role)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "role without admin flag",
+			doctor: &domain.Doctor{
+				Name:     "Accountant",
+				Login:    "accountant",
+				Password: "password123",
+				Role:     domain.RoleAccountant,
+			},
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doctor *domain.Doctor) error {
+					assert.Equal(t, domain.RoleAccountant, doctor.Role)
+					assert.False(t, doctor.IsAdmin)
+					return nil
+				})
 			},
 			wantErr: false,
 		},
@@ -230,7 +328,7 @@ func TestDoctorUseCase_CreateDoctor(t *testing.T) {
 				Password: "password123",
 			},
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -244,9 +342,9 @@ func TestDoctorUseCase_CreateDoctor(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
-			err := uc.CreateDoctor(tt.doctor)
+			err := uc.CreateDoctor(context.Background(), tt.doctor)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -275,10 +373,40 @@ func TestDoctorUseCase_UpdateDoctor(t *testing.T) {
 				Password: "newpassword",
 			},
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doctor *domain.Doctor) error {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(doctor.Password), []byte("newpassword")))
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "success without password",
+			doctor: &domain.Doctor{
+				ID:    1,
+				Name:  "Dr. Smith Updated",
+				Login: "drsmith",
+			},
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doctor *domain.Doctor) error {
+					assert.Empty(t, doctor.Password)
+					return nil
+				})
 			},
 			wantErr: false,
 		},
+		{
+			name: "password too short",
+			doctor: &domain.Doctor{
+				ID:       1,
+				Name:     "Dr. Smith",
+				Login:    "drsmith",
+				Password: "123",
+			},
+			setup:   func(m *repository.MockDoctorRepository) {},
+			wantErr: true,
+			errMsg:  "doctor password is too short",
+		},
 		{
 			name: "validation error",
 			doctor: &domain.Doctor{
@@ -300,7 +428,7 @@ func TestDoctorUseCase_UpdateDoctor(t *testing.T) {
 				Password: "password",
 			},
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Update(gomock.Any()).Return(errors.New("doctor not found"))
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("doctor not found"))
 			},
 			wantErr: true,
 			errMsg:  "doctor not found",
@@ -314,9 +442,9 @@ func TestDoctorUseCase_UpdateDoctor(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
-			err := uc.UpdateDoctor(tt.doctor)
+			err := uc.UpdateDoctor(context.Background(), tt.doctor)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -340,7 +468,7 @@ func TestDoctorUseCase_DeleteDoctor(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Delete(1).Return(nil)
+				m.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -362,7 +490,7 @@ func TestDoctorUseCase_DeleteDoctor(t *testing.T) {
 			name: "not found",
 			id:   999,
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().Delete(999).Return(errors.New("doctor not found"))
+				m.EXPECT().Delete(gomock.Any(), 999).Return(errors.New("doctor not found"))
 			},
 			wantErr: true,
 			errMsg:  "doctor not found",
@@ -376,9 +504,9 @@ func TestDoctorUseCase_DeleteDoctor(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
-			err := uc.DeleteDoctor(tt.id)
+			err := uc.DeleteDoctor(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -404,13 +532,32 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 			login:    "drsmith",
 			password: "password123",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByLogin("drsmith").Return(&domain.Doctor{
+				m.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{
 					ID:       1,
 					Name:     "Dr. Smith",
 					Login:    "drsmith",
 					Password: "password123",
 					IsAdmin:  false,
 				}, nil)
+				m.EXPECT().UpdatePassword(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, id int, hash string) error {
+					assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("password123")))
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name:     "second factor pending keeps failed logins",
+			login:    "drsmith",
+			password: "password123",
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{
+					ID:                  1,
+					Login:               "drsmith",
+					Password:            mustHashPassword(t, "password123", passwordHashCost),
+					TOTPEnabled:         true,
+					FailedLoginAttempts: 3,
+				}, nil)
 			},
 			wantErr: false,
 		},
@@ -419,16 +566,73 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 			login:    "admin",
 			password: "adminpass",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByLogin("admin").Return(&domain.Doctor{
+				m.EXPECT().GetByLogin(gomock.Any(), "admin").Return(&domain.Doctor{
 					ID:       1,
 					Name:     "Admin",
 					Login:    "admin",
 					Password: "adminpass",
 					IsAdmin:  true,
 				}, nil)
+				m.EXPECT().UpdatePassword(gomock.Any(), 1, gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success hashed password",
+			login:    "drhash",
+			password: "password123",
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByLogin(gomock.Any(), "drhash").Return(&domain.Doctor{
+					ID:       2,
+					Login:    "drhash",
+					Password: mustHashPassword(t, "password123", passwordHashCost),
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "low cost hash is upgraded",
+			login:    "drhash",
+			password: "password123",
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByLogin(gomock.Any(), "drhash").Return(&domain.Doctor{
+					ID:       2,
+					Login:    "drhash",
+					Password: mustHashPassword(t, "password123", bcrypt.MinCost),
+				}, nil)
+				m.EXPECT().UpdatePassword(gomock.Any(), 2, gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name:     "rehash failure does not block login",
+			login:    "drsmith",
+			password: "password123",
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{
+					ID:       1,
+					Login:    "drsmith",
+					Password: "password123",
+				}, nil)
+				m.EXPECT().UpdatePassword(gomock.Any(), 1, gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: false,
+		},
+		{
+			name:     "wrong hashed password",
+			login:    "drhash",
+			password: "wrongpassword",
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByLogin(gomock.Any(), "drhash").Return(&domain.Doctor{
+					ID:       2,
+					Login:    "drhash",
+					Password: mustHashPassword(t, "password123", passwordHashCost),
+				}, nil)
+				m.EXPECT().IncrementFailedLogins(gomock.Any(), 2).Return(1, nil)
+			},
+			wantErr: true,
+			errMsg:  "invalid login or password",
+		},
 		{
 			name:     "empty login",
 			login:    "",
@@ -458,7 +662,7 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 			login:    "unknown",
 			password: "password",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByLogin("unknown").Return(nil, errors.New("not found"))
+				m.EXPECT().GetByLogin(gomock.Any(), "unknown").Return(nil, errors.New("not found"))
 			},
 			wantErr: true,
 			errMsg:  "not found",
@@ -468,7 +672,7 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 			login:    "unknown",
 			password: "password",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByLogin("unknown").Return(nil, nil)
+				m.EXPECT().GetByLogin(gomock.Any(), "unknown").Return(nil, nil)
 			},
 			wantErr: true,
 			errMsg:  "invalid login or password",
@@ -478,12 +682,13 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 			login:    "drsmith",
 			password: "wrongpassword",
 			setup: func(m *repository.MockDoctorRepository) {
-				m.EXPECT().GetByLogin("drsmith").Return(&domain.Doctor{
+				m.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{
 					ID:       1,
 					Name:     "Dr. Smith",
 					Login:    "drsmith",
 					Password: "correctpassword",
 				}, nil)
+				m.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
 			},
 			wantErr: true,
 			errMsg:  "invalid login or password",
@@ -497,9 +702,13 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 
 			mockRepo := repository.NewMockDoctorRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewDoctorUseCase(mockRepo)
+			mockAttemptRepo := repository.NewMockLoginAttemptRepository(ctrl)
+			mockAttemptRepo.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil).AnyTimes()
+			mockAttemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			uc := NewDoctorUseCase(mockRepo, mockAttemptRepo)
+			uc.sleep = func(time.Duration) {}
 
-			doctor, err := uc.AuthenticateDoctor(tt.login, tt.password)
+			doctor, err := uc.AuthenticateDoctor(context.Background(), tt.login, tt.password, "10.0.0.1")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -514,12 +723,200 @@ func TestDoctorUseCase_AuthenticateDoctor(t *testing.T) {
 	}
 }
 
+func TestDoctorUseCase_AuthenticateDoctor_BruteForceProtection(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		password  string
+		setup     func(*repository.MockDoctorRepository, *repository.MockLoginAttemptRepository)
+		wantErr   error
+		wantDelay time.Duration
+	}{
+		{
+			name:     "ip blocked after too many failures",
+			password: "password123",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(MaxFailedLoginsPerIP, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.False(t, attempt.Success)
+					assert.Equal(t, domain.LoginReasonIPBlocked, attempt.Reason)
+					return nil
+				})
+			},
+			wantErr: domain.ErrTooManyLoginAttempts,
+		},
+		{
+			name:     "locked account rejects correct password",
+			password: "password123",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				d.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{ID: 1, Login: "drsmith", Password: mustHashPassword(t, "password123", passwordHashCost), LockedUntil: &future}, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.Equal(t, domain.LoginReasonAccountLocked, attempt.Reason)
+					require.NotNil(t, attempt.DoctorID)
+					assert.Equal(t, 1, *attempt.DoctorID)
+					return nil
+				})
+			},
+			wantErr: domain.ErrAccountLocked,
+		},
+		{
+			name:     "last allowed failure locks account",
+			password: "wrongpassword",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(4, nil)
+				d.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{ID: 1, Login: "drsmith", Password: mustHashPassword(t, "password123", passwordHashCost), FailedLoginAttempts: 4}, nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(MaxFailedLogins, nil)
+				d.EXPECT().LockUntil(gomock.Any(), 1, gomock.Any()).Return(nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.Equal(t, domain.LoginReasonInvalidPassword, attempt.Reason)
+					return nil
+				})
+			},
+			wantErr:   errors.New("invalid login or password"),
+			wantDelay: loginDelay(MaxFailedLogins),
+		},
+		{
+			name:     "expired lock and success reset counter",
+			password: "password123",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				d.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{ID: 1, Login: "drsmith", Password: mustHashPassword(t, "password123", passwordHashCost), FailedLoginAttempts: 5, LockedUntil: &past}, nil)
+				d.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.True(t, attempt.Success)
+					assert.Empty(t, attempt.Reason)
+					assert.Equal(t, "10.0.0.1", attempt.IP)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "lock expired, one bad password does not lock again",
+			password: "wrongpassword",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				d.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{ID: 1, Login: "drsmith", Password: mustHashPassword(t, "password123", passwordHashCost), FailedLoginAttempts: MaxFailedLogins, LockedUntil: &past}, nil)
+				d.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.Equal(t, domain.LoginReasonInvalidPassword, attempt.Reason)
+					return nil
+				})
+			},
+			wantErr: errors.New("invalid login or password"),
+		},
+		{
+			name:     "audit failure does not block login",
+			password: "password123",
+			setup: func(d *repository.MockDoctorRepository, a *repository.MockLoginAttemptRepository) {
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				d.EXPECT().GetByLogin(gomock.Any(), "drsmith").Return(&domain.Doctor{ID: 1, Login: "drsmith", Password: mustHashPassword(t, "password123", passwordHashCost)}, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockDoctorRepository(ctrl)
+			mockAttemptRepo := repository.NewMockLoginAttemptRepository(ctrl)
+			tt.setup(mockRepo, mockAttemptRepo)
+			uc := NewDoctorUseCase(mockRepo, mockAttemptRepo)
+			var delay time.Duration
+			uc.sleep = func(d time.Duration) { delay += d }
+
+			doctor, err := uc.AuthenticateDoctor(context.Background(), "drsmith", tt.password, "10.0.0.1")
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				assert.Nil(t, doctor)
+			} else {
+				require.NoError(t, err)
+				assert.Zero(t, doctor.FailedLoginAttempts)
+				assert.Nil(t, doctor.LockedUntil)
+			}
+			assert.Equal(t, tt.wantDelay, delay)
+		})
+	}
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 0, want: 0},
+		{failures: 1, want: 0},
+		{failures: 2, want: 500 * time.Millisecond},
+		{failures: 3, want: time.Second},
+		{failures: 5, want: 4 * time.Second},
+		{failures: 6, want: 8 * time.Second},
+		{failures: 50, want: 8 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, loginDelay(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestDoctorUseCase_UnlockDoctor(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		setup   func(*repository.MockDoctorRepository)
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Doctor{ID: 1}, nil)
+				m.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name: "not found",
+			id:   9,
+			setup: func(m *repository.MockDoctorRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 9).Return(nil, nil)
+			},
+			wantErr: domain.ErrDoctorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
+
+			err := uc.UnlockDoctor(context.Background(), tt.id)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestDoctorUseCase_ValidateDoctor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := repository.NewMockDoctorRepository(ctrl)
-	uc := NewDoctorUseCase(mockRepo)
+	uc := NewDoctorUseCase(mockRepo, repository.NewMockLoginAttemptRepository(ctrl))
 
 	tests := []struct {
 		name    string
@@ -587,6 +984,27 @@ func TestDoctorUseCase_ValidateDoctor(t *testing.T) {
 			wantErr: true,
 			errMsg:  "doctor password is too short",
 		},
+		{
+			name: "valid receptionist role",
+			doctor: &domain.Doctor{
+				Name:     "Reception",
+				Login:    "reception",
+				Password: "password",
+				Role:     domain.RoleReceptionist,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid role",
+			doctor: &domain.Doctor{
+				Name:     "Dr. Smith",
+				Login:    "drsmith",
+				Password: "password",
+				Role:     "superuser",
+			},
+			wantErr: true,
+			errMsg:  "invalid doctor role",
+		},
 		{
 			name: "password exactly 4 chars",
 			doctor: &domain.Doctor{
@@ -612,3 +1030,9 @@ func TestDoctorUseCase_ValidateDoctor(t *testing.T) {
 	}
 }
 
+
+func mustHashPassword(t *testing.T, password string, cost int) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	require.NoError(t, err)
+	return string(hash)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// validateSort проверяет поле сортировки по белому списку; пустое поле означает сортировку по умолчанию
+func validateSort(sort domain.Sort, fields []string) error {
+	if sort.Field != "" && !slices.Contains(fields, sort.Field) {
+		message := fmt.Sprintf("invalid sort field %q, allowed: %s", sort.Field, strings.Join(fields, ", "))
+		return domain.NewValidationError("sort", domain.FieldInvalid, message)
+	}
+	return nil
+}
+
+// normalizePage проверяет страницу и приводит ее размер к допустимому: 0 заменяется defaultLimit,
+// слишком большой размер ограничивается maxLimit
+func normalizePage(page *domain.Page, defaultLimit, maxLimit int) error {
+	if page.Limit < 0 {
+		return domain.NewValidationError("limit", domain.FieldNegative, "limit must not be negative")
+	}
+	if page.Offset < 0 {
+		return domain.NewValidationError("offset", domain.FieldNegative, "offset must not be negative")
+	}
+	if page.Limit == 0 {
+		page.Limit = defaultLimit
+	}
+	if page.Limit > maxLimit {
+		page.Limit = maxLimit
+	}
+	return nil
+}
+
+// normalizeList проверяет сортировку и страницу списка с ограничениями по умолчанию
+func normalizeList(sort domain.Sort, page *domain.Page, fields []string) error {
+	if err := validateSort(sort, fields); err != nil {
+		return err
+	}
+	return normalizePage(page, domain.DefaultPageLimit, domain.MaxPageLimit)
+}
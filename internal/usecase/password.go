@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordHashCost задает стоимость bcrypt для новых хешей
+const passwordHashCost = bcrypt.DefaultCost
+
+// dummyPasswordHash используется при отсутствии врача, чтобы время ответа не выдавало существование логина
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("crmstom-dummy-password"), passwordHashCost)
+
+// hashPassword возвращает bcrypt-хеш пароля
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// isPasswordHash сообщает, является ли сохраненное значение bcrypt-хешем
+func isPasswordHash(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// checkPassword сравнивает пароль с сохраненным значением за постоянное время.
+// needsRehash равен true, если значение хранится открытым текстом или со стоимостью ниже текущей.
+func checkPassword(stored, password string) (ok bool, needsRehash bool) {
+	if !isPasswordHash(stored) {
+		ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return ok, ok
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err == nil && cost < passwordHashCost
+}
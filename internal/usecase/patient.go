@@ -1,7 +1,7 @@
 package usecase
 
 import (
-	"errors"
+	"context"
 	"strings"
 	"time"
 
@@ -10,135 +10,150 @@ import (
 
 type PatientUseCase struct {
 	patientRepo domain.PatientRepository
+	uow         domain.UnitOfWork
 }
 
-func NewPatientUseCase(patientRepo domain.PatientRepository) *PatientUseCase {
+func NewPatientUseCase(patientRepo domain.PatientRepository, uow domain.UnitOfWork) *PatientUseCase {
 	return &PatientUseCase{
 		patientRepo: patientRepo,
+		uow:         uow,
 	}
 }
 
 // GetPatient получает пациента по ID
-func (u *PatientUseCase) GetPatient(id int) (*domain.Patient, error) {
+func (u *PatientUseCase) GetPatient(ctx context.Context, id int) (*domain.Patient, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid patient ID")
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid patient ID")
 	}
-	return u.patientRepo.GetByID(id)
+	return u.patientRepo.GetByID(ctx, id)
 }
 
 // GetAllPatients получает всех пациентов
-func (u *PatientUseCase) GetAllPatients() ([]*domain.Patient, error) {
-	return u.patientRepo.GetAll()
+func (u *PatientUseCase) GetAllPatients(ctx context.Context) ([]*domain.Patient, error) {
+	return u.patientRepo.GetAll(ctx)
 }
 
-// CreatePatient создает нового пациента
-func (u *PatientUseCase) CreatePatient(patient *domain.Patient) error {
+// ListPatients получает страницу пациентов по фильтру и общее число подходящих пациентов
+func (u *PatientUseCase) ListPatients(ctx context.Context, filter domain.PatientFilter) ([]*domain.Patient, int, error) {
+	if err := normalizeList(filter.Sort, &filter.Page, domain.PatientSortFields); err != nil {
+		return nil, 0, err
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	return u.patientRepo.List(ctx, filter)
+}
+
+// CreatePatient создает нового пациента; проверка дубликатов и вставка выполняются в одной транзакции
+func (u *PatientUseCase) CreatePatient(ctx context.Context, patient *domain.Patient) error {
 	if err := u.ValidatePatient(patient); err != nil {
 		return err
 	}
 
-	// Проверяем, не существует ли уже пациент с таким ИИН
-	if patient.IIN != "" {
-		existingPatient, err := u.patientRepo.GetByIIN(patient.IIN)
-		if err == nil && existingPatient != nil {
-			return errors.New("пациент с таким ИИН уже существует")
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// Проверяем, не существует ли уже пациент с таким ИИН
+		if patient.IIN != "" {
+			existingPatient, err := u.patientRepo.GetByIIN(ctx, patient.IIN)
+			if err == nil && existingPatient != nil {
+				return domain.ErrPatientIINExists
+			}
 		}
-	}
 
-	// Проверяем, не существует ли уже пациент с таким телефоном
-	if patient.Phone != "" {
-		existingPatient, err := u.patientRepo.GetByPhone(patient.Phone)
-		if err == nil && existingPatient != nil {
-			return errors.New("пациент с таким номером телефона уже существует")
+		// Проверяем, не существует ли уже пациент с таким телефоном
+		if patient.Phone != "" {
+			existingPatient, err := u.patientRepo.GetByPhone(ctx, patient.Phone)
+			if err == nil && existingPatient != nil {
+				return domain.ErrPatientPhoneExists
+			}
 		}
-	}
 
-	patient.CreatedAt = time.Now()
-	patient.UpdatedAt = time.Now()
+		patient.CreatedAt = time.Now()
+		patient.UpdatedAt = time.Now()
 
-	return u.patientRepo.Create(patient)
+		return u.patientRepo.Create(ctx, patient)
+	})
 }
 
-// UpdatePatient обновляет пациента
-func (u *PatientUseCase) UpdatePatient(patient *domain.Patient) error {
+// UpdatePatient обновляет пациента; проверка дубликатов и изменение выполняются в одной транзакции
+func (u *PatientUseCase) UpdatePatient(ctx context.Context, patient *domain.Patient) error {
 	if err := u.ValidatePatient(patient); err != nil {
 		return err
 	}
 
-	// Проверяем, не существует ли уже другой пациент с таким ИИН
-	if patient.IIN != "" {
-		existingPatient, err := u.patientRepo.GetByIIN(patient.IIN)
-		if err == nil && existingPatient != nil && existingPatient.ID != patient.ID {
-			return errors.New("пациент с таким ИИН уже существует")
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// Проверяем, не существует ли уже другой пациент с таким ИИН
+		if patient.IIN != "" {
+			existingPatient, err := u.patientRepo.GetByIIN(ctx, patient.IIN)
+			if err == nil && existingPatient != nil && existingPatient.ID != patient.ID {
+				return domain.ErrPatientIINExists
+			}
 		}
-	}
 
-	// Проверяем, не существует ли уже другой пациент с таким телефоном
-	if patient.Phone != "" {
-		existingPatient, err := u.patientRepo.GetByPhone(patient.Phone)
-		if err == nil && existingPatient != nil && existingPatient.ID != patient.ID {
-			return errors.New("пациент с таким номером телефона уже существует")
+		// Проверяем, не существует ли уже другой пациент с таким телефоном
+		if patient.Phone != "" {
+			existingPatient, err := u.patientRepo.GetByPhone(ctx, patient.Phone)
+			if err == nil && existingPatient != nil && existingPatient.ID != patient.ID {
+				return domain.ErrPatientPhoneExists
+			}
 		}
-	}
 
-	patient.UpdatedAt = time.Now()
+		patient.UpdatedAt = time.Now()
 
-	return u.patientRepo.Update(patient)
+		return u.patientRepo.Update(ctx, patient)
+	})
 }
 
 // DeletePatient удаляет пациента
-func (u *PatientUseCase) DeletePatient(id int) error {
+func (u *PatientUseCase) DeletePatient(ctx context.Context, id int) error {
 	if id <= 0 {
-		return errors.New("invalid patient ID")
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid patient ID")
 	}
-	return u.patientRepo.Delete(id)
+	return u.patientRepo.Delete(ctx, id)
 }
 
 // SearchPatients ищет пациентов по запросу
-func (u *PatientUseCase) SearchPatients(query string) ([]*domain.Patient, error) {
+func (u *PatientUseCase) SearchPatients(ctx context.Context, query string) ([]*domain.Patient, error) {
 	if strings.TrimSpace(query) == "" {
-		return u.patientRepo.GetAll()
+		return u.patientRepo.GetAll(ctx)
 	}
-	return u.patientRepo.Search(query)
+	return u.patientRepo.Search(ctx, query)
 }
 
 // ValidatePatient валидирует данные пациента
 func (u *PatientUseCase) ValidatePatient(patient *domain.Patient) error {
 	if patient == nil {
-		return errors.New("patient cannot be nil")
+		return domain.NewValidationError("", domain.FieldRequired, "patient cannot be nil")
 	}
 
 	if strings.TrimSpace(patient.Name) == "" {
-		return errors.New("patient name is required")
+		return domain.NewValidationError("name", domain.FieldRequired, "patient name is required")
 	}
 
 	if len(patient.Name) > 100 {
-		return errors.New("patient name is too long")
+		return domain.NewValidationError("name", domain.FieldTooLong, "patient name is too long")
 	}
 
 	if patient.IIN != "" && len(patient.IIN) != 12 {
-		return errors.New("ИИН должен содержать 12 символов")
+		return domain.NewValidationError("iin", domain.FieldInvalid, "ИИН должен содержать 12 символов")
 	}
 
 	if patient.Phone != "" && len(patient.Phone) > 20 {
-		return errors.New("phone number is too long")
+		return domain.NewValidationError("phone", domain.FieldTooLong, "phone number is too long")
 	}
 
 	if patient.Email != "" {
 		if len(patient.Email) > 100 {
-			return errors.New("email is too long")
+			return domain.NewValidationError("email", domain.FieldTooLong, "email is too long")
 		}
 		if !strings.Contains(patient.Email, "@") {
-			return errors.New("invalid email format")
+			return domain.NewValidationError("email", domain.FieldInvalid, "invalid email format")
 		}
 	}
 
 	if patient.Address != "" && len(patient.Address) > 200 {
-		return errors.New("address is too long")
+		return domain.NewValidationError("address", domain.FieldTooLong, "address is too long")
 	}
 
 	if patient.Notes != "" && len(patient.Notes) > 500 {
-		return errors.New("notes are too long")
+		return domain.NewValidationError("notes", domain.FieldTooLong, "notes are too long")
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -24,7 +25,7 @@ func TestPatientUseCase_GetPatient(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1, Name: "John Doe"}, nil)
 			},
 			wantErr: false,
 		},
@@ -46,7 +47,7 @@ func TestPatientUseCase_GetPatient(t *testing.T) {
 			name: "patient not found",
 			id:   999,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("patient not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("patient not found"))
 			},
 			wantErr: true,
 			errMsg:  "patient not found",
@@ -55,7 +56,7 @@ func TestPatientUseCase_GetPatient(t *testing.T) {
 			name: "repository error",
 			id:   1,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByID(1).Return(nil, errors.New("database error"))
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -69,9 +70,9 @@ func TestPatientUseCase_GetPatient(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			patient, err := uc.GetPatient(tt.id)
+			patient, err := uc.GetPatient(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -96,7 +97,7 @@ func TestPatientUseCase_GetAllPatients(t *testing.T) {
 		{
 			name: "success with patients",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Patient{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{
 					{ID: 1, Name: "John"},
 					{ID: 2, Name: "Jane"},
 				}, nil)
@@ -107,7 +108,7 @@ func TestPatientUseCase_GetAllPatients(t *testing.T) {
 		{
 			name: "success empty list",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Patient{}, nil)
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -115,7 +116,7 @@ func TestPatientUseCase_GetAllPatients(t *testing.T) {
 		{
 			name: "repository error",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				m.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			want:    0,
 			wantErr: true,
@@ -129,9 +130,9 @@ func TestPatientUseCase_GetAllPatients(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			patients, err := uc.GetAllPatients()
+			patients, err := uc.GetAllPatients(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -143,6 +144,84 @@ func TestPatientUseCase_GetAllPatients(t *testing.T) {
 	}
 }
 
+func TestPatientUseCase_ListPatients(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    domain.PatientFilter
+		setup     func(*repository.MockPatientRepository)
+		wantTotal int
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:   "default page",
+			filter: domain.PatientFilter{Query: "  Иван "},
+			setup: func(m *repository.MockPatientRepository) {
+				m.EXPECT().List(gomock.Any(), domain.PatientFilter{Query: "Иван", Page: domain.Page{Limit: domain.DefaultPageLimit}}).
+					Return([]*domain.Patient{{ID: 1}}, 1, nil)
+			},
+			wantTotal: 1,
+		},
+		{
+			name:   "sort and capped limit",
+			filter: domain.PatientFilter{Sort: domain.Sort{Field: "last_visit", Desc: true}, Page: domain.Page{Limit: 5000, Offset: 200}},
+			setup: func(m *repository.MockPatientRepository) {
+				m.EXPECT().List(gomock.Any(), domain.PatientFilter{
+					Sort: domain.Sort{Field: "last_visit", Desc: true},
+					Page: domain.Page{Limit: domain.MaxPageLimit, Offset: 200},
+				}).Return([]*domain.Patient{}, 150, nil)
+			},
+			wantTotal: 150,
+		},
+		{
+			name:    "unknown sort field",
+			filter:  domain.PatientFilter{Sort: domain.Sort{Field: "password"}},
+			setup:   func(m *repository.MockPatientRepository) {},
+			wantErr: true,
+			errMsg:  "invalid sort field",
+		},
+		{
+			name:    "negative offset",
+			filter:  domain.PatientFilter{Page: domain.Page{Offset: -1}},
+			setup:   func(m *repository.MockPatientRepository) {},
+			wantErr: true,
+			errMsg:  "offset must not be negative",
+		},
+		{
+			name:   "repository error",
+			filter: domain.PatientFilter{},
+			setup: func(m *repository.MockPatientRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockPatientRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
+
+			patients, total, err := uc.ListPatients(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, patients)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
 func TestPatientUseCase_CreatePatient(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -159,8 +238,8 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				Email: "john@example.com",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByPhone("+1234567890").Return(nil, errors.New("not found"))
-				m.EXPECT().Create(gomock.Any()).Return(nil)
+				m.EXPECT().GetByPhone(gomock.Any(), "+1234567890").Return(nil, errors.New("not found"))
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -170,7 +249,7 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				Name: "John Doe",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -285,8 +364,8 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				IIN:  "123456789012",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByIIN("123456789012").Return(nil, errors.New("not found"))
-				m.EXPECT().Create(gomock.Any()).Return(nil)
+				m.EXPECT().GetByIIN(gomock.Any(), "123456789012").Return(nil, errors.New("not found"))
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -297,7 +376,7 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				IIN:  "123456789012",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByIIN("123456789012").Return(&domain.Patient{ID: 1, IIN: "123456789012"}, nil)
+				m.EXPECT().GetByIIN(gomock.Any(), "123456789012").Return(&domain.Patient{ID: 1, IIN: "123456789012"}, nil)
 			},
 			wantErr: true,
 			errMsg:  "пациент с таким ИИН уже существует",
@@ -309,7 +388,7 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				Phone: "+1234567890",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByPhone("+1234567890").Return(&domain.Patient{ID: 1, Phone: "+1234567890"}, nil)
+				m.EXPECT().GetByPhone(gomock.Any(), "+1234567890").Return(&domain.Patient{ID: 1, Phone: "+1234567890"}, nil)
 			},
 			wantErr: true,
 			errMsg:  "пациент с таким номером телефона уже существует",
@@ -320,7 +399,7 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 				Name: "John Doe",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -334,9 +413,9 @@ func TestPatientUseCase_CreatePatient(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			err := uc.CreatePatient(tt.patient)
+			err := uc.CreatePatient(context.Background(), tt.patient)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -366,8 +445,8 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				Phone: "+1234567890",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByPhone("+1234567890").Return(&domain.Patient{ID: 1, Phone: "+1234567890"}, nil)
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().GetByPhone(gomock.Any(), "+1234567890").Return(&domain.Patient{ID: 1, Phone: "+1234567890"}, nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -379,8 +458,8 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				Phone: "+9999999999",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByPhone("+9999999999").Return(nil, errors.New("not found"))
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().GetByPhone(gomock.Any(), "+9999999999").Return(nil, errors.New("not found"))
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -392,7 +471,7 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				Phone: "+9999999999",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByPhone("+9999999999").Return(&domain.Patient{ID: 2, Phone: "+9999999999"}, nil)
+				m.EXPECT().GetByPhone(gomock.Any(), "+9999999999").Return(&domain.Patient{ID: 2, Phone: "+9999999999"}, nil)
 			},
 			wantErr: true,
 			errMsg:  "пациент с таким номером телефона уже существует",
@@ -405,8 +484,8 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				IIN:  "123456789012",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByIIN("123456789012").Return(&domain.Patient{ID: 1, IIN: "123456789012"}, nil)
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().GetByIIN(gomock.Any(), "123456789012").Return(&domain.Patient{ID: 1, IIN: "123456789012"}, nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -418,8 +497,8 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				IIN:  "999999999999",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByIIN("999999999999").Return(nil, errors.New("not found"))
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().GetByIIN(gomock.Any(), "999999999999").Return(nil, errors.New("not found"))
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -431,7 +510,7 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				IIN:  "999999999999",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetByIIN("999999999999").Return(&domain.Patient{ID: 2, IIN: "999999999999"}, nil)
+				m.EXPECT().GetByIIN(gomock.Any(), "999999999999").Return(&domain.Patient{ID: 2, IIN: "999999999999"}, nil)
 			},
 			wantErr: true,
 			errMsg:  "пациент с таким ИИН уже существует",
@@ -443,7 +522,7 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 				Name: "John",
 			},
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Update(gomock.Any()).Return(errors.New("patient not found"))
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("patient not found"))
 			},
 			wantErr: true,
 			errMsg:  "patient not found",
@@ -464,9 +543,9 @@ func TestPatientUseCase_UpdatePatient(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			err := uc.UpdatePatient(tt.patient)
+			err := uc.UpdatePatient(context.Background(), tt.patient)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -491,7 +570,7 @@ func TestPatientUseCase_DeletePatient(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Delete(1).Return(nil)
+				m.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -513,7 +592,7 @@ func TestPatientUseCase_DeletePatient(t *testing.T) {
 			name: "patient not found",
 			id:   999,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Delete(999).Return(errors.New("patient not found"))
+				m.EXPECT().Delete(gomock.Any(), 999).Return(errors.New("patient not found"))
 			},
 			wantErr: true,
 			errMsg:  "patient not found",
@@ -522,7 +601,7 @@ func TestPatientUseCase_DeletePatient(t *testing.T) {
 			name: "repository error",
 			id:   1,
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Delete(1).Return(errors.New("database error"))
+				m.EXPECT().Delete(gomock.Any(), 1).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -536,9 +615,9 @@ func TestPatientUseCase_DeletePatient(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			err := uc.DeletePatient(tt.id)
+			err := uc.DeletePatient(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -562,7 +641,7 @@ func TestPatientUseCase_SearchPatients(t *testing.T) {
 			name:  "search with query",
 			query: "john",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Search("john").Return([]*domain.Patient{{ID: 1, Name: "John Doe"}}, nil)
+				m.EXPECT().Search(gomock.Any(), "john").Return([]*domain.Patient{{ID: 1, Name: "John Doe"}}, nil)
 			},
 			want:    1,
 			wantErr: false,
@@ -571,7 +650,7 @@ func TestPatientUseCase_SearchPatients(t *testing.T) {
 			name:  "empty query returns all",
 			query: "",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Patient{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{
 					{ID: 1, Name: "John"},
 					{ID: 2, Name: "Jane"},
 				}, nil)
@@ -583,7 +662,7 @@ func TestPatientUseCase_SearchPatients(t *testing.T) {
 			name:  "whitespace query returns all",
 			query: "   ",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Patient{{ID: 1, Name: "John"}}, nil)
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Patient{{ID: 1, Name: "John"}}, nil)
 			},
 			want:    1,
 			wantErr: false,
@@ -592,7 +671,7 @@ func TestPatientUseCase_SearchPatients(t *testing.T) {
 			name:  "search error",
 			query: "test",
 			setup: func(m *repository.MockPatientRepository) {
-				m.EXPECT().Search("test").Return(nil, errors.New("search failed"))
+				m.EXPECT().Search(gomock.Any(), "test").Return(nil, errors.New("search failed"))
 			},
 			want:    0,
 			wantErr: true,
@@ -606,9 +685,9 @@ func TestPatientUseCase_SearchPatients(t *testing.T) {
 
 			mockRepo := repository.NewMockPatientRepository(ctrl)
 			tt.setup(mockRepo)
-			uc := NewPatientUseCase(mockRepo)
+			uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
-			patients, err := uc.SearchPatients(tt.query)
+			patients, err := uc.SearchPatients(context.Background(), tt.query)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -625,7 +704,7 @@ func TestPatientUseCase_ValidatePatient(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repository.NewMockPatientRepository(ctrl)
-	uc := NewPatientUseCase(mockRepo)
+	uc := NewPatientUseCase(mockRepo, newTestUnitOfWork(ctrl))
 
 	tests := []struct {
 		name    string
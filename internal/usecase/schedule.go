@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Рабочее время по умолчанию для врачей без настроенного недельного графика
+const (
+	defaultWorkdayStart = "09:00"
+	defaultWorkdayEnd   = "18:00"
+)
+
+type ScheduleUseCase struct {
+	scheduleRepo domain.ScheduleRepository
+	doctorRepo   domain.DoctorRepository
+}
+
+func NewScheduleUseCase(scheduleRepo domain.ScheduleRepository, doctorRepo domain.DoctorRepository) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		doctorRepo:   doctorRepo,
+	}
+}
+
+// GetDoctorSchedule получает график врача и рабочие интервалы в периоде [from, to].
+// Без права на просмотр всех графиков врач видит только собственный.
+func (u *ScheduleUseCase) GetDoctorSchedule(ctx context.Context, actor *domain.Doctor, doctorID int, from, to time.Time) (*domain.DoctorSchedule, error) {
+	if !canViewSchedule(actor, doctorID) {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := u.ensureDoctor(ctx, doctorID); err != nil {
+		return nil, err
+	}
+
+	if to.Before(from) {
+		return nil, domain.NewValidationError("date_to", domain.FieldRange, "date to must not be before date from")
+	}
+
+	hours, err := u.scheduleRepo.GetWorkingHours(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks, err := u.scheduleRepo.GetBreaks(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := u.scheduleRepo.GetExceptions(ctx, doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := u.scheduleRepo.GetHolidays(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var availability []domain.TimeSlot
+	for day := startOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		availability = append(availability, dayIntervals(day, hours, breaks, exceptions, holidays)...)
+	}
+
+	return &domain.DoctorSchedule{
+		DoctorID:     doctorID,
+		WorkingHours: hours,
+		Breaks:       breaks,
+		Exceptions:   exceptions,
+		Holidays:     holidays,
+		Availability: availability,
+	}, nil
+}
+
+// UpdateWeeklySchedule заменяет недельный шаблон рабочих часов и перерывов врача
+func (u *ScheduleUseCase) UpdateWeeklySchedule(ctx context.Context, doctorID int, hours []*domain.WorkingHours, breaks []*domain.ScheduleBreak) error {
+	if err := u.ensureDoctor(ctx, doctorID); err != nil {
+		return err
+	}
+
+	for _, h := range hours {
+		if err := validateWeeklyInterval(h.Weekday, h.StartTime, h.EndTime); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range breaks {
+		if err := validateWeeklyInterval(b.Weekday, b.StartTime, b.EndTime); err != nil {
+			return err
+		}
+		if len(b.Title) > 255 {
+			return domain.NewValidationError("title", domain.FieldTooLong, "break title is too long")
+		}
+	}
+
+	return u.scheduleRepo.ReplaceWeeklySchedule(ctx, doctorID, hours, breaks)
+}
+
+// AddException добавляет исключение из графика врача (выходной, отпуск, больничный, иные часы)
+func (u *ScheduleUseCase) AddException(ctx context.Context, exception *domain.ScheduleException) error {
+	if exception == nil {
+		return domain.NewValidationError("", domain.FieldRequired, "schedule exception cannot be nil")
+	}
+
+	if err := u.ensureDoctor(ctx, exception.DoctorID); err != nil {
+		return err
+	}
+
+	switch exception.Type {
+	case domain.ExceptionDayOff, domain.ExceptionVacation, domain.ExceptionSickLeave:
+		exception.StartTime = ""
+		exception.EndTime = ""
+	case domain.ExceptionCustomHours:
+		if _, _, err := parseInterval(exception.StartTime, exception.EndTime); err != nil {
+			return err
+		}
+	default:
+		return domain.NewValidationError("type", domain.FieldInvalid, "invalid schedule exception type")
+	}
+
+	if exception.DateFrom.IsZero() {
+		return domain.NewValidationError("date_from", domain.FieldRequired, "date from is required")
+	}
+	if exception.DateTo.IsZero() {
+		exception.DateTo = exception.DateFrom
+	}
+	if exception.DateTo.Before(exception.DateFrom) {
+		return domain.NewValidationError("date_to", domain.FieldRange, "date to must not be before date from")
+	}
+
+	if len(exception.Reason) > 500 {
+		return domain.NewValidationError("reason", domain.FieldTooLong, "reason is too long")
+	}
+
+	return u.scheduleRepo.CreateException(ctx, exception)
+}
+
+// DeleteException удаляет исключение из графика врача
+func (u *ScheduleUseCase) DeleteException(ctx context.Context, doctorID, id int) error {
+	if doctorID <= 0 {
+		return domain.NewValidationError("doctor_id", domain.FieldInvalid, "invalid doctor ID")
+	}
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid schedule exception ID")
+	}
+	return u.scheduleRepo.DeleteException(ctx, doctorID, id)
+}
+
+// GetHolidays получает праздничные дни клиники в периоде [from, to]
+func (u *ScheduleUseCase) GetHolidays(ctx context.Context, from, to time.Time) ([]*domain.ClinicHoliday, error) {
+	if to.Before(from) {
+		return nil, domain.NewValidationError("date_to", domain.FieldRange, "date to must not be before date from")
+	}
+	return u.scheduleRepo.GetHolidays(ctx, from, to)
+}
+
+// AddHoliday добавляет праздничный день клиники
+func (u *ScheduleUseCase) AddHoliday(ctx context.Context, holiday *domain.ClinicHoliday) error {
+	if holiday == nil {
+		return domain.NewValidationError("", domain.FieldRequired, "holiday cannot be nil")
+	}
+
+	if holiday.Date.IsZero() {
+		return domain.NewValidationError("date", domain.FieldRequired, "holiday date is required")
+	}
+
+	if strings.TrimSpace(holiday.Name) == "" {
+		return domain.NewValidationError("name", domain.FieldRequired, "holiday name is required")
+	}
+
+	if len(holiday.Name) > 255 {
+		return domain.NewValidationError("name", domain.FieldTooLong, "holiday name is too long")
+	}
+
+	return u.scheduleRepo.CreateHoliday(ctx, holiday)
+}
+
+// DeleteHoliday удаляет праздничный день клиники
+func (u *ScheduleUseCase) DeleteHoliday(ctx context.Context, id int) error {
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid holiday ID")
+	}
+	return u.scheduleRepo.DeleteHoliday(ctx, id)
+}
+
+// canViewSchedule проверяет право пользователя на просмотр графика врача
+func canViewSchedule(actor *domain.Doctor, doctorID int) bool {
+	if actor == nil || !actor.Can(domain.PermScheduleRead) {
+		return false
+	}
+	return actor.ID == doctorID || actor.Can(domain.PermScheduleReadAll)
+}
+
+// ensureDoctor проверяет, что врач существует
+func (u *ScheduleUseCase) ensureDoctor(ctx context.Context, doctorID int) error {
+	if doctorID <= 0 {
+		return domain.NewValidationError("doctor_id", domain.FieldInvalid, "invalid doctor ID")
+	}
+
+	doctor, err := u.doctorRepo.GetByID(ctx, doctorID)
+	if err != nil {
+		return err
+	}
+	if doctor == nil {
+		return domain.ErrDoctorNotFound
+	}
+
+	return nil
+}
+
+// workingIntervals рассчитывает рабочие интервалы врача для каждого дня в периоде [from, to)
+func workingIntervals(ctx context.Context, repo domain.ScheduleRepository, doctorID int, from, to time.Time) ([]domain.TimeSlot, error) {
+	hours, err := repo.GetWorkingHours(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks, err := repo.GetBreaks(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := repo.GetExceptions(ctx, doctorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := repo.GetHolidays(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var intervals []domain.TimeSlot
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		intervals = append(intervals, dayIntervals(day, hours, breaks, exceptions, holidays)...)
+	}
+
+	return intervals, nil
+}
+
+// dayIntervals рассчитывает рабочие интервалы врача в конкретный день.
+// Праздник, выходной, отпуск и больничный закрывают день целиком; custom_hours заменяет недельный шаблон;
+// врач без шаблона работает по часам клиники по умолчанию. Перерывы вычитаются из результата.
+func dayIntervals(
+	day time.Time,
+	hours []*domain.WorkingHours,
+	breaks []*domain.ScheduleBreak,
+	exceptions []*domain.ScheduleException,
+	holidays []*domain.ClinicHoliday,
+) []domain.TimeSlot {
+	key := day.Format("2006-01-02")
+
+	for _, h := range holidays {
+		if h.Date.Format("2006-01-02") == key {
+			return nil
+		}
+	}
+
+	var intervals []domain.TimeSlot
+	customHours := false
+	for _, e := range exceptions {
+		if e.DateFrom.Format("2006-01-02") > key || e.DateTo.Format("2006-01-02") < key {
+			continue
+		}
+		if e.Type != domain.ExceptionCustomHours {
+			return nil
+		}
+		customHours = true
+		intervals = append(intervals, clockInterval(day, e.StartTime, e.EndTime))
+	}
+
+	if !customHours {
+		if len(hours) == 0 {
+			intervals = append(intervals, clockInterval(day, defaultWorkdayStart, defaultWorkdayEnd))
+		}
+		for _, h := range hours {
+			if h.Weekday == day.Weekday() {
+				intervals = append(intervals, clockInterval(day, h.StartTime, h.EndTime))
+			}
+		}
+	}
+
+	for _, b := range breaks {
+		if b.Weekday == day.Weekday() {
+			intervals = subtractInterval(intervals, clockInterval(day, b.StartTime, b.EndTime))
+		}
+	}
+
+	return intervals
+}
+
+// subtractInterval вычитает интервал cut из каждого интервала списка
+func subtractInterval(intervals []domain.TimeSlot, cut domain.TimeSlot) []domain.TimeSlot {
+	result := make([]domain.TimeSlot, 0, len(intervals))
+	for _, interval := range intervals {
+		if !cut.Start.Before(interval.End) || !cut.End.After(interval.Start) {
+			result = append(result, interval)
+			continue
+		}
+		if interval.Start.Before(cut.Start) {
+			result = append(result, domain.TimeSlot{Start: interval.Start, End: cut.Start})
+		}
+		if cut.End.Before(interval.End) {
+			result = append(result, domain.TimeSlot{Start: cut.End, End: interval.End})
+		}
+	}
+	return result
+}
+
+// coversInterval проверяет, что интервал [start, end) целиком лежит в одном из рабочих интервалов
+func coversInterval(intervals []domain.TimeSlot, start, end time.Time) bool {
+	for _, interval := range intervals {
+		if !start.Before(interval.Start) && !end.After(interval.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// clockInterval строит интервал дня по времени HH:MM; значения проверяются при сохранении графика
+func clockInterval(day time.Time, start, end string) domain.TimeSlot {
+	from, to, _ := parseInterval(start, end)
+	return domain.TimeSlot{Start: atClock(day, from), End: atClock(day, to)}
+}
+
+// atClock возвращает момент дня по смещению HH:MM в часовом поясе дня; в отличие от Add учитывает переход на летнее время
+func atClock(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(clock/time.Minute), 0, 0, day.Location())
+}
+
+// parseInterval разбирает интервал HH:MM–HH:MM в смещения от начала суток
+func parseInterval(start, end string) (time.Duration, time.Duration, error) {
+	from, err := parseClock(start)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := parseClock(end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if from >= to {
+		return 0, 0, domain.NewValidationError("end_time", domain.FieldRange, "start time must be before end time")
+	}
+
+	return from, to, nil
+}
+
+// parseClock разбирает время HH:MM в смещение от начала суток
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, domain.NewValidationError("time", domain.FieldInvalid, "invalid time format, expected HH:MM")
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// validateWeeklyInterval проверяет день недели и интервал шаблона графика
+func validateWeeklyInterval(weekday time.Weekday, start, end string) error {
+	if weekday < time.Sunday || weekday > time.Saturday {
+		return domain.NewValidationError("weekday", domain.FieldInvalid, "invalid weekday")
+	}
+	_, _, err := parseInterval(start, end)
+	return err
+}
@@ -0,0 +1,406 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduleUseCase_GetDoctorSchedule(t *testing.T) {
+	monday := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+	sunday := monday.AddDate(0, 0, 6)
+
+	tests := []struct {
+		name             string
+		actor            *domain.Doctor
+		doctorID         int
+		from             time.Time
+		to               time.Time
+		setup            func(*repository.MockScheduleRepository, *repository.MockDoctorRepository)
+		wantAvailability []domain.TimeSlot
+		wantErr          bool
+		errMsg           string
+	}{
+		{
+			name:     "weekly template with break and day off",
+			doctorID: 2,
+			from:     monday,
+			to:       sunday,
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().GetWorkingHours(gomock.Any(), 2).Return([]*domain.WorkingHours{
+					{DoctorID: 2, Weekday: time.Monday, StartTime: "09:00", EndTime: "17:00"},
+					{DoctorID: 2, Weekday: time.Wednesday, StartTime: "12:00", EndTime: "20:00"},
+				}, nil)
+				s.EXPECT().GetBreaks(gomock.Any(), 2).Return([]*domain.ScheduleBreak{
+					{DoctorID: 2, Weekday: time.Monday, StartTime: "13:00", EndTime: "14:00"},
+				}, nil)
+				s.EXPECT().GetExceptions(gomock.Any(), 2, monday, sunday).Return([]*domain.ScheduleException{
+					{DoctorID: 2, Type: domain.ExceptionDayOff, DateFrom: monday.AddDate(0, 0, 2), DateTo: monday.AddDate(0, 0, 2)},
+				}, nil)
+				s.EXPECT().GetHolidays(gomock.Any(), monday, sunday).Return(nil, nil)
+			},
+			wantAvailability: []domain.TimeSlot{
+				{Start: monday.Add(9 * time.Hour), End: monday.Add(13 * time.Hour)},
+				{Start: monday.Add(14 * time.Hour), End: monday.Add(17 * time.Hour)},
+			},
+		},
+		{
+			name:     "default hours without template",
+			doctorID: 2,
+			from:     monday,
+			to:       monday,
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().GetWorkingHours(gomock.Any(), 2).Return(nil, nil)
+				s.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+				s.EXPECT().GetExceptions(gomock.Any(), 2, monday, monday).Return(nil, nil)
+				s.EXPECT().GetHolidays(gomock.Any(), monday, monday).Return(nil, nil)
+			},
+			wantAvailability: []domain.TimeSlot{
+				{Start: monday.Add(9 * time.Hour), End: monday.Add(18 * time.Hour)},
+			},
+		},
+		{
+			name:     "doctor sees own schedule",
+			actor:    &domain.Doctor{ID: 2, Role: domain.RoleDoctor},
+			doctorID: 2,
+			from:     monday,
+			to:       monday,
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().GetWorkingHours(gomock.Any(), 2).Return(nil, nil)
+				s.EXPECT().GetBreaks(gomock.Any(), 2).Return(nil, nil)
+				s.EXPECT().GetExceptions(gomock.Any(), 2, monday, monday).Return(nil, nil)
+				s.EXPECT().GetHolidays(gomock.Any(), monday, monday).Return(nil, nil)
+			},
+			wantAvailability: []domain.TimeSlot{
+				{Start: monday.Add(9 * time.Hour), End: monday.Add(18 * time.Hour)},
+			},
+		},
+		{
+			name:     "doctor cannot see other doctor schedule",
+			actor:    &domain.Doctor{ID: 3, Role: domain.RoleDoctor},
+			doctorID: 2,
+			from:     monday,
+			to:       sunday,
+			setup:    func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {},
+			wantErr:  true,
+			errMsg:   "access denied",
+		},
+		{
+			name:     "accountant has no schedule access",
+			actor:    &domain.Doctor{ID: 2, Role: domain.RoleAccountant},
+			doctorID: 2,
+			from:     monday,
+			to:       sunday,
+			setup:    func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {},
+			wantErr:  true,
+			errMsg:   "access denied",
+		},
+		{
+			name:     "invalid doctor id",
+			doctorID: 0,
+			from:     monday,
+			to:       sunday,
+			setup:    func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {},
+			wantErr:  true,
+			errMsg:   "invalid doctor ID",
+		},
+		{
+			name:     "doctor not found",
+			doctorID: 99,
+			from:     monday,
+			to:       sunday,
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 99).Return(nil, nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor not found",
+		},
+		{
+			name:     "date to before date from",
+			doctorID: 2,
+			from:     sunday,
+			to:       monday,
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "date to must not be before date from",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(mockScheduleRepo, mockDoctorRepo)
+			uc := NewScheduleUseCase(mockScheduleRepo, mockDoctorRepo)
+
+			actor := tt.actor
+			if actor == nil {
+				actor = &domain.Doctor{ID: 1, Role: domain.RoleReceptionist}
+			}
+
+			schedule, err := uc.GetDoctorSchedule(context.Background(), actor, tt.doctorID, tt.from, tt.to)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.doctorID, schedule.DoctorID)
+				assert.Equal(t, tt.wantAvailability, schedule.Availability)
+			}
+		})
+	}
+}
+
+func TestScheduleUseCase_UpdateWeeklySchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []*domain.WorkingHours
+		breaks  []*domain.ScheduleBreak
+		setup   func(*repository.MockScheduleRepository, *repository.MockDoctorRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:   "success",
+			hours:  []*domain.WorkingHours{{Weekday: time.Monday, StartTime: "09:00", EndTime: "18:00"}},
+			breaks: []*domain.ScheduleBreak{{Weekday: time.Monday, StartTime: "13:00", EndTime: "14:00", Title: "Обед"}},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().ReplaceWeeklySchedule(gomock.Any(), 2, gomock.Len(1), gomock.Len(1)).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "end before start",
+			hours: []*domain.WorkingHours{{Weekday: time.Monday, StartTime: "18:00", EndTime: "09:00"}},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "start time must be before end time",
+		},
+		{
+			name:  "invalid weekday",
+			hours: []*domain.WorkingHours{{Weekday: 7, StartTime: "09:00", EndTime: "18:00"}},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "invalid weekday",
+		},
+		{
+			name:   "invalid break time",
+			breaks: []*domain.ScheduleBreak{{Weekday: time.Monday, StartTime: "1300", EndTime: "14:00"}},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "invalid time format",
+		},
+		{
+			name: "repository error",
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().ReplaceWeeklySchedule(gomock.Any(), 2, gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(mockScheduleRepo, mockDoctorRepo)
+			uc := NewScheduleUseCase(mockScheduleRepo, mockDoctorRepo)
+
+			err := uc.UpdateWeeklySchedule(context.Background(), 2, tt.hours, tt.breaks)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestScheduleUseCase_AddException(t *testing.T) {
+	day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		exception *domain.ScheduleException
+		setup     func(*repository.MockScheduleRepository, *repository.MockDoctorRepository)
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "vacation defaults date to",
+			exception: &domain.ScheduleException{DoctorID: 2, Type: domain.ExceptionVacation, DateFrom: day, StartTime: "10:00"},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().CreateException(gomock.Any(), &domain.ScheduleException{DoctorID: 2, Type: domain.ExceptionVacation, DateFrom: day, DateTo: day}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "custom hours",
+			exception: &domain.ScheduleException{DoctorID: 2, Type: domain.ExceptionCustomHours, DateFrom: day, DateTo: day, StartTime: "12:00", EndTime: "16:00"},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().CreateException(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "nil exception",
+			exception: nil,
+			setup:     func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {},
+			wantErr:   true,
+			errMsg:    "schedule exception cannot be nil",
+		},
+		{
+			name:      "custom hours without time",
+			exception: &domain.ScheduleException{DoctorID: 2, Type: domain.ExceptionCustomHours, DateFrom: day},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "invalid time format",
+		},
+		{
+			name:      "invalid type",
+			exception: &domain.ScheduleException{DoctorID: 2, Type: "holiday", DateFrom: day},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "invalid schedule exception type",
+		},
+		{
+			name:      "date to before date from",
+			exception: &domain.ScheduleException{DoctorID: 2, Type: domain.ExceptionSickLeave, DateFrom: day, DateTo: day.AddDate(0, 0, -1)},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+			},
+			wantErr: true,
+			errMsg:  "date to must not be before date from",
+		},
+		{
+			name:      "doctor not found",
+			exception: &domain.ScheduleException{DoctorID: 99, Type: domain.ExceptionDayOff, DateFrom: day},
+			setup: func(s *repository.MockScheduleRepository, d *repository.MockDoctorRepository) {
+				d.EXPECT().GetByID(gomock.Any(), 99).Return(nil, nil)
+			},
+			wantErr: true,
+			errMsg:  "doctor not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			mockDoctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(mockScheduleRepo, mockDoctorRepo)
+			uc := NewScheduleUseCase(mockScheduleRepo, mockDoctorRepo)
+
+			err := uc.AddException(context.Background(), tt.exception)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestScheduleUseCase_AddHoliday(t *testing.T) {
+	day := time.Date(2030, 3, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		holiday *domain.ClinicHoliday
+		setup   func(*repository.MockScheduleRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "success",
+			holiday: &domain.ClinicHoliday{Date: day, Name: "Наурыз"},
+			setup: func(s *repository.MockScheduleRepository) {
+				s.EXPECT().CreateHoliday(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing date",
+			holiday: &domain.ClinicHoliday{Name: "Наурыз"},
+			setup:   func(s *repository.MockScheduleRepository) {},
+			wantErr: true,
+			errMsg:  "holiday date is required",
+		},
+		{
+			name:    "missing name",
+			holiday: &domain.ClinicHoliday{Date: day, Name: "  "},
+			setup:   func(s *repository.MockScheduleRepository) {},
+			wantErr: true,
+			errMsg:  "holiday name is required",
+		},
+		{
+			name:    "repository error",
+			holiday: &domain.ClinicHoliday{Date: day, Name: "Наурыз"},
+			setup: func(s *repository.MockScheduleRepository) {
+				s.EXPECT().CreateHoliday(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduleRepo := repository.NewMockScheduleRepository(ctrl)
+			tt.setup(mockScheduleRepo)
+			uc := NewScheduleUseCase(mockScheduleRepo, repository.NewMockDoctorRepository(ctrl))
+
+			err := uc.AddHoliday(context.Background(), tt.holiday)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,13 +1,16 @@
 package usecase
 
 import (
-	"errors"
+	"context"
 	"strings"
 	"time"
 
 	"github.com/sdk17/crmstom/internal/domain"
 )
 
+// maxServiceDuration максимальная длительность услуги в минутах
+const maxServiceDuration = 12 * 60
+
 type ServiceUseCase struct {
 	serviceRepo domain.ServiceRepository
 }
@@ -19,89 +22,152 @@ func NewServiceUseCase(serviceRepo domain.ServiceRepository) *ServiceUseCase {
 }
 
 // GetService получает услугу по ID
-func (u *ServiceUseCase) GetService(id int) (*domain.Service, error) {
+func (u *ServiceUseCase) GetService(ctx context.Context, id int) (*domain.Service, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid service ID")
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid service ID")
 	}
-	return u.serviceRepo.GetByID(id)
+	return u.serviceRepo.GetByID(ctx, id)
 }
 
 // GetAllServices получает все услуги
-func (u *ServiceUseCase) GetAllServices() ([]*domain.Service, error) {
-	return u.serviceRepo.GetAll()
+func (u *ServiceUseCase) GetAllServices(ctx context.Context) ([]*domain.Service, error) {
+	return u.serviceRepo.GetAll(ctx)
+}
+
+// ListServices получает страницу услуг по фильтру и общее число подходящих услуг
+func (u *ServiceUseCase) ListServices(ctx context.Context, filter domain.ServiceFilter) ([]*domain.Service, int, error) {
+	if err := normalizeList(filter.Sort, &filter.Page, domain.ServiceSortFields); err != nil {
+		return nil, 0, err
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	return u.serviceRepo.List(ctx, filter)
 }
 
 // CreateService создает новую услугу
-func (u *ServiceUseCase) CreateService(service *domain.Service) error {
+func (u *ServiceUseCase) CreateService(ctx context.Context, service *domain.Service) error {
 	if err := u.ValidateService(service); err != nil {
 		return err
 	}
 
+	if service.Duration == 0 {
+		service.Duration = domain.DefaultAppointmentDuration
+	}
+
 	service.CreatedAt = time.Now()
 	service.UpdatedAt = time.Now()
 
-	return u.serviceRepo.Create(service)
+	return u.serviceRepo.Create(ctx, service)
 }
 
 // UpdateService обновляет услугу
-func (u *ServiceUseCase) UpdateService(service *domain.Service) error {
+func (u *ServiceUseCase) UpdateService(ctx context.Context, service *domain.Service) error {
 	if err := u.ValidateService(service); err != nil {
 		return err
 	}
 
+	if service.Duration == 0 {
+		service.Duration = domain.DefaultAppointmentDuration
+	}
+
 	service.UpdatedAt = time.Now()
 
-	return u.serviceRepo.Update(service)
+	return u.serviceRepo.Update(ctx, service)
 }
 
 // DeleteService удаляет услугу
-func (u *ServiceUseCase) DeleteService(id int) error {
+func (u *ServiceUseCase) DeleteService(ctx context.Context, id int) error {
 	if id <= 0 {
-		return errors.New("invalid service ID")
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid service ID")
 	}
-	return u.serviceRepo.Delete(id)
+	return u.serviceRepo.Delete(ctx, id)
 }
 
 // GetServicesByCategory получает услуги по категории
-func (u *ServiceUseCase) GetServicesByCategory(category string) ([]*domain.Service, error) {
+func (u *ServiceUseCase) GetServicesByCategory(ctx context.Context, category string) ([]*domain.Service, error) {
 	if strings.TrimSpace(category) == "" {
-		return u.serviceRepo.GetAll()
+		return u.serviceRepo.GetAll(ctx)
 	}
-	return u.serviceRepo.GetByCategory(category)
+	return u.serviceRepo.GetByCategory(ctx, category)
 }
 
 // SearchServices ищет услуги по запросу
-func (u *ServiceUseCase) SearchServices(query string) ([]*domain.Service, error) {
+func (u *ServiceUseCase) SearchServices(ctx context.Context, query string) ([]*domain.Service, error) {
 	if strings.TrimSpace(query) == "" {
-		return u.serviceRepo.GetAll()
+		return u.serviceRepo.GetAll(ctx)
 	}
-	return u.serviceRepo.Search(query)
+	return u.serviceRepo.Search(ctx, query)
+}
+
+// GetPriceHistory получает историю изменений цены услуги
+func (u *ServiceUseCase) GetPriceHistory(ctx context.Context, serviceID int) ([]*domain.ServicePrice, error) {
+	if serviceID <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid service ID")
+	}
+
+	if _, err := u.serviceRepo.GetByID(ctx, serviceID); err != nil {
+		return nil, err
+	}
+
+	return u.serviceRepo.GetPriceHistory(ctx, serviceID)
+}
+
+// AddPrice добавляет изменение цены услуги; без даты начала цена действует с текущего момента
+func (u *ServiceUseCase) AddPrice(ctx context.Context, price *domain.ServicePrice) error {
+	if price == nil {
+		return domain.NewValidationError("", domain.FieldRequired, "price cannot be nil")
+	}
+
+	if price.ServiceID <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid service ID")
+	}
+
+	if price.Price < 0 {
+		return domain.NewValidationError("price", domain.FieldNegative, "service price must not be negative")
+	}
+
+	if _, err := u.serviceRepo.GetByID(ctx, price.ServiceID); err != nil {
+		return err
+	}
+
+	if price.EffectiveFrom.IsZero() {
+		price.EffectiveFrom = time.Now()
+	}
+
+	return u.serviceRepo.AddPrice(ctx, price)
 }
 
 // ValidateService валидирует данные услуги
 func (u *ServiceUseCase) ValidateService(service *domain.Service) error {
 	if service == nil {
-		return errors.New("service cannot be nil")
+		return domain.NewValidationError("", domain.FieldRequired, "service cannot be nil")
 	}
 
 	if strings.TrimSpace(service.Name) == "" {
-		return errors.New("service name is required")
+		return domain.NewValidationError("name", domain.FieldRequired, "service name is required")
 	}
 
 	if len(service.Name) > 100 {
-		return errors.New("service name is too long")
+		return domain.NewValidationError("name", domain.FieldTooLong, "service name is too long")
 	}
 
 	if strings.TrimSpace(service.Type) == "" {
-		return errors.New("service type is required")
+		return domain.NewValidationError("type", domain.FieldRequired, "service type is required")
 	}
 
 	if len(service.Type) > 50 {
-		return errors.New("service type is too long")
+		return domain.NewValidationError("type", domain.FieldTooLong, "service type is too long")
 	}
 
 	if service.Notes != "" && len(service.Notes) > 500 {
-		return errors.New("service notes are too long")
+		return domain.NewValidationError("notes", domain.FieldTooLong, "service notes are too long")
+	}
+
+	if service.Price < 0 {
+		return domain.NewValidationError("price", domain.FieldNegative, "service price must not be negative")
+	}
+
+	if service.Duration < 0 || service.Duration > maxServiceDuration {
+		return domain.NewValidationError("duration", domain.FieldInvalid, "invalid service duration")
 	}
 
 	return nil
@@ -1,8 +1,10 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/sdk17/crmstom/gen/mocks/repository"
 	"github.com/sdk17/crmstom/internal/domain"
@@ -23,7 +25,7 @@ func TestServiceUseCase_GetService(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetByID(1).Return(&domain.Service{ID: 1, Name: "Консультация", Type: "consultation"}, nil)
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1, Name: "Консультация", Type: "consultation"}, nil)
 			},
 			wantErr: false,
 		},
@@ -45,7 +47,7 @@ func TestServiceUseCase_GetService(t *testing.T) {
 			name: "service not found",
 			id:   999,
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetByID(999).Return(nil, errors.New("service not found"))
+				m.EXPECT().GetByID(gomock.Any(), 999).Return(nil, errors.New("service not found"))
 			},
 			wantErr: true,
 			errMsg:  "service not found",
@@ -61,7 +63,7 @@ func TestServiceUseCase_GetService(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			service, err := uc.GetService(tt.id)
+			service, err := uc.GetService(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -85,7 +87,7 @@ func TestServiceUseCase_GetAllServices(t *testing.T) {
 		{
 			name: "success with services",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{
 					{ID: 1, Name: "Консультация", Type: "consultation"},
 					{ID: 2, Name: "Лечение", Type: "treatment"},
 				}, nil)
@@ -96,7 +98,7 @@ func TestServiceUseCase_GetAllServices(t *testing.T) {
 		{
 			name: "success empty list",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{}, nil)
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{}, nil)
 			},
 			want:    0,
 			wantErr: false,
@@ -104,7 +106,7 @@ func TestServiceUseCase_GetAllServices(t *testing.T) {
 		{
 			name: "repository error",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return(nil, errors.New("database error"))
+				m.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -119,7 +121,7 @@ func TestServiceUseCase_GetAllServices(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			services, err := uc.GetAllServices()
+			services, err := uc.GetAllServices(context.Background())
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -131,6 +133,66 @@ func TestServiceUseCase_GetAllServices(t *testing.T) {
 	}
 }
 
+func TestServiceUseCase_ListServices(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    domain.ServiceFilter
+		setup     func(*repository.MockServiceRepository)
+		wantTotal int
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:   "type and sort",
+			filter: domain.ServiceFilter{Type: "Терапия", Sort: domain.Sort{Field: "price"}, Page: domain.Page{Limit: 20, Offset: 40}},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().List(gomock.Any(), domain.ServiceFilter{Type: "Терапия", Sort: domain.Sort{Field: "price"}, Page: domain.Page{Limit: 20, Offset: 40}}).
+					Return([]*domain.Service{{ID: 1}}, 41, nil)
+			},
+			wantTotal: 41,
+		},
+		{
+			name:    "unknown sort field",
+			filter:  domain.ServiceFilter{Sort: domain.Sort{Field: "current_price"}},
+			setup:   func(m *repository.MockServiceRepository) {},
+			wantErr: true,
+			errMsg:  "invalid sort field",
+		},
+		{
+			name:   "repository error",
+			filter: domain.ServiceFilter{},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockServiceRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewServiceUseCase(mockRepo)
+
+			services, total, err := uc.ListServices(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, services)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
 func TestServiceUseCase_CreateService(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -147,10 +209,35 @@ func TestServiceUseCase_CreateService(t *testing.T) {
 				Notes: "Первичный осмотр",
 			},
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, service *domain.Service) error {
+					assert.Equal(t, domain.DefaultAppointmentDuration, service.Duration)
+					return nil
+				})
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative price",
+			service: &domain.Service{
+				Name:  "Консультация",
+				Type:  "consultation",
+				Price: domain.Tenge(-1),
+			},
+			setup:   func(m *repository.MockServiceRepository) {},
+			wantErr: true,
+			errMsg:  "service price must not be negative",
+		},
+		{
+			name: "duration too long",
+			service: &domain.Service{
+				Name:     "Консультация",
+				Type:     "consultation",
+				Duration: 24 * 60,
+			},
+			setup:   func(m *repository.MockServiceRepository) {},
+			wantErr: true,
+			errMsg:  "invalid service duration",
+		},
 		{
 			name:    "nil service",
 			service: nil,
@@ -226,7 +313,7 @@ func TestServiceUseCase_CreateService(t *testing.T) {
 				Type: "consultation",
 			},
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errMsg:  "database error",
@@ -242,7 +329,7 @@ func TestServiceUseCase_CreateService(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			err := uc.CreateService(tt.service)
+			err := uc.CreateService(context.Background(), tt.service)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -272,7 +359,7 @@ func TestServiceUseCase_UpdateService(t *testing.T) {
 				Type: "consultation",
 			},
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Update(gomock.Any()).Return(nil)
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -291,7 +378,7 @@ func TestServiceUseCase_UpdateService(t *testing.T) {
 				Type: "test",
 			},
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Update(gomock.Any()).Return(errors.New("service not found"))
+				m.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("service not found"))
 			},
 			wantErr: true,
 			errMsg:  "service not found",
@@ -307,7 +394,7 @@ func TestServiceUseCase_UpdateService(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			err := uc.UpdateService(tt.service)
+			err := uc.UpdateService(context.Background(), tt.service)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -332,7 +419,7 @@ func TestServiceUseCase_DeleteService(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Delete(1).Return(nil)
+				m.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -354,7 +441,7 @@ func TestServiceUseCase_DeleteService(t *testing.T) {
 			name: "not found",
 			id:   999,
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Delete(999).Return(errors.New("service not found"))
+				m.EXPECT().Delete(gomock.Any(), 999).Return(errors.New("service not found"))
 			},
 			wantErr: true,
 			errMsg:  "service not found",
@@ -370,7 +457,7 @@ func TestServiceUseCase_DeleteService(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			err := uc.DeleteService(tt.id)
+			err := uc.DeleteService(context.Background(), tt.id)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -394,7 +481,7 @@ func TestServiceUseCase_GetServicesByCategory(t *testing.T) {
 			name:     "success with category",
 			category: "consultation",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetByCategory("consultation").Return([]*domain.Service{
+				m.EXPECT().GetByCategory(gomock.Any(), "consultation").Return([]*domain.Service{
 					{ID: 1, Name: "Консультация", Type: "consultation"},
 				}, nil)
 			},
@@ -405,7 +492,7 @@ func TestServiceUseCase_GetServicesByCategory(t *testing.T) {
 			name:     "empty category returns all",
 			category: "",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{
 					{ID: 1, Name: "Service 1"},
 					{ID: 2, Name: "Service 2"},
 				}, nil)
@@ -417,7 +504,7 @@ func TestServiceUseCase_GetServicesByCategory(t *testing.T) {
 			name:     "whitespace category returns all",
 			category: "   ",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{
 					{ID: 1, Name: "Service 1"},
 				}, nil)
 			},
@@ -428,7 +515,7 @@ func TestServiceUseCase_GetServicesByCategory(t *testing.T) {
 			name:     "repository error",
 			category: "consultation",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetByCategory("consultation").Return(nil, errors.New("database error"))
+				m.EXPECT().GetByCategory(gomock.Any(), "consultation").Return(nil, errors.New("database error"))
 			},
 			wantErr: true,
 		},
@@ -443,7 +530,7 @@ func TestServiceUseCase_GetServicesByCategory(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			services, err := uc.GetServicesByCategory(tt.category)
+			services, err := uc.GetServicesByCategory(context.Background(), tt.category)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -467,7 +554,7 @@ func TestServiceUseCase_SearchServices(t *testing.T) {
 			name:  "search with query",
 			query: "консульт",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Search("консульт").Return([]*domain.Service{
+				m.EXPECT().Search(gomock.Any(), "консульт").Return([]*domain.Service{
 					{ID: 1, Name: "Консультация"},
 				}, nil)
 			},
@@ -478,7 +565,7 @@ func TestServiceUseCase_SearchServices(t *testing.T) {
 			name:  "empty query returns all",
 			query: "",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{
 					{ID: 1, Name: "Service 1"},
 					{ID: 2, Name: "Service 2"},
 				}, nil)
@@ -490,7 +577,7 @@ func TestServiceUseCase_SearchServices(t *testing.T) {
 			name:  "whitespace query returns all",
 			query: "   ",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().GetAll().Return([]*domain.Service{
+				m.EXPECT().GetAll(gomock.Any()).Return([]*domain.Service{
 					{ID: 1, Name: "Service 1"},
 				}, nil)
 			},
@@ -501,7 +588,7 @@ func TestServiceUseCase_SearchServices(t *testing.T) {
 			name:  "search error",
 			query: "test",
 			setup: func(m *repository.MockServiceRepository) {
-				m.EXPECT().Search("test").Return(nil, errors.New("search failed"))
+				m.EXPECT().Search(gomock.Any(), "test").Return(nil, errors.New("search failed"))
 			},
 			wantErr: true,
 		},
@@ -516,7 +603,7 @@ func TestServiceUseCase_SearchServices(t *testing.T) {
 			tt.setup(mockRepo)
 			uc := NewServiceUseCase(mockRepo)
 
-			services, err := uc.SearchServices(tt.query)
+			services, err := uc.SearchServices(context.Background(), tt.query)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -597,3 +684,160 @@ func TestServiceUseCase_ValidateService(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceUseCase_GetPriceHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		serviceID int
+		setup     func(*repository.MockServiceRepository)
+		want      int
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "success",
+			serviceID: 1,
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1}, nil)
+				m.EXPECT().GetPriceHistory(gomock.Any(), 1).Return([]*domain.ServicePrice{
+					{ID: 2, ServiceID: 1, Price: domain.Tenge(6000)},
+					{ID: 1, ServiceID: 1, Price: domain.Tenge(5000)},
+				}, nil)
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name:      "invalid id",
+			serviceID: 0,
+			setup:     func(m *repository.MockServiceRepository) {},
+			wantErr:   true,
+			errMsg:    "invalid service ID",
+		},
+		{
+			name:      "service not found",
+			serviceID: 99,
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
+		{
+			name:      "database error",
+			serviceID: 1,
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.New("connection refused"))
+			},
+			wantErr: true,
+			errMsg:  "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockServiceRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewServiceUseCase(mockRepo)
+
+			prices, err := uc.GetPriceHistory(context.Background(), tt.serviceID)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, prices, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceUseCase_AddPrice(t *testing.T) {
+	effectiveFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		price   *domain.ServicePrice
+		setup   func(*repository.MockServiceRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:  "success",
+			price: &domain.ServicePrice{ServiceID: 1, Price: domain.Tenge(6000), EffectiveFrom: effectiveFrom},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1}, nil)
+				m.EXPECT().AddPrice(gomock.Any(), &domain.ServicePrice{ServiceID: 1, Price: domain.Tenge(6000), EffectiveFrom: effectiveFrom}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "effective immediately by default",
+			price: &domain.ServicePrice{ServiceID: 1, Price: domain.Tenge(6000)},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Service{ID: 1}, nil)
+				m.EXPECT().AddPrice(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, price *domain.ServicePrice) error {
+					assert.False(t, price.EffectiveFrom.IsZero())
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name:    "nil price",
+			price:   nil,
+			setup:   func(m *repository.MockServiceRepository) {},
+			wantErr: true,
+			errMsg:  "price cannot be nil",
+		},
+		{
+			name:    "negative price",
+			price:   &domain.ServicePrice{ServiceID: 1, Price: domain.Tenge(-100)},
+			setup:   func(m *repository.MockServiceRepository) {},
+			wantErr: true,
+			errMsg:  "service price must not be negative",
+		},
+		{
+			name:  "service not found",
+			price: &domain.ServicePrice{ServiceID: 99, Price: domain.Tenge(6000)},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
+		{
+			name:  "database error",
+			price: &domain.ServicePrice{ServiceID: 1, Price: domain.Tenge(6000)},
+			setup: func(m *repository.MockServiceRepository) {
+				m.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.New("connection refused"))
+			},
+			wantErr: true,
+			errMsg:  "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repository.NewMockServiceRepository(ctrl)
+			tt.setup(mockRepo)
+			uc := NewServiceUseCase(mockRepo)
+
+			err := uc.AddPrice(context.Background(), tt.price)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
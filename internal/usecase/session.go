@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Время жизни токенов сессии
+const (
+	SessionTTL        = 12 * time.Hour
+	RefreshSessionTTL = 30 * 24 * time.Hour
+)
+
+type SessionUseCase struct {
+	sessionRepo domain.SessionRepository
+	doctorRepo  domain.DoctorRepository
+}
+
+func NewSessionUseCase(sessionRepo domain.SessionRepository, doctorRepo domain.DoctorRepository) *SessionUseCase {
+	return &SessionUseCase{
+		sessionRepo: sessionRepo,
+		doctorRepo:  doctorRepo,
+	}
+}
+
+// CreateSession открывает сессию для аутентифицированного врача и возвращает ее токены
+func (u *SessionUseCase) CreateSession(ctx context.Context, doctor *domain.Doctor) (*domain.SessionTokens, error) {
+	accessToken, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		DoctorID:         doctor.ID,
+		TokenHash:        hashToken(accessToken),
+		RefreshTokenHash: hashToken(refreshToken),
+		ExpiresAt:        now.Add(SessionTTL),
+		RefreshExpiresAt: now.Add(RefreshSessionTTL),
+	}
+
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &domain.SessionTokens{
+		AccessToken:      accessToken,
+		ExpiresAt:        session.ExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.RefreshExpiresAt,
+	}, nil
+}
+
+// Authenticate возвращает врача по токену доступа действующей сессии
+func (u *SessionUseCase) Authenticate(ctx context.Context, accessToken string) (*domain.Doctor, error) {
+	if accessToken == "" {
+		return nil, domain.ErrInvalidSession
+	}
+
+	session, err := u.sessionRepo.GetByTokenHash(ctx, hashToken(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || session.RevokedAt != nil || !time.Now().Before(session.ExpiresAt) {
+		return nil, domain.ErrInvalidSession
+	}
+
+	return u.sessionDoctor(ctx, session)
+}
+
+// Refresh обменивает токен обновления на новую сессию; старая сессия отзывается
+func (u *SessionUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.Doctor, *domain.SessionTokens, error) {
+	if refreshToken == "" {
+		return nil, nil, domain.ErrInvalidSession
+	}
+
+	session, err := u.sessionRepo.GetByRefreshTokenHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session == nil || !time.Now().Before(session.RefreshExpiresAt) {
+		return nil, nil, domain.ErrInvalidSession
+	}
+
+	if session.RevokedAt != nil {
+		return nil, nil, u.rejectReusedRefreshToken(ctx, session.DoctorID)
+	}
+
+	doctor, err := u.sessionDoctor(ctx, session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Сессию, уже отозванную параллельным обменом того же токена, отозвать повторно нельзя — это тоже повторное использование
+	err = u.sessionRepo.Revoke(ctx, session.ID)
+	if errors.Is(err, domain.ErrInvalidSession) {
+		return nil, nil, u.rejectReusedRefreshToken(ctx, session.DoctorID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := u.CreateSession(ctx, doctor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doctor, tokens, nil
+}
+
+// rejectReusedRefreshToken отзывает все сессии врача: повторное использование токена обновления означает его утечку
+func (u *SessionUseCase) rejectReusedRefreshToken(ctx context.Context, doctorID int) error {
+	if err := u.sessionRepo.RevokeAllForDoctor(ctx, doctorID); err != nil {
+		return err
+	}
+	return domain.ErrInvalidSession
+}
+
+// Logout отзывает сессию по токену доступа
+func (u *SessionUseCase) Logout(ctx context.Context, accessToken string) error {
+	if accessToken == "" {
+		return domain.ErrInvalidSession
+	}
+
+	session, err := u.sessionRepo.GetByTokenHash(ctx, hashToken(accessToken))
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.RevokedAt != nil {
+		return domain.ErrInvalidSession
+	}
+
+	return u.sessionRepo.Revoke(ctx, session.ID)
+}
+
+// RevokeDoctorSessions отзывает все сессии врача, например после смены пароля
+func (u *SessionUseCase) RevokeDoctorSessions(ctx context.Context, doctorID int) error {
+	return u.sessionRepo.RevokeAllForDoctor(ctx, doctorID)
+}
+
+// sessionDoctor получает врача сессии; удаленный врач делает сессию недействительной
+func (u *SessionUseCase) sessionDoctor(ctx context.Context, session *domain.Session) (*domain.Doctor, error) {
+	doctor, err := u.doctorRepo.GetByID(ctx, session.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doctor == nil {
+		return nil, domain.ErrInvalidSession
+	}
+
+	doctor.Password = ""
+
+	return doctor, nil
+}
+
+// generateToken генерирует случайный непрозрачный токен
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken возвращает SHA-256 хеш токена для хранения в базе
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
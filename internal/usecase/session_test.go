@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionUseCase_CreateSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*repository.MockSessionRepository)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "success",
+			setup: func(s *repository.MockSessionRepository) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, session *domain.Session) error {
+					assert.Equal(t, 1, session.DoctorID)
+					assert.Len(t, session.TokenHash, 64)
+					assert.Len(t, session.RefreshTokenHash, 64)
+					assert.NotEqual(t, session.TokenHash, session.RefreshTokenHash)
+					assert.True(t, session.RefreshExpiresAt.After(session.ExpiresAt))
+					return nil
+				})
+			},
+		},
+		{
+			name: "repository error",
+			setup: func(s *repository.MockSessionRepository) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+			errMsg:  "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := repository.NewMockSessionRepository(ctrl)
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(sessionRepo)
+			uc := NewSessionUseCase(sessionRepo, doctorRepo)
+
+			tokens, err := uc.CreateSession(context.Background(), &domain.Doctor{ID: 1})
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, tokens)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.NotEqual(t, tokens.AccessToken, tokens.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_Authenticate(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		token   string
+		setup   func(*repository.MockSessionRepository, *repository.MockDoctorRepository)
+		wantErr error
+	}{
+		{
+			name:  "success",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(&domain.Session{ID: 1, DoctorID: 2, ExpiresAt: now.Add(time.Hour)}, nil)
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2, Password: "hash"}, nil)
+			},
+		},
+		{
+			name:    "empty token",
+			token:   "",
+			setup:   func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "unknown token",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(nil, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "expired session",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(&domain.Session{ID: 1, DoctorID: 2, ExpiresAt: now.Add(-time.Second)}, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "revoked session",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(&domain.Session{ID: 1, DoctorID: 2, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "deleted doctor",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(&domain.Session{ID: 1, DoctorID: 2, ExpiresAt: now.Add(time.Hour)}, nil)
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(nil, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := repository.NewMockSessionRepository(ctrl)
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(sessionRepo, doctorRepo)
+			uc := NewSessionUseCase(sessionRepo, doctorRepo)
+
+			doctor, err := uc.Authenticate(context.Background(), tt.token)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, doctor)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 2, doctor.ID)
+				assert.Empty(t, doctor.Password)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_Refresh(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		token   string
+		setup   func(*repository.MockSessionRepository, *repository.MockDoctorRepository)
+		wantErr error
+	}{
+		{
+			name:  "success rotates session",
+			token: "refresh",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByRefreshTokenHash(gomock.Any(), hashToken("refresh")).Return(&domain.Session{ID: 5, DoctorID: 2, ExpiresAt: now.Add(-time.Hour), RefreshExpiresAt: now.Add(time.Hour)}, nil)
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().Revoke(gomock.Any(), 5).Return(nil)
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:  "expired refresh token",
+			token: "refresh",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByRefreshTokenHash(gomock.Any(), hashToken("refresh")).Return(&domain.Session{ID: 5, DoctorID: 2, RefreshExpiresAt: now.Add(-time.Second)}, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "reused refresh token revokes all doctor sessions",
+			token: "refresh",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByRefreshTokenHash(gomock.Any(), hashToken("refresh")).Return(&domain.Session{ID: 5, DoctorID: 2, RefreshExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, nil)
+				s.EXPECT().RevokeAllForDoctor(gomock.Any(), 2).Return(nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "concurrent refresh with same token revokes all doctor sessions",
+			token: "refresh",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByRefreshTokenHash(gomock.Any(), hashToken("refresh")).Return(&domain.Session{ID: 5, DoctorID: 2, RefreshExpiresAt: now.Add(time.Hour)}, nil)
+				d.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Doctor{ID: 2}, nil)
+				s.EXPECT().Revoke(gomock.Any(), 5).Return(domain.ErrInvalidSession)
+				s.EXPECT().RevokeAllForDoctor(gomock.Any(), 2).Return(nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+		{
+			name:  "unknown refresh token",
+			token: "refresh",
+			setup: func(s *repository.MockSessionRepository, d *repository.MockDoctorRepository) {
+				s.EXPECT().GetByRefreshTokenHash(gomock.Any(), hashToken("refresh")).Return(nil, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := repository.NewMockSessionRepository(ctrl)
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			tt.setup(sessionRepo, doctorRepo)
+			uc := NewSessionUseCase(sessionRepo, doctorRepo)
+
+			doctor, tokens, err := uc.Refresh(context.Background(), tt.token)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tokens)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 2, doctor.ID)
+				assert.NotEmpty(t, tokens.AccessToken)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_Logout(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		setup   func(*repository.MockSessionRepository)
+		wantErr error
+	}{
+		{
+			name:  "success",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(&domain.Session{ID: 3}, nil)
+				s.EXPECT().Revoke(gomock.Any(), 3).Return(nil)
+			},
+		},
+		{
+			name:  "unknown token",
+			token: "token",
+			setup: func(s *repository.MockSessionRepository) {
+				s.EXPECT().GetByTokenHash(gomock.Any(), hashToken("token")).Return(nil, nil)
+			},
+			wantErr: domain.ErrInvalidSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := repository.NewMockSessionRepository(ctrl)
+			tt.setup(sessionRepo)
+			uc := NewSessionUseCase(sessionRepo, repository.NewMockDoctorRepository(ctrl))
+
+			err := uc.Logout(context.Background(), tt.token)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
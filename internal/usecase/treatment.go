@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Ограничения длины текстовых полей процедуры в символах
+const (
+	maxTreatmentMaterialsLength  = 500
+	maxTreatmentAnesthesiaLength = 200
+	maxTreatmentCommentLength    = 1000
+)
+
+type TreatmentUseCase struct {
+	treatmentRepo   domain.TreatmentRepository
+	appointmentRepo domain.AppointmentRepository
+	serviceRepo     domain.ServiceRepository
+	chartRepo       domain.DentalChartRepository
+	catalog         domain.DiagnosisRepository
+	uow             domain.UnitOfWork
+}
+
+func NewTreatmentUseCase(
+	treatmentRepo domain.TreatmentRepository,
+	appointmentRepo domain.AppointmentRepository,
+	serviceRepo domain.ServiceRepository,
+	chartRepo domain.DentalChartRepository,
+	catalog domain.DiagnosisRepository,
+	uow domain.UnitOfWork,
+) *TreatmentUseCase {
+	return &TreatmentUseCase{
+		treatmentRepo:   treatmentRepo,
+		appointmentRepo: appointmentRepo,
+		serviceRepo:     serviceRepo,
+		chartRepo:       chartRepo,
+		catalog:         catalog,
+		uow:             uow,
+	}
+}
+
+// ListTreatments получает процедуры приема
+func (u *TreatmentUseCase) ListTreatments(ctx context.Context, appointmentID int) ([]*domain.Treatment, error) {
+	if appointmentID <= 0 {
+		return nil, domain.NewValidationError("appointment_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+	if _, err := u.appointmentRepo.GetByID(ctx, appointmentID); err != nil {
+		return nil, err
+	}
+	return u.treatmentRepo.GetByAppointmentID(ctx, appointmentID)
+}
+
+// AddTreatment добавляет процедуру к приему; цена приема пересчитывается, состояние зуба переносится в зубную формулу
+func (u *TreatmentUseCase) AddTreatment(ctx context.Context, treatment *domain.Treatment) error {
+	if err := validateTreatment(treatment); err != nil {
+		return err
+	}
+	if err := u.checkDiagnosisCode(ctx, treatment.DiagnosisCode); err != nil {
+		return err
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		appointment, err := heldAppointment(ctx, u.appointmentRepo, treatment.AppointmentID)
+		if err != nil {
+			return err
+		}
+		if err := u.resolveService(ctx, treatment, appointment); err != nil {
+			return err
+		}
+
+		if err := u.treatmentRepo.Create(ctx, treatment); err != nil {
+			return err
+		}
+		return u.recordToothChange(ctx, appointment, treatment)
+	})
+}
+
+// UpdateTreatment изменяет процедуру приема; новое состояние зуба записывается в зубную формулу, только если оно изменилось
+func (u *TreatmentUseCase) UpdateTreatment(ctx context.Context, treatment *domain.Treatment) error {
+	if treatment.ID <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment ID")
+	}
+	if err := validateTreatment(treatment); err != nil {
+		return err
+	}
+	if err := u.checkDiagnosisCode(ctx, treatment.DiagnosisCode); err != nil {
+		return err
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := u.treatmentRepo.GetByID(ctx, treatment.ID)
+		if err != nil {
+			return err
+		}
+		if existing.AppointmentID != treatment.AppointmentID {
+			return domain.ErrTreatmentNotFound.WithMessage("процедура с ID %d не найдена в записи %d", treatment.ID, treatment.AppointmentID)
+		}
+
+		appointment, err := heldAppointment(ctx, u.appointmentRepo, treatment.AppointmentID)
+		if err != nil {
+			return err
+		}
+		if err := u.resolveService(ctx, treatment, appointment); err != nil {
+			return err
+		}
+
+		if err := u.treatmentRepo.Update(ctx, treatment); err != nil {
+			return err
+		}
+		treatment.CreatedAt = existing.CreatedAt
+
+		if existing.Tooth == treatment.Tooth && existing.ToothStatus == treatment.ToothStatus &&
+			slices.Equal(existing.Surfaces, treatment.Surfaces) {
+			return nil
+		}
+		return u.recordToothChange(ctx, appointment, treatment)
+	})
+}
+
+// DeleteTreatment удаляет процедуру приема; зубная формула не откатывается, ее история сохраняется
+func (u *TreatmentUseCase) DeleteTreatment(ctx context.Context, appointmentID, id int) error {
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment ID")
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := u.treatmentRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing.AppointmentID != appointmentID {
+			return domain.ErrTreatmentNotFound.WithMessage("процедура с ID %d не найдена в записи %d", id, appointmentID)
+		}
+		return u.treatmentRepo.Delete(ctx, id)
+	})
+}
+
+// checkDiagnosisCode проверяет код диагноза процедуры по справочнику МКБ-10; код необязателен
+func (u *TreatmentUseCase) checkDiagnosisCode(ctx context.Context, code string) error {
+	if code == "" {
+		return nil
+	}
+	_, err := catalogDiagnosis(ctx, u.catalog, "diagnosis_code", code)
+	return err
+}
+
+// heldAppointment получает прием, который состоялся или еще состоится; отмененный, пропущенный или перенесенный прием
+// не содержит процедур и диагнозов
+func heldAppointment(ctx context.Context, appointmentRepo domain.AppointmentRepository, appointmentID int) (*domain.Appointment, error) {
+	appointment, err := appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.Status.Active() {
+		return nil, domain.NewValidationError("appointment_id", domain.FieldInvalid, "appointment was cancelled, missed or rescheduled")
+	}
+	return appointment, nil
+}
+
+// resolveService проверяет услугу процедуры и подставляет цену из прайс-листа на дату приема, если цена не указана
+func (u *TreatmentUseCase) resolveService(ctx context.Context, treatment *domain.Treatment, appointment *domain.Appointment) error {
+	service, err := u.serviceRepo.GetByID(ctx, treatment.ServiceID)
+	if err != nil {
+		return err
+	}
+	treatment.Service = service.Name
+
+	if treatment.Price == 0 {
+		price, err := u.serviceRepo.GetPriceAt(ctx, service.ID, appointment.Date)
+		if err != nil {
+			return err
+		}
+		treatment.Price = price
+	}
+	return nil
+}
+
+// recordToothChange переносит состояние зуба после процедуры в зубную формулу пациента
+func (u *TreatmentUseCase) recordToothChange(ctx context.Context, appointment *domain.Appointment, treatment *domain.Treatment) error {
+	if treatment.Tooth == 0 || treatment.ToothStatus == "" {
+		return nil
+	}
+
+	change := &domain.ToothChange{
+		PatientID:     appointment.PatientID,
+		Tooth:         treatment.Tooth,
+		Status:        treatment.ToothStatus,
+		Surfaces:      []domain.ToothSurface{},
+		Notes:         treatment.Service,
+		AppointmentID: appointment.ID,
+	}
+	if treatment.ToothStatus.HasSurfaces() {
+		change.Surfaces = treatment.Surfaces
+	}
+	if doctor, ok := domain.DoctorFromContext(ctx); ok {
+		change.DoctorID = doctor.ID
+	}
+
+	return u.chartRepo.RecordChanges(ctx, []*domain.ToothChange{change})
+}
+
+// validateTreatment проверяет поля процедуры и приводит поверхности и код диагноза к каноническому виду
+func validateTreatment(treatment *domain.Treatment) error {
+	if treatment.AppointmentID <= 0 {
+		return domain.NewValidationError("appointment_id", domain.FieldInvalid, "invalid appointment ID")
+	}
+	if treatment.ServiceID <= 0 {
+		return domain.NewValidationError("service_id", domain.FieldRequired, "service is required")
+	}
+	if treatment.Price.IsNegative() {
+		return domain.NewValidationError("price", domain.FieldNegative, "price cannot be negative")
+	}
+
+	if treatment.Tooth != 0 && !domain.ValidToothNumber(treatment.Tooth) {
+		return domain.NewValidationError("tooth", domain.FieldInvalid, "invalid FDI tooth number")
+	}
+	if treatment.Tooth == 0 && (len(treatment.Surfaces) > 0 || treatment.ToothStatus != "") {
+		return domain.NewValidationError("tooth", domain.FieldRequired, "tooth is required for surfaces and tooth status")
+	}
+
+	surfaces, err := normalizeSurfaces(treatment.Tooth, treatment.Surfaces)
+	if err != nil {
+		return domain.NewValidationError("surfaces", domain.FieldInvalid, err.Error())
+	}
+	treatment.Surfaces = surfaces
+
+	if treatment.ToothStatus != "" {
+		if !treatment.ToothStatus.Valid() {
+			return domain.NewValidationError("tooth_status", domain.FieldInvalid, "invalid tooth status")
+		}
+		if treatment.ToothStatus.HasSurfaces() && len(treatment.Surfaces) == 0 {
+			return domain.NewValidationError("surfaces", domain.FieldRequired, "at least one surface is required for caries and fillings")
+		}
+	}
+
+	treatment.DiagnosisCode = normalizeDiagnosisCode(treatment.DiagnosisCode)
+	if treatment.DiagnosisCode != "" && !diagnosisCodePattern.MatchString(treatment.DiagnosisCode) {
+		return domain.NewValidationError("diagnosis_code", domain.FieldInvalid, "invalid ICD-10 code")
+	}
+
+	treatment.Materials = strings.TrimSpace(treatment.Materials)
+	treatment.Anesthesia = strings.TrimSpace(treatment.Anesthesia)
+	treatment.Comment = strings.TrimSpace(treatment.Comment)
+	switch {
+	case len([]rune(treatment.Materials)) > maxTreatmentMaterialsLength:
+		return domain.NewValidationError("materials", domain.FieldTooLong, "materials are too long")
+	case len([]rune(treatment.Anesthesia)) > maxTreatmentAnesthesiaLength:
+		return domain.NewValidationError("anesthesia", domain.FieldTooLong, "anesthesia is too long")
+	case len([]rune(treatment.Comment)) > maxTreatmentCommentLength:
+		return domain.NewValidationError("comment", domain.FieldTooLong, "comment is too long")
+	}
+
+	return nil
+}
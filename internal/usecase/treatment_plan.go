@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Ограничения плана лечения
+const (
+	maxPlanTitleLength     = 200
+	maxPlanNotesLength     = 2000
+	maxPlanSignerLength    = 200
+	maxPlanStepNotesLength = 1000
+	maxPlanVariants        = 10
+	maxPlanSteps           = 100
+)
+
+type TreatmentPlanUseCase struct {
+	planRepo     domain.TreatmentPlanRepository
+	patientRepo  domain.PatientRepository
+	serviceRepo  domain.ServiceRepository
+	appointments domain.AppointmentService
+	uow          domain.UnitOfWork
+}
+
+func NewTreatmentPlanUseCase(
+	planRepo domain.TreatmentPlanRepository,
+	patientRepo domain.PatientRepository,
+	serviceRepo domain.ServiceRepository,
+	appointments domain.AppointmentService,
+	uow domain.UnitOfWork,
+) *TreatmentPlanUseCase {
+	return &TreatmentPlanUseCase{
+		planRepo:     planRepo,
+		patientRepo:  patientRepo,
+		serviceRepo:  serviceRepo,
+		appointments: appointments,
+		uow:          uow,
+	}
+}
+
+// GetPlan получает план лечения с вариантами
+func (u *TreatmentPlanUseCase) GetPlan(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	if id <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+	return u.planRepo.GetByID(ctx, id)
+}
+
+// GetPatientPlans получает планы лечения пациента, начиная с последних
+func (u *TreatmentPlanUseCase) GetPatientPlans(ctx context.Context, patientID int) ([]*domain.TreatmentPlan, error) {
+	if patientID <= 0 {
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
+	}
+	if _, err := u.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, err
+	}
+	return u.planRepo.GetByPatientID(ctx, patientID)
+}
+
+// CreatePlan создает черновик плана лечения; автором плана считается врач из контекста
+func (u *TreatmentPlanUseCase) CreatePlan(ctx context.Context, plan *domain.TreatmentPlan) error {
+	if plan.PatientID <= 0 {
+		return domain.NewValidationError("patient_id", domain.FieldRequired, "patient is required")
+	}
+	if err := validateTreatmentPlan(plan); err != nil {
+		return err
+	}
+
+	plan.Status = domain.PlanDraft
+	plan.AcceptedVariantID, plan.AcceptedAt, plan.SignedBy, plan.DeclinedAt = 0, nil, "", nil
+	if doctor, ok := domain.DoctorFromContext(ctx); ok {
+		plan.DoctorID = doctor.ID
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := u.patientRepo.GetByID(ctx, plan.PatientID); err != nil {
+			return err
+		}
+		if err := u.resolveStepServices(ctx, plan); err != nil {
+			return err
+		}
+		return u.planRepo.Create(ctx, plan)
+	})
+}
+
+// UpdatePlan изменяет название, заметки и варианты черновика плана; пациент плана не меняется
+func (u *TreatmentPlanUseCase) UpdatePlan(ctx context.Context, plan *domain.TreatmentPlan) error {
+	if plan.ID <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+	if err := validateTreatmentPlan(plan); err != nil {
+		return err
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := u.planRepo.GetByID(ctx, plan.ID)
+		if err != nil {
+			return err
+		}
+		if existing.Status != domain.PlanDraft {
+			return domain.ErrTreatmentPlanNotEditable
+		}
+
+		plan.PatientID = existing.PatientID
+		plan.Status = existing.Status
+		plan.DoctorID = existing.DoctorID
+		plan.AcceptedVariantID, plan.AcceptedAt, plan.SignedBy, plan.DeclinedAt = 0, nil, "", nil
+		plan.CreatedAt = existing.CreatedAt
+
+		if err := u.resolveStepServices(ctx, plan); err != nil {
+			return err
+		}
+		return u.planRepo.Update(ctx, plan)
+	})
+}
+
+// DeletePlan удаляет план лечения; принятый план — подписанное согласие пациента, поэтому не удаляется
+func (u *TreatmentPlanUseCase) DeletePlan(ctx context.Context, id int) error {
+	if id <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		plan, err := u.planRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if plan.Status == domain.PlanAccepted {
+			return domain.ErrTreatmentPlanNotEditable.WithMessage("принятый план лечения нельзя удалить")
+		}
+		return u.planRepo.Delete(ctx, id)
+	})
+}
+
+// AcceptPlan записывает согласие пациента с вариантом variantID; подписавший указывается в signedBy.
+// Принять можно черновик или план, от которого пациент ранее отказался
+func (u *TreatmentPlanUseCase) AcceptPlan(ctx context.Context, id, variantID int, signedBy string) (*domain.TreatmentPlan, error) {
+	if id <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+	if variantID <= 0 {
+		return nil, domain.NewValidationError("variant_id", domain.FieldRequired, "variant is required")
+	}
+	signedBy = strings.TrimSpace(signedBy)
+	if signedBy == "" {
+		return nil, domain.NewValidationError("signed_by", domain.FieldRequired, "signer is required")
+	}
+	if len([]rune(signedBy)) > maxPlanSignerLength {
+		return nil, domain.NewValidationError("signed_by", domain.FieldTooLong, "signer is too long")
+	}
+
+	var plan *domain.TreatmentPlan
+	err := u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = u.planRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if plan.Status == domain.PlanAccepted {
+			return domain.ErrTreatmentPlanNotEditable.WithMessage("план лечения уже принят")
+		}
+		if plan.Variant(variantID) == nil {
+			return domain.NewValidationError("variant_id", domain.FieldInvalid, "variant does not belong to the treatment plan")
+		}
+
+		now := time.Now()
+		plan.Status = domain.PlanAccepted
+		plan.AcceptedVariantID = variantID
+		plan.AcceptedAt = &now
+		plan.SignedBy = signedBy
+		plan.DeclinedAt = nil
+		return u.planRepo.UpdateStatus(ctx, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// DeclinePlan записывает отказ пациента от плана; отказаться можно и от принятого плана, созданные записи остаются
+func (u *TreatmentPlanUseCase) DeclinePlan(ctx context.Context, id int) (*domain.TreatmentPlan, error) {
+	if id <= 0 {
+		return nil, domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+
+	var plan *domain.TreatmentPlan
+	err := u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = u.planRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if plan.Status == domain.PlanDeclined {
+			return domain.ErrTreatmentPlanNotEditable.WithMessage("пациент уже отказался от плана лечения")
+		}
+
+		now := time.Now()
+		plan.Status = domain.PlanDeclined
+		plan.DeclinedAt = &now
+		return u.planRepo.UpdateStatus(ctx, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ScheduleStep создает запись на прием по процедуре принятого варианта плана. Дата, врач и длительность
+// берутся из appointment; пациент, услуга и цена — из плана. Процедуру, последняя запись которой с учетом
+// переносов отменена или пропущена, можно записать снова
+func (u *TreatmentPlanUseCase) ScheduleStep(ctx context.Context, planID, stepID int, appointment *domain.Appointment) error {
+	if planID <= 0 {
+		return domain.NewValidationError("id", domain.FieldInvalid, "invalid treatment plan ID")
+	}
+	if stepID <= 0 {
+		return domain.NewValidationError("step_id", domain.FieldInvalid, "invalid plan step ID")
+	}
+
+	return u.uow.WithinTx(ctx, func(ctx context.Context) error {
+		plan, err := u.planRepo.GetByID(ctx, planID)
+		if err != nil {
+			return err
+		}
+		if plan.Status != domain.PlanAccepted {
+			return domain.ErrTreatmentPlanNotAccepted
+		}
+
+		var step *domain.TreatmentPlanStep
+		if variant := plan.Variant(plan.AcceptedVariantID); variant != nil {
+			for _, s := range variant.Steps {
+				if s.ID == stepID {
+					step = s
+				}
+			}
+		}
+		if step == nil {
+			return domain.ErrTreatmentPlanNotFound.WithMessage("процедура с ID %d не найдена в принятом варианте плана %d", stepID, planID)
+		}
+
+		if step.AppointmentID > 0 {
+			existing, err := u.latestAppointment(ctx, step.AppointmentID)
+			if err != nil {
+				return err
+			}
+			if existing.Status != domain.StatusCancelled && existing.Status != domain.StatusNoShow {
+				return domain.ErrPlanStepScheduled.WithMessage("по процедуре плана уже создана запись %d", existing.ID)
+			}
+		}
+
+		appointment.PatientID = plan.PatientID
+		appointment.ServiceID = step.ServiceID
+		appointment.Price = step.EstimatedPrice
+		if strings.TrimSpace(appointment.Notes) == "" {
+			appointment.Notes = fmt.Sprintf("%s, этап %d", plan.Title, step.Stage)
+		}
+		if err := u.appointments.CreateAppointment(ctx, appointment); err != nil {
+			return err
+		}
+
+		return u.planRepo.SetStepAppointment(ctx, step.ID, appointment.ID)
+	})
+}
+
+// latestAppointment проходит цепочку переносов от записи id до последней записи, как GetProgress в репозитории
+func (u *TreatmentPlanUseCase) latestAppointment(ctx context.Context, id int) (*domain.Appointment, error) {
+	appointment, err := u.appointments.GetAppointment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for appointment.Status == domain.StatusRescheduled {
+		next, _, err := u.appointments.ListAppointments(ctx, domain.AppointmentFilter{
+			RescheduledFromID: appointment.ID,
+			Page:              domain.Page{Limit: 1},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(next) == 0 {
+			break
+		}
+		appointment = next[0]
+	}
+
+	return appointment, nil
+}
+
+// GetPlanProgress получает выполнение и оплату принятых планов лечения; patientID = 0 — по всем пациентам
+func (u *TreatmentPlanUseCase) GetPlanProgress(ctx context.Context, patientID int) ([]*domain.TreatmentPlanProgress, error) {
+	if patientID < 0 {
+		return nil, domain.NewValidationError("patient_id", domain.FieldInvalid, "invalid patient ID")
+	}
+	return u.planRepo.GetProgress(ctx, patientID)
+}
+
+// resolveStepServices проверяет услуги процедур плана и подставляет текущую цену услуги, если оценка не указана
+func (u *TreatmentPlanUseCase) resolveStepServices(ctx context.Context, plan *domain.TreatmentPlan) error {
+	services := make(map[int]*domain.Service)
+	for _, variant := range plan.Variants {
+		variant.Total = 0
+		for _, step := range variant.Steps {
+			service, ok := services[step.ServiceID]
+			if !ok {
+				var err error
+				service, err = u.serviceRepo.GetByID(ctx, step.ServiceID)
+				if err != nil {
+					return err
+				}
+				services[step.ServiceID] = service
+			}
+
+			step.Service = service.Name
+			if step.EstimatedPrice == 0 {
+				step.EstimatedPrice = service.CurrentPrice
+			}
+			variant.Total += step.EstimatedPrice
+		}
+	}
+	return nil
+}
+
+// validateTreatmentPlan проверяет поля плана, его вариантов и процедур; этап процедуры по умолчанию первый
+func validateTreatmentPlan(plan *domain.TreatmentPlan) error {
+	plan.Title = strings.TrimSpace(plan.Title)
+	plan.Notes = strings.TrimSpace(plan.Notes)
+	switch {
+	case plan.Title == "":
+		return domain.NewValidationError("title", domain.FieldRequired, "title is required")
+	case len([]rune(plan.Title)) > maxPlanTitleLength:
+		return domain.NewValidationError("title", domain.FieldTooLong, "title is too long")
+	case len([]rune(plan.Notes)) > maxPlanNotesLength:
+		return domain.NewValidationError("notes", domain.FieldTooLong, "notes are too long")
+	case len(plan.Variants) == 0:
+		return domain.NewValidationError("variants", domain.FieldRequired, "at least one variant is required")
+	case len(plan.Variants) > maxPlanVariants:
+		return domain.NewValidationError("variants", domain.FieldRange, "too many variants")
+	}
+
+	for i, variant := range plan.Variants {
+		field := fmt.Sprintf("variants[%d]", i)
+
+		variant.Title = strings.TrimSpace(variant.Title)
+		switch {
+		case variant.Title == "":
+			return domain.NewValidationError(field+".title", domain.FieldRequired, "variant title is required")
+		case len([]rune(variant.Title)) > maxPlanTitleLength:
+			return domain.NewValidationError(field+".title", domain.FieldTooLong, "variant title is too long")
+		case len(variant.Steps) == 0:
+			return domain.NewValidationError(field+".steps", domain.FieldRequired, "at least one step is required")
+		case len(variant.Steps) > maxPlanSteps:
+			return domain.NewValidationError(field+".steps", domain.FieldRange, "too many steps")
+		}
+
+		for j, step := range variant.Steps {
+			field := fmt.Sprintf("%s.steps[%d]", field, j)
+
+			if step.ServiceID <= 0 {
+				return domain.NewValidationError(field+".service_id", domain.FieldRequired, "service is required")
+			}
+			if step.Stage == 0 {
+				step.Stage = 1
+			}
+			if step.Stage < 0 || step.Stage > maxPlanSteps {
+				return domain.NewValidationError(field+".stage", domain.FieldRange, "stage is out of range")
+			}
+			if step.Tooth != 0 && !domain.ValidToothNumber(step.Tooth) {
+				return domain.NewValidationError(field+".tooth", domain.FieldInvalid, "invalid FDI tooth number")
+			}
+			if step.EstimatedPrice.IsNegative() {
+				return domain.NewValidationError(field+".estimated_price", domain.FieldNegative, "estimated price cannot be negative")
+			}
+
+			step.Notes = strings.TrimSpace(step.Notes)
+			if len([]rune(step.Notes)) > maxPlanStepNotesLength {
+				return domain.NewValidationError(field+".notes", domain.FieldTooLong, "notes are too long")
+			}
+			step.AppointmentID = 0
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/gen/mocks/service"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type treatmentPlanMocks struct {
+	plans        *repository.MockTreatmentPlanRepository
+	patients     *repository.MockPatientRepository
+	services     *repository.MockServiceRepository
+	appointments *service.MockAppointmentService
+}
+
+func newTreatmentPlanTestUseCase(ctrl *gomock.Controller, setup func(treatmentPlanMocks)) *TreatmentPlanUseCase {
+	m := treatmentPlanMocks{
+		plans:        repository.NewMockTreatmentPlanRepository(ctrl),
+		patients:     repository.NewMockPatientRepository(ctrl),
+		services:     repository.NewMockServiceRepository(ctrl),
+		appointments: service.NewMockAppointmentService(ctrl),
+	}
+	setup(m)
+	return NewTreatmentPlanUseCase(m.plans, m.patients, m.services, m.appointments, newTestUnitOfWork(ctrl))
+}
+
+func newTestTreatmentPlan() *domain.TreatmentPlan {
+	return &domain.TreatmentPlan{
+		PatientID: 1,
+		Title:     " Восстановление 36 ",
+		Variants: []*domain.TreatmentPlanVariant{
+			{Title: "Имплант", Steps: []*domain.TreatmentPlanStep{
+				{ServiceID: 2, Tooth: 36, EstimatedPrice: domain.Tenge(250000)},
+				{ServiceID: 3, Tooth: 36, Stage: 2},
+			}},
+			{Title: "Мост", Steps: []*domain.TreatmentPlanStep{{ServiceID: 3, Tooth: 36, EstimatedPrice: domain.Tenge(180000)}}},
+		},
+	}
+}
+
+func TestTreatmentPlanUseCase_CreatePlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		plan       func() *domain.TreatmentPlan
+		setup      func(treatmentPlanMocks)
+		wantErr    bool
+		errMsg     string
+		wantTotals []domain.Money
+	}{
+		{
+			name: "estimates from price list",
+			plan: newTestTreatmentPlan,
+			setup: func(m treatmentPlanMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Имплантация"}, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 3).Return(&domain.Service{ID: 3, Name: "Коронка", CurrentPrice: domain.Tenge(90000)}, nil)
+				m.plans.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *domain.TreatmentPlan) error {
+					assert.Equal(t, domain.PlanDraft, plan.Status)
+					assert.Equal(t, 4, plan.DoctorID)
+					assert.Equal(t, "Восстановление 36", plan.Title)
+					return nil
+				})
+			},
+			wantTotals: []domain.Money{domain.Tenge(340000), domain.Tenge(180000)},
+		},
+		{
+			name: "missing title",
+			plan: func() *domain.TreatmentPlan {
+				plan := newTestTreatmentPlan()
+				plan.Title = " "
+				return plan
+			},
+			setup:   func(m treatmentPlanMocks) {},
+			wantErr: true,
+			errMsg:  "title is required",
+		},
+		{
+			name: "no variants",
+			plan: func() *domain.TreatmentPlan {
+				return &domain.TreatmentPlan{PatientID: 1, Title: "План"}
+			},
+			setup:   func(m treatmentPlanMocks) {},
+			wantErr: true,
+			errMsg:  "at least one variant is required",
+		},
+		{
+			name: "variant without steps",
+			plan: func() *domain.TreatmentPlan {
+				plan := newTestTreatmentPlan()
+				plan.Variants[1].Steps = nil
+				return plan
+			},
+			setup:   func(m treatmentPlanMocks) {},
+			wantErr: true,
+			errMsg:  "at least one step is required",
+		},
+		{
+			name: "invalid tooth",
+			plan: func() *domain.TreatmentPlan {
+				plan := newTestTreatmentPlan()
+				plan.Variants[0].Steps[0].Tooth = 19
+				return plan
+			},
+			setup:   func(m treatmentPlanMocks) {},
+			wantErr: true,
+			errMsg:  "invalid FDI tooth number",
+		},
+		{
+			name: "negative estimate",
+			plan: func() *domain.TreatmentPlan {
+				plan := newTestTreatmentPlan()
+				plan.Variants[0].Steps[0].EstimatedPrice = domain.Tenge(-1)
+				return plan
+			},
+			setup:   func(m treatmentPlanMocks) {},
+			wantErr: true,
+			errMsg:  "estimated price cannot be negative",
+		},
+		{
+			name: "service not found",
+			plan: newTestTreatmentPlan,
+			setup: func(m treatmentPlanMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 2 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 2 не найдена",
+		},
+		{
+			name: "service lookup error",
+			plan: newTestTreatmentPlan,
+			setup: func(m treatmentPlanMocks) {
+				m.patients.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Patient{ID: 1}, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(nil, errors.New("pq: could not serialize access"))
+			},
+			wantErr: true,
+			errMsg:  "could not serialize access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentPlanTestUseCase(ctrl, tt.setup)
+			ctx := domain.WithDoctor(context.Background(), &domain.Doctor{ID: 4})
+			plan := tt.plan()
+			err := uc.CreatePlan(ctx, plan)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				for i, total := range tt.wantTotals {
+					assert.Equal(t, total, plan.Variants[i].Total)
+				}
+				assert.Equal(t, 2, plan.Variants[0].Steps[1].Stage)
+				assert.Equal(t, 1, plan.Variants[0].Steps[0].Stage)
+			}
+		})
+	}
+}
+
+func TestTreatmentPlanUseCase_AcceptPlan(t *testing.T) {
+	newPlan := func(status domain.TreatmentPlanStatus) *domain.TreatmentPlan {
+		return &domain.TreatmentPlan{
+			ID: 1, PatientID: 1, Status: status,
+			Variants: []*domain.TreatmentPlanVariant{{ID: 10}, {ID: 11}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		variantID int
+		signedBy  string
+		setup     func(treatmentPlanMocks)
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "accept draft",
+			variantID: 11,
+			signedBy:  " Иванов И.И. ",
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanDraft), nil)
+				m.plans.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *domain.TreatmentPlan) error {
+					assert.Equal(t, domain.PlanAccepted, plan.Status)
+					assert.Equal(t, 11, plan.AcceptedVariantID)
+					assert.Equal(t, "Иванов И.И.", plan.SignedBy)
+					assert.NotNil(t, plan.AcceptedAt)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "accept after decline",
+			variantID: 10,
+			signedBy:  "Иванов И.И.",
+			setup: func(m treatmentPlanMocks) {
+				plan := newPlan(domain.PlanDeclined)
+				declinedAt := time.Now()
+				plan.DeclinedAt = &declinedAt
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(plan, nil)
+				m.plans.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *domain.TreatmentPlan) error {
+					assert.Nil(t, plan.DeclinedAt)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "missing signer",
+			variantID: 10,
+			setup:     func(m treatmentPlanMocks) {},
+			wantErr:   true,
+			errMsg:    "signer is required",
+		},
+		{
+			name:      "foreign variant",
+			variantID: 99,
+			signedBy:  "Иванов И.И.",
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanDraft), nil)
+			},
+			wantErr: true,
+			errMsg:  "variant does not belong to the treatment plan",
+		},
+		{
+			name:      "already accepted",
+			variantID: 10,
+			signedBy:  "Иванов И.И.",
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted), nil)
+			},
+			wantErr: true,
+			errMsg:  "уже принят",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentPlanTestUseCase(ctrl, tt.setup)
+			plan, err := uc.AcceptPlan(context.Background(), 1, tt.variantID, tt.signedBy)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, domain.PlanAccepted, plan.Status)
+			}
+		})
+	}
+}
+
+func TestTreatmentPlanUseCase_UpdatePlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := newTreatmentPlanTestUseCase(ctrl, func(m treatmentPlanMocks) {
+		m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.TreatmentPlan{ID: 1, Status: domain.PlanAccepted}, nil)
+	})
+	plan := newTestTreatmentPlan()
+	plan.ID = 1
+	err := uc.UpdatePlan(context.Background(), plan)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrTreatmentPlanNotEditable))
+}
+
+func TestTreatmentPlanUseCase_ScheduleStep(t *testing.T) {
+	date := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	newPlan := func(status domain.TreatmentPlanStatus, appointmentID int) *domain.TreatmentPlan {
+		return &domain.TreatmentPlan{
+			ID: 1, PatientID: 8, Title: "Восстановление 36", Status: status, AcceptedVariantID: 10,
+			Variants: []*domain.TreatmentPlanVariant{
+				{ID: 10, Steps: []*domain.TreatmentPlanStep{
+					{ID: 100, VariantID: 10, Stage: 2, ServiceID: 3, EstimatedPrice: domain.Tenge(90000), AppointmentID: appointmentID},
+				}},
+				{ID: 11, Steps: []*domain.TreatmentPlanStep{{ID: 101, VariantID: 11, ServiceID: 3}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		stepID  int
+		setup   func(treatmentPlanMocks)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:   "creates appointment",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 0), nil)
+				m.appointments.EXPECT().CreateAppointment(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, appointment *domain.Appointment) error {
+					assert.Equal(t, 8, appointment.PatientID)
+					assert.Equal(t, 3, appointment.ServiceID)
+					assert.Equal(t, 2, appointment.DoctorID)
+					assert.Equal(t, domain.Tenge(90000), appointment.Price)
+					assert.Equal(t, "Восстановление 36, этап 2", appointment.Notes)
+					appointment.ID = 50
+					return nil
+				})
+				m.plans.EXPECT().SetStepAppointment(gomock.Any(), 100, 50).Return(nil)
+			},
+		},
+		{
+			name:   "reschedule after cancellation",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 40), nil)
+				m.appointments.EXPECT().GetAppointment(gomock.Any(), 40).Return(&domain.Appointment{ID: 40, Status: domain.StatusCancelled}, nil)
+				m.appointments.EXPECT().CreateAppointment(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, appointment *domain.Appointment) error {
+					appointment.ID = 51
+					return nil
+				})
+				m.plans.EXPECT().SetStepAppointment(gomock.Any(), 100, 51).Return(nil)
+			},
+		},
+		{
+			name:   "reschedule after cancellation of moved appointment",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 40), nil)
+				m.appointments.EXPECT().GetAppointment(gomock.Any(), 40).Return(&domain.Appointment{ID: 40, Status: domain.StatusRescheduled}, nil)
+				m.appointments.EXPECT().ListAppointments(gomock.Any(), domain.AppointmentFilter{RescheduledFromID: 40, Page: domain.Page{Limit: 1}}).
+					Return([]*domain.Appointment{{ID: 45, Status: domain.StatusCancelled, RescheduledFromID: 40}}, 1, nil)
+				m.appointments.EXPECT().CreateAppointment(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, appointment *domain.Appointment) error {
+					appointment.ID = 52
+					return nil
+				})
+				m.plans.EXPECT().SetStepAppointment(gomock.Any(), 100, 52).Return(nil)
+			},
+		},
+		{
+			name:   "moved appointment still scheduled",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 40), nil)
+				m.appointments.EXPECT().GetAppointment(gomock.Any(), 40).Return(&domain.Appointment{ID: 40, Status: domain.StatusRescheduled}, nil)
+				m.appointments.EXPECT().ListAppointments(gomock.Any(), domain.AppointmentFilter{RescheduledFromID: 40, Page: domain.Page{Limit: 1}}).
+					Return([]*domain.Appointment{{ID: 45, Status: domain.StatusScheduled, RescheduledFromID: 40}}, 1, nil)
+			},
+			wantErr: true,
+			errMsg:  "уже создана запись 45",
+		},
+		{
+			name:   "already scheduled",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 40), nil)
+				m.appointments.EXPECT().GetAppointment(gomock.Any(), 40).Return(&domain.Appointment{ID: 40, Status: domain.StatusConfirmed}, nil)
+			},
+			wantErr: true,
+			errMsg:  "уже создана запись 40",
+		},
+		{
+			name:   "plan not accepted",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanDraft, 0), nil)
+			},
+			wantErr: true,
+			errMsg:  "has not been accepted",
+		},
+		{
+			name:   "step of another variant",
+			stepID: 101,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 0), nil)
+			},
+			wantErr: true,
+			errMsg:  "не найдена в принятом варианте",
+		},
+		{
+			name:   "doctor unavailable",
+			stepID: 100,
+			setup: func(m treatmentPlanMocks) {
+				m.plans.EXPECT().GetByID(gomock.Any(), 1).Return(newPlan(domain.PlanAccepted, 0), nil)
+				m.appointments.EXPECT().CreateAppointment(gomock.Any(), gomock.Any()).Return(domain.ErrDoctorUnavailable)
+			},
+			wantErr: true,
+			errMsg:  "doctor is not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentPlanTestUseCase(ctrl, tt.setup)
+			appointment := &domain.Appointment{Date: date, DoctorID: 2}
+			err := uc.ScheduleStep(context.Background(), 1, tt.stepID, appointment)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
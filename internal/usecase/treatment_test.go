@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type treatmentMocks struct {
+	treatments   *repository.MockTreatmentRepository
+	appointments *repository.MockAppointmentRepository
+	services     *repository.MockServiceRepository
+	chart        *repository.MockDentalChartRepository
+	catalog      *repository.MockDiagnosisRepository
+}
+
+func newTreatmentTestUseCase(ctrl *gomock.Controller, setup func(treatmentMocks)) *TreatmentUseCase {
+	m := treatmentMocks{
+		treatments:   repository.NewMockTreatmentRepository(ctrl),
+		appointments: repository.NewMockAppointmentRepository(ctrl),
+		services:     repository.NewMockServiceRepository(ctrl),
+		chart:        repository.NewMockDentalChartRepository(ctrl),
+		catalog:      repository.NewMockDiagnosisRepository(ctrl),
+	}
+	setup(m)
+	return NewTreatmentUseCase(m.treatments, m.appointments, m.services, m.chart, m.catalog, newTestUnitOfWork(ctrl))
+}
+
+func TestTreatmentUseCase_AddTreatment(t *testing.T) {
+	appointmentDate := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{ID: 5, PatientID: 1, Date: appointmentDate, Status: domain.StatusInProgress}
+
+	tests := []struct {
+		name         string
+		treatment    *domain.Treatment
+		setup        func(treatmentMocks)
+		wantErr      bool
+		errMsg       string
+		wantPrice    domain.Money
+		wantSurfaces []domain.ToothSurface
+	}{
+		{
+			name: "filling updates chart",
+			treatment: &domain.Treatment{
+				AppointmentID: 5, ServiceID: 2, Tooth: 36, Surfaces: []domain.ToothSurface{"d", "O", "M"},
+				ToothStatus: domain.ToothFilled, DiagnosisCode: " k02.1 ", Price: domain.Tenge(25000),
+			},
+			setup: func(m treatmentMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K02.1").Return(&domain.Diagnosis{Code: "K02.1", Category: "K02"}, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Пломба"}, nil)
+				m.treatments.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				m.chart.EXPECT().RecordChanges(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, changes []*domain.ToothChange) error {
+					require.Len(t, changes, 1)
+					assert.Equal(t, 1, changes[0].PatientID)
+					assert.Equal(t, 36, changes[0].Tooth)
+					assert.Equal(t, domain.ToothFilled, changes[0].Status)
+					assert.Equal(t, 5, changes[0].AppointmentID)
+					assert.Equal(t, 3, changes[0].DoctorID)
+					assert.Equal(t, "Пломба", changes[0].Notes)
+					return nil
+				})
+			},
+			wantPrice:    domain.Tenge(25000),
+			wantSurfaces: []domain.ToothSurface{domain.SurfaceMesial, domain.SurfaceOcclusal, domain.SurfaceDistal},
+		},
+		{
+			name:      "price from price list",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2},
+			setup: func(m treatmentMocks) {
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Консультация"}, nil)
+				m.services.EXPECT().GetPriceAt(gomock.Any(), 2, appointmentDate).Return(domain.Tenge(5000), nil)
+				m.treatments.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantPrice:    domain.Tenge(5000),
+			wantSurfaces: []domain.ToothSurface{},
+		},
+		{
+			name:      "missing service",
+			treatment: &domain.Treatment{AppointmentID: 5},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "service is required",
+		},
+		{
+			name:      "negative price",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, Price: domain.Tenge(-1)},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "price cannot be negative",
+		},
+		{
+			name:      "invalid tooth",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, Tooth: 49},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "invalid FDI tooth number",
+		},
+		{
+			name:      "surfaces without tooth",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, Surfaces: []domain.ToothSurface{"O"}},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "tooth is required",
+		},
+		{
+			name:      "filling without surfaces",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, Tooth: 36, ToothStatus: domain.ToothFilled},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "at least one surface is required",
+		},
+		{
+			name:      "invalid diagnosis code",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, DiagnosisCode: "caries"},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "invalid ICD-10 code",
+		},
+		{
+			name:      "diagnosis code not in catalog",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, DiagnosisCode: "J06.9"},
+			setup: func(m treatmentMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "J06.9").Return(nil, domain.ErrDiagnosisNotFound)
+			},
+			wantErr: true,
+			errMsg:  "not in the dental diagnosis catalog",
+		},
+		{
+			name:      "diagnosis category with subcodes",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2, DiagnosisCode: "K02"},
+			setup: func(m treatmentMocks) {
+				m.catalog.EXPECT().GetByCode(gomock.Any(), "K02").Return(&domain.Diagnosis{Code: "K02", Category: "K02", HasSubcodes: true}, nil)
+			},
+			wantErr: true,
+			errMsg:  "use a more specific ICD-10 code",
+		},
+		{
+			name:      "cancelled appointment",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2},
+			setup: func(m treatmentMocks) {
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Appointment{ID: 5, Status: domain.StatusCancelled}, nil)
+			},
+			wantErr: true,
+			errMsg:  "appointment was cancelled",
+		},
+		{
+			name:      "service not found",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 99},
+			setup: func(m treatmentMocks) {
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 99).Return(nil, domain.ErrServiceNotFound.WithMessage("услуга с ID 99 не найдена"))
+			},
+			wantErr: true,
+			errMsg:  "услуга с ID 99 не найдена",
+		},
+		{
+			name:      "service lookup error",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 1},
+			setup: func(m treatmentMocks) {
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.New("pq: could not serialize access"))
+			},
+			wantErr: true,
+			errMsg:  "could not serialize access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentTestUseCase(ctrl, tt.setup)
+			ctx := domain.WithDoctor(context.Background(), &domain.Doctor{ID: 3})
+			err := uc.AddTreatment(ctx, tt.treatment)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantPrice, tt.treatment.Price)
+				assert.Equal(t, tt.wantSurfaces, tt.treatment.Surfaces)
+			}
+		})
+	}
+}
+
+func TestTreatmentUseCase_UpdateTreatment(t *testing.T) {
+	appointment := &domain.Appointment{ID: 5, PatientID: 1, Status: domain.StatusCompleted}
+	existing := &domain.Treatment{
+		ID: 7, AppointmentID: 5, ServiceID: 2, Tooth: 36, ToothStatus: domain.ToothFilled,
+		Surfaces: []domain.ToothSurface{domain.SurfaceOcclusal}, Price: domain.Tenge(20000),
+	}
+
+	tests := []struct {
+		name      string
+		treatment *domain.Treatment
+		setup     func(treatmentMocks)
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name: "same tooth state keeps chart",
+			treatment: &domain.Treatment{
+				ID: 7, AppointmentID: 5, ServiceID: 2, Tooth: 36, ToothStatus: domain.ToothFilled,
+				Surfaces: []domain.ToothSurface{"O"}, Comment: "polished", Price: domain.Tenge(20000),
+			},
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(existing, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Пломба"}, nil)
+				m.treatments.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "changed surfaces update chart",
+			treatment: &domain.Treatment{
+				ID: 7, AppointmentID: 5, ServiceID: 2, Tooth: 36, ToothStatus: domain.ToothFilled,
+				Surfaces: []domain.ToothSurface{"M", "O"}, Price: domain.Tenge(30000),
+			},
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(existing, nil)
+				m.appointments.EXPECT().GetByID(gomock.Any(), 5).Return(appointment, nil)
+				m.services.EXPECT().GetByID(gomock.Any(), 2).Return(&domain.Service{ID: 2, Name: "Пломба"}, nil)
+				m.treatments.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				m.chart.EXPECT().RecordChanges(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:      "treatment of another appointment",
+			treatment: &domain.Treatment{ID: 7, AppointmentID: 6, ServiceID: 2},
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(existing, nil)
+			},
+			wantErr: true,
+			errMsg:  "не найдена в записи 6",
+		},
+		{
+			name:      "invalid id",
+			treatment: &domain.Treatment{AppointmentID: 5, ServiceID: 2},
+			setup:     func(m treatmentMocks) {},
+			wantErr:   true,
+			errMsg:    "invalid treatment ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentTestUseCase(ctrl, tt.setup)
+			err := uc.UpdateTreatment(context.Background(), tt.treatment)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTreatmentUseCase_DeleteTreatment(t *testing.T) {
+	tests := []struct {
+		name          string
+		appointmentID int
+		setup         func(treatmentMocks)
+		wantErr       bool
+		errMsg        string
+	}{
+		{
+			name:          "success",
+			appointmentID: 5,
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(&domain.Treatment{ID: 7, AppointmentID: 5}, nil)
+				m.treatments.EXPECT().Delete(gomock.Any(), 7).Return(nil)
+			},
+		},
+		{
+			name:          "treatment of another appointment",
+			appointmentID: 6,
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(&domain.Treatment{ID: 7, AppointmentID: 5}, nil)
+			},
+			wantErr: true,
+			errMsg:  "не найдена в записи 6",
+		},
+		{
+			name:          "not found",
+			appointmentID: 5,
+			setup: func(m treatmentMocks) {
+				m.treatments.EXPECT().GetByID(gomock.Any(), 7).Return(nil, domain.ErrTreatmentNotFound)
+			},
+			wantErr: true,
+			errMsg:  "treatment not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := newTreatmentTestUseCase(ctrl, tt.setup)
+			err := uc.DeleteTreatment(context.Background(), tt.appointmentID, 7)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/sdk17/crmstom/internal/domain"
+)
+
+// Параметры входа со вторым фактором
+const (
+	MFAChallengeTTL        = 5 * time.Minute
+	maxMFAChallengeRetries = 5
+	recoveryCodeCount      = 10
+)
+
+type TwoFactorUseCase struct {
+	doctorRepo       domain.DoctorRepository
+	twoFactorRepo    domain.TwoFactorRepository
+	loginAttemptRepo domain.LoginAttemptRepository
+	requireForAdmins bool
+	issuer           string
+	now              func() time.Time
+	sleep            func(time.Duration)
+}
+
+// NewTwoFactorUseCase создает use case второго фактора; requireForAdmins обязывает администраторов подключить TOTP,
+// issuer отображается в приложении-аутентификаторе
+func NewTwoFactorUseCase(
+	doctorRepo domain.DoctorRepository,
+	twoFactorRepo domain.TwoFactorRepository,
+	loginAttemptRepo domain.LoginAttemptRepository,
+	requireForAdmins bool,
+	issuer string,
+) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		doctorRepo:       doctorRepo,
+		twoFactorRepo:    twoFactorRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		requireForAdmins: requireForAdmins,
+		issuer:           issuer,
+		now:              time.Now,
+		sleep:            time.Sleep,
+	}
+}
+
+// BeginLogin решает, нужен ли второй фактор после проверки пароля.
+// Возвращает nil, если сессию можно открыть сразу. Администратор без TOTP при обязательной политике
+// получает проверку с подключением: секрет и otpauth URI выдаются в ответе на вход.
+func (u *TwoFactorUseCase) BeginLogin(ctx context.Context, doctor *domain.Doctor) (*domain.MFAChallenge, error) {
+	enrollment := !doctor.TOTPEnabled && u.requiredFor(doctor)
+	if !doctor.TOTPEnabled && !enrollment {
+		return nil, nil
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &domain.MFAChallenge{
+		DoctorID:   doctor.ID,
+		Token:      token,
+		TokenHash:  hashToken(token),
+		Enrollment: enrollment,
+		ExpiresAt:  u.now().Add(MFAChallengeTTL),
+	}
+
+	if enrollment {
+		enroll, err := u.startEnrollment(ctx, doctor)
+		if err != nil {
+			return nil, err
+		}
+		challenge.Secret = enroll.Secret
+		challenge.OTPAuthURI = enroll.OTPAuthURI
+	}
+
+	if err := u.twoFactorRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// VerifyLogin завершает вход кодом TOTP или кодом восстановления.
+// Неверные коды записываются в журнал входов и учитываются в тех же ограничениях по IP и учетной записи,
+// что и неверные пароли. Для проверки с подключением включает TOTP и возвращает новые коды восстановления.
+func (u *TwoFactorUseCase) VerifyLogin(ctx context.Context, mfaToken, code, ip string) (*domain.Doctor, []string, error) {
+	if mfaToken == "" {
+		return nil, nil, domain.ErrInvalidMFAChallenge
+	}
+
+	challenge, err := u.twoFactorRepo.GetChallenge(ctx, hashToken(mfaToken))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if challenge == nil || challenge.UsedAt != nil || !u.now().Before(challenge.ExpiresAt) ||
+		challenge.Attempts >= maxMFAChallengeRetries {
+		return nil, nil, domain.ErrInvalidMFAChallenge
+	}
+
+	doctor, err := u.getDoctor(ctx, challenge.DoctorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := u.now()
+
+	ipFailures, err := u.loginAttemptRepo.CountFailuresByIP(ctx, ip, now.Add(-LoginAttemptWindow))
+	if err != nil {
+		return nil, nil, err
+	}
+	if ipFailures >= MaxFailedLoginsPerIP {
+		recordLoginAttempt(ctx, u.loginAttemptRepo, doctor.Login, doctor, ip, domain.LoginReasonIPBlocked)
+		return nil, nil, domain.ErrTooManyLoginAttempts
+	}
+
+	if doctor.IsLocked(now) {
+		recordLoginAttempt(ctx, u.loginAttemptRepo, doctor.Login, doctor, ip, domain.LoginReasonAccountLocked)
+		return nil, nil, domain.ErrAccountLocked
+	}
+
+	var ok bool
+	if challenge.Enrollment {
+		ok, err = u.checkTOTP(ctx, doctor, code)
+	} else {
+		ok, err = u.checkCode(ctx, doctor, code)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !ok {
+		if err := u.twoFactorRepo.IncrementChallengeAttempts(ctx, challenge.ID); err != nil {
+			return nil, nil, err
+		}
+		failures, err := registerLoginFailure(ctx, u.doctorRepo, doctor.ID, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		recordLoginAttempt(ctx, u.loginAttemptRepo, doctor.Login, doctor, ip, domain.LoginReasonInvalidMFACode)
+		u.sleep(loginDelay(failures))
+		return nil, nil, domain.ErrInvalidMFACode
+	}
+
+	if err := u.twoFactorRepo.MarkChallengeUsed(ctx, challenge.ID); err != nil {
+		return nil, nil, domain.ErrInvalidMFAChallenge
+	}
+
+	if doctor.FailedLoginAttempts > 0 || doctor.LockedUntil != nil {
+		if err := u.doctorRepo.ResetFailedLogins(ctx, doctor.ID); err != nil {
+			return nil, nil, err
+		}
+		doctor.FailedLoginAttempts = 0
+		doctor.LockedUntil = nil
+	}
+
+	var recoveryCodes []string
+	if challenge.Enrollment {
+		if recoveryCodes, err = u.enable(ctx, doctor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	doctor.Password = ""
+	doctor.TOTPSecret = ""
+
+	return doctor, recoveryCodes, nil
+}
+
+// Enroll начинает подключение TOTP для врача с открытой сессией
+func (u *TwoFactorUseCase) Enroll(ctx context.Context, doctorID int) (*domain.TOTPEnrollment, error) {
+	doctor, err := u.getDoctor(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doctor.TOTPEnabled {
+		return nil, domain.ErrTOTPAlreadyEnabled
+	}
+
+	return u.startEnrollment(ctx, doctor)
+}
+
+// ConfirmEnrollment завершает подключение TOTP первым кодом из приложения и возвращает коды восстановления
+func (u *TwoFactorUseCase) ConfirmEnrollment(ctx context.Context, doctorID int, code string) ([]string, error) {
+	doctor, err := u.getDoctor(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doctor.TOTPEnabled {
+		return nil, domain.ErrTOTPAlreadyEnabled
+	}
+
+	ok, err := u.checkTOTP(ctx, doctor, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	return u.enable(ctx, doctor)
+}
+
+// RegenerateRecoveryCodes выпускает новые коды восстановления взамен старых после проверки кода
+func (u *TwoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, doctorID int, code string) ([]string, error) {
+	doctor, err := u.getDoctor(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !doctor.TOTPEnabled {
+		return nil, domain.ErrTOTPNotEnrolled
+	}
+
+	ok, err := u.checkTOTP(ctx, doctor, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	return u.issueRecoveryCodes(ctx, doctor.ID)
+}
+
+// Disable отключает TOTP после проверки кода; при обязательной политике администратор отключить TOTP не может
+func (u *TwoFactorUseCase) Disable(ctx context.Context, doctorID int, code string) error {
+	doctor, err := u.getDoctor(ctx, doctorID)
+	if err != nil {
+		return err
+	}
+
+	if !doctor.TOTPEnabled {
+		return domain.ErrTOTPNotEnrolled
+	}
+
+	if u.requiredFor(doctor) {
+		return domain.ErrTOTPRequiredForAdmin
+	}
+
+	ok, err := u.checkCode(ctx, doctor, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	return u.twoFactorRepo.DisableTOTP(ctx, doctor.ID)
+}
+
+// requiredFor сообщает, обязателен ли второй фактор для врача по политике
+func (u *TwoFactorUseCase) requiredFor(doctor *domain.Doctor) bool {
+	return u.requireForAdmins && doctor.EffectiveRole() == domain.RoleAdmin
+}
+
+// startEnrollment генерирует и сохраняет новый секрет незавершенного подключения
+func (u *TwoFactorUseCase) startEnrollment(ctx context.Context, doctor *domain.Doctor) (*domain.TOTPEnrollment, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.twoFactorRepo.SetTOTPSecret(ctx, doctor.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totpURI(u.issuer, doctor.Login, secret),
+	}, nil
+}
+
+// enable включает TOTP и выпускает коды восстановления
+func (u *TwoFactorUseCase) enable(ctx context.Context, doctor *domain.Doctor) ([]string, error) {
+	if err := u.twoFactorRepo.EnableTOTP(ctx, doctor.ID); err != nil {
+		return nil, err
+	}
+	doctor.TOTPEnabled = true
+
+	return u.issueRecoveryCodes(ctx, doctor.ID)
+}
+
+// checkCode принимает код TOTP или одноразовый код восстановления
+func (u *TwoFactorUseCase) checkCode(ctx context.Context, doctor *domain.Doctor, code string) (bool, error) {
+	if ok, err := u.checkTOTP(ctx, doctor, code); ok || err != nil {
+		return ok, err
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+
+	return u.twoFactorRepo.UseRecoveryCode(ctx, doctor.ID, hashToken(normalized))
+}
+
+// checkTOTP проверяет код TOTP и отклоняет повторное использование уже принятого кода
+func (u *TwoFactorUseCase) checkTOTP(ctx context.Context, doctor *domain.Doctor, code string) (bool, error) {
+	if doctor.TOTPSecret == "" {
+		return false, domain.ErrTOTPNotEnrolled
+	}
+
+	step, ok := validateTOTP(doctor.TOTPSecret, code, u.now())
+	if !ok {
+		return false, nil
+	}
+
+	return u.twoFactorRepo.UseTOTPStep(ctx, doctor.ID, step)
+}
+
+// issueRecoveryCodes генерирует коды восстановления; в базе хранятся только их хеши
+func (u *TwoFactorUseCase) issueRecoveryCodes(ctx context.Context, doctorID int) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	if err := u.twoFactorRepo.ReplaceRecoveryCodes(ctx, doctorID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// getDoctor получает врача или возвращает domain.ErrDoctorNotFound
+func (u *TwoFactorUseCase) getDoctor(ctx context.Context, id int) (*domain.Doctor, error) {
+	doctor, err := u.doctorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if doctor == nil {
+		return nil, domain.ErrDoctorNotFound
+	}
+	return doctor, nil
+}
+
+// normalizeRecoveryCode приводит код восстановления к виду, в котором он хешируется
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	if len(code) != 8 {
+		return ""
+	}
+	return code
+}
@@ -0,0 +1,592 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdk17/crmstom/gen/mocks/repository"
+	"github.com/sdk17/crmstom/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var twoFactorNow = time.Unix(1111111109, 0)
+
+func newTestTwoFactorUseCase(doctorRepo domain.DoctorRepository, twoFactorRepo domain.TwoFactorRepository, loginAttemptRepo domain.LoginAttemptRepository, requireForAdmins bool) *TwoFactorUseCase {
+	uc := NewTwoFactorUseCase(doctorRepo, twoFactorRepo, loginAttemptRepo, requireForAdmins, "CRM Стоматология")
+	uc.now = func() time.Time { return twoFactorNow }
+	uc.sleep = func(time.Duration) {}
+	return uc
+}
+
+func TestTwoFactorUseCase_BeginLogin(t *testing.T) {
+	tests := []struct {
+		name             string
+		doctor           *domain.Doctor
+		requireForAdmins bool
+		setup            func(*repository.MockTwoFactorRepository)
+		wantChallenge    bool
+		wantEnrollment   bool
+		wantErr          bool
+	}{
+		{
+			name:   "second factor not enabled",
+			doctor: &domain.Doctor{ID: 1, Login: "doctor1", Role: domain.RoleDoctor},
+			setup:  func(r *repository.MockTwoFactorRepository) {},
+		},
+		{
+			name:   "admin without policy",
+			doctor: &domain.Doctor{ID: 1, Login: "admin", Role: domain.RoleAdmin},
+			setup:  func(r *repository.MockTwoFactorRepository) {},
+		},
+		{
+			name:             "policy does not apply to doctors",
+			doctor:           &domain.Doctor{ID: 1, Login: "doctor1", Role: domain.RoleDoctor},
+			requireForAdmins: true,
+			setup:            func(r *repository.MockTwoFactorRepository) {},
+		},
+		{
+			name:   "totp enabled",
+			doctor: &domain.Doctor{ID: 1, Login: "doctor1", Role: domain.RoleDoctor, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c *domain.MFAChallenge) error {
+					assert.Equal(t, 1, c.DoctorID)
+					assert.Equal(t, hashToken(c.Token), c.TokenHash)
+					assert.Equal(t, twoFactorNow.Add(MFAChallengeTTL), c.ExpiresAt)
+					assert.False(t, c.Enrollment)
+					return nil
+				})
+			},
+			wantChallenge: true,
+		},
+		{
+			name:             "admin without totp must enroll",
+			doctor:           &domain.Doctor{ID: 1, Login: "admin", Role: domain.RoleAdmin},
+			requireForAdmins: true,
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().SetTOTPSecret(gomock.Any(), 1, gomock.Any()).Return(nil)
+				r.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c *domain.MFAChallenge) error {
+					assert.True(t, c.Enrollment)
+					return nil
+				})
+			},
+			wantChallenge:  true,
+			wantEnrollment: true,
+		},
+		{
+			name:   "repository error",
+			doctor: &domain.Doctor{ID: 1, Login: "doctor1", Role: domain.RoleDoctor, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().CreateChallenge(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			twoFactorRepo := repository.NewMockTwoFactorRepository(ctrl)
+			tt.setup(twoFactorRepo)
+			uc := newTestTwoFactorUseCase(doctorRepo, twoFactorRepo, nil, tt.requireForAdmins)
+
+			challenge, err := uc.BeginLogin(context.Background(), tt.doctor)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, challenge)
+				return
+			}
+
+			require.NoError(t, err)
+			if !tt.wantChallenge {
+				assert.Nil(t, challenge)
+				return
+			}
+
+			require.NotNil(t, challenge)
+			assert.NotEmpty(t, challenge.Token)
+			assert.Equal(t, tt.wantEnrollment, challenge.Enrollment)
+			if tt.wantEnrollment {
+				assert.NotEmpty(t, challenge.Secret)
+				assert.Contains(t, challenge.OTPAuthURI, "otpauth://totp/")
+				assert.Contains(t, challenge.OTPAuthURI, challenge.Secret)
+			} else {
+				assert.Empty(t, challenge.Secret)
+				assert.Empty(t, challenge.OTPAuthURI)
+			}
+		})
+	}
+}
+
+func TestTwoFactorUseCase_VerifyLogin(t *testing.T) {
+	const token = "mfa-token"
+	validCode, err := totpCode(rfc6238Secret, totpStep(twoFactorNow))
+	require.NoError(t, err)
+
+	enabledDoctor := func() *domain.Doctor {
+		return &domain.Doctor{ID: 1, Login: "doctor1", Password: "hash", Role: domain.RoleDoctor, TOTPEnabled: true, TOTPSecret: rfc6238Secret}
+	}
+	challenge := func(enrollment bool) *domain.MFAChallenge {
+		return &domain.MFAChallenge{ID: 7, DoctorID: 1, TokenHash: hashToken(token), Enrollment: enrollment, ExpiresAt: twoFactorNow.Add(time.Minute)}
+	}
+	usedAt := twoFactorNow.Add(-time.Minute)
+	noMocks := func(*repository.MockDoctorRepository, *repository.MockTwoFactorRepository, *repository.MockLoginAttemptRepository) {
+	}
+
+	tests := []struct {
+		name              string
+		token             string
+		code              string
+		setup             func(*repository.MockDoctorRepository, *repository.MockTwoFactorRepository, *repository.MockLoginAttemptRepository)
+		wantErr           error
+		wantRecoveryCodes bool
+	}{
+		{
+			name:  "valid totp code",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().MarkChallengeUsed(gomock.Any(), 7).Return(nil)
+			},
+		},
+		{
+			name:  "valid recovery code",
+			token: token,
+			code:  "ABCD-EFGH",
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseRecoveryCode(gomock.Any(), 1, hashToken("abcdefgh")).Return(true, nil)
+				r.EXPECT().MarkChallengeUsed(gomock.Any(), 7).Return(nil)
+			},
+		},
+		{
+			name:  "replayed totp code",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(false, nil)
+				r.EXPECT().IncrementChallengeAttempts(gomock.Any(), 7).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
+				expectMFAFailureRecorded(t, a)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:  "wrong code",
+			token: token,
+			code:  "000000",
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().IncrementChallengeAttempts(gomock.Any(), 7).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
+				expectMFAFailureRecorded(t, a)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:  "used recovery code",
+			token: token,
+			code:  "abcd-efgh",
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseRecoveryCode(gomock.Any(), 1, hashToken("abcdefgh")).Return(false, nil)
+				r.EXPECT().IncrementChallengeAttempts(gomock.Any(), 7).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
+				expectMFAFailureRecorded(t, a)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:  "failed code locks account",
+			token: token,
+			code:  "000000",
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				doctor := enabledDoctor()
+				doctor.FailedLoginAttempts = MaxFailedLogins - 1
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(doctor, nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().IncrementChallengeAttempts(gomock.Any(), 7).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(MaxFailedLogins, nil)
+				d.EXPECT().LockUntil(gomock.Any(), 1, twoFactorNow.Add(LoginLockoutDuration)).Return(nil)
+				expectMFAFailureRecorded(t, a)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:  "locked account rejects valid code",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				doctor := enabledDoctor()
+				lockedUntil := twoFactorNow.Add(time.Minute)
+				doctor.LockedUntil = &lockedUntil
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(doctor, nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.Equal(t, domain.LoginReasonAccountLocked, attempt.Reason)
+					return nil
+				})
+			},
+			wantErr: domain.ErrAccountLocked,
+		},
+		{
+			name:  "ip blocked",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(MaxFailedLoginsPerIP, nil)
+				a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+					assert.Equal(t, domain.LoginReasonIPBlocked, attempt.Reason)
+					return nil
+				})
+			},
+			wantErr: domain.ErrTooManyLoginAttempts,
+		},
+		{
+			name:  "valid code resets failed logins",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				doctor := enabledDoctor()
+				doctor.FailedLoginAttempts = 2
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(doctor, nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().MarkChallengeUsed(gomock.Any(), 7).Return(nil)
+				d.EXPECT().ResetFailedLogins(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name:  "enrollment challenge enables totp",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				doctor := enabledDoctor()
+				doctor.Role = domain.RoleAdmin
+				doctor.TOTPEnabled = false
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(true), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(doctor, nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().MarkChallengeUsed(gomock.Any(), 7).Return(nil)
+				r.EXPECT().EnableTOTP(gomock.Any(), 1).Return(nil)
+				r.EXPECT().ReplaceRecoveryCodes(gomock.Any(), 1, gomock.Len(recoveryCodeCount)).Return(nil)
+			},
+			wantRecoveryCodes: true,
+		},
+		{
+			name:  "enrollment challenge does not accept recovery codes",
+			token: token,
+			code:  "abcd-efgh",
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				doctor := enabledDoctor()
+				doctor.TOTPEnabled = false
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(true), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(doctor, nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().IncrementChallengeAttempts(gomock.Any(), 7).Return(nil)
+				d.EXPECT().IncrementFailedLogins(gomock.Any(), 1).Return(1, nil)
+				expectMFAFailureRecorded(t, a)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:    "empty token",
+			setup:   noMocks,
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+		{
+			name:  "unknown token",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(nil, nil)
+			},
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+		{
+			name:  "expired challenge",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				c := challenge(false)
+				c.ExpiresAt = twoFactorNow
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(c, nil)
+			},
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+		{
+			name:  "used challenge",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				c := challenge(false)
+				c.UsedAt = &usedAt
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(c, nil)
+			},
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+		{
+			name:  "too many attempts",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				c := challenge(false)
+				c.Attempts = maxMFAChallengeRetries
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(c, nil)
+			},
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+		{
+			name:  "challenge already used concurrently",
+			token: token,
+			code:  validCode,
+			setup: func(d *repository.MockDoctorRepository, r *repository.MockTwoFactorRepository, a *repository.MockLoginAttemptRepository) {
+				r.EXPECT().GetChallenge(gomock.Any(), hashToken(token)).Return(challenge(false), nil)
+				d.EXPECT().GetByID(gomock.Any(), 1).Return(enabledDoctor(), nil)
+				a.EXPECT().CountFailuresByIP(gomock.Any(), "10.0.0.1", gomock.Any()).Return(0, nil)
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().MarkChallengeUsed(gomock.Any(), 7).Return(errors.New("no rows"))
+			},
+			wantErr: domain.ErrInvalidMFAChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			twoFactorRepo := repository.NewMockTwoFactorRepository(ctrl)
+			loginAttemptRepo := repository.NewMockLoginAttemptRepository(ctrl)
+			tt.setup(doctorRepo, twoFactorRepo, loginAttemptRepo)
+			uc := newTestTwoFactorUseCase(doctorRepo, twoFactorRepo, loginAttemptRepo, true)
+
+			doctor, recoveryCodes, err := uc.VerifyLogin(context.Background(), tt.token, tt.code, "10.0.0.1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, doctor)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, doctor)
+			assert.Empty(t, doctor.Password)
+			assert.Empty(t, doctor.TOTPSecret)
+			assert.True(t, doctor.TOTPEnabled)
+			if tt.wantRecoveryCodes {
+				assert.Len(t, recoveryCodes, recoveryCodeCount)
+			} else {
+				assert.Nil(t, recoveryCodes)
+			}
+		})
+	}
+}
+
+func TestTwoFactorUseCase_ConfirmEnrollment(t *testing.T) {
+	validCode, err := totpCode(rfc6238Secret, totpStep(twoFactorNow))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		code    string
+		doctor  *domain.Doctor
+		setup   func(*repository.MockTwoFactorRepository)
+		wantErr error
+	}{
+		{
+			name:   "success",
+			code:   validCode,
+			doctor: &domain.Doctor{ID: 1, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().EnableTOTP(gomock.Any(), 1).Return(nil)
+				r.EXPECT().ReplaceRecoveryCodes(gomock.Any(), 1, gomock.Len(recoveryCodeCount)).Return(nil)
+			},
+		},
+		{
+			name:    "wrong code",
+			code:    "000000",
+			doctor:  &domain.Doctor{ID: 1, TOTPSecret: rfc6238Secret},
+			setup:   func(r *repository.MockTwoFactorRepository) {},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:    "enrollment not started",
+			code:    validCode,
+			doctor:  &domain.Doctor{ID: 1},
+			setup:   func(r *repository.MockTwoFactorRepository) {},
+			wantErr: domain.ErrTOTPNotEnrolled,
+		},
+		{
+			name:    "already enabled",
+			code:    validCode,
+			doctor:  &domain.Doctor{ID: 1, TOTPSecret: rfc6238Secret, TOTPEnabled: true},
+			setup:   func(r *repository.MockTwoFactorRepository) {},
+			wantErr: domain.ErrTOTPAlreadyEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			twoFactorRepo := repository.NewMockTwoFactorRepository(ctrl)
+			doctorRepo.EXPECT().GetByID(gomock.Any(), 1).Return(tt.doctor, nil)
+			tt.setup(twoFactorRepo)
+			uc := newTestTwoFactorUseCase(doctorRepo, twoFactorRepo, nil, false)
+
+			recoveryCodes, err := uc.ConfirmEnrollment(context.Background(), 1, tt.code)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, recoveryCodes)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, recoveryCodes, recoveryCodeCount)
+			for _, code := range recoveryCodes {
+				assert.Len(t, code, 9)
+				assert.NotEmpty(t, normalizeRecoveryCode(code))
+			}
+		})
+	}
+}
+
+func TestTwoFactorUseCase_Disable(t *testing.T) {
+	validCode, err := totpCode(rfc6238Secret, totpStep(twoFactorNow))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		code             string
+		doctor           *domain.Doctor
+		requireForAdmins bool
+		setup            func(*repository.MockTwoFactorRepository)
+		wantErr          error
+	}{
+		{
+			name:   "success",
+			code:   validCode,
+			doctor: &domain.Doctor{ID: 1, Role: domain.RoleDoctor, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().DisableTOTP(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name:   "admin without policy",
+			code:   validCode,
+			doctor: &domain.Doctor{ID: 1, Role: domain.RoleAdmin, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().UseTOTPStep(gomock.Any(), 1, totpStep(twoFactorNow)).Return(true, nil)
+				r.EXPECT().DisableTOTP(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name:             "admin under policy",
+			code:             validCode,
+			doctor:           &domain.Doctor{ID: 1, Role: domain.RoleAdmin, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			requireForAdmins: true,
+			setup:            func(r *repository.MockTwoFactorRepository) {},
+			wantErr:          domain.ErrTOTPRequiredForAdmin,
+		},
+		{
+			name:   "wrong code",
+			code:   "000000",
+			doctor: &domain.Doctor{ID: 1, Role: domain.RoleDoctor, TOTPEnabled: true, TOTPSecret: rfc6238Secret},
+			setup: func(r *repository.MockTwoFactorRepository) {
+				r.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantErr: domain.ErrInvalidMFACode,
+		},
+		{
+			name:    "not enabled",
+			code:    validCode,
+			doctor:  &domain.Doctor{ID: 1, Role: domain.RoleDoctor},
+			setup:   func(r *repository.MockTwoFactorRepository) {},
+			wantErr: domain.ErrTOTPNotEnrolled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			doctorRepo := repository.NewMockDoctorRepository(ctrl)
+			twoFactorRepo := repository.NewMockTwoFactorRepository(ctrl)
+			doctorRepo.EXPECT().GetByID(gomock.Any(), 1).Return(tt.doctor, nil)
+			tt.setup(twoFactorRepo)
+			uc := newTestTwoFactorUseCase(doctorRepo, twoFactorRepo, nil, tt.requireForAdmins)
+
+			err := uc.Disable(context.Background(), 1, tt.code)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "with dash", code: "abcd-efgh", want: "abcdefgh"},
+		{name: "upper case", code: " ABCD-EFGH ", want: "abcdefgh"},
+		{name: "without dash", code: "abcdefgh", want: "abcdefgh"},
+		{name: "totp code", code: "123456", want: ""},
+		{name: "empty", code: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeRecoveryCode(tt.code))
+		})
+	}
+}
+
+func expectMFAFailureRecorded(t *testing.T, a *repository.MockLoginAttemptRepository) {
+	a.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, attempt *domain.LoginAttempt) error {
+		assert.False(t, attempt.Success)
+		assert.Equal(t, domain.LoginReasonInvalidMFACode, attempt.Reason)
+		assert.Equal(t, "10.0.0.1", attempt.IP)
+		return nil
+	})
+}
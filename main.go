@@ -1,29 +1,56 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/sdk17/crmstom/internal/repository"
+	"github.com/sdk17/crmstom/internal/config"
 	httphandler "github.com/sdk17/crmstom/internal/interfaces/http"
 	"github.com/sdk17/crmstom/internal/usecase"
 )
 
 func main() {
-	// Подключение к PostgreSQL
-	if os.Getenv("DB_HOST") == "" {
-		log.Fatal("DB_HOST environment variable is required. Please configure PostgreSQL connection.")
+	// Контекст отменяется по SIGINT/SIGTERM, после чего сервер завершает текущие запросы
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Настройки: значения по умолчанию, файл -config, переменные окружения, флаги
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
+	if err != nil {
+		log.Fatalf("Ошибка конфигурации: %v", err)
+	}
+	fmt.Println("Действующие настройки:")
+	cfg.Dump(os.Stdout)
 
+	// Подключение к PostgreSQL
 	fmt.Println("Подключение к PostgreSQL...")
-	config := repository.NewDatabaseConfig()
-	db, err := repository.ConnectToDatabase(config)
+	db, err := repository.ConnectToDatabase(&repository.DatabaseConfig{
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.Name,
+		SSLMode:         cfg.Database.SSLMode,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		TimeZone:        cfg.Clinic.Timezone,
+	})
 	if err != nil {
 		log.Fatalf("Ошибка подключения к базе данных: %v", err)
 	}
-	defer db.Close()
 	fmt.Println("Подключение к PostgreSQL успешно")
 
 	// Инициализация репозиториев
@@ -31,16 +58,42 @@ func main() {
 	appointmentRepo := repository.NewAppointmentRepository(db)
 	serviceRepo := repository.NewServiceRepository(db)
 	doctorRepo := repository.NewDoctorRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	dentalChartRepo := repository.NewDentalChartRepository(db)
+	treatmentRepo := repository.NewTreatmentRepository(db)
+	treatmentPlanRepo := repository.NewTreatmentPlanRepository(db)
+	appointmentDiagnosisRepo := repository.NewAppointmentDiagnosisRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+
+	// Справочник диагнозов МКБ-10 встроен в приложение
+	diagnosisCatalog, err := repository.NewDiagnosisRepository()
+	if err != nil {
+		log.Fatalf("Ошибка загрузки справочника МКБ-10: %v", err)
+	}
 
 	// Инициализация use cases
-	patientUseCase := usecase.NewPatientUseCase(patientRepo)
-	appointmentUseCase := usecase.NewAppointmentUseCase(appointmentRepo, patientRepo, serviceRepo)
+	// Все даты и границы дней считаются в часовом поясе клиники
+	location := cfg.Location()
+	patientUseCase := usecase.NewPatientUseCase(patientRepo, unitOfWork)
+	appointmentUseCase := usecase.NewAppointmentUseCase(appointmentRepo, patientRepo, serviceRepo, doctorRepo, scheduleRepo, unitOfWork, location)
 	serviceUseCase := usecase.NewServiceUseCase(serviceRepo)
-	dashboardUseCase := usecase.NewDashboardUseCase(patientRepo, appointmentRepo, serviceRepo)
-	doctorUseCase := usecase.NewDoctorUseCase(doctorRepo)
+	dashboardUseCase := usecase.NewDashboardUseCase(patientRepo, appointmentRepo, serviceRepo, location)
+	doctorUseCase := usecase.NewDoctorUseCase(doctorRepo, loginAttemptRepo)
+	scheduleUseCase := usecase.NewScheduleUseCase(scheduleRepo, doctorRepo)
+	sessionUseCase := usecase.NewSessionUseCase(sessionRepo, doctorRepo)
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(doctorRepo, twoFactorRepo, loginAttemptRepo, cfg.Auth.RequireAdmin2FA, cfg.Clinic.Name)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+	dentalChartUseCase := usecase.NewDentalChartUseCase(dentalChartRepo, patientRepo, appointmentRepo, unitOfWork)
+	treatmentUseCase := usecase.NewTreatmentUseCase(treatmentRepo, appointmentRepo, serviceRepo, dentalChartRepo, diagnosisCatalog, unitOfWork)
+	treatmentPlanUseCase := usecase.NewTreatmentPlanUseCase(treatmentPlanRepo, patientRepo, serviceRepo, appointmentUseCase, unitOfWork)
+	diagnosisUseCase := usecase.NewDiagnosisUseCase(diagnosisCatalog, appointmentDiagnosisRepo, appointmentRepo, unitOfWork)
 
 	// Инициализация HTTP handlers
-	handler := httphandler.NewHandler(patientUseCase, appointmentUseCase, serviceUseCase, dashboardUseCase, doctorUseCase)
+	handler := httphandler.NewHandler(patientUseCase, appointmentUseCase, serviceUseCase, dashboardUseCase, doctorUseCase, scheduleUseCase, sessionUseCase, twoFactorUseCase, auditUseCase, dentalChartUseCase, treatmentUseCase, treatmentPlanUseCase, diagnosisUseCase, location)
 
 	// Настройка маршрутов
 	mux := http.NewServeMux()
@@ -48,21 +101,41 @@ func main() {
 	// API маршруты
 	handler.SetupRoutes(mux)
 
-	// Статические файлы
-	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static/"))))
+	// Статические файлы; HTML страницы без действующей сессии перенаправляются на вход
+	mux.Handle("/static/", handler.RequirePageSession(http.StripPrefix("/static/", http.FileServer(http.Dir("static/")))))
 
 	// HTML страницы
-	mux.HandleFunc("/", serveIndex)
+	mux.Handle("/", handler.RequirePageSession(http.HandlerFunc(serveIndex)))
 	mux.HandleFunc("/login.html", serveLogin)
-	mux.HandleFunc("/patients.html", servePatients)
-	mux.HandleFunc("/appointments.html", serveAppointments)
-	mux.HandleFunc("/patients-appointments.html", servePatientsAppointments)
-	mux.HandleFunc("/services.html", serveServices)
-	mux.HandleFunc("/reports.html", serveReports)
+	mux.Handle("/patients.html", handler.RequirePageSession(http.HandlerFunc(servePatients)))
+	mux.Handle("/appointments.html", handler.RequirePageSession(http.HandlerFunc(serveAppointments)))
+	mux.Handle("/patients-appointments.html", handler.RequirePageSession(http.HandlerFunc(servePatientsAppointments)))
+	mux.Handle("/services.html", handler.RequirePageSession(http.HandlerFunc(serveServices)))
+	mux.Handle("/reports.html", handler.RequirePageSession(http.HandlerFunc(serveReports)))
+
+	serverConfig := &httphandler.ServerConfig{
+		Addr:              cfg.Server.Addr,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ShutdownTimeout:   cfg.Server.ShutdownTimeout,
+		TLSCertFile:       cfg.Server.TLSCertFile,
+		TLSKeyFile:        cfg.Server.TLSKeyFile,
+	}
 
-	fmt.Println("🚀 Сервер запущен на http://localhost:8080")
+	fmt.Printf("🚀 Сервер запущен на %s\n", serverConfig.URL())
 	fmt.Println("📊 Clean Architecture + SOLID принципы")
-	log.Fatal(http.ListenAndServe(":8080", mux))
+	serveErr := httphandler.Run(ctx, serverConfig, handler.RequestID(handler.Timeout(cfg.Server.RequestTimeout, mux)))
+
+	// Пул соединений закрывается после завершения всех запросов
+	if err := db.Close(); err != nil {
+		log.Printf("Ошибка закрытия подключения к базе данных: %v", err)
+	}
+	if serveErr != nil {
+		log.Fatalf("Ошибка HTTP сервера: %v", serveErr)
+	}
+	fmt.Println("Сервер остановлен")
 }
 
 // Обработчики для статических файлов
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Prevent double-booking: a doctor cannot have overlapping active appointments
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE appointments ADD CONSTRAINT appointments_doctor_no_overlap
+    EXCLUDE USING gist (
+        doctor_id WITH =,
+        tsrange(appointment_date, appointment_date + COALESCE(duration_minutes, 0) * INTERVAL '1 minute') WITH &&
+    )
+    WHERE (deleted_at IS NULL AND status <> 'cancelled');
+
+-- +goose Down
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_doctor_no_overlap;
@@ -0,0 +1,53 @@
+-- +goose Up
+-- Doctor working hours, breaks, schedule exceptions and clinic holidays
+
+CREATE TABLE IF NOT EXISTS doctor_working_hours (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE IF NOT EXISTS doctor_breaks (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    title VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE IF NOT EXISTS doctor_schedule_exceptions (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    date_from DATE NOT NULL,
+    date_to DATE NOT NULL,
+    start_time TIME,
+    end_time TIME,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (date_from <= date_to)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_holidays (
+    id SERIAL PRIMARY KEY,
+    holiday_date DATE NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_doctor_working_hours_doctor ON doctor_working_hours(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctor_breaks_doctor ON doctor_breaks(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctor_schedule_exceptions_doctor ON doctor_schedule_exceptions(doctor_id, date_from, date_to);
+
+-- +goose Down
+DROP TABLE IF EXISTS clinic_holidays;
+DROP TABLE IF EXISTS doctor_schedule_exceptions;
+DROP TABLE IF EXISTS doctor_breaks;
+DROP TABLE IF EXISTS doctor_working_hours;
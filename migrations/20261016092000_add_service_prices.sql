@@ -0,0 +1,31 @@
+-- +goose Up
+-- Service price list: base price and default duration, effective-dated price changes
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS price DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (price >= 0);
+ALTER TABLE services ADD COLUMN IF NOT EXISTS duration_minutes INTEGER NOT NULL DEFAULT 30 CHECK (duration_minutes >= 0);
+
+-- Base price of existing services is taken from their latest appointment
+UPDATE services s SET price = latest.price
+FROM (
+    SELECT DISTINCT ON (service_id) service_id, price
+    FROM appointments
+    WHERE price IS NOT NULL AND deleted_at IS NULL
+    ORDER BY service_id, appointment_date DESC
+) latest
+WHERE latest.service_id = s.id;
+
+CREATE TABLE IF NOT EXISTS service_prices (
+    id SERIAL PRIMARY KEY,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    effective_from TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (service_id, effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_prices_service_effective ON service_prices(service_id, effective_from DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS service_prices;
+ALTER TABLE services DROP COLUMN IF EXISTS duration_minutes;
+ALTER TABLE services DROP COLUMN IF EXISTS price;
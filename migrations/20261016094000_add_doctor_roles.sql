@@ -0,0 +1,14 @@
+-- +goose Up
+-- Roles replace the single is_admin flag: admin, doctor, receptionist, accountant
+
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'doctor'
+    CHECK (role IN ('admin', 'doctor', 'receptionist', 'accountant'));
+
+UPDATE doctors SET role = 'admin' WHERE is_admin;
+
+ALTER TABLE doctors DROP COLUMN IF EXISTS is_admin;
+
+-- +goose Down
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS is_admin BOOLEAN DEFAULT FALSE;
+UPDATE doctors SET is_admin = (role = 'admin');
+ALTER TABLE doctors DROP COLUMN IF EXISTS role;
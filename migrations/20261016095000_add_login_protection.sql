@@ -0,0 +1,23 @@
+-- +goose Up
+-- Brute-force protection: per-account failure counter with temporary lockout and a login attempts journal
+
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id SERIAL PRIMARY KEY,
+    login VARCHAR(100) NOT NULL,
+    doctor_id INTEGER REFERENCES doctors(id) ON DELETE SET NULL,
+    ip VARCHAR(45) NOT NULL,
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(30),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created ON login_attempts(ip, created_at) WHERE NOT success;
+CREATE INDEX IF NOT EXISTS idx_login_attempts_login_created ON login_attempts(login, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempts;
+ALTER TABLE doctors DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE doctors DROP COLUMN IF EXISTS failed_login_attempts;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Optional TOTP (RFC 6238) second factor: secret per doctor, one-time recovery codes and login challenges
+
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+CREATE TABLE IF NOT EXISTS doctor_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (doctor_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    enrollment BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS doctor_recovery_codes;
+ALTER TABLE doctors DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE doctors DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE doctors DROP COLUMN IF EXISTS totp_secret;
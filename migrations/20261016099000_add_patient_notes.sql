@@ -0,0 +1,11 @@
+-- +goose Up
+-- Persist patient notes; the last visit is computed from completed appointments
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_appointments_patient_completed
+    ON appointments(patient_id, appointment_date DESC)
+    WHERE status = 'completed' AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_appointments_patient_completed;
+ALTER TABLE patients DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,59 @@
+-- +goose Up
+-- Appointment lifecycle: scheduled -> confirmed -> arrived -> in_progress -> completed,
+-- plus cancelled, no_show and rescheduled, with the time of each transition
+ALTER TABLE appointments
+    ADD COLUMN confirmed_at TIMESTAMPTZ,
+    ADD COLUMN arrived_at TIMESTAMPTZ,
+    ADD COLUMN started_at TIMESTAMPTZ,
+    ADD COLUMN completed_at TIMESTAMPTZ,
+    ADD COLUMN cancelled_at TIMESTAMPTZ,
+    ADD COLUMN no_show_at TIMESTAMPTZ,
+    ADD COLUMN rescheduled_at TIMESTAMPTZ,
+    ADD COLUMN cancellation_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN rescheduled_from_id INTEGER REFERENCES appointments(id);
+
+-- The exact transition time of existing appointments is unknown; the last update is the closest estimate
+UPDATE appointments SET completed_at = updated_at WHERE status = 'completed';
+UPDATE appointments SET cancelled_at = updated_at WHERE status = 'cancelled';
+UPDATE appointments SET status = 'scheduled' WHERE status IS NULL;
+
+ALTER TABLE appointments
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT appointments_status_check CHECK (status IN
+        ('scheduled', 'confirmed', 'arrived', 'in_progress', 'completed', 'cancelled', 'no_show', 'rescheduled'));
+
+-- Cancelled, missed and rescheduled appointments do not occupy the doctor's time
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_doctor_no_overlap;
+
+ALTER TABLE appointments ADD CONSTRAINT appointments_doctor_no_overlap
+    EXCLUDE USING gist (
+        doctor_id WITH =,
+        tstzrange(appointment_date, appointment_date + COALESCE(duration_minutes, 0) * INTERVAL '1 minute') WITH &&
+    )
+    WHERE (deleted_at IS NULL AND status NOT IN ('cancelled', 'no_show', 'rescheduled'));
+
+-- +goose Down
+ALTER TABLE appointments DROP CONSTRAINT IF EXISTS appointments_doctor_no_overlap;
+
+UPDATE appointments SET status = 'cancelled' WHERE status IN ('no_show', 'rescheduled');
+UPDATE appointments SET status = 'scheduled' WHERE status IN ('confirmed', 'arrived', 'in_progress');
+
+ALTER TABLE appointments ADD CONSTRAINT appointments_doctor_no_overlap
+    EXCLUDE USING gist (
+        doctor_id WITH =,
+        tstzrange(appointment_date, appointment_date + COALESCE(duration_minutes, 0) * INTERVAL '1 minute') WITH &&
+    )
+    WHERE (deleted_at IS NULL AND status <> 'cancelled');
+
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_status_check,
+    ALTER COLUMN status DROP NOT NULL,
+    DROP COLUMN IF EXISTS rescheduled_from_id,
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP COLUMN IF EXISTS rescheduled_at,
+    DROP COLUMN IF EXISTS no_show_at,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS arrived_at,
+    DROP COLUMN IF EXISTS confirmed_at;
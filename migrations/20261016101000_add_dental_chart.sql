@@ -0,0 +1,33 @@
+-- +goose Up
+-- Dental chart: current state of each tooth (FDI numbering) and the history of changes by appointment.
+-- Surfaces are stored as a formula string in canonical order, e.g. 'MOD'
+CREATE TABLE dental_chart_teeth (
+    patient_id INTEGER NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    tooth SMALLINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    surfaces VARCHAR(6) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    appointment_id INTEGER REFERENCES appointments(id),
+    doctor_id INTEGER REFERENCES doctors(id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (patient_id, tooth)
+);
+
+CREATE TABLE dental_chart_history (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    tooth SMALLINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    surfaces VARCHAR(6) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    appointment_id INTEGER REFERENCES appointments(id),
+    doctor_id INTEGER REFERENCES doctors(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_dental_chart_history_patient_tooth ON dental_chart_history(patient_id, tooth, created_at DESC);
+CREATE INDEX idx_dental_chart_history_appointment ON dental_chart_history(appointment_id) WHERE appointment_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS dental_chart_history;
+DROP TABLE IF EXISTS dental_chart_teeth;
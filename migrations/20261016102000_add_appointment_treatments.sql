@@ -0,0 +1,24 @@
+-- +goose Up
+-- Structured treatment records: the procedures done during an appointment, each with its own price.
+-- An appointment with treatments is billed at the sum of their prices
+CREATE TABLE appointment_treatments (
+    id SERIAL PRIMARY KEY,
+    appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+    service_id INTEGER NOT NULL REFERENCES services(id),
+    tooth SMALLINT,
+    surfaces VARCHAR(6) NOT NULL DEFAULT '',
+    tooth_status VARCHAR(20) NOT NULL DEFAULT '',
+    materials TEXT NOT NULL DEFAULT '',
+    anesthesia TEXT NOT NULL DEFAULT '',
+    diagnosis_code VARCHAR(10) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    price DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_appointment_treatments_appointment ON appointment_treatments(appointment_id) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS appointment_treatments;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Treatment plans: alternative variants of planned procedures the patient chooses between.
+-- Steps of the accepted variant are turned into appointments one by one
+CREATE TABLE treatment_plans (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    title VARCHAR(200) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'accepted', 'declined')),
+    doctor_id INTEGER REFERENCES doctors(id),
+    accepted_variant_id INTEGER,
+    accepted_at TIMESTAMPTZ,
+    signed_by VARCHAR(200) NOT NULL DEFAULT '',
+    declined_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_treatment_plans_patient ON treatment_plans(patient_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE treatment_plan_variants (
+    id SERIAL PRIMARY KEY,
+    plan_id INTEGER NOT NULL REFERENCES treatment_plans(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    title VARCHAR(200) NOT NULL
+);
+
+CREATE INDEX idx_treatment_plan_variants_plan ON treatment_plan_variants(plan_id);
+
+ALTER TABLE treatment_plans ADD CONSTRAINT fk_treatment_plans_accepted_variant
+    FOREIGN KEY (accepted_variant_id) REFERENCES treatment_plan_variants(id);
+
+CREATE TABLE treatment_plan_steps (
+    id SERIAL PRIMARY KEY,
+    variant_id INTEGER NOT NULL REFERENCES treatment_plan_variants(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    stage SMALLINT NOT NULL DEFAULT 1 CHECK (stage >= 1),
+    service_id INTEGER NOT NULL REFERENCES services(id),
+    tooth SMALLINT,
+    estimated_price DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (estimated_price >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    appointment_id INTEGER REFERENCES appointments(id)
+);
+
+CREATE INDEX idx_treatment_plan_steps_variant ON treatment_plan_steps(variant_id);
+
+-- +goose Down
+ALTER TABLE IF EXISTS treatment_plans DROP CONSTRAINT IF EXISTS fk_treatment_plans_accepted_variant;
+DROP TABLE IF EXISTS treatment_plan_steps;
+DROP TABLE IF EXISTS treatment_plan_variants;
+DROP TABLE IF EXISTS treatment_plans;
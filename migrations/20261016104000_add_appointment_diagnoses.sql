@@ -0,0 +1,21 @@
+-- +goose Up
+-- ICD-10 diagnoses made during an appointment, for the whole mouth or a single tooth.
+-- Codes come from the bundled ICD-10 dental catalog (K00-K14), so the table stores only the code
+CREATE TABLE appointment_diagnoses (
+    id SERIAL PRIMARY KEY,
+    appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+    code VARCHAR(10) NOT NULL,
+    tooth SMALLINT,
+    comment TEXT NOT NULL DEFAULT '',
+    doctor_id INTEGER REFERENCES doctors(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_appointment_diagnoses_appointment ON appointment_diagnoses(appointment_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_appointment_diagnoses_code ON appointment_diagnoses(code) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_appointment_diagnoses_unique ON appointment_diagnoses(appointment_id, code, COALESCE(tooth, 0))
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS appointment_diagnoses;
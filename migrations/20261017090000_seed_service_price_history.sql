@@ -0,0 +1,14 @@
+-- +goose Up
+-- Base price of every service becomes the first entry of its price history, so that prices of past dates
+-- no longer depend on edits of services.price. The entry is placed before any existing price change.
+
+INSERT INTO service_prices (service_id, price, effective_from)
+SELECT s.id, s.price,
+       LEAST(COALESCE(s.created_at, CURRENT_TIMESTAMP), MIN(sp.effective_from) - INTERVAL '1 second')
+FROM services s
+LEFT JOIN service_prices sp ON sp.service_id = s.id
+GROUP BY s.id, s.price, s.created_at
+ON CONFLICT (service_id, effective_from) DO NOTHING;
+
+-- +goose Down
+-- Seeded entries cannot be told apart from later price changes, so they are kept
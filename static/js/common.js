@@ -45,9 +45,13 @@ const Auth = {
         return true;
     },
 
-    logout() {
-        localStorage.clear();
-        window.location.href = '/login.html';
+    async logout() {
+        try {
+            await fetch('/api/auth/logout', { method: 'POST' });
+        } finally {
+            localStorage.clear();
+            window.location.href = '/login.html';
+        }
     },
 
     getUser() {
@@ -58,6 +62,16 @@ const Auth = {
         };
     },
 
+    roleName(role) {
+        const names = {
+            admin: 'Администратор',
+            doctor: 'Врач',
+            receptionist: 'Администратор регистратуры',
+            accountant: 'Бухгалтер'
+        };
+        return names[role] || role;
+    },
+
     setupLogoutButton() {
         const nav = document.querySelector('.nav');
         if (!nav) return;
@@ -74,6 +88,36 @@ const Auth = {
     }
 };
 
+// Session handling: on 401 from the API try to refresh the session once, otherwise go to login
+const originalFetch = window.fetch.bind(window);
+let refreshPromise = null;
+
+function refreshSession() {
+    if (!refreshPromise) {
+        refreshPromise = originalFetch('/api/auth/refresh', { method: 'POST' })
+            .then(response => response.ok)
+            .catch(() => false)
+            .finally(() => { refreshPromise = null; });
+    }
+    return refreshPromise;
+}
+
+window.fetch = async (input, init) => {
+    const response = await originalFetch(input, init);
+    const url = typeof input === 'string' ? input : input.url;
+    if (response.status !== 401 || !url.includes('/api/') || url.includes('/api/auth')) {
+        return response;
+    }
+
+    if (await refreshSession()) {
+        return originalFetch(input, init);
+    }
+
+    localStorage.clear();
+    window.location.href = '/login.html';
+    return response;
+};
+
 // Date formatting utilities
 const DateUtils = {
     format(dateString) {
@@ -99,6 +143,8 @@ const DateUtils = {
 
     toInputFormat(dateString) {
         if (!dateString) return '';
+        // Сервер отдает время клиники со смещением, поэтому дата берется как есть, без пересчета в UTC
+        if (/^\d{4}-\d{2}-\d{2}/.test(dateString)) return dateString.slice(0, 10);
         const date = new Date(dateString);
         if (isNaN(date.getTime())) return '';
         return date.toISOString().split('T')[0];
@@ -173,46 +219,116 @@ const Phone = {
     }
 };
 
+// Тексты ошибок API по стабильному коду error.code; для неизвестных кодов показывается error.message
+const ErrorMessages = {
+    bad_request: 'Некорректный запрос',
+    invalid_request_body: 'Некорректные данные запроса',
+    validation_failed: 'Проверьте правильность заполнения полей',
+    unauthorized: 'Требуется авторизация',
+    forbidden: 'Недостаточно прав',
+    not_found: 'Не найдено',
+    conflict: 'Конфликт данных',
+    internal_error: 'Внутренняя ошибка сервера',
+    request_timeout: 'Сервер не ответил вовремя, попробуйте еще раз',
+    too_many_requests: 'Слишком много запросов, попробуйте позже',
+    patient_not_found: 'Пациент не найден',
+    service_not_found: 'Услуга не найдена',
+    doctor_not_found: 'Врач не найден',
+    appointment_not_found: 'Запись не найдена',
+    schedule_exception_not_found: 'Исключение из графика не найдено',
+    holiday_not_found: 'Праздничный день не найден',
+    treatment_not_found: 'Процедура не найдена',
+    treatment_plan_not_found: 'План лечения не найден',
+    treatment_plan_not_editable: 'Изменить можно только черновик плана лечения',
+    treatment_plan_not_accepted: 'План лечения еще не принят пациентом',
+    plan_step_scheduled: 'По этой процедуре уже есть запись',
+    diagnosis_not_found: 'Диагноз не найден в справочнике МКБ-10',
+    appointment_diagnosis_not_found: 'Диагноз приема не найден',
+    appointment_diagnosis_exists: 'Этот диагноз уже поставлен на приеме',
+    patient_iin_exists: 'Пациент с таким ИИН уже существует',
+    patient_phone_exists: 'Пациент с таким номером телефона уже существует',
+    doctor_login_exists: 'Врач с таким логином уже существует',
+    appointment_conflict: 'Врач занят в это время',
+    doctor_unavailable: 'Врач не работает в это время',
+    invalid_status_transition: 'Нельзя перевести запись в этот статус',
+    concurrent_update: 'Данные изменились, попробуйте еще раз',
+    invalid_money: 'Некорректная сумма',
+    invalid_credentials: 'Неверный логин или пароль',
+    account_locked: 'Учетная запись временно заблокирована, попробуйте позже',
+    too_many_login_attempts: 'Слишком много попыток входа, попробуйте позже',
+    invalid_mfa_code: 'Неверный код подтверждения',
+    invalid_mfa_challenge: 'Срок действия входа истек, войдите заново',
+    totp_already_enabled: 'Двухфакторная аутентификация уже включена',
+    totp_not_enrolled: 'Двухфакторная аутентификация не настроена',
+    totp_required_for_admin: 'Администраторам необходима двухфакторная аутентификация',
+    invalid_session: 'Сессия истекла, войдите заново'
+};
+
+// Ошибка ответа API: code — стабильный код из error.code, details — остальные поля ошибки (fields, conflicts)
+class APIError extends Error {
+    constructor(status, error) {
+        super(ErrorMessages[error.code] || error.message || `HTTP error! status: ${status}`);
+        this.status = status;
+        this.code = error.code;
+        this.details = error;
+    }
+}
+
 // API utilities
 const API = {
-    async get(url) {
-        const response = await fetch(url);
+    async request(url, options = {}) {
+        const response = await fetch(url, options);
         if (!response.ok) {
-            throw new Error(`HTTP error! status: ${response.status}`);
+            const body = await response.json().catch(() => ({}));
+            throw new APIError(response.status, body.error || {});
         }
         return response.json();
     },
 
+    // Текст ошибки для пользователя: переведенное сообщение API или fallback для сетевых и прочих ошибок
+    errorMessage(error, fallback) {
+        return error instanceof APIError ? error.message : fallback;
+    },
+
+    async get(url) {
+        return this.request(url);
+    },
+
+    // Загружает все страницы списка, переходя по pagination.next_cursor
+    async getAll(url) {
+        const items = [];
+        let cursor = '';
+        do {
+            const separator = url.includes('?') ? '&' : '?';
+            const pageUrl = cursor ? `${url}${separator}cursor=${encodeURIComponent(cursor)}` : url;
+            const result = await this.get(pageUrl);
+            if (Array.isArray(result)) {
+                return result;
+            }
+            items.push(...(result.data || []));
+            cursor = result.pagination ? result.pagination.next_cursor : '';
+        } while (cursor);
+        return items;
+    },
+
     async post(url, data) {
-        const response = await fetch(url, {
+        return this.request(url, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify(data)
         });
-        if (!response.ok) {
-            throw new Error(`HTTP error! status: ${response.status}`);
-        }
-        return response.json();
     },
 
     async put(url, data) {
-        const response = await fetch(url, {
+        return this.request(url, {
             method: 'PUT',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify(data)
         });
-        if (!response.ok) {
-            throw new Error(`HTTP error! status: ${response.status}`);
-        }
-        return response.json();
     },
 
     async delete(url) {
-        const response = await fetch(url, { method: 'DELETE' });
-        if (!response.ok) {
-            throw new Error(`HTTP error! status: ${response.status}`);
-        }
-        return response.json();
+        return this.request(url, { method: 'DELETE' });
     }
 };
 
@@ -301,16 +417,151 @@ const Modal = {
     }
 };
 
+// Appointment lifecycle: статусы записи и действия, доступные из каждого статуса
+const AppointmentStatus = {
+    labels: {
+        scheduled: 'Запланировано',
+        confirmed: 'Подтверждено',
+        arrived: 'Пациент пришел',
+        in_progress: 'На приеме',
+        completed: 'Завершено',
+        cancelled: 'Отменено',
+        no_show: 'Не пришел',
+        rescheduled: 'Перенесено'
+    },
+
+    actions: {
+        scheduled: ['confirm', 'arrive', 'no-show', 'cancel'],
+        confirmed: ['arrive', 'no-show', 'cancel'],
+        arrived: ['start', 'complete', 'cancel'],
+        in_progress: ['complete']
+    },
+
+    buttons: {
+        confirm: { icon: '📞', title: 'Подтвердить', class: 'btn-primary' },
+        arrive: { icon: '🚪', title: 'Пациент пришел', class: 'btn-primary' },
+        start: { icon: '▶️', title: 'Начать прием', class: 'btn-primary' },
+        complete: { icon: '✓', title: 'Завершить', class: 'btn-success' },
+        'no-show': { icon: '🚫', title: 'Не пришел', class: 'btn-warning' },
+        cancel: { icon: '✖', title: 'Отменить', class: 'btn-danger' }
+    },
+
+    label(status) {
+        return this.labels[status] || status;
+    },
+
+    // Кнопки действий для записи; onclick вызывает handler(id, action)
+    renderActions(appointment, handler = 'changeStatus') {
+        return (this.actions[appointment.status] || []).map(action => {
+            const button = this.buttons[action];
+            return `<button class="btn btn-sm ${button.class}" title="${button.title}" onclick="${handler}(${appointment.id}, '${action}')">${button.icon}</button>`;
+        }).join('');
+    },
+
+    // Выполняет действие над записью; для отмены спрашивает причину
+    async perform(id, action) {
+        let body = {};
+        if (action === 'cancel') {
+            const reason = prompt('Причина отмены (необязательно):');
+            if (reason === null) return null;
+            body = { reason };
+        }
+        return API.post(`/api/appointments/${id}/${action}`, body);
+    }
+};
+
+// Dental chart: состояния зубов и расположение зубов в формуле по FDI
+const DentalChart = {
+    statuses: {
+        healthy: 'Здоров',
+        caries: 'Кариес',
+        filled: 'Пломба',
+        root_canal: 'Каналы запломбированы',
+        crown: 'Коронка',
+        veneer: 'Винир',
+        bridge: 'Мост',
+        implant: 'Имплант',
+        impacted: 'Ретинирован',
+        missing: 'Отсутствует'
+    },
+
+    // Поверхности указываются только для кариеса и пломб
+    withSurfaces: ['caries', 'filled'],
+
+    surfaces: {
+        M: 'Медиальная',
+        O: 'Жевательная',
+        I: 'Режущий край',
+        D: 'Дистальная',
+        B: 'Вестибулярная',
+        L: 'Оральная'
+    },
+
+    // Ряды формулы так, как ее видит врач: верхняя челюсть сверху, правая сторона пациента слева
+    layouts: {
+        permanent: [
+            [18, 17, 16, 15, 14, 13, 12, 11, 21, 22, 23, 24, 25, 26, 27, 28],
+            [48, 47, 46, 45, 44, 43, 42, 41, 31, 32, 33, 34, 35, 36, 37, 38]
+        ],
+        primary: [
+            [55, 54, 53, 52, 51, 61, 62, 63, 64, 65],
+            [85, 84, 83, 82, 81, 71, 72, 73, 74, 75]
+        ]
+    },
+
+    label(status) {
+        return this.statuses[status] || status;
+    },
+
+    // Поверхности зуба: у резцов и клыков режущий край, у боковых зубов жевательная поверхность
+    surfacesFor(tooth) {
+        const anterior = tooth % 10 <= 3;
+        return Object.keys(this.surfaces).filter(s => anterior ? s !== 'O' : s !== 'I');
+    }
+};
+
+// Treatment plans: статусы согласования плана лечения с пациентом
+const TreatmentPlanStatus = {
+    labels: {
+        draft: 'Черновик',
+        accepted: 'Принят',
+        declined: 'Отказ'
+    },
+
+    label(status) {
+        return this.labels[status] || status;
+    }
+};
+
+// Diagnosis catalog: подсказки кодов МКБ-10 из справочника при вводе диагноза
+const DiagnosisCatalog = {
+    // bind подключает к полю ввода список подсказок datalist, который обновляется по мере ввода
+    bind(inputId, datalistId) {
+        const input = document.getElementById(inputId);
+        const datalist = document.getElementById(datalistId);
+        if (!input || !datalist) return;
+        input.setAttribute('list', datalistId);
+
+        let timer = null;
+        input.addEventListener('input', () => {
+            clearTimeout(timer);
+            timer = setTimeout(async () => {
+                try {
+                    const result = await API.get(`/api/diagnoses?query=${encodeURIComponent(input.value.trim())}&limit=20`);
+                    datalist.innerHTML = (result.data || [])
+                        .filter(d => !d.has_subcodes)
+                        .map(d => `<option value="${d.code}">${d.name}</option>`).join('');
+                } catch (error) {
+                    datalist.innerHTML = '';
+                }
+            }, 250);
+        });
+    }
+};
+
 // Status badge renderer
 function renderStatusBadge(status) {
-    const statusMap = {
-        'scheduled': { text: 'Запланировано', class: 'status-scheduled' },
-        'completed': { text: 'Завершено', class: 'status-completed' },
-        'cancelled': { text: 'Отменено', class: 'status-cancelled' }
-    };
-
-    const info = statusMap[status] || { text: status, class: '' };
-    return `<span class="status-badge ${info.class}">${info.text}</span>`;
+    return `<span class="status-badge status-${status}">${AppointmentStatus.label(status)}</span>`;
 }
 
 // Initialize common functionality